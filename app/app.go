@@ -17,6 +17,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/server/config"
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
 	"github.com/cosmos/cosmos-sdk/simapp"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 	"github.com/cosmos/cosmos-sdk/version"
@@ -86,6 +88,7 @@ import (
 	"github.com/ignite-hq/cli/ignite/pkg/openapiconsole"
 	"github.com/lavanet/lava/app/keepers"
 	"github.com/lavanet/lava/app/upgrades"
+	"github.com/lavanet/lava/app/upgrades/registry"
 	"github.com/lavanet/lava/app/upgrades/v0_5_0"
 	"github.com/lavanet/lava/app/upgrades/v0_5_1"
 	"github.com/lavanet/lava/app/upgrades/v0_5_2"
@@ -103,6 +106,9 @@ import (
 	specmoduleclient "github.com/lavanet/lava/x/spec/client"
 	specmodulekeeper "github.com/lavanet/lava/x/spec/keeper"
 	specmoduletypes "github.com/lavanet/lava/x/spec/types"
+	upgradesmodule "github.com/lavanet/lava/x/upgrades"
+	upgradesmodulekeeper "github.com/lavanet/lava/x/upgrades/keeper"
+	upgradesmoduletypes "github.com/lavanet/lava/x/upgrades/types"
 	"github.com/spf13/cast"
 	abci "github.com/tendermint/tendermint/abci/types"
 	tmjson "github.com/tendermint/tendermint/libs/json"
@@ -117,8 +123,9 @@ const (
 	Name                 = "lava"
 )
 
-// Upgrades add here future upgrades (upgrades.Upgrade)
-var Upgrades = []upgrades.Upgrade{
+// UpgradeRegistry register here future upgrades (upgrades.Upgrade)
+// upgrades after v0.5.2 that only patch specs, change params or stores are added as manifests in app/upgrades/manifests
+var UpgradeRegistry = registry.MustNew(
 	upgrades.Upgrade_0_4_0,
 	upgrades.Upgrade_0_4_3,
 	upgrades.Upgrade_0_4_4,
@@ -128,6 +135,20 @@ var Upgrades = []upgrades.Upgrade{
 	v0_5_2.Upgrade,
 	upgrades.Upgrade_0_6_0,
 	upgrades.Upgrade_0_6_0_RC3,
+).MustRegister(upgrades.MustLoadManifestUpgrades()...)
+
+// ungatedUpgrades ran on chain before upgrades were gated by invariants, they run as they did
+// so a node syncing from genesis doesn't abort a block the network already committed
+var ungatedUpgrades = map[string]struct{}{
+	upgrades.Upgrade_0_4_0.UpgradeName:     {},
+	upgrades.Upgrade_0_4_3.UpgradeName:     {},
+	upgrades.Upgrade_0_4_4.UpgradeName:     {},
+	upgrades.Upgrade_0_4_5.UpgradeName:     {},
+	v0_5_0.UpgradeName:                     {},
+	v0_5_1.UpgradeName:                     {},
+	v0_5_2.UpgradeName:                     {},
+	upgrades.Upgrade_0_6_0.UpgradeName:     {},
+	upgrades.Upgrade_0_6_0_RC3.UpgradeName: {},
 }
 
 // this line is used by starport scaffolding # stargate/wasm/app/enabledProposals
@@ -179,6 +200,7 @@ var (
 		epochstoragemodule.AppModuleBasic{},
 		pairingmodule.AppModuleBasic{},
 		conflictmodule.AppModuleBasic{},
+		upgradesmodule.AppModuleBasic{},
 		// this line is used by starport scaffolding # stargate/app/moduleBasic
 	)
 
@@ -275,6 +297,7 @@ func New(
 		epochstoragemoduletypes.StoreKey,
 		pairingmoduletypes.StoreKey,
 		conflictmoduletypes.StoreKey,
+		upgradesmoduletypes.StoreKey,
 		// this line is used by starport scaffolding # stargate/app/storeKey
 	)
 	tkeys := sdk.NewTransientStoreKeys(paramstypes.TStoreKey)
@@ -334,7 +357,7 @@ func New(
 	app.UpgradeKeeper = upgradekeeper.NewKeeper(skipUpgradeHeights, keys[upgradetypes.StoreKey], appCodec, homePath, app.BaseApp)
 
 	// Upgrade the KVStoreKey after upgrade keeper initialization
-	app.setupUpgradeStoreLoaders()
+	app.setupUpgradeStoreLoaders(db)
 
 	// register the staking hooks
 	// NOTE: stakingKeeper above is passed by reference, so that it will contain these hooks
@@ -434,6 +457,18 @@ func New(
 	)
 	conflictModule := conflictmodule.NewAppModule(appCodec, app.ConflictKeeper, app.AccountKeeper, app.BankKeeper)
 
+	app.UpgradesKeeper = *upgradesmodulekeeper.NewKeeper(
+		appCodec,
+		keys[upgradesmoduletypes.StoreKey],
+		UpgradeRegistry.SupportedUpgrades(),
+
+		app.StakingKeeper,
+		app.UpgradeKeeper,
+		app.EpochstorageKeeper,
+		app.SpecKeeper,
+	)
+	upgradesModule := upgradesmodule.NewAppModule(appCodec, app.UpgradesKeeper)
+
 	// this line is used by starport scaffolding # stargate/app/keeperDefinition
 
 	// Create static IBC router, add transfer route, then set and seal it
@@ -477,6 +512,7 @@ func New(
 		epochstorageModule,
 		pairingModule,
 		conflictModule,
+		upgradesModule,
 		// this line is used by starport scaffolding # stargate/app/appModule
 	)
 
@@ -505,7 +541,8 @@ func New(
 		vestingtypes.ModuleName,
 		upgradetypes.ModuleName,
 		feegrant.ModuleName,
-		paramstypes.ModuleName)
+		paramstypes.ModuleName,
+		upgradesmoduletypes.ModuleName)
 
 	app.mm.SetOrderEndBlockers(
 		capabilitytypes.ModuleName,
@@ -528,7 +565,8 @@ func New(
 		vestingtypes.ModuleName,
 		upgradetypes.ModuleName,
 		feegrant.ModuleName,
-		paramstypes.ModuleName)
+		paramstypes.ModuleName,
+		upgradesmoduletypes.ModuleName)
 
 	// NOTE: The genutils module must occur after staking so that pools are
 	// properly initialized with tokens from genesis accounts.
@@ -556,6 +594,7 @@ func New(
 		upgradetypes.ModuleName,
 		feegrant.ModuleName,
 		paramstypes.ModuleName,
+		upgradesmoduletypes.ModuleName,
 		conflictmoduletypes.ModuleName, // NOTICE: the last module to initgenesis needs to push fixation in epoch storage
 		// this line is used by starport scaffolding # stargate/app/initGenesis
 	)
@@ -592,7 +631,7 @@ func New(
 	app.sm.RegisterStoreDecoders()
 
 	// initialize stores
-	app.MountKVStores(keys)
+	app.MountKVStores(app.mountedKVStores())
 	app.MountTransientStores(tkeys)
 	app.MountMemoryStores(memKeys)
 
@@ -624,35 +663,111 @@ func New(
 }
 
 // setupUpgradeStoreLoaders when intoducing new modules.
-func (app *LavaApp) setupUpgradeStoreLoaders() {
+func (app *LavaApp) setupUpgradeStoreLoaders(db dbm.DB) {
 	upgradeInfo, err := app.UpgradeKeeper.ReadUpgradeInfoFromDisk()
 	if err != nil {
 		panic(fmt.Sprintf("failed to read upgrade info from disk %s", err))
 	}
 
+	storeNames := make([]string, 0, len(app.keys))
+	for storeName := range app.keys {
+		storeNames = append(storeNames, storeName)
+	}
+	if err := UpgradeRegistry.ValidateStores(storeNames); err != nil {
+		panic(fmt.Sprintf("invalid upgrade registry: %s", err))
+	}
+	mountedStores := make([]string, 0, len(app.keys))
+	for storeName := range app.mountedKVStores() {
+		mountedStores = append(mountedStores, storeName)
+	}
+
 	if app.UpgradeKeeper.IsSkipHeight(upgradeInfo.Height) {
 		return
 	}
 
-	for _, upgrade := range Upgrades {
-		if upgradeInfo.Name == upgrade.UpgradeName {
-			app.SetStoreLoader(upgradetypes.UpgradeStoreLoader(upgradeInfo.Height, &upgrade.StoreUpgrades))
+	lastCommittedHeight, committedStores, err := lastCommitInfo(db)
+	if err != nil {
+		panic(fmt.Sprintf("failed to read last commit info %s", err))
+	}
+	// memory stores are committed too, only the kv stores are compared
+	committedKVStores := make([]string, 0, len(committedStores))
+	for _, storeName := range committedStores {
+		if app.memKeys[storeName] == nil && app.tkeys[storeName] == nil {
+			committedKVStores = append(committedKVStores, storeName)
 		}
 	}
+	if err := UpgradeRegistry.ValidatePendingUpgrade(upgradeInfo, lastCommittedHeight, committedKVStores, mountedStores); err != nil {
+		panic(err.Error())
+	}
+
+	if upgrade, found := UpgradeRegistry.Get(upgradeInfo.Name); found {
+		app.SetStoreLoader(upgradetypes.UpgradeStoreLoader(upgradeInfo.Height, &upgrade.StoreUpgrades))
+	}
+}
+
+// mountedKVStores returns the kv stores the app mounts, the stores of upgrades left out of the registry aren't mounted
+func (app *LavaApp) mountedKVStores() map[string]*sdk.KVStoreKey {
+	mounted := make(map[string]*sdk.KVStoreKey, len(app.keys))
+	for storeName, key := range app.keys {
+		mounted[storeName] = key
+	}
+	for _, storeName := range UpgradeRegistry.UnmountedStores() {
+		delete(mounted, storeName)
+	}
+	return mounted
+}
+
+// lastCommitInfo reads the last committed height and its stores the way the root multistore saves them
+func lastCommitInfo(db dbm.DB) (int64, []string, error) {
+	lastCommittedHeight := rootmulti.GetLatestVersion(db)
+	if lastCommittedHeight == 0 {
+		return 0, nil, nil
+	}
+	bz, err := db.Get([]byte(fmt.Sprintf("s/%d", lastCommittedHeight)))
+	if err != nil {
+		return 0, nil, err
+	}
+	commitInfo := &storetypes.CommitInfo{}
+	if err := commitInfo.Unmarshal(bz); err != nil {
+		return 0, nil, err
+	}
+	committedStores := make([]string, 0, len(commitInfo.StoreInfos))
+	for _, storeInfo := range commitInfo.StoreInfos {
+		committedStores = append(committedStores, storeInfo.Name)
+	}
+	return lastCommittedHeight, committedStores, nil
 }
 
 // setupUpgradeHandlers when modifing already existing modules
 func (app *LavaApp) setupUpgradeHandlers() {
-	for _, upgrade := range Upgrades {
-		app.UpgradeKeeper.SetUpgradeHandler(
-			upgrade.UpgradeName,
-			upgrade.CreateUpgradeHandler(
-				app.mm,
-				app.configurator,
-				app.BaseApp,
-				&app.LavaKeepers,
-			),
+	// the invariants of the lava modules run before and after every new upgrade handler, see upgrades.WithInvariantGate
+	invariants := upgrades.ModuleInvariants(app.CrisisKeeper.Routes(),
+		specmoduletypes.ModuleName,
+		epochstoragemoduletypes.ModuleName,
+		pairingmoduletypes.ModuleName,
+		conflictmoduletypes.ModuleName,
+	)
+	// the upgrades history is kept by the upgrades module, binaries from before it was added have nowhere to keep it
+	_, recordHistory := app.mountedKVStores()[upgradesmoduletypes.StoreKey]
+	for _, upgrade := range UpgradeRegistry.Upgrades() {
+		if upgrade.Manifest != nil {
+			if err := upgrades.ValidateManifest(upgrade.Manifest, app.ParamsKeeper, app.keys, app.tkeys); err != nil {
+				panic(fmt.Sprintf("invalid upgrade manifest: %s", err))
+			}
+		}
+		upgradeHandler := upgrade.CreateUpgradeHandler(
+			app.mm,
+			app.configurator,
+			app.BaseApp,
+			&app.LavaKeepers,
 		)
+		if _, ok := ungatedUpgrades[upgrade.UpgradeName]; !ok {
+			upgradeHandler = upgrades.WithInvariantGate(upgrade.UpgradeName, upgradeHandler, invariants)
+		}
+		if recordHistory {
+			upgradeHandler = upgrades.WithUpgradeRecord(upgrade.UpgradeName, upgradeHandler, app.UpgradesKeeper, app.SpecKeeper, app.keys[paramstypes.StoreKey])
+		}
+		app.UpgradeKeeper.SetUpgradeHandler(upgrade.UpgradeName, upgradeHandler)
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 	epochstoragemodulekeeper "github.com/lavanet/lava/x/epochstorage/keeper"
 	pairingmodulekeeper "github.com/lavanet/lava/x/pairing/keeper"
 	specmodulekeeper "github.com/lavanet/lava/x/spec/keeper"
+	upgradesmodulekeeper "github.com/lavanet/lava/x/upgrades/keeper"
 	// this line is used by starport scaffolding # stargate/app/moduleImport
 )
 
@@ -50,4 +51,5 @@ type LavaKeepers struct {
 	EpochstorageKeeper epochstoragemodulekeeper.Keeper
 	PairingKeeper      pairingmodulekeeper.Keeper
 	ConflictKeeper     conflictmodulekeeper.Keeper
+	UpgradesKeeper     upgradesmodulekeeper.Keeper
 }
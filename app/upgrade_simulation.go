@@ -0,0 +1,188 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/simapp"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	upgradekeeper "github.com/cosmos/cosmos-sdk/x/upgrade/keeper"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/ignite-hq/cli/ignite/pkg/cosmoscmd"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// UpgradeSimulationResult holds the outcome of running an upgrade handler on an exported state
+type UpgradeSimulationResult struct {
+	UpgradeName      string            `json:"upgrade_name"`
+	Height           int64             `json:"height"`
+	VersionMapBefore module.VersionMap `json:"version_map_before"`
+	VersionMapAfter  module.VersionMap `json:"version_map_after"`
+	StoreDiffs       []StoreDiff       `json:"store_diffs"`
+}
+
+// StoreDiff lists the keys an upgrade changed in a single module store
+type StoreDiff struct {
+	StoreKey string   `json:"store_key"`
+	Added    []string `json:"added,omitempty"`
+	Deleted  []string `json:"deleted,omitempty"`
+	Changed  []string `json:"changed,omitempty"`
+}
+
+func (sd StoreDiff) IsEmpty() bool {
+	return len(sd.Added) == 0 && len(sd.Deleted) == 0 && len(sd.Changed) == 0
+}
+
+// SimulateUpgrade loads the exported state in genesisDoc into an in-memory app, applies the StoreUpgrades
+// and runs the handler of upgradeName, nothing is written to disk besides a temporary home directory.
+// fromVersions overrides the module versions of the exported state, as they are not part of the export
+func SimulateUpgrade(logger log.Logger, genesisDoc *tmtypes.GenesisDoc, upgradeName string, fromVersions module.VersionMap) (result *UpgradeSimulationResult, err error) {
+	if _, found := UpgradeRegistry.Get(upgradeName); !found {
+		return nil, fmt.Errorf("upgrade %s is not part of this binary", upgradeName)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = fmt.Errorf("simulating upgrade %s panicked: %v", upgradeName, r)
+		}
+	}()
+	home, err := os.MkdirTemp("", "lava-upgrade-simulation")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(home)
+
+	db := dbm.NewMemDB()
+	encodingConfig := cosmoscmd.MakeEncodingConfig(ModuleBasics)
+
+	// load the exported state, as a node would on InitChain, and commit it
+	exportedApp := New(logger, db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{}).(*LavaApp)
+	exportedApp.InitChain(abci.RequestInitChain{
+		Time:            genesisDoc.GenesisTime,
+		ChainId:         genesisDoc.ChainID,
+		ConsensusParams: tmtypes.TM2PB.ConsensusParams(genesisDoc.ConsensusParams),
+		AppStateBytes:   genesisDoc.AppState,
+		InitialHeight:   genesisDoc.InitialHeight,
+	})
+	if len(fromVersions) > 0 {
+		ctx := exportedApp.NewContext(false, tmproto.Header{ChainID: genesisDoc.ChainID, Height: genesisDoc.InitialHeight})
+		exportedApp.UpgradeKeeper.SetModuleVersionMap(ctx, fromVersions)
+	}
+	exportedApp.Commit()
+	before := snapshotStores(exportedApp.CommitMultiStore(), exportedApp.keys)
+	upgradeHeight := exportedApp.LastBlockHeight() + 1
+
+	// a node restarting with the new binary at the upgrade height finds upgrade-info.json and applies the StoreUpgrades
+	if err := writeUpgradeInfo(home, upgradeName, upgradeHeight); err != nil {
+		return nil, err
+	}
+	upgradedApp := New(logger, db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{}).(*LavaApp)
+	header := tmproto.Header{ChainID: genesisDoc.ChainID, Height: upgradeHeight, Time: genesisDoc.GenesisTime}
+	ctx := sdk.NewContext(upgradedApp.CommitMultiStore().CacheMultiStore(), header, false, logger)
+	versionMapBefore := upgradedApp.UpgradeKeeper.GetModuleVersionMap(ctx)
+
+	upgradedApp.UpgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: upgradeName, Height: upgradeHeight})
+
+	after := snapshotStores(ctx.MultiStore(), upgradedApp.keys)
+	return &UpgradeSimulationResult{
+		UpgradeName:      upgradeName,
+		Height:           upgradeHeight,
+		VersionMapBefore: versionMapBefore,
+		VersionMapAfter:  upgradedApp.UpgradeKeeper.GetModuleVersionMap(ctx),
+		StoreDiffs:       diffStores(before, after),
+	}, nil
+}
+
+func writeUpgradeInfo(home string, upgradeName string, height int64) error {
+	dataDir := filepath.Join(home, "data")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return err
+	}
+	bz, err := json.Marshal(storetypes.UpgradeInfo{Name: upgradeName, Height: height})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataDir, upgradekeeper.UpgradeInfoFileName), bz, 0o600)
+}
+
+type storeSnapshot map[string]map[string][]byte
+
+func snapshotStores(multiStore sdk.MultiStore, keys map[string]*sdk.KVStoreKey) storeSnapshot {
+	snapshot := storeSnapshot{}
+	for name, key := range keys {
+		if !storeMounted(multiStore, key) {
+			continue
+		}
+		values := map[string][]byte{}
+		iterator := multiStore.GetKVStore(key).Iterator(nil, nil)
+		for ; iterator.Valid(); iterator.Next() {
+			values[string(iterator.Key())] = iterator.Value()
+		}
+		iterator.Close()
+		snapshot[name] = values
+	}
+	return snapshot
+}
+
+// storeMounted returns false for stores an upgrade deletes, GetKVStore panics on them
+func storeMounted(multiStore sdk.MultiStore, key *sdk.KVStoreKey) (mounted bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			mounted = false
+		}
+	}()
+	return multiStore.GetKVStore(key) != nil
+}
+
+func diffStores(before storeSnapshot, after storeSnapshot) []StoreDiff {
+	storeNames := map[string]struct{}{}
+	for name := range before {
+		storeNames[name] = struct{}{}
+	}
+	for name := range after {
+		storeNames[name] = struct{}{}
+	}
+	diffs := []StoreDiff{}
+	for name := range storeNames {
+		diff := StoreDiff{StoreKey: name}
+		for key, value := range after[name] {
+			beforeValue, ok := before[name][key]
+			if !ok {
+				diff.Added = append(diff.Added, FormatStoreKey([]byte(key)))
+			} else if !bytes.Equal(beforeValue, value) {
+				diff.Changed = append(diff.Changed, FormatStoreKey([]byte(key)))
+			}
+		}
+		for key := range before[name] {
+			if _, ok := after[name][key]; !ok {
+				diff.Deleted = append(diff.Deleted, FormatStoreKey([]byte(key)))
+			}
+		}
+		if diff.IsEmpty() {
+			continue
+		}
+		sort.Strings(diff.Added)
+		sort.Strings(diff.Deleted)
+		sort.Strings(diff.Changed)
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].StoreKey < diffs[j].StoreKey })
+	return diffs
+}
+
+// FormatStoreKey prints lava style keys (e.g. "Spec/value/ETH1/") as is and escapes binary bytes
+func FormatStoreKey(key []byte) string {
+	quoted := strconv.Quote(string(key))
+	return quoted[1 : len(quoted)-1]
+}
@@ -0,0 +1,90 @@
+package app_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/simapp"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/ignite-hq/cli/ignite/pkg/cosmoscmd"
+	"github.com/lavanet/lava/app"
+	"github.com/lavanet/lava/app/upgrades/v0_5_2"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func defaultGenesisDoc(t *testing.T) *tmtypes.GenesisDoc {
+	encodingConfig := cosmoscmd.MakeEncodingConfig(app.ModuleBasics)
+	appState, err := json.Marshal(app.NewDefaultGenesisState(encodingConfig.Marshaler))
+	require.NoError(t, err)
+	genesisDoc := &tmtypes.GenesisDoc{
+		GenesisTime:   time.Now(),
+		ChainID:       "lava",
+		InitialHeight: 1,
+		AppState:      appState,
+	}
+	require.NoError(t, genesisDoc.ValidateAndComplete())
+	return genesisDoc
+}
+
+func TestSimulateUpgrade(t *testing.T) {
+	genesisDoc := defaultGenesisDoc(t)
+	result, err := app.SimulateUpgrade(log.NewNopLogger(), genesisDoc, v0_5_2.UpgradeName, nil)
+	require.NoError(t, err)
+	require.Equal(t, v0_5_2.UpgradeName, result.UpgradeName)
+	require.NotEmpty(t, result.VersionMapAfter)
+	require.Equal(t, result.VersionMapBefore, result.VersionMapAfter)
+
+	storeKeys := map[string]app.StoreDiff{}
+	for _, storeDiff := range result.StoreDiffs {
+		storeKeys[storeDiff.StoreKey] = storeDiff
+	}
+	// the upgrade module marks the upgrade as done
+	require.Contains(t, storeKeys, "upgrade")
+	require.Contains(t, storeKeys["params"].Changed, "epochstorage/UnstakeHoldBlocksStatic")
+}
+
+func TestSimulateUpgradeMissingMigration(t *testing.T) {
+	genesisDoc := defaultGenesisDoc(t)
+	// spec has no registered migration from version 1, RunMigrations panics and the simulation reports it
+	_, err := app.SimulateUpgrade(log.NewNopLogger(), genesisDoc, v0_5_2.UpgradeName, module.VersionMap{"spec": 1})
+	require.ErrorContains(t, err, "no migration found for module spec from version 1 to version 2")
+}
+
+func TestSimulateUnknownUpgrade(t *testing.T) {
+	genesisDoc := defaultGenesisDoc(t)
+	_, err := app.SimulateUpgrade(log.NewNopLogger(), genesisDoc, "not-an-upgrade", nil)
+	require.Error(t, err)
+}
+
+func TestUnknownPendingUpgrade(t *testing.T) {
+	home := t.TempDir()
+	db := dbm.NewMemDB()
+	encodingConfig := cosmoscmd.MakeEncodingConfig(app.ModuleBasics)
+	lavaApp := app.New(log.NewNopLogger(), db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{}).(*app.LavaApp)
+	genesisDoc := defaultGenesisDoc(t)
+	lavaApp.InitChain(abci.RequestInitChain{ChainId: genesisDoc.ChainID, AppStateBytes: genesisDoc.AppState})
+	lavaApp.Commit()
+
+	// the node halted for an upgrade this binary doesn't have
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "data"), 0o755))
+	upgradeInfo := fmt.Sprintf(`{"name":"not-an-upgrade","height":%d}`, lavaApp.LastBlockHeight()+1)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "data", "upgrade-info.json"), []byte(upgradeInfo), 0o600))
+	require.Panics(t, func() {
+		app.New(log.NewNopLogger(), db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{})
+	})
+
+	// a known upgrade loads
+	upgradeInfo = fmt.Sprintf(`{"name":"v0.5.2","height":%d}`, lavaApp.LastBlockHeight()+1)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "data", "upgrade-info.json"), []byte(upgradeInfo), 0o600))
+	require.NotPanics(t, func() {
+		app.New(log.NewNopLogger(), db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{})
+	})
+}
@@ -0,0 +1,116 @@
+package upgrades
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/version"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	upgradestypes "github.com/lavanet/lava/x/upgrades/types"
+)
+
+// UpgradeRecorder stores the upgrade records, implemented by the upgrades module keeper
+type UpgradeRecorder interface {
+	SetUpgradeRecord(ctx sdk.Context, upgradeRecord upgradestypes.UpgradeRecord)
+}
+
+type SpecLister interface {
+	GetAllSpec(ctx sdk.Context) []spectypes.Spec
+}
+
+// WithUpgradeRecord records the upgrade in the upgrades module history once the handler succeeded: the module versions
+// before and after it, and the specs and params it changed, found by comparing the spec and params stores around the handler
+func WithUpgradeRecord(upgradeName string, handler upgradetypes.UpgradeHandler, recorder UpgradeRecorder, specLister SpecLister, paramsStoreKey sdk.StoreKey) upgradetypes.UpgradeHandler {
+	return func(ctx sdk.Context, plan upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		fromVersions := moduleVersions(fromVM)
+		specsBefore := specSnapshot(specLister.GetAllSpec(ctx))
+		paramsBefore := storeSnapshot(ctx.KVStore(paramsStoreKey))
+
+		toVM, err := handler(ctx, plan, fromVM)
+		if err != nil {
+			return nil, err
+		}
+
+		upgradeRecord := upgradestypes.UpgradeRecord{
+			PlanName:     plan.Name,
+			Height:       ctx.BlockHeight(),
+			Time:         ctx.BlockTime(),
+			FromVersions: fromVersions,
+			ToVersions:   moduleVersions(toVM),
+		}
+		upgradeRecord.AddedSpecs, upgradeRecord.ModifiedSpecs, upgradeRecord.RemovedSpecs = diffSnapshots(specsBefore, specSnapshot(specLister.GetAllSpec(ctx)))
+		upgradeRecord.ParamChanges = paramChanges(paramsBefore, storeSnapshot(ctx.KVStore(paramsStoreKey)))
+		recorder.SetUpgradeRecord(ctx, upgradeRecord)
+		// the binary version differs between the nodes running the handler, it is only logged and never stored
+		ctx.Logger().Info("recorded upgrade", "upgrade", upgradeName, "binaryVersion", version.Version, "addedSpecs", len(upgradeRecord.AddedSpecs),
+			"modifiedSpecs", len(upgradeRecord.ModifiedSpecs), "removedSpecs", len(upgradeRecord.RemovedSpecs), "paramChanges", len(upgradeRecord.ParamChanges))
+		return toVM, nil
+	}
+}
+
+func moduleVersions(vm module.VersionMap) []upgradestypes.ModuleVersion {
+	versions := make([]upgradestypes.ModuleVersion, 0, len(vm))
+	for moduleName, version := range vm {
+		versions = append(versions, upgradestypes.ModuleVersion{Name: moduleName, Version: version})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Name < versions[j].Name })
+	return versions
+}
+
+func specSnapshot(specs []spectypes.Spec) map[string][]byte {
+	snapshot := map[string][]byte{}
+	for _, spec := range specs {
+		specCopy := spec
+		// specs were read from the store, marshaling them back can't fail
+		snapshot[spec.Index], _ = specCopy.Marshal()
+	}
+	return snapshot
+}
+
+func storeSnapshot(store sdk.KVStore) map[string][]byte {
+	snapshot := map[string][]byte{}
+	iterator := store.Iterator(nil, nil)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		snapshot[string(iterator.Key())] = iterator.Value()
+	}
+	return snapshot
+}
+
+// diffSnapshots returns the sorted keys that were added, modified and removed between the snapshots
+func diffSnapshots(before map[string][]byte, after map[string][]byte) (added []string, modified []string, removed []string) {
+	for key, value := range after {
+		beforeValue, found := before[key]
+		if !found {
+			added = append(added, key)
+		} else if !bytes.Equal(beforeValue, value) {
+			modified = append(modified, key)
+		}
+	}
+	for key := range before {
+		if _, found := after[key]; !found {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(modified)
+	sort.Strings(removed)
+	return added, modified, removed
+}
+
+// paramChanges turns the params store keys, "subspace/key", of the changed params into param change records
+func paramChanges(before map[string][]byte, after map[string][]byte) []upgradestypes.ParamChangeRecord {
+	added, modified, removed := diffSnapshots(before, after)
+	changed := append(append(added, modified...), removed...)
+	sort.Strings(changed)
+	records := []upgradestypes.ParamChangeRecord{}
+	for _, storeKey := range changed {
+		subspace, key, _ := strings.Cut(storeKey, "/")
+		records = append(records, upgradestypes.ParamChangeRecord{Subspace: subspace, Key: key, Value: string(after[storeKey])})
+	}
+	return records
+}
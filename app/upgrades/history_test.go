@@ -0,0 +1,56 @@
+package upgrades_test
+
+import (
+	"fmt"
+
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/lavanet/lava/app/upgrades"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	upgradestypes "github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeRecord(t *testing.T) {
+	lavaApp, ctx := setupApp(t)
+	lavaApp.SpecKeeper.SetSpec(ctx, spectypes.Spec{Index: "ETH1", Name: "ethereum", Enabled: true})
+	lavaApp.SpecKeeper.SetSpec(ctx, spectypes.Spec{Index: "LAV1", Name: "lava", Enabled: true})
+	lavaApp.SpecKeeper.SetSpec(ctx, spectypes.Spec{Index: "FTM250", Name: "fantom", Enabled: true})
+
+	handler := func(ctx sdk.Context, plan upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+		lavaApp.SpecKeeper.SetSpec(ctx, spectypes.Spec{Index: "ETH1", Name: "ethereum", Enabled: false})
+		lavaApp.SpecKeeper.SetSpec(ctx, spectypes.Spec{Index: "COS3", Name: "osmosis", Enabled: true})
+		lavaApp.SpecKeeper.RemoveSpec(ctx, "FTM250")
+		epochstorageParams := lavaApp.EpochstorageKeeper.GetParams(ctx)
+		epochstorageParams.UnstakeHoldBlocksStatic = 1500
+		lavaApp.EpochstorageKeeper.SetParams(ctx, epochstorageParams)
+		return module.VersionMap{"spec": 2, "upgrades": 1}, nil
+	}
+	recorded := upgrades.WithUpgradeRecord("v1", handler, lavaApp.UpgradesKeeper, lavaApp.SpecKeeper, lavaApp.GetKey(paramstypes.StoreKey))
+	_, err := recorded(ctx, upgradetypes.Plan{Name: "v1", Height: ctx.BlockHeight()}, module.VersionMap{"spec": 1})
+	require.NoError(t, err)
+
+	upgradeRecord, found := lavaApp.UpgradesKeeper.GetUpgradeRecord(ctx, ctx.BlockHeight())
+	require.True(t, found)
+	require.Equal(t, "v1", upgradeRecord.PlanName)
+	require.Equal(t, []upgradestypes.ModuleVersion{{Name: "spec", Version: 1}}, upgradeRecord.FromVersions)
+	require.Equal(t, []upgradestypes.ModuleVersion{{Name: "spec", Version: 2}, {Name: "upgrades", Version: 1}}, upgradeRecord.ToVersions)
+	require.Equal(t, []string{"COS3"}, upgradeRecord.AddedSpecs)
+	require.Equal(t, []string{"ETH1"}, upgradeRecord.ModifiedSpecs)
+	require.Equal(t, []string{"FTM250"}, upgradeRecord.RemovedSpecs)
+	require.Equal(t, []upgradestypes.ParamChangeRecord{{Subspace: epochstoragetypes.ModuleName, Key: string(epochstoragetypes.KeyUnstakeHoldBlocksStatic), Value: `"1500"`}}, upgradeRecord.ParamChanges)
+
+	// a failed handler isn't recorded
+	failing := func(ctx sdk.Context, plan upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+		return nil, fmt.Errorf("migration failed")
+	}
+	recorded = upgrades.WithUpgradeRecord("v2", failing, lavaApp.UpgradesKeeper, lavaApp.SpecKeeper, lavaApp.GetKey(paramstypes.StoreKey))
+	_, err = recorded(ctx.WithBlockHeight(ctx.BlockHeight()+1), upgradetypes.Plan{Name: "v2"}, module.VersionMap{})
+	require.Error(t, err)
+	require.Len(t, lavaApp.UpgradesKeeper.GetAllUpgradeRecord(ctx), 1)
+}
@@ -0,0 +1,166 @@
+package upgrades
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	crisistypes "github.com/cosmos/cosmos-sdk/x/crisis/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+// BrokenInvariant is an invariant that failed and the message it reported
+type BrokenInvariant struct {
+	Route   string // module/route
+	Module  string
+	Message string
+}
+
+// ModuleMigration is a consensus version change of a module made by an upgrade handler
+type ModuleMigration struct {
+	Module      string
+	FromVersion uint64 // 0 for a module that was added by the upgrade
+	ToVersion   uint64
+}
+
+func (mm ModuleMigration) String() string {
+	if mm.FromVersion == 0 {
+		return fmt.Sprintf("%s (new module, version %d)", mm.Module, mm.ToVersion)
+	}
+	return fmt.Sprintf("%s %d->%d", mm.Module, mm.FromVersion, mm.ToVersion)
+}
+
+// InvariantsBrokenError aborts an upgrade, it reports the broken invariants and the migrations the handler ran
+type InvariantsBrokenError struct {
+	UpgradeName string
+	// BeforeUpgrade is set when the invariants were already broken before the handler ran
+	BeforeUpgrade bool
+	Broken        []BrokenInvariant
+	Migrations    []ModuleMigration
+}
+
+// SuspectedMigrations returns the migrations of the modules whose invariants broke,
+// the handler itself (e.g. spec patches or param changes) is suspected when none of them migrated
+func (e *InvariantsBrokenError) SuspectedMigrations() []ModuleMigration {
+	brokenModules := map[string]struct{}{}
+	for _, broken := range e.Broken {
+		brokenModules[broken.Module] = struct{}{}
+	}
+	suspected := []ModuleMigration{}
+	for _, migration := range e.Migrations {
+		if _, ok := brokenModules[migration.Module]; ok {
+			suspected = append(suspected, migration)
+		}
+	}
+	return suspected
+}
+
+func (e *InvariantsBrokenError) Error() string {
+	var sb strings.Builder
+	if e.BeforeUpgrade {
+		fmt.Fprintf(&sb, "upgrade %s aborted: %d invariant(s) are broken before the upgrade handler ran, the state was already inconsistent\n", e.UpgradeName, len(e.Broken))
+	} else {
+		fmt.Fprintf(&sb, "upgrade %s aborted: %d invariant(s) broke during the upgrade handler\n", e.UpgradeName, len(e.Broken))
+		migrations := []string{}
+		for _, migration := range e.Migrations {
+			migrations = append(migrations, migration.String())
+		}
+		fmt.Fprintf(&sb, "migrations run: [%s]\n", strings.Join(migrations, ", "))
+		suspected := []string{}
+		for _, migration := range e.SuspectedMigrations() {
+			suspected = append(suspected, migration.String())
+		}
+		if len(suspected) > 0 {
+			fmt.Fprintf(&sb, "suspected migrations: [%s]\n", strings.Join(suspected, ", "))
+		} else {
+			sb.WriteString("no migration of the broken modules ran, suspected the upgrade handler itself\n")
+		}
+	}
+	for _, broken := range e.Broken {
+		sb.WriteString(broken.Message)
+	}
+	return sb.String()
+}
+
+// ModuleInvariants filters the registered invariants of the given modules
+func ModuleInvariants(routes []crisistypes.InvarRoute, moduleNames ...string) []crisistypes.InvarRoute {
+	modules := map[string]struct{}{}
+	for _, moduleName := range moduleNames {
+		modules[moduleName] = struct{}{}
+	}
+	filtered := []crisistypes.InvarRoute{}
+	for _, route := range routes {
+		if _, ok := modules[route.ModuleName]; ok {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// WithInvariantGate runs the invariants right before and right after the upgrade handler,
+// the upgrade is aborted with an InvariantsBrokenError when any of them is broken
+func WithInvariantGate(upgradeName string, handler upgradetypes.UpgradeHandler, invariants []crisistypes.InvarRoute) upgradetypes.UpgradeHandler {
+	return func(ctx sdk.Context, plan upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		logger := ctx.Logger().With("upgrade", upgradeName)
+		if broken := AssertInvariants(ctx, invariants); len(broken) > 0 {
+			err := &InvariantsBrokenError{UpgradeName: upgradeName, BeforeUpgrade: true, Broken: broken}
+			logger.Error("invariants broken before the upgrade", "error", err.Error())
+			return nil, err
+		}
+
+		fromVersions := module.VersionMap{}
+		for moduleName, version := range fromVM {
+			fromVersions[moduleName] = version
+		}
+		toVM, err := handler(ctx, plan, fromVM)
+		if err != nil {
+			return nil, err
+		}
+
+		if broken := AssertInvariants(ctx, invariants); len(broken) > 0 {
+			err := &InvariantsBrokenError{UpgradeName: upgradeName, Broken: broken, Migrations: migrationsRun(fromVersions, toVM)}
+			for _, migration := range err.SuspectedMigrations() {
+				logger.Error("migration broke invariants", "migration", migration.String())
+			}
+			logger.Error("invariants broken by the upgrade", "error", err.Error())
+			return nil, err
+		}
+		logger.Info("upgrade invariants hold", "invariants", len(invariants))
+		return toVM, nil
+	}
+}
+
+// AssertInvariants runs the invariants and returns the broken ones, an invariant that panics counts as broken
+func AssertInvariants(ctx sdk.Context, invariants []crisistypes.InvarRoute) []BrokenInvariant {
+	broken := []BrokenInvariant{}
+	for _, route := range invariants {
+		if message, isBroken := runInvariant(ctx, route); isBroken {
+			broken = append(broken, BrokenInvariant{Route: route.FullRoute(), Module: route.ModuleName, Message: message})
+		}
+	}
+	return broken
+}
+
+func runInvariant(ctx sdk.Context, route crisistypes.InvarRoute) (message string, broken bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			message, broken = fmt.Sprintf("%s invariant panicked: %v\n", route.FullRoute(), r), true
+		}
+	}()
+	// invariants only read, the cache keeps a misbehaving one from writing to the upgrade state
+	cacheCtx, _ := ctx.CacheContext()
+	return route.Invar(cacheCtx)
+}
+
+func migrationsRun(fromVM module.VersionMap, toVM module.VersionMap) []ModuleMigration {
+	migrations := []ModuleMigration{}
+	for moduleName, toVersion := range toVM {
+		if fromVersion := fromVM[moduleName]; fromVersion != toVersion {
+			migrations = append(migrations, ModuleMigration{Module: moduleName, FromVersion: fromVersion, ToVersion: toVersion})
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Module < migrations[j].Module })
+	return migrations
+}
@@ -0,0 +1,70 @@
+package upgrades_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	crisistypes "github.com/cosmos/cosmos-sdk/x/crisis/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/lavanet/lava/app/upgrades"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvariantGate(t *testing.T) {
+	_, ctx := setupApp(t)
+	pairingBroken := false
+	invariants := []crisistypes.InvarRoute{
+		crisistypes.NewInvarRoute("spec", "ok", func(sdk.Context) (string, bool) { return "", false }),
+		crisistypes.NewInvarRoute("pairing", "breakable", func(sdk.Context) (string, bool) {
+			return sdk.FormatInvariant("pairing", "breakable", "\tbroken on purpose\n"), pairingBroken
+		}),
+	}
+	breakPairing := false
+	handler := func(ctx sdk.Context, plan upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+		pairingBroken = pairingBroken || breakPairing
+		return module.VersionMap{"spec": 2, "pairing": 3, "conflict": 1, "upgrades": 1}, nil
+	}
+	fromVM := module.VersionMap{"spec": 1, "pairing": 2, "conflict": 1}
+	gated := upgrades.WithInvariantGate("v1", handler, invariants)
+
+	toVM, err := gated(ctx, upgradetypes.Plan{Name: "v1"}, fromVM)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), toVM["pairing"])
+
+	// the handler breaks the pairing invariant, the pairing migration is suspected
+	breakPairing = true
+	_, err = gated(ctx, upgradetypes.Plan{Name: "v1"}, fromVM)
+	require.Error(t, err)
+	brokenErr, ok := err.(*upgrades.InvariantsBrokenError)
+	require.True(t, ok)
+	require.False(t, brokenErr.BeforeUpgrade)
+	require.Len(t, brokenErr.Broken, 1)
+	require.Equal(t, "pairing/breakable", brokenErr.Broken[0].Route)
+	require.Equal(t, []upgrades.ModuleMigration{{Module: "pairing", FromVersion: 2, ToVersion: 3}}, brokenErr.SuspectedMigrations())
+	require.Len(t, brokenErr.Migrations, 3) // pairing, spec and the new upgrades module
+	require.Contains(t, err.Error(), "suspected migrations: [pairing 2->3]")
+	require.Contains(t, err.Error(), "broken on purpose")
+
+	// already broken before the handler runs
+	_, err = gated(ctx, upgradetypes.Plan{Name: "v1"}, fromVM)
+	require.ErrorContains(t, err, "broken before the upgrade handler ran")
+
+	// a panicking invariant counts as broken
+	panicking := []crisistypes.InvarRoute{crisistypes.NewInvarRoute("spec", "panics", func(sdk.Context) (string, bool) { panic("corrupted state") })}
+	broken := upgrades.AssertInvariants(ctx, panicking)
+	require.Len(t, broken, 1)
+	require.Contains(t, broken[0].Message, "corrupted state")
+}
+
+func TestModuleInvariants(t *testing.T) {
+	lavaApp, ctx := setupApp(t)
+	invariants := upgrades.ModuleInvariants(lavaApp.CrisisKeeper.Routes(), "spec", "epochstorage", "pairing", "conflict")
+	routes := []string{}
+	for _, invariant := range invariants {
+		routes = append(routes, invariant.FullRoute())
+	}
+	require.ElementsMatch(t, []string{"spec/spec-imports", "epochstorage/unstake-queue-order", "pairing/module-account-stake", "pairing/epoch-payments", "conflict/conflict-votes"}, routes)
+	// the invariants hold on a fresh chain
+	require.Empty(t, upgrades.AssertInvariants(ctx, invariants))
+}
@@ -0,0 +1,196 @@
+package upgrades
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	store "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/ghodss/yaml"
+	"github.com/lavanet/lava/app/keepers"
+)
+
+// ManifestVersion is the manifest format this binary reads, bump it on breaking changes to the format
+const ManifestVersion = 1
+
+// AllSpecs as a spec patch index applies the patch to every spec in the store
+const AllSpecs = "*"
+
+//go:embed manifests
+var manifestsFS embed.FS
+
+const manifestsDir = "manifests"
+
+// Manifest describes a routine upgrade without code: spec patches, param changes and store changes.
+// manifests are json or yaml files in app/upgrades/manifests, they are embedded into the binary and
+// run by the same engine, see ApplyManifest
+type Manifest struct {
+	Version       int                 `json:"version"`
+	UpgradeName   string              `json:"upgrade_name"`
+	SpecPatches   []SpecPatch         `json:"spec_patches,omitempty"`
+	ParamChanges  []ParamChange       `json:"param_changes,omitempty"`
+	StoreUpgrades store.StoreUpgrades `json:"store_upgrades,omitempty"`
+}
+
+// SpecPatch is a json merge patch (RFC 7386) applied on the proto json of a spec,
+// e.g. {"providers_types": "dynamic", "min_stake_client": {"denom": "ulava", "amount": "5000000000"}}
+type SpecPatch struct {
+	Index string          `json:"index"` // spec index (chain id) or "*" for all specs
+	Patch json.RawMessage `json:"patch"`
+}
+
+// ParamChange sets a single param, the value is the amino json of the param as in a param change proposal
+type ParamChange struct {
+	Subspace string          `json:"subspace"`
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// ParseManifest reads a json or yaml manifest, the format is chosen by the file extension
+func ParseManifest(fileName string, bz []byte) (*Manifest, error) {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".json":
+	case ".yaml", ".yml":
+		var err error
+		bz, err = yaml.YAMLToJSON(bz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid yaml in upgrade manifest %s: %w", fileName, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported upgrade manifest file %s, expected .json, .yaml or .yml", fileName)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	decoder.DisallowUnknownFields()
+	manifest := &Manifest{}
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("failed decoding upgrade manifest %s: %w", fileName, err)
+	}
+	if err := manifest.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid upgrade manifest %s: %w", fileName, err)
+	}
+	return manifest, nil
+}
+
+// ValidateBasic checks the manifest without access to the app, see ValidateManifest for the full validation
+func (m *Manifest) ValidateBasic() error {
+	if m.Version != ManifestVersion {
+		return fmt.Errorf("manifest version %d is not supported, this binary reads version %d", m.Version, ManifestVersion)
+	}
+	if m.UpgradeName == "" {
+		return fmt.Errorf("missing upgrade_name")
+	}
+	for i, specPatch := range m.SpecPatches {
+		if specPatch.Index == "" {
+			return fmt.Errorf("spec patch %d: missing index, use %q for all specs", i, AllSpecs)
+		}
+		if _, err := specPatch.fields(); err != nil {
+			return fmt.Errorf("spec patch %d (%s): %w", i, specPatch.Index, err)
+		}
+	}
+	seenParams := map[string]struct{}{}
+	for i, paramChange := range m.ParamChanges {
+		if paramChange.Subspace == "" || paramChange.Key == "" {
+			return fmt.Errorf("param change %d: subspace and key are required", i)
+		}
+		if len(paramChange.Value) == 0 {
+			return fmt.Errorf("param change %d (%s/%s): missing value", i, paramChange.Subspace, paramChange.Key)
+		}
+		paramKey := paramChange.Subspace + "/" + paramChange.Key
+		if _, ok := seenParams[paramKey]; ok {
+			return fmt.Errorf("param %s is changed more than once", paramKey)
+		}
+		seenParams[paramKey] = struct{}{}
+	}
+	seenStores := map[string]struct{}{}
+	storeKeys := append(append([]string{}, m.StoreUpgrades.Added...), m.StoreUpgrades.Deleted...)
+	for _, rename := range m.StoreUpgrades.Renamed {
+		storeKeys = append(storeKeys, rename.OldKey, rename.NewKey)
+	}
+	for _, storeKey := range storeKeys {
+		if storeKey == "" {
+			return fmt.Errorf("empty store key in store_upgrades")
+		}
+		if _, ok := seenStores[storeKey]; ok {
+			return fmt.Errorf("store %s appears more than once in store_upgrades", storeKey)
+		}
+		seenStores[storeKey] = struct{}{}
+	}
+	return nil
+}
+
+// fields decodes the patch, it must be a json object and can't change the spec index
+func (sp SpecPatch) fields() (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(sp.Patch, &fields); err != nil {
+		return nil, fmt.Errorf("patch must be a json object: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty patch")
+	}
+	if _, ok := fields["index"]; ok {
+		return nil, fmt.Errorf("patch can't change the spec index")
+	}
+	return fields, nil
+}
+
+// NewManifestUpgrade creates an upgrade that applies the manifest and then runs the module migrations
+func NewManifestUpgrade(manifest *Manifest) Upgrade {
+	return Upgrade{
+		UpgradeName: manifest.UpgradeName,
+		CreateUpgradeHandler: func(mm *module.Manager, configurator module.Configurator, bapm BaseAppParamManager, lk *keepers.LavaKeepers) upgradetypes.UpgradeHandler {
+			return func(ctx sdk.Context, plan upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+				if err := ApplyManifest(ctx, lk, manifest); err != nil {
+					return nil, err
+				}
+				return mm.RunMigrations(ctx, configurator, vm)
+			}
+		},
+		StoreUpgrades: manifest.StoreUpgrades,
+		Manifest:      manifest,
+	}
+}
+
+// LoadManifestUpgrades parses the manifests embedded from app/upgrades/manifests, sorted by file name
+func LoadManifestUpgrades() ([]Upgrade, error) {
+	entries, err := manifestsFS.ReadDir(manifestsDir)
+	if err != nil {
+		return nil, err
+	}
+	fileNames := []string{}
+	for _, entry := range entries {
+		switch strings.ToLower(path.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			fileNames = append(fileNames, entry.Name())
+		}
+	}
+	sort.Strings(fileNames)
+	manifestUpgrades := []Upgrade{}
+	for _, fileName := range fileNames {
+		bz, err := manifestsFS.ReadFile(path.Join(manifestsDir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := ParseManifest(fileName, bz)
+		if err != nil {
+			return nil, err
+		}
+		manifestUpgrades = append(manifestUpgrades, NewManifestUpgrade(manifest))
+	}
+	return manifestUpgrades, nil
+}
+
+// MustLoadManifestUpgrades is LoadManifestUpgrades for package level declarations, a broken manifest can't be shipped
+func MustLoadManifestUpgrades() []Upgrade {
+	manifestUpgrades, err := LoadManifestUpgrades()
+	if err != nil {
+		panic(fmt.Sprintf("failed loading upgrade manifests: %s", err))
+	}
+	return manifestUpgrades
+}
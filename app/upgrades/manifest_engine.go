@@ -0,0 +1,191 @@
+package upgrades
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	storepkg "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/lavanet/lava/app/keepers"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// ApplyManifest runs the spec patches and param changes of a manifest, store changes are applied by the store loader
+func ApplyManifest(ctx sdk.Context, lk *keepers.LavaKeepers, manifest *Manifest) error {
+	logger := ctx.Logger().With("upgrade", manifest.UpgradeName)
+	// a spec patched more than once by the manifest becomes a single new version
+	patchedSpecs := map[string]spectypes.Spec{}
+	patchedIndexes := []string{}
+	for _, specPatch := range manifest.SpecPatches {
+		specs := []spectypes.Spec{}
+		if specPatch.Index == AllSpecs {
+			specs = lk.SpecKeeper.GetAllSpec(ctx)
+		} else {
+			spec, found := lk.SpecKeeper.GetSpec(ctx, specPatch.Index)
+			if !found {
+				return fmt.Errorf("upgrade %s: spec %s to patch was not found", manifest.UpgradeName, specPatch.Index)
+			}
+			specs = append(specs, spec)
+		}
+		for _, spec := range specs {
+			if patched, ok := patchedSpecs[spec.Index]; ok {
+				spec = patched
+			} else {
+				patchedIndexes = append(patchedIndexes, spec.Index)
+			}
+			patched, err := PatchSpec(spec, specPatch.Patch)
+			if err != nil {
+				return fmt.Errorf("upgrade %s: failed patching spec %s: %w", manifest.UpgradeName, spec.Index, err)
+			}
+			patchedSpecs[spec.Index] = patched
+		}
+	}
+	for _, index := range patchedIndexes {
+		patched := patchedSpecs[index]
+		if current, _ := lk.SpecKeeper.GetSpec(ctx, index); patched.Equal(current) {
+			// the spec already has the patched values, it keeps its version
+			continue
+		}
+		patched = lk.SpecKeeper.SetSpecNewVersion(ctx, patched)
+		logger.Info("patched spec", "spec", index, "version", patched.Version)
+	}
+	for _, paramChange := range manifest.ParamChanges {
+		subspace, found := lk.ParamsKeeper.GetSubspace(paramChange.Subspace)
+		if !found {
+			return fmt.Errorf("upgrade %s: param subspace %s was not found", manifest.UpgradeName, paramChange.Subspace)
+		}
+		if err := updateParam(ctx, subspace, paramChange); err != nil {
+			return fmt.Errorf("upgrade %s: %w", manifest.UpgradeName, err)
+		}
+		logger.Info("changed param", "subspace", paramChange.Subspace, "key", paramChange.Key, "value", string(paramChange.Value))
+	}
+	return nil
+}
+
+// PatchSpec merges patch into the proto json of spec and decodes the result back
+func PatchSpec(spec spectypes.Spec, patch json.RawMessage) (spectypes.Spec, error) {
+	specJSON, err := codec.ProtoMarshalJSON(&spec, nil)
+	if err != nil {
+		return spec, err
+	}
+	target := map[string]interface{}{}
+	if err := json.Unmarshal(specJSON, &target); err != nil {
+		return spec, err
+	}
+	patchFields := map[string]interface{}{}
+	if err := json.Unmarshal(patch, &patchFields); err != nil {
+		return spec, err
+	}
+	patchedJSON, err := json.Marshal(mergePatch(target, patchFields))
+	if err != nil {
+		return spec, err
+	}
+	patched := spectypes.Spec{}
+	if err := codec.NewProtoCodec(codectypes.NewInterfaceRegistry()).UnmarshalJSON(patchedJSON, &patched); err != nil {
+		return spec, err
+	}
+	return patched, nil
+}
+
+// mergePatch implements RFC 7386: objects are merged recursively, null removes a field and anything else replaces it
+func mergePatch(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	for key, patchValue := range patch {
+		if patchValue == nil {
+			delete(target, key)
+			continue
+		}
+		patchObject, patchIsObject := patchValue.(map[string]interface{})
+		targetObject, targetIsObject := target[key].(map[string]interface{})
+		if patchIsObject && targetIsObject {
+			target[key] = mergePatch(targetObject, patchObject)
+			continue
+		}
+		if patchIsObject {
+			// merging into a missing or non object field starts from an empty object
+			target[key] = mergePatch(map[string]interface{}{}, patchObject)
+			continue
+		}
+		target[key] = patchValue
+	}
+	return target
+}
+
+// updateParam sets the param like a param change proposal does, Update panics on keys that aren't registered
+func updateParam(ctx sdk.Context, subspace paramstypes.Subspace, paramChange ParamChange) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed changing param %s/%s: %v", paramChange.Subspace, paramChange.Key, r)
+		}
+	}()
+	if err := subspace.Update(ctx, []byte(paramChange.Key), paramChange.Value); err != nil {
+		return fmt.Errorf("failed changing param %s/%s: %w", paramChange.Subspace, paramChange.Key, err)
+	}
+	return nil
+}
+
+// ValidateManifest checks the manifest against the running app: store keys are checked against the mounted stores,
+// spec patches must decode into a spec and param changes are applied on an empty in-memory params store
+func ValidateManifest(manifest *Manifest, paramsKeeper paramskeeper.Keeper, keys map[string]*sdk.KVStoreKey, tkeys map[string]*sdk.TransientStoreKey) error {
+	if err := manifest.ValidateBasic(); err != nil {
+		return err
+	}
+	for _, added := range manifest.StoreUpgrades.Added {
+		if _, ok := keys[added]; !ok {
+			return fmt.Errorf("upgrade %s adds store %s which is not mounted by the app", manifest.UpgradeName, added)
+		}
+	}
+	for _, rename := range manifest.StoreUpgrades.Renamed {
+		if _, ok := keys[rename.NewKey]; !ok {
+			return fmt.Errorf("upgrade %s renames store %s to %s which is not mounted by the app", manifest.UpgradeName, rename.OldKey, rename.NewKey)
+		}
+	}
+	for _, deleted := range manifest.StoreUpgrades.Deleted {
+		if _, ok := keys[deleted]; ok {
+			return fmt.Errorf("upgrade %s deletes store %s which is still mounted by the app", manifest.UpgradeName, deleted)
+		}
+	}
+	for _, specPatch := range manifest.SpecPatches {
+		if _, err := PatchSpec(spectypes.Spec{}, specPatch.Patch); err != nil {
+			return fmt.Errorf("upgrade %s: invalid patch for spec %s: %w", manifest.UpgradeName, specPatch.Index, err)
+		}
+	}
+	if len(manifest.ParamChanges) == 0 {
+		return nil
+	}
+	ctx, err := scratchParamsContext(keys[paramstypes.StoreKey], tkeys[paramstypes.TStoreKey])
+	if err != nil {
+		return err
+	}
+	for _, paramChange := range manifest.ParamChanges {
+		subspace, found := paramsKeeper.GetSubspace(paramChange.Subspace)
+		if !found {
+			return fmt.Errorf("upgrade %s: param subspace %s was not found", manifest.UpgradeName, paramChange.Subspace)
+		}
+		if err := updateParam(ctx, subspace, paramChange); err != nil {
+			return fmt.Errorf("upgrade %s: %w", manifest.UpgradeName, err)
+		}
+	}
+	return nil
+}
+
+// scratchParamsContext mounts the params stores on an empty in-memory db, so param changes can be tried before the app is loaded
+func scratchParamsContext(key *sdk.KVStoreKey, tkey *sdk.TransientStoreKey) (sdk.Context, error) {
+	if key == nil || tkey == nil {
+		return sdk.Context{}, fmt.Errorf("params stores are not mounted")
+	}
+	db := dbm.NewMemDB()
+	multiStore := storepkg.NewCommitMultiStore(db)
+	multiStore.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	multiStore.MountStoreWithDB(tkey, sdk.StoreTypeTransient, db)
+	if err := multiStore.LoadLatestVersion(); err != nil {
+		return sdk.Context{}, err
+	}
+	return sdk.NewContext(multiStore, tmproto.Header{}, false, log.NewNopLogger()), nil
+}
@@ -0,0 +1,196 @@
+package upgrades_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/simapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/ignite-hq/cli/ignite/pkg/cosmoscmd"
+	"github.com/lavanet/lava/app"
+	"github.com/lavanet/lava/app/upgrades"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func setupApp(t *testing.T) (*app.LavaApp, sdk.Context) {
+	encodingConfig := cosmoscmd.MakeEncodingConfig(app.ModuleBasics)
+	lavaApp := app.New(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, t.TempDir(), 0, encodingConfig, simapp.EmptyAppOptions{}).(*app.LavaApp)
+	appState, err := json.Marshal(app.NewDefaultGenesisState(encodingConfig.Marshaler))
+	require.NoError(t, err)
+	lavaApp.InitChain(abci.RequestInitChain{ChainId: "lava", AppStateBytes: appState})
+	lavaApp.Commit()
+	return lavaApp, lavaApp.NewUncachedContext(false, tmproto.Header{ChainID: "lava", Height: lavaApp.LastBlockHeight() + 1})
+}
+
+func TestParseManifest(t *testing.T) {
+	manifestYAML := `
+version: 1
+upgrade_name: v0.7.0
+spec_patches:
+  - index: ETH1
+    patch:
+      enabled: false
+param_changes:
+  - subspace: epochstorage
+    key: UnstakeHoldBlocksStatic
+    value: "1500"
+store_upgrades:
+  added: [newmodule]
+`
+	manifest, err := upgrades.ParseManifest("v0.7.0.yaml", []byte(manifestYAML))
+	require.NoError(t, err)
+	require.Equal(t, "v0.7.0", manifest.UpgradeName)
+	require.Len(t, manifest.SpecPatches, 1)
+	require.JSONEq(t, `{"enabled":false}`, string(manifest.SpecPatches[0].Patch))
+	require.JSONEq(t, `"1500"`, string(manifest.ParamChanges[0].Value))
+	require.Equal(t, []string{"newmodule"}, manifest.StoreUpgrades.Added)
+
+	manifestJSON, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	fromJSON, err := upgrades.ParseManifest("v0.7.0.json", manifestJSON)
+	require.NoError(t, err)
+	require.Equal(t, manifest.UpgradeName, fromJSON.UpgradeName)
+
+	invalid := map[string]string{
+		"unknown field":      `{"version":1,"upgrade_name":"a","spec_patch":[]}`,
+		"version":            `{"version":2,"upgrade_name":"a"}`,
+		"missing name":       `{"version":1}`,
+		"patch index":        `{"version":1,"upgrade_name":"a","spec_patches":[{"index":"ETH1","patch":{"index":"ETH2"}}]}`,
+		"patch not object":   `{"version":1,"upgrade_name":"a","spec_patches":[{"index":"ETH1","patch":[1]}]}`,
+		"missing spec":       `{"version":1,"upgrade_name":"a","spec_patches":[{"patch":{"enabled":true}}]}`,
+		"duplicate param":    `{"version":1,"upgrade_name":"a","param_changes":[{"subspace":"s","key":"k","value":"1"},{"subspace":"s","key":"k","value":"2"}]}`,
+		"missing value":      `{"version":1,"upgrade_name":"a","param_changes":[{"subspace":"s","key":"k"}]}`,
+		"duplicate store":    `{"version":1,"upgrade_name":"a","store_upgrades":{"added":["s"],"deleted":["s"]}}`,
+		"empty store rename": `{"version":1,"upgrade_name":"a","store_upgrades":{"renamed":[{"old_key":"s"}]}}`,
+	}
+	for name, manifestJSON := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := upgrades.ParseManifest("manifest.json", []byte(manifestJSON))
+			require.Error(t, err)
+		})
+	}
+	_, err = upgrades.ParseManifest("manifest.toml", []byte(`version = 1`))
+	require.Error(t, err)
+}
+
+func TestEmbeddedManifests(t *testing.T) {
+	manifestUpgrades, err := upgrades.LoadManifestUpgrades()
+	require.NoError(t, err)
+	names := map[string]struct{}{}
+	for _, upgrade := range manifestUpgrades {
+		require.NotNil(t, upgrade.Manifest)
+		names[upgrade.UpgradeName] = struct{}{}
+	}
+	require.Contains(t, names, "v0.7.0")
+}
+
+func TestPatchSpec(t *testing.T) {
+	spec := spectypes.Spec{
+		Index:            "ETH1",
+		Name:             "ethereum",
+		Enabled:          true,
+		Apis:             []spectypes.ServiceApi{{Name: "eth_blockNumber", Enabled: true}},
+		MinStakeProvider: sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(1)),
+		MinStakeClient:   sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(2)),
+		ProvidersTypes:   spectypes.Spec_static,
+	}
+	patched, err := upgrades.PatchSpec(spec, json.RawMessage(`{"min_stake_client":{"amount":"5000"},"providers_types":"dynamic","name":null}`))
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(5000)), patched.MinStakeClient)
+	require.Equal(t, spectypes.Spec_dynamic, patched.ProvidersTypes)
+	require.Equal(t, "", patched.Name)
+	// untouched fields are kept
+	require.Equal(t, spec.Index, patched.Index)
+	require.Len(t, patched.Apis, 1)
+	require.True(t, spec.Apis[0].Equal(patched.Apis[0]))
+	require.Equal(t, spec.MinStakeProvider, patched.MinStakeProvider)
+
+	_, err = upgrades.PatchSpec(spec, json.RawMessage(`{"not_a_field":1}`))
+	require.Error(t, err)
+}
+
+func TestApplyManifest(t *testing.T) {
+	lavaApp, ctx := setupApp(t)
+	lavaApp.SpecKeeper.SetSpecNewVersion(ctx, spectypes.Spec{Index: "ETH1", Name: "ethereum", Enabled: true})
+	lavaApp.SpecKeeper.SetSpecNewVersion(ctx, spectypes.Spec{Index: "LAV1", Name: "lava", Enabled: true})
+	lavaApp.SpecKeeper.SetSpecNewVersion(ctx, spectypes.Spec{Index: "OSMO", Name: "osmosis", Enabled: true, ProvidersTypes: spectypes.Spec_static})
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 10)
+
+	manifest, err := upgrades.ParseManifest("v0.7.0.json", []byte(`{
+		"version": 1,
+		"upgrade_name": "v0.7.0",
+		"spec_patches": [
+			{"index": "*", "patch": {"providers_types": "static"}},
+			{"index": "ETH1", "patch": {"enabled": false}}
+		],
+		"param_changes": [
+			{"subspace": "epochstorage", "key": "UnstakeHoldBlocksStatic", "value": "1500"}
+		]
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, upgrades.ApplyManifest(ctx, &lavaApp.LavaKeepers, manifest))
+
+	eth, found := lavaApp.SpecKeeper.GetSpec(ctx, "ETH1")
+	require.True(t, found)
+	require.False(t, eth.Enabled)
+	require.Equal(t, spectypes.Spec_static, eth.ProvidersTypes)
+	lava, found := lavaApp.SpecKeeper.GetSpec(ctx, "LAV1")
+	require.True(t, found)
+	require.True(t, lava.Enabled)
+	require.Equal(t, spectypes.Spec_static, lava.ProvidersTypes)
+	require.Equal(t, uint64(1500), lavaApp.EpochstorageKeeper.UnstakeHoldBlocksStaticRaw(ctx))
+
+	// every patched spec is a new version active from the upgrade block, even when the manifest patched it twice
+	for _, spec := range []spectypes.Spec{eth, lava} {
+		require.Equal(t, uint64(2), spec.Version)
+		require.Equal(t, uint64(ctx.BlockHeight()), spec.BlockLastUpdated)
+		history, found := lavaApp.SpecKeeper.GetSpecVersionAtBlock(ctx, spec.Index, uint64(ctx.BlockHeight()))
+		require.True(t, found)
+		require.Equal(t, spec, history)
+		previous, found := lavaApp.SpecKeeper.GetSpecVersionAtBlock(ctx, spec.Index, uint64(ctx.BlockHeight()-1))
+		require.True(t, found)
+		require.Equal(t, uint64(1), previous.Version)
+	}
+	// a spec that already had the patched values keeps its version
+	osmo, found := lavaApp.SpecKeeper.GetSpec(ctx, "OSMO")
+	require.True(t, found)
+	require.Equal(t, uint64(1), osmo.Version)
+
+	// a missing spec fails the upgrade
+	manifest.SpecPatches = []upgrades.SpecPatch{{Index: "NOPE", Patch: json.RawMessage(`{"enabled":true}`)}}
+	require.Error(t, upgrades.ApplyManifest(ctx, &lavaApp.LavaKeepers, manifest))
+}
+
+func TestValidateManifest(t *testing.T) {
+	lavaApp, _ := setupApp(t)
+	keys := map[string]*sdk.KVStoreKey{
+		paramstypes.StoreKey:       lavaApp.GetKey(paramstypes.StoreKey),
+		spectypes.StoreKey:         lavaApp.GetKey(spectypes.StoreKey),
+		epochstoragetypes.StoreKey: lavaApp.GetKey(epochstoragetypes.StoreKey),
+	}
+	tkeys := map[string]*sdk.TransientStoreKey{paramstypes.TStoreKey: lavaApp.GetTKey(paramstypes.TStoreKey)}
+	validate := func(manifestJSON string) error {
+		manifest, err := upgrades.ParseManifest("manifest.json", []byte(manifestJSON))
+		require.NoError(t, err)
+		return upgrades.ValidateManifest(manifest, lavaApp.ParamsKeeper, keys, tkeys)
+	}
+
+	require.NoError(t, validate(`{"version":1,"upgrade_name":"a","param_changes":[{"subspace":"epochstorage","key":"UnstakeHoldBlocksStatic","value":"1500"}],"store_upgrades":{"added":["spec"]}}`))
+	// unknown subspace, unknown key and a value of the wrong type
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","param_changes":[{"subspace":"nope","key":"UnstakeHoldBlocksStatic","value":"1500"}]}`))
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","param_changes":[{"subspace":"epochstorage","key":"Nope","value":"1500"}]}`))
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","param_changes":[{"subspace":"epochstorage","key":"UnstakeHoldBlocksStatic","value":"not a number"}]}`))
+	// spec patch with a field the spec doesn't have
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","spec_patches":[{"index":"*","patch":{"not_a_field":true}}]}`))
+	// stores must match the mounted stores
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","store_upgrades":{"added":["notmounted"]}}`))
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","store_upgrades":{"deleted":["spec"]}}`))
+	require.Error(t, validate(`{"version":1,"upgrade_name":"a","store_upgrades":{"renamed":[{"old_key":"old","new_key":"notmounted"}]}}`))
+}
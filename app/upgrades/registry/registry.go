@@ -0,0 +1,211 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	store "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/lavanet/lava/app/upgrades"
+	upgradestypes "github.com/lavanet/lava/x/upgrades/types"
+)
+
+// Registry holds every upgrade the binary can run, registering validates the upgrade so a broken
+// list fails when the app starts and not when the chain halts at the upgrade height
+type Registry struct {
+	upgrades []upgrades.Upgrade
+	byName   map[string]int
+	// the stores added by the upgrades left out by Without, a binary released before them doesn't mount them
+	unmountedStores []string
+}
+
+func New() *Registry {
+	return &Registry{byName: map[string]int{}}
+}
+
+// MustNew creates a registry with upgrades registered in order, it panics on the first invalid upgrade
+func MustNew(upgradesToRegister ...upgrades.Upgrade) *Registry {
+	return New().MustRegister(upgradesToRegister...)
+}
+
+// Register adds an upgrade, names must be unique and a store can appear only once in the store upgrades
+func (r *Registry) Register(upgrade upgrades.Upgrade) error {
+	if upgrade.UpgradeName == "" {
+		return fmt.Errorf("upgrade with an empty name")
+	}
+	if _, ok := r.byName[upgrade.UpgradeName]; ok {
+		return fmt.Errorf("upgrade %s is registered more than once", upgrade.UpgradeName)
+	}
+	if upgrade.CreateUpgradeHandler == nil {
+		return fmt.Errorf("upgrade %s has no upgrade handler", upgrade.UpgradeName)
+	}
+	if err := validateStoreUpgrades(upgrade.StoreUpgrades); err != nil {
+		return fmt.Errorf("upgrade %s: %w", upgrade.UpgradeName, err)
+	}
+	r.byName[upgrade.UpgradeName] = len(r.upgrades)
+	r.upgrades = append(r.upgrades, upgrade)
+	return nil
+}
+
+func (r *Registry) MustRegister(upgradesToRegister ...upgrades.Upgrade) *Registry {
+	for _, upgrade := range upgradesToRegister {
+		if err := r.Register(upgrade); err != nil {
+			panic(fmt.Sprintf("invalid upgrade registration: %s", err))
+		}
+	}
+	return r
+}
+
+func (r *Registry) Get(upgradeName string) (upgrades.Upgrade, bool) {
+	index, ok := r.byName[upgradeName]
+	if !ok {
+		return upgrades.Upgrade{}, false
+	}
+	return r.upgrades[index], true
+}
+
+// Upgrades returns the registered upgrades in registration order
+func (r *Registry) Upgrades() []upgrades.Upgrade {
+	return append([]upgrades.Upgrade{}, r.upgrades...)
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.upgrades))
+	for _, upgrade := range r.upgrades {
+		names = append(names, upgrade.UpgradeName)
+	}
+	return names
+}
+
+// Without returns the registry of a binary released before the given upgrades: it can't run them and
+// doesn't mount the stores they add. the upgrade tests use it to open the app as it was before an upgrade
+func (r *Registry) Without(upgradeNames ...string) *Registry {
+	excluded := map[string]struct{}{}
+	for _, upgradeName := range upgradeNames {
+		excluded[upgradeName] = struct{}{}
+	}
+	without := New()
+	without.unmountedStores = append(without.unmountedStores, r.unmountedStores...)
+	for _, upgrade := range r.upgrades {
+		if _, ok := excluded[upgrade.UpgradeName]; ok {
+			without.unmountedStores = append(without.unmountedStores, upgrade.StoreUpgrades.Added...)
+			continue
+		}
+		without.MustRegister(upgrade)
+	}
+	return without
+}
+
+// UnmountedStores returns the stores added by the upgrades the registry was created without
+func (r *Registry) UnmountedStores() []string {
+	return append([]string{}, r.unmountedStores...)
+}
+
+// SupportedUpgrades describes the registered upgrades for the upgrades module query
+func (r *Registry) SupportedUpgrades() []upgradestypes.SupportedUpgrade {
+	supported := make([]upgradestypes.SupportedUpgrade, 0, len(r.upgrades))
+	for _, upgrade := range r.upgrades {
+		renamed := []upgradestypes.StoreRename{}
+		for _, rename := range upgrade.StoreUpgrades.Renamed {
+			renamed = append(renamed, upgradestypes.StoreRename{OldKey: rename.OldKey, NewKey: rename.NewKey})
+		}
+		supported = append(supported, upgradestypes.SupportedUpgrade{
+			Name:          upgrade.UpgradeName,
+			AddedStores:   upgrade.StoreUpgrades.Added,
+			RenamedStores: renamed,
+			DeletedStores: upgrade.StoreUpgrades.Deleted,
+			Declarative:   upgrade.Manifest != nil,
+		})
+	}
+	return supported
+}
+
+// ValidateStores checks the store upgrades against the stores the app mounts:
+// added stores and rename targets must be mounted, deleted stores must not be
+func (r *Registry) ValidateStores(mountedStores []string) error {
+	mounted := map[string]struct{}{}
+	for _, storeName := range mountedStores {
+		mounted[storeName] = struct{}{}
+	}
+	for _, upgrade := range r.upgrades {
+		for _, added := range upgrade.StoreUpgrades.Added {
+			if _, ok := mounted[added]; !ok {
+				return fmt.Errorf("upgrade %s adds store %s which is not mounted by the app", upgrade.UpgradeName, added)
+			}
+		}
+		for _, rename := range upgrade.StoreUpgrades.Renamed {
+			if _, ok := mounted[rename.NewKey]; !ok {
+				return fmt.Errorf("upgrade %s renames store %s to %s which is not mounted by the app", upgrade.UpgradeName, rename.OldKey, rename.NewKey)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidatePendingUpgrade is called on start with the upgrade info the node dumped when it halted.
+// when the upgrade wasn't applied yet (it is planned right after the last committed height) the binary must know it,
+// and the stores the app mounts must match the committed stores after applying the upgrade's store changes
+func (r *Registry) ValidatePendingUpgrade(upgradeInfo store.UpgradeInfo, lastCommittedHeight int64, committedStores []string, mountedStores []string) error {
+	if upgradeInfo.Name == "" || upgradeInfo.Height <= lastCommittedHeight {
+		// no plan, or the upgrade was already applied
+		return nil
+	}
+	upgrade, found := r.Get(upgradeInfo.Name)
+	if !found {
+		return fmt.Errorf("the node halted for upgrade %s at height %d but this binary doesn't support it, install the binary of the upgrade. supported upgrades: %s",
+			upgradeInfo.Name, upgradeInfo.Height, strings.Join(r.Names(), ", "))
+	}
+	if upgradeInfo.Height != lastCommittedHeight+1 || len(committedStores) == 0 {
+		return nil
+	}
+	expected := map[string]struct{}{}
+	for _, storeName := range committedStores {
+		expected[storeName] = struct{}{}
+	}
+	for _, added := range upgrade.StoreUpgrades.Added {
+		expected[added] = struct{}{}
+	}
+	for _, rename := range upgrade.StoreUpgrades.Renamed {
+		delete(expected, rename.OldKey)
+		expected[rename.NewKey] = struct{}{}
+	}
+	for _, deleted := range upgrade.StoreUpgrades.Deleted {
+		delete(expected, deleted)
+	}
+	missingUpgrades := []string{}
+	mounted := map[string]struct{}{}
+	for _, storeName := range mountedStores {
+		mounted[storeName] = struct{}{}
+		if _, ok := expected[storeName]; !ok {
+			missingUpgrades = append(missingUpgrades, fmt.Sprintf("store %s is mounted but wasn't committed, add it to StoreUpgrades.Added", storeName))
+		}
+	}
+	for storeName := range expected {
+		if _, ok := mounted[storeName]; !ok {
+			missingUpgrades = append(missingUpgrades, fmt.Sprintf("store %s was committed but isn't mounted, add it to StoreUpgrades.Deleted", storeName))
+		}
+	}
+	if len(missingUpgrades) > 0 {
+		sort.Strings(missingUpgrades)
+		return fmt.Errorf("upgrade %s doesn't match the stores of this binary: %s", upgradeInfo.Name, strings.Join(missingUpgrades, "; "))
+	}
+	return nil
+}
+
+func validateStoreUpgrades(storeUpgrades store.StoreUpgrades) error {
+	seen := map[string]struct{}{}
+	storeNames := append(append([]string{}, storeUpgrades.Added...), storeUpgrades.Deleted...)
+	for _, rename := range storeUpgrades.Renamed {
+		storeNames = append(storeNames, rename.OldKey, rename.NewKey)
+	}
+	for _, storeName := range storeNames {
+		if storeName == "" {
+			return fmt.Errorf("empty store name in store upgrades")
+		}
+		if _, ok := seen[storeName]; ok {
+			return fmt.Errorf("store %s appears more than once in store upgrades", storeName)
+		}
+		seen[storeName] = struct{}{}
+	}
+	return nil
+}
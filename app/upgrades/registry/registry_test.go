@@ -0,0 +1,126 @@
+package registry_test
+
+import (
+	"testing"
+
+	store "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/lavanet/lava/app/keepers"
+	"github.com/lavanet/lava/app/upgrades"
+	"github.com/lavanet/lava/app/upgrades/registry"
+	upgradestypes "github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testUpgrade(name string, storeUpgrades store.StoreUpgrades) upgrades.Upgrade {
+	return upgrades.Upgrade{
+		UpgradeName: name,
+		CreateUpgradeHandler: func(m *module.Manager, c module.Configurator, bapm upgrades.BaseAppParamManager, lk *keepers.LavaKeepers) upgradetypes.UpgradeHandler {
+			return func(ctx sdk.Context, plan upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+				return m.RunMigrations(ctx, c, vm)
+			}
+		},
+		StoreUpgrades: storeUpgrades,
+	}
+}
+
+func TestRegister(t *testing.T) {
+	r := registry.New()
+	require.NoError(t, r.Register(testUpgrade("v1", store.StoreUpgrades{})))
+	require.NoError(t, r.Register(testUpgrade("v2", store.StoreUpgrades{Added: []string{"newstore"}})))
+	require.Equal(t, []string{"v1", "v2"}, r.Names())
+	upgrade, found := r.Get("v2")
+	require.True(t, found)
+	require.Equal(t, []string{"newstore"}, upgrade.StoreUpgrades.Added)
+	_, found = r.Get("v3")
+	require.False(t, found)
+
+	// duplicate name
+	require.Error(t, r.Register(testUpgrade("v1", store.StoreUpgrades{})))
+	// empty name
+	require.Error(t, r.Register(testUpgrade("", store.StoreUpgrades{})))
+	// no handler
+	require.Error(t, r.Register(upgrades.Upgrade{UpgradeName: "v3"}))
+	// a store added and deleted in the same upgrade
+	require.Error(t, r.Register(testUpgrade("v3", store.StoreUpgrades{Added: []string{"s"}, Deleted: []string{"s"}})))
+	require.Error(t, r.Register(testUpgrade("v3", store.StoreUpgrades{Renamed: []store.StoreRename{{OldKey: "s"}}})))
+	require.Equal(t, []string{"v1", "v2"}, r.Names())
+
+	require.Panics(t, func() {
+		registry.MustNew(testUpgrade("v1", store.StoreUpgrades{}), testUpgrade("v1", store.StoreUpgrades{}))
+	})
+}
+
+func TestSupportedUpgrades(t *testing.T) {
+	r := registry.MustNew(
+		testUpgrade("v1", store.StoreUpgrades{}),
+		testUpgrade("v2", store.StoreUpgrades{Added: []string{"a"}, Renamed: []store.StoreRename{{OldKey: "b", NewKey: "c"}}, Deleted: []string{"d"}}),
+	)
+	r.MustRegister(upgrades.NewManifestUpgrade(&upgrades.Manifest{Version: upgrades.ManifestVersion, UpgradeName: "v3"}))
+
+	supported := r.SupportedUpgrades()
+	require.Len(t, supported, 3)
+	require.Equal(t, "v1", supported[0].Name)
+	require.False(t, supported[0].Declarative)
+	require.Equal(t, []string{"a"}, supported[1].AddedStores)
+	require.Equal(t, []upgradestypes.StoreRename{{OldKey: "b", NewKey: "c"}}, supported[1].RenamedStores)
+	require.Equal(t, []string{"d"}, supported[1].DeletedStores)
+	require.True(t, supported[2].Declarative)
+}
+
+func TestWithout(t *testing.T) {
+	r := registry.MustNew(
+		testUpgrade("v1", store.StoreUpgrades{Added: []string{"a"}}),
+		testUpgrade("v2", store.StoreUpgrades{Added: []string{"b", "c"}}),
+		testUpgrade("v3", store.StoreUpgrades{}),
+	)
+	without := r.Without("v2", "v3")
+	require.Equal(t, []string{"v1"}, without.Names())
+	require.Equal(t, []string{"b", "c"}, without.UnmountedStores())
+	require.Empty(t, r.UnmountedStores())
+	require.Equal(t, []string{"v1", "v2", "v3"}, r.Names())
+
+	// leaving out more upgrades keeps the stores already left out
+	require.Equal(t, []string{"b", "c", "a"}, without.Without("v1").UnmountedStores())
+}
+
+func TestValidateStores(t *testing.T) {
+	mounted := []string{"spec", "pairing", "newstore"}
+	r := registry.MustNew(testUpgrade("v1", store.StoreUpgrades{Added: []string{"newstore"}}))
+	require.NoError(t, r.ValidateStores(mounted))
+
+	r.MustRegister(testUpgrade("v2", store.StoreUpgrades{Added: []string{"notmounted"}}))
+	require.Error(t, r.ValidateStores(mounted))
+
+	r = registry.MustNew(testUpgrade("v1", store.StoreUpgrades{Renamed: []store.StoreRename{{OldKey: "old", NewKey: "notmounted"}}}))
+	require.Error(t, r.ValidateStores(mounted))
+}
+
+func TestValidatePendingUpgrade(t *testing.T) {
+	committed := []string{"spec", "pairing", "oldstore"}
+	mounted := []string{"spec", "pairing", "newstore"}
+	r := registry.MustNew(
+		testUpgrade("v1", store.StoreUpgrades{}),
+		testUpgrade("v2", store.StoreUpgrades{Added: []string{"newstore"}, Deleted: []string{"oldstore"}}),
+	)
+
+	// no upgrade planned
+	require.NoError(t, r.ValidatePendingUpgrade(store.UpgradeInfo{}, 100, committed, mounted))
+	// an unknown upgrade that was already applied doesn't matter
+	require.NoError(t, r.ValidatePendingUpgrade(store.UpgradeInfo{Name: "v0", Height: 50}, 100, committed, mounted))
+	// an unknown pending upgrade fails with the list of supported upgrades
+	err := r.ValidatePendingUpgrade(store.UpgradeInfo{Name: "v3", Height: 101}, 100, committed, mounted)
+	require.ErrorContains(t, err, "v3")
+	require.ErrorContains(t, err, "v1, v2")
+
+	// the store changes of v2 match the mounted stores
+	require.NoError(t, r.ValidatePendingUpgrade(store.UpgradeInfo{Name: "v2", Height: 101}, 100, committed, mounted))
+	// v1 has no store changes, so newstore is missing from Added and oldstore from Deleted
+	err = r.ValidatePendingUpgrade(store.UpgradeInfo{Name: "v1", Height: 101}, 100, committed, mounted)
+	require.ErrorContains(t, err, "store newstore is mounted but wasn't committed")
+	require.ErrorContains(t, err, "store oldstore was committed but isn't mounted")
+	// stores are only compared right before the upgrade height
+	require.NoError(t, r.ValidatePendingUpgrade(store.UpgradeInfo{Name: "v1", Height: 200}, 100, committed, mounted))
+}
@@ -19,4 +19,6 @@ type Upgrade struct {
 	UpgradeName          string
 	CreateUpgradeHandler func(*module.Manager, module.Configurator, BaseAppParamManager, *keepers.LavaKeepers) upgradetypes.UpgradeHandler
 	StoreUpgrades        store.StoreUpgrades
+	// Manifest is set for declarative upgrades, see NewManifestUpgrade
+	Manifest *Manifest
 }
@@ -0,0 +1,37 @@
+package upgrades_test
+
+import (
+	"testing"
+
+	"github.com/lavanet/lava/app"
+	"github.com/lavanet/lava/testutil/upgradetest"
+	upgradestypes "github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisteredUpgrades runs every registered upgrade, one after the other, on a chain populated with the
+// cookbook specs, staked providers and clients and epoch payments, and checks the queries after each of them
+func TestRegisteredUpgrades(t *testing.T) {
+	cfg := upgradetest.DefaultConfig()
+	cfg.Upgrades = app.UpgradeRegistry.Names()
+	harness := upgradetest.New(t, cfg)
+	harness.AssertQueries()
+	harness.AssertEpochPayments()
+
+	for i := range cfg.Upgrades {
+		harness.Upgrade()
+		harness.AssertQueries()
+		if i == 0 {
+			// the payments of the last epoch are kept across the first upgrade
+			harness.AssertEpochPayments()
+		}
+	}
+
+	// the upgrades from before the upgrades module was added have no record, the upgrade adding it records itself
+	history := &upgradestypes.QueryHistoryResponse{}
+	require.NoError(t, harness.Query("/lavanet.lava.upgrades.Query/History", &upgradestypes.QueryHistoryRequest{}, history))
+	require.NotEmpty(t, history.Records)
+	lastRecord := history.Records[len(history.Records)-1]
+	require.Equal(t, cfg.Upgrades[len(cfg.Upgrades)-1], lastRecord.PlanName)
+	require.NotEmpty(t, lastRecord.ToVersions)
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	_ "net/http/pprof"
@@ -19,8 +21,12 @@ import (
 	"github.com/ignite-hq/cli/ignite/pkg/cosmoscmd"
 	"github.com/lavanet/lava/app"
 	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/protocol/metrics"
 	"github.com/lavanet/lava/protocol/rpcconsumer"
 	"github.com/lavanet/lava/protocol/rpcprovider"
+	"github.com/lavanet/lava/protocol/rpcprovider/ratelimiter"
+	"github.com/lavanet/lava/protocol/rpcprovider/rewardserver"
+	"github.com/lavanet/lava/protocol/upgradewatcher"
 	"github.com/lavanet/lava/relayer"
 	"github.com/lavanet/lava/relayer/chainproxy"
 	"github.com/lavanet/lava/relayer/performance"
@@ -287,7 +293,22 @@ func main() {
 			}
 			txFactory := tx.NewFactoryCLI(clientCtx, cmd.Flags()).WithChainID(networkChainId)
 			rpcConsumer := rpcconsumer.RPCConsumer{}
-			requiredResponses := 1 // TODO: handle secure flag, for a majority between providers
+			requiredResponses := 1
+			secure, err := cmd.Flags().GetBool("secure")
+			if err != nil {
+				utils.LavaFormatFatal("failed to read secure flag", err, nil)
+			}
+			if secure {
+				// relays are sent to several providers, the majority reply is returned and disagreeing providers are reported
+				secureProviders, err := cmd.Flags().GetUint(rpcconsumer.SecureProvidersFlagName)
+				if err != nil {
+					utils.LavaFormatFatal("failed to read secure providers flag", err, nil)
+				}
+				if secureProviders < 2 {
+					return utils.LavaFormatError("secure mode needs at least two providers to compare", nil, &map[string]string{"secureProviders": strconv.FormatUint(uint64(secureProviders), 10)})
+				}
+				requiredResponses = int(secureProviders)
+			}
 			utils.LavaFormatInfo("lavad Binary Version: "+version.Version, nil)
 			rand.Seed(time.Now().UnixNano())
 			vrf_sk, _, err := utils.GetOrCreateVRFKey(clientCtx)
@@ -425,11 +446,70 @@ func main() {
 			if err != nil {
 				utils.LavaFormatFatal("error fetching chainproxy.ParallelConnectionsFlag", err, nil)
 			}
-			err = rpcProvider.Start(ctx, txFactory, clientCtx, rpcProviderEndpoints, cache, numberOfNodeParallelConnections)
+			rewardsDBDir, err := cmd.Flags().GetString(rewardserver.RewardsDBDirFlag)
+			if err != nil {
+				utils.LavaFormatFatal("error fetching rewardserver.RewardsDBDirFlag", err, nil)
+			}
+			metricsListenAddress, err := cmd.Flags().GetString(metrics.MetricsListenFlagName)
+			if err != nil {
+				utils.LavaFormatFatal("error fetching metrics.MetricsListenFlagName", err, nil)
+			}
+			consumerPolicyFile, err := cmd.Flags().GetString(ratelimiter.ConsumerPolicyFileFlag)
+			if err != nil {
+				utils.LavaFormatFatal("error fetching ratelimiter.ConsumerPolicyFileFlag", err, nil)
+			}
+			err = rpcProvider.Start(ctx, txFactory, clientCtx, rpcProviderEndpoints, cache, numberOfNodeParallelConnections, rewardsDBDir, metricsListenAddress, consumerPolicyFile)
 			return err
 		},
 	}
 
+	cmdUpgradeWatcher := &cobra.Command{
+		Use:   "upgrade-watcher [start-flags...]",
+		Short: `upgrade-watcher runs the node and swaps its binary when a software upgrade plan is reached`,
+		Long: `upgrade-watcher runs "lavad start" as a child process and follows the x/upgrade plan through the node's rpc.
+		when a plan is scheduled it stages the binary for this platform from the artifacts directory, verifying it with the checksum in the plan info json.
+		once the node halts at the upgrade height the child process is stopped, the staged binary becomes current and the node is restarted.
+		binaries are kept under <home>/upgrade-watcher, arguments are passed as is to "lavad start"
+		`,
+		Example: `lavad upgrade-watcher --artifacts-dir ./artifacts
+		lavad upgrade-watcher --artifacts-dir ./artifacts --node tcp://127.0.0.1:26657 -- --log_level debug`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			utils.LavaFormatInfo("Upgrade watcher started", &map[string]string{"args": strings.Join(args, ",")})
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			artifactsDir, err := cmd.Flags().GetString(upgradewatcher.ArtifactsDirFlag)
+			if err != nil {
+				return err
+			}
+			pollInterval, err := cmd.Flags().GetDuration(upgradewatcher.PollIntervalFlag)
+			if err != nil {
+				return err
+			}
+			stopTimeout, err := cmd.Flags().GetDuration(upgradewatcher.StopTimeoutFlag)
+			if err != nil {
+				return err
+			}
+			genesisBinary, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			config := upgradewatcher.UpgradeWatcherConfig{
+				Home:         clientCtx.HomeDir,
+				DaemonName:   app.Name + "d",
+				ArtifactsDir: artifactsDir,
+				PollInterval: pollInterval,
+				StopTimeout:  stopTimeout,
+				StartArgs:    args,
+			}
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			upgradeWatcher := upgradewatcher.NewUpgradeWatcher(config, upgradewatcher.NewLavaPlanFetcher(clientCtx))
+			return upgradeWatcher.Run(ctx, genesisBinary)
+		},
+	}
+
 	// Server command flags
 	flags.AddTxFlagsToCmd(cmdServer)
 	cmdServer.MarkFlagRequired(flags.FlagFrom)
@@ -467,7 +547,8 @@ func main() {
 	cmdRPCConsumer.Flags().String(flags.FlagChainID, app.Name, "network chain id")
 	cmdRPCConsumer.Flags().Uint64(sentry.GeolocationFlag, 0, "geolocation to run from")
 	cmdRPCConsumer.MarkFlagRequired(sentry.GeolocationFlag)
-	cmdRPCConsumer.Flags().Bool("secure", false, "secure sends reliability on every message")
+	cmdRPCConsumer.Flags().Bool("secure", false, "secure sends every relay to several providers, returns the majority reply and reports the disagreeing providers")
+	cmdRPCConsumer.Flags().Uint(rpcconsumer.SecureProvidersFlagName, rpcconsumer.DefaultSecureProviders, "number of providers each relay is sent to in secure mode")
 	cmdRPCConsumer.Flags().String(performance.PprofAddressFlagName, "", "pprof server address, used for code profiling")
 	cmdRPCConsumer.Flags().String(performance.CacheFlagName, "", "address for a cache server to improve performance")
 	rootCmd.AddCommand(cmdRPCConsumer)
@@ -481,7 +562,20 @@ func main() {
 	cmdRPCProvider.Flags().String(performance.PprofAddressFlagName, "", "pprof server address, used for code profiling")
 	cmdRPCProvider.Flags().String(performance.CacheFlagName, "", "address for a cache server to improve performance")
 	cmdRPCProvider.Flags().Uint(chainproxy.ParallelConnectionsFlag, chainproxy.NumberOfParallelConnections, "parallel connections")
-	// rootCmd.AddCommand(cmdRPCProvider) // TODO: DISABLE COMMAND SO IT'S NOT EXPOSED ON MAIN YET
+	cmdRPCProvider.Flags().String(rewardserver.RewardsDBDirFlag, "", "directory of the dbs keeping unpaid relay proofs and conflict vote commitments across restarts (default: the data directory in the node home)")
+	cmdRPCProvider.Flags().String(metrics.MetricsListenFlagName, "", "address to serve the provider prometheus metrics on, e.g. 127.0.0.1:7779 (default: metrics disabled)")
+	cmdRPCProvider.Flags().String(ratelimiter.ConsumerPolicyFileFlag, "", "yml file with the consumer allow and deny lists and priority classes, changes to it apply without a restart (default: every consumer is served with the same priority)")
+	rootCmd.AddCommand(cmdRPCProvider)
+
+	// Upgrade Watcher command flags
+	flags.AddQueryFlagsToCmd(cmdUpgradeWatcher)
+	cmdUpgradeWatcher.Flags().String(upgradewatcher.ArtifactsDirFlag, "", "directory to stage upgrade binaries from, <dir>/<upgrade-name>/lavad or the file name in the plan binary url")
+	cmdUpgradeWatcher.MarkFlagRequired(upgradewatcher.ArtifactsDirFlag)
+	cmdUpgradeWatcher.Flags().Duration(upgradewatcher.PollIntervalFlag, upgradewatcher.DefaultPollInterval, "how often to query the upgrade plan")
+	cmdUpgradeWatcher.Flags().Duration(upgradewatcher.StopTimeoutFlag, upgradewatcher.DefaultStopTimeout, "how long to wait for the node to stop before killing it")
+	rootCmd.AddCommand(cmdUpgradeWatcher)
+
+	rootCmd.AddCommand(NewUpgradeCmd())
 
 	if err := svrcmd.Execute(rootCmd, app.DefaultNodeHome); err != nil {
 		os.Exit(1)
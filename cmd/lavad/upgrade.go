@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/lavanet/lava/app"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/tendermint/libs/cli"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const (
+	GenesisFlag      = "genesis"
+	FromVersionsFlag = "from-versions"
+)
+
+func NewUpgradeCmd() *cobra.Command {
+	cmdUpgrade := &cobra.Command{
+		Use:   "upgrade",
+		Short: "software upgrade tools",
+	}
+
+	cmdUpgradeSimulate := &cobra.Command{
+		Use:   "simulate [upgrade-name]",
+		Short: `simulate runs an upgrade handler of this binary on an exported state without touching the node`,
+		Long: `simulate loads the exported state into an in-memory store, applies the StoreUpgrades and runs the upgrade handler.
+		it prints the module version map before and after the upgrade and the keys the upgrade added, deleted or changed in each store.
+		module versions are not part of the export, use --from-versions to set the versions the running chain is on`,
+		Example: `lavad export > exported.json
+		lavad upgrade simulate v0.5.2 --genesis exported.json
+		lavad upgrade simulate v0.5.2 --genesis exported.json --from-versions spec=2,pairing=2 --output json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			upgradeName := args[0]
+			genesisFile, err := cmd.Flags().GetString(GenesisFlag)
+			if err != nil {
+				return err
+			}
+			fromVersionsArg, err := cmd.Flags().GetString(FromVersionsFlag)
+			if err != nil {
+				return err
+			}
+			output, err := cmd.Flags().GetString(cli.OutputFlag)
+			if err != nil {
+				return err
+			}
+			fromVersions, err := parseVersionMap(fromVersionsArg)
+			if err != nil {
+				return err
+			}
+			genesisDoc, err := tmtypes.GenesisDocFromFile(genesisFile)
+			if err != nil {
+				return err
+			}
+			logger := server.GetServerContextFromCmd(cmd).Logger
+			result, err := app.SimulateUpgrade(logger, genesisDoc, upgradeName, fromVersions)
+			if err != nil {
+				return err
+			}
+			if output == "json" {
+				bz, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+				return nil
+			}
+			printUpgradeSimulation(cmd, result)
+			return nil
+		},
+	}
+	cmdUpgradeSimulate.Flags().String(GenesisFlag, "", "exported state to run the upgrade on, the output of lavad export")
+	cmdUpgradeSimulate.MarkFlagRequired(GenesisFlag)
+	cmdUpgradeSimulate.Flags().String(FromVersionsFlag, "", "module versions of the exported state, comma separated module=version")
+	cmdUpgradeSimulate.Flags().StringP(cli.OutputFlag, "o", "text", "output format (text|json)")
+
+	cmdUpgrade.AddCommand(cmdUpgradeSimulate)
+	return cmdUpgrade
+}
+
+// parseVersionMap parses "spec=2,pairing=2" into a version map
+func parseVersionMap(arg string) (module.VersionMap, error) {
+	versionMap := module.VersionMap{}
+	if arg == "" {
+		return versionMap, nil
+	}
+	for _, entry := range strings.Split(arg, ",") {
+		moduleVersion := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(moduleVersion) != 2 || moduleVersion[0] == "" {
+			return nil, fmt.Errorf("invalid module version %q, expected module=version", entry)
+		}
+		version, err := strconv.ParseUint(moduleVersion[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module version %q: %w", entry, err)
+		}
+		versionMap[moduleVersion[0]] = version
+	}
+	return versionMap, nil
+}
+
+func printUpgradeSimulation(cmd *cobra.Command, result *app.UpgradeSimulationResult) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "upgrade %s simulated at height %d\n\n", result.UpgradeName, result.Height)
+	fmt.Fprintln(out, "module versions:")
+	moduleNames := []string{}
+	for moduleName := range result.VersionMapAfter {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	for moduleName := range result.VersionMapBefore {
+		if _, ok := result.VersionMapAfter[moduleName]; !ok {
+			moduleNames = append(moduleNames, moduleName)
+		}
+	}
+	sort.Strings(moduleNames)
+	for _, moduleName := range moduleNames {
+		before, after := result.VersionMapBefore[moduleName], result.VersionMapAfter[moduleName]
+		if before == after {
+			fmt.Fprintf(out, "  %-20s %d\n", moduleName, after)
+		} else {
+			fmt.Fprintf(out, "  %-20s %d -> %d\n", moduleName, before, after)
+		}
+	}
+	fmt.Fprintln(out)
+	if len(result.StoreDiffs) == 0 {
+		fmt.Fprintln(out, "no store changes")
+		return
+	}
+	fmt.Fprintln(out, "store changes:")
+	for _, storeDiff := range result.StoreDiffs {
+		fmt.Fprintf(out, "  %s: %d added, %d deleted, %d changed\n", storeDiff.StoreKey, len(storeDiff.Added), len(storeDiff.Deleted), len(storeDiff.Changed))
+		for _, key := range storeDiff.Added {
+			fmt.Fprintf(out, "    + %s\n", key)
+		}
+		for _, key := range storeDiff.Deleted {
+			fmt.Fprintf(out, "    - %s\n", key)
+		}
+		for _, key := range storeDiff.Changed {
+			fmt.Fprintf(out, "    ~ %s\n", key)
+		}
+	}
+}
@@ -28,6 +28,17 @@ type parsedMessage struct {
 	msg              interface{}
 }
 
+// VerificationContainer is a call a provider makes to its node to verify the node serves the chain of the spec
+type VerificationContainer struct {
+	ApiName        string
+	ApiInterface   string
+	ConnectionType string
+	Url            string
+	Data           []byte
+	ResultParsing  spectypes.BlockParser
+	ExpectedValue  string
+}
+
 type BaseChainProxy struct {
 	averageBlockTime time.Duration
 }
@@ -128,6 +139,38 @@ func getServiceApis(spec spectypes.Spec, rpcInterface string) (retServerApis map
 	return serverApis, taggedApis
 }
 
+func getVerifications(spec spectypes.Spec, rpcInterface string) []VerificationContainer {
+	verifications := []VerificationContainer{}
+	if !spec.Enabled {
+		return verifications
+	}
+	for _, api := range spec.Apis {
+		if !api.Enabled {
+			continue
+		}
+		for _, apiInterface := range api.ApiInterfaces {
+			if apiInterface.Interface != rpcInterface || apiInterface.Verification == nil {
+				continue
+			}
+			verification := VerificationContainer{
+				ApiName:        api.Name,
+				ApiInterface:   rpcInterface,
+				ConnectionType: apiInterface.Type,
+				ResultParsing:  apiInterface.Verification.ResultParsing,
+				ExpectedValue:  apiInterface.Verification.ExpectedValue,
+			}
+			// rest requests are described by their url, the rest of the interfaces by their data
+			if rpcInterface == spectypes.APIInterfaceRest {
+				verification.Url = apiInterface.Verification.FunctionTemplate
+			} else {
+				verification.Data = []byte(apiInterface.Verification.FunctionTemplate)
+			}
+			verifications = append(verifications, verification)
+		}
+	}
+	return verifications
+}
+
 // matchSpecApiByName returns service api which match given name
 func matchSpecApiByName(name string, serverApis map[string]spectypes.ServiceApi) (spectypes.ServiceApi, bool) {
 	// TODO: make it faster and better by not doing a regex instead using a better algorithm
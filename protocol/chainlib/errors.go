@@ -0,0 +1,10 @@
+package chainlib
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var ( // Provider Side Node Errors
+	NodeVerificationMismatchError = sdkerrors.New("NodeVerificationMismatch Error", 10801, "node replied to a verification with a value other than the one in the spec")
+	NoVerifiedNodesError          = sdkerrors.New("NoVerifiedNodes Error", 10802, "no node of the endpoint passed the verification of the chain")
+)
@@ -130,6 +130,20 @@ func (apip *GrpcChainParser) SetSpec(spec spectypes.Spec) {
 	apip.taggedApis = taggedApis
 }
 
+// GetVerifications returns the calls the provider makes to verify its node serves the chain of the spec
+func (apip *GrpcChainParser) GetVerifications() []VerificationContainer {
+	// Guard that the GrpcChainParser instance exists
+	if apip == nil {
+		return nil
+	}
+
+	// Acquire read lock
+	apip.rwLock.RLock()
+	defer apip.rwLock.RUnlock()
+
+	return getVerifications(apip.spec, spectypes.APIInterfaceGrpc)
+}
+
 // DataReliabilityParams returns data reliability params from spec (spec.enabled and spec.dataReliabilityThreshold)
 func (apip *GrpcChainParser) DataReliabilityParams() (enabled bool, dataReliabilityThreshold uint32) {
 	// Guard that the GrpcChainParser instance exists
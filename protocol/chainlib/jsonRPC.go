@@ -130,6 +130,20 @@ func (apip *JsonRPCChainParser) getSupportedApi(name string) (*spectypes.Service
 	return &api, nil
 }
 
+// GetVerifications returns the calls the provider makes to verify its node serves the chain of the spec
+func (apip *JsonRPCChainParser) GetVerifications() []VerificationContainer {
+	// Guard that the JsonRPCChainParser instance exists
+	if apip == nil {
+		return nil
+	}
+
+	// Acquire read lock
+	apip.rwLock.RLock()
+	defer apip.rwLock.RUnlock()
+
+	return getVerifications(apip.spec, spectypes.APIInterfaceJsonRPC)
+}
+
 // DataReliabilityParams returns data reliability params from spec (spec.enabled and spec.dataReliabilityThreshold)
 func (apip *JsonRPCChainParser) DataReliabilityParams() (enabled bool, dataReliabilityThreshold uint32) {
 	// Guard that the JsonRPCChainParser instance exists
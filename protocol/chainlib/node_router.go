@@ -0,0 +1,353 @@
+package chainlib
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lavanet/lava/protocol/chainlib/chainproxy/rpcclient"
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+)
+
+const (
+	MinNodeHealthCheckInterval = time.Second
+	MinRelaysForNodeErrorRate  = 10 // below this many relays in a health check interval the error rate of a node is ignored
+	MaxNodeErrorRatePercent    = 30
+	NodeVerificationInterval   = 5 * time.Minute
+)
+
+type latestBlockFetcher interface {
+	FetchLatestBlockNum(ctx context.Context) (int64, error)
+}
+
+type latestBlockGetter interface {
+	GetLatestBlockNum() int64
+}
+
+type routedNode struct {
+	url           string // used for logs
+	chainProxy    ChainProxy
+	fetcher       latestBlockFetcher
+	weight        uint64
+	historyBlocks uint64 // 0 for an archive node
+	latestBlock   int64  // 0 until the node reported its latest block, protected by the router lock
+	healthy       bool   // protected by the router lock
+	verified      bool   // false when the node failed the verification of the chain, protected by the router lock
+	// set while the node couldn't be queried for the verification since startup, it is retried on every health check. protected by the router lock
+	verificationPending bool
+	relays              uint64 // relays since the last health check, accessed atomically
+	errors              uint64 // failed relays since the last health check, accessed atomically
+}
+
+func newRoutedNode(nodeConfig lavasession.NodeConfig, chainProxy ChainProxy, fetcher latestBlockFetcher) *routedNode {
+	weight := nodeConfig.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	return &routedNode{
+		url:           strings.Join(nodeConfig.Urls, ","),
+		chainProxy:    chainProxy,
+		fetcher:       fetcher,
+		weight:        weight,
+		historyBlocks: nodeConfig.HistoryBlocks,
+		healthy:       true,
+		verified:      true,
+	}
+}
+
+// holdsBlock returns whether the node still has the requested block, when we don't know the latest block we assume it does
+func (node *routedNode) holdsBlock(requestedBlock int64, referenceBlock int64) bool {
+	if requestedBlock == spectypes.EARLIEST_BLOCK {
+		return node.historyBlocks == 0
+	}
+	if requestedBlock < 0 || node.historyBlocks == 0 {
+		// latest, pending and the rest of the relative blocks are held by every node
+		return true
+	}
+	latestBlock := node.latestBlock
+	if latestBlock == 0 {
+		latestBlock = referenceBlock
+	}
+	if latestBlock == 0 {
+		return true
+	}
+	return latestBlock-requestedBlock < int64(node.historyBlocks)
+}
+
+func (node *routedNode) reportRelay(failed bool) {
+	atomic.AddUint64(&node.relays, 1)
+	if failed {
+		atomic.AddUint64(&node.errors, 1)
+	}
+}
+
+// NodeRouter is the chain proxy of an endpoint, it spreads the relays between the nodes of the endpoint by their weights,
+// keeps unhealthy nodes out of rotation, never relays to nodes that failed the verification of the chain
+// and fails over to the next node when a node fails a relay
+type NodeRouter struct {
+	lock           sync.RWMutex
+	nodes          []*routedNode // set on creation and never changed
+	chainTracker   latestBlockGetter
+	chainParser    ChainParser
+	referenceBlock int64 // the latest block of the chain as far as we know
+}
+
+func NewNodeRouter(ctx context.Context, nConns uint, rpcProviderEndpoint *lavasession.RPCProviderEndpoint, averageBlockTime time.Duration) (*NodeRouter, error) {
+	nodeConfigs := rpcProviderEndpoint.NodeConfigs()
+	if len(nodeConfigs) == 0 {
+		return nil, utils.LavaFormatError("rpcProviderEndpoint.NodeUrl list is empty missing node url", nil, &map[string]string{"chainID": rpcProviderEndpoint.ChainID, "ApiInterface": rpcProviderEndpoint.ApiInterface})
+	}
+	nodes := make([]*routedNode, 0, len(nodeConfigs))
+	for _, nodeConfig := range nodeConfigs {
+		nodeEndpoint := *rpcProviderEndpoint
+		nodeEndpoint.NodeUrl = nodeConfig.Urls
+		nodeEndpoint.Nodes = nil
+		chainProxy, err := GetChainProxy(ctx, nConns, &nodeEndpoint, averageBlockTime)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, newRoutedNode(nodeConfig, chainProxy, NewChainFetcher(ctx, chainProxy)))
+	}
+	return newNodeRouter(nodes), nil
+}
+
+func newNodeRouter(nodes []*routedNode) *NodeRouter {
+	return &NodeRouter{nodes: nodes}
+}
+
+// VerifyNodes runs the verifications of the spec against every node, a node that serves another chain or can't be verified
+// is never relayed to. a node that couldn't be queried is verified again by the health monitor. it returns NodeVerificationMismatchError
+// when every node serves another chain, and NoVerifiedNodesError when no node passed the verification yet
+func (nr *NodeRouter) VerifyNodes(ctx context.Context, chainParser ChainParser) error {
+	nr.verifyNodes(ctx, chainParser, nr.nodes, false)
+	nr.lock.RLock()
+	defer nr.lock.RUnlock()
+	pending := false
+	for _, node := range nr.nodes {
+		if node.verified {
+			return nil
+		}
+		pending = pending || node.verificationPending
+	}
+	if !pending {
+		return NodeVerificationMismatchError.Wrapf("no node of the endpoint serves its chain")
+	}
+	return NoVerifiedNodesError
+}
+
+// verifyNodes updates the verification state of the nodes, when keepOnError is set a node we failed to query keeps its state
+func (nr *NodeRouter) verifyNodes(ctx context.Context, chainParser ChainParser, nodes []*routedNode, keepOnError bool) {
+	for _, node := range nodes {
+		verifyCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+		err := verifyNode(verifyCtx, chainParser, node.chainProxy)
+		cancel()
+		nr.lock.Lock()
+		switch {
+		case err == nil:
+			if !node.verified {
+				utils.LavaFormatInfo("node passed the verification of the chain, returning it to rotation", &map[string]string{"node": node.url})
+			}
+			node.verified = true
+			node.verificationPending = false
+		case NodeVerificationMismatchError.Is(err):
+			utils.LavaFormatError("node failed the verification of the chain, it will not be relayed to", err, &map[string]string{"node": node.url})
+			node.verified = false
+			node.verificationPending = false
+		case !keepOnError:
+			utils.LavaFormatWarning("failed verifying node, it will not be relayed to until it is verified", err, &map[string]string{"node": node.url})
+			node.verified = false
+			node.verificationPending = true
+		default:
+			utils.LavaFormatWarning("failed verifying node, keeping its verification state", err, &map[string]string{"node": node.url, "verified": strconv.FormatBool(node.verified)})
+		}
+		nr.lock.Unlock()
+	}
+}
+
+// StartHealthMonitor checks the nodes every block, a node that lags behind the chain tracker by more than the spec allows
+// or fails too many of its relays is taken out of rotation until the next check finds it healthy. the verification of the chain
+// is repeated every NodeVerificationInterval
+func (nr *NodeRouter) StartHealthMonitor(ctx context.Context, chainTracker latestBlockGetter, chainParser ChainParser) {
+	nr.lock.Lock()
+	nr.chainTracker = chainTracker
+	nr.chainParser = chainParser
+	nr.lock.Unlock()
+	_, interval, _, _ := chainParser.ChainBlockStats()
+	if interval < MinNodeHealthCheckInterval {
+		interval = MinNodeHealthCheckInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		verificationTicker := time.NewTicker(NodeVerificationInterval)
+		defer verificationTicker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if pendingNodes := nr.nodesPendingVerification(); len(pendingNodes) > 0 {
+					nr.verifyNodes(ctx, chainParser, pendingNodes, true)
+				}
+				nr.checkNodesHealth(ctx)
+			case <-verificationTicker.C:
+				nr.verifyNodes(ctx, chainParser, nr.nodes, true)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (nr *NodeRouter) nodesPendingVerification() []*routedNode {
+	nr.lock.RLock()
+	defer nr.lock.RUnlock()
+	pendingNodes := []*routedNode{}
+	for _, node := range nr.nodes {
+		if node.verificationPending {
+			pendingNodes = append(pendingNodes, node)
+		}
+	}
+	return pendingNodes
+}
+
+func (nr *NodeRouter) checkNodesHealth(ctx context.Context) {
+	// the nodes are queried without the lock so a stuck node doesn't hold the relays
+	fetchedBlocks := make([]int64, len(nr.nodes))
+	for idx, node := range nr.nodes {
+		fetchCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+		latestBlock, err := node.fetcher.FetchLatestBlockNum(fetchCtx)
+		cancel()
+		if err != nil {
+			utils.LavaFormatDebug("failed fetching the latest block of a node, its lag is checked against its last known block", &map[string]string{"node": node.url, "error": err.Error()})
+			continue
+		}
+		fetchedBlocks[idx] = latestBlock
+	}
+
+	nr.lock.Lock()
+	defer nr.lock.Unlock()
+	referenceBlock := nr.referenceBlock
+	if nr.chainTracker != nil && nr.chainTracker.GetLatestBlockNum() > referenceBlock {
+		referenceBlock = nr.chainTracker.GetLatestBlockNum()
+	}
+	for idx, node := range nr.nodes {
+		if fetchedBlocks[idx] > 0 {
+			node.latestBlock = fetchedBlocks[idx]
+		}
+		if node.latestBlock > referenceBlock {
+			referenceBlock = node.latestBlock
+		}
+	}
+	nr.referenceBlock = referenceBlock
+	allowedBlockLag := int64(0)
+	if nr.chainParser != nil {
+		allowedBlockLag, _, _, _ = nr.chainParser.ChainBlockStats()
+	}
+
+	for _, node := range nr.nodes {
+		relays := atomic.SwapUint64(&node.relays, 0)
+		errors := atomic.SwapUint64(&node.errors, 0)
+		healthy := true
+		reason := ""
+		if node.latestBlock > 0 && referenceBlock-node.latestBlock > allowedBlockLag {
+			healthy = false
+			reason = "node is lagging behind the chain"
+		} else if relays >= MinRelaysForNodeErrorRate && errors*100 > relays*MaxNodeErrorRatePercent {
+			healthy = false
+			reason = "node failed too many relays"
+		}
+		if healthy != node.healthy {
+			details := &map[string]string{
+				"node":           node.url,
+				"latestBlock":    strconv.FormatInt(node.latestBlock, 10),
+				"referenceBlock": strconv.FormatInt(referenceBlock, 10),
+				"relays":         strconv.FormatUint(relays, 10),
+				"errors":         strconv.FormatUint(errors, 10),
+			}
+			if healthy {
+				utils.LavaFormatInfo("node is healthy again, returning it to rotation", details)
+			} else {
+				utils.LavaFormatWarning("taking node out of rotation: "+reason, nil, details)
+			}
+		}
+		node.healthy = healthy
+	}
+}
+
+// nodesForRequest returns the nodes that hold the requested block in the order they should be tried,
+// healthy nodes are ordered randomly by their weights and unhealthy ones are kept as a last resort
+func (nr *NodeRouter) nodesForRequest(requestedBlock int64) ([]*routedNode, error) {
+	nr.lock.RLock()
+	defer nr.lock.RUnlock()
+	healthyNodes := []*routedNode{}
+	unhealthyNodes := []*routedNode{}
+	verifiedNodes := 0
+	for _, node := range nr.nodes {
+		if !node.verified {
+			continue
+		}
+		verifiedNodes++
+		if !node.holdsBlock(requestedBlock, nr.referenceBlock) {
+			continue
+		}
+		if node.healthy {
+			healthyNodes = append(healthyNodes, node)
+		} else {
+			unhealthyNodes = append(unhealthyNodes, node)
+		}
+	}
+	if verifiedNodes == 0 {
+		return nil, NoVerifiedNodesError
+	}
+	if len(healthyNodes) == 0 && len(unhealthyNodes) == 0 {
+		return nil, utils.LavaFormatError("no node holds the requested block", nil, &map[string]string{"requestedBlock": strconv.FormatInt(requestedBlock, 10), "latestBlock": strconv.FormatInt(nr.referenceBlock, 10)})
+	}
+	return append(weightedShuffle(healthyNodes), weightedShuffle(unhealthyNodes)...), nil
+}
+
+func weightedShuffle(nodes []*routedNode) []*routedNode {
+	remaining := append([]*routedNode{}, nodes...)
+	shuffled := make([]*routedNode, 0, len(nodes))
+	for len(remaining) > 0 {
+		totalWeight := uint64(0)
+		for _, node := range remaining {
+			totalWeight += node.weight
+		}
+		pick := uint64(rand.Int63n(int64(totalWeight)))
+		for idx, node := range remaining {
+			if pick < node.weight {
+				shuffled = append(shuffled, node)
+				remaining = append(remaining[:idx], remaining[idx+1:]...)
+				break
+			}
+			pick -= node.weight
+		}
+	}
+	return shuffled
+}
+
+func (nr *NodeRouter) SendNodeMsg(ctx context.Context, ch chan interface{}, chainMessage ChainMessage) (relayReply *pairingtypes.RelayReply, subscriptionID string, relayReplyServer *rpcclient.ClientSubscription, err error) {
+	nodes, err := nr.nodesForRequest(chainMessage.RequestedBlock())
+	if err != nil {
+		return nil, "", nil, err
+	}
+	for _, node := range nodes {
+		relayReply, subscriptionID, relayReplyServer, err = node.chainProxy.SendNodeMsg(ctx, ch, chainMessage)
+		if ctx.Err() != nil {
+			// the relay was cancelled, it says nothing about the node
+			return relayReply, subscriptionID, relayReplyServer, err
+		}
+		node.reportRelay(err != nil)
+		if err == nil {
+			return relayReply, subscriptionID, relayReplyServer, nil
+		}
+		utils.LavaFormatWarning("node failed sending relay, trying the next node", err, &map[string]string{"node": node.url})
+	}
+	return relayReply, subscriptionID, relayReplyServer, err
+}
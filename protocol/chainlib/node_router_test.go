@@ -0,0 +1,268 @@
+package chainlib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/lavanet/lava/protocol/chainlib/chainproxy/rpcclient"
+	"github.com/lavanet/lava/protocol/lavasession"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNodeChainProxy struct {
+	lock  sync.Mutex
+	name  string
+	reply string // replied instead of the name when set
+	fail  bool
+	calls int
+}
+
+func (mcp *mockNodeChainProxy) SendNodeMsg(ctx context.Context, ch chan interface{}, chainMessage ChainMessage) (relayReply *pairingtypes.RelayReply, subscriptionID string, relayReplyServer *rpcclient.ClientSubscription, err error) {
+	mcp.lock.Lock()
+	defer mcp.lock.Unlock()
+	mcp.calls++
+	if mcp.fail {
+		return nil, "", nil, fmt.Errorf("node %s is down", mcp.name)
+	}
+	if mcp.reply != "" {
+		return &pairingtypes.RelayReply{Data: []byte(mcp.reply)}, "", nil, nil
+	}
+	return &pairingtypes.RelayReply{Data: []byte(mcp.name)}, "", nil, nil
+}
+
+func (mcp *mockNodeChainProxy) setReply(reply string, fail bool) {
+	mcp.lock.Lock()
+	defer mcp.lock.Unlock()
+	mcp.reply = reply
+	mcp.fail = fail
+}
+
+func (mcp *mockNodeChainProxy) getCalls() int {
+	mcp.lock.Lock()
+	defer mcp.lock.Unlock()
+	return mcp.calls
+}
+
+type mockBlockFetcher struct {
+	latestBlock int64
+}
+
+func (mbf *mockBlockFetcher) FetchLatestBlockNum(ctx context.Context) (int64, error) {
+	if mbf.latestBlock == 0 {
+		return 0, fmt.Errorf("not implemented")
+	}
+	return mbf.latestBlock, nil
+}
+
+type mockLatestBlockGetter struct {
+	latestBlock int64
+}
+
+func (mbg *mockLatestBlockGetter) GetLatestBlockNum() int64 {
+	return mbg.latestBlock
+}
+
+func createTestNode(name string, weight uint64, historyBlocks uint64, latestBlock int64) (*routedNode, *mockNodeChainProxy, *mockBlockFetcher) {
+	chainProxy := &mockNodeChainProxy{name: name}
+	fetcher := &mockBlockFetcher{latestBlock: latestBlock}
+	return newRoutedNode(lavasession.NodeConfig{Urls: []string{name}, Weight: weight, HistoryBlocks: historyBlocks}, chainProxy, fetcher), chainProxy, fetcher
+}
+
+func sendTestRelay(t *testing.T, nodeRouter *NodeRouter, requestedBlock int64) string {
+	reply, _, _, err := nodeRouter.SendNodeMsg(context.Background(), nil, parsedMessage{requestedBlock: requestedBlock})
+	require.Nil(t, err)
+	return string(reply.Data)
+}
+
+func TestNodeConfigs(t *testing.T) {
+	endpoint := &lavasession.RPCProviderEndpoint{
+		NodeUrl: []string{"ws://127.0.0.1:26657/websocket", "http://127.0.0.1:26657"},
+		Nodes:   []lavasession.NodeConfig{{Urls: []string{"ws://127.0.0.2:26657/websocket", "http://127.0.0.2:26657"}, Weight: 2, HistoryBlocks: 100}},
+	}
+	nodeConfigs := endpoint.NodeConfigs()
+	require.Len(t, nodeConfigs, 2)
+	require.Equal(t, endpoint.NodeUrl, nodeConfigs[0].Urls)
+	require.Equal(t, uint64(1), nodeConfigs[0].Weight)
+	require.Equal(t, uint64(0), nodeConfigs[0].HistoryBlocks)
+	require.Equal(t, endpoint.Nodes[0], nodeConfigs[1])
+}
+
+func TestNodeRouterWeights(t *testing.T) {
+	light, _, _ := createTestNode("light", 1, 0, 0)
+	heavy, _, _ := createTestNode("heavy", 3, 0, 0)
+	nodeRouter := newNodeRouter([]*routedNode{light, heavy})
+	replies := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		replies[sendTestRelay(t, nodeRouter, spectypes.LATEST_BLOCK)]++
+	}
+	require.InDelta(t, 3000, replies["heavy"], 200)
+	require.InDelta(t, 1000, replies["light"], 200)
+}
+
+func TestNodeRouterFailover(t *testing.T) {
+	failing, failingProxy, _ := createTestNode("failing", 1, 0, 0)
+	working, workingProxy, _ := createTestNode("working", 1, 0, 0)
+	failingProxy.fail = true
+	nodeRouter := newNodeRouter([]*routedNode{failing, working})
+	for i := 0; i < 6*MinRelaysForNodeErrorRate; i++ {
+		require.Equal(t, "working", sendTestRelay(t, nodeRouter, spectypes.LATEST_BLOCK))
+	}
+	require.Greater(t, failingProxy.getCalls(), 0)
+
+	// the failing node is taken out of rotation
+	nodeRouter.checkNodesHealth(context.Background())
+	require.False(t, failing.healthy)
+	failingCalls := failingProxy.getCalls()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, "working", sendTestRelay(t, nodeRouter, spectypes.LATEST_BLOCK))
+	}
+	require.Equal(t, failingCalls, failingProxy.getCalls())
+
+	// when every healthy node fails the unhealthy ones are still tried
+	workingProxy.fail = true
+	_, _, _, err := nodeRouter.SendNodeMsg(context.Background(), nil, parsedMessage{requestedBlock: spectypes.LATEST_BLOCK})
+	require.NotNil(t, err)
+	require.Equal(t, failingCalls+1, failingProxy.getCalls())
+
+	// a node with no relays in the last interval is given another chance
+	workingProxy.fail = false
+	failingProxy.fail = false
+	nodeRouter.checkNodesHealth(context.Background())
+	require.True(t, failing.healthy)
+}
+
+func TestNodeRouterLaggingNode(t *testing.T) {
+	synced, _, _ := createTestNode("synced", 1, 0, 1000)
+	lagging, _, laggingFetcher := createTestNode("lagging", 1, 0, 990)
+	unknown, _, _ := createTestNode("unknown", 1, 0, 0)
+	nodeRouter := newNodeRouter([]*routedNode{synced, lagging, unknown})
+	nodeRouter.chainTracker = &mockLatestBlockGetter{latestBlock: 1002}
+	chainParser, err := NewJrpcChainParser()
+	require.Nil(t, err)
+	chainParser.SetSpec(spectypes.Spec{Enabled: true, AllowedBlockLagForQosSync: 5})
+	nodeRouter.chainParser = chainParser
+	nodeRouter.checkNodesHealth(context.Background())
+	require.True(t, synced.healthy)
+	require.False(t, lagging.healthy)
+	// a node we can't read the latest block of is judged by its error rate only
+	require.True(t, unknown.healthy)
+	for i := 0; i < 100; i++ {
+		require.NotEqual(t, "lagging", sendTestRelay(t, nodeRouter, spectypes.LATEST_BLOCK))
+	}
+
+	laggingFetcher.latestBlock = 1001
+	nodeRouter.checkNodesHealth(context.Background())
+	require.True(t, lagging.healthy)
+}
+
+func TestNodeRouterHistoricalRequests(t *testing.T) {
+	archive, _, _ := createTestNode("archive", 1, 0, 1000)
+	pruned, _, _ := createTestNode("pruned", 1000, 100, 1000)
+	nodeRouter := newNodeRouter([]*routedNode{archive, pruned})
+	nodeRouter.checkNodesHealth(context.Background())
+
+	for i := 0; i < 100; i++ {
+		require.Equal(t, "archive", sendTestRelay(t, nodeRouter, 500))
+		require.Equal(t, "archive", sendTestRelay(t, nodeRouter, spectypes.EARLIEST_BLOCK))
+	}
+	replies := map[string]int{}
+	for i := 0; i < 100; i++ {
+		replies[sendTestRelay(t, nodeRouter, 950)]++
+	}
+	require.Greater(t, replies["pruned"], 0)
+
+	// without an archive node old blocks are refused
+	nodeRouter = newNodeRouter([]*routedNode{pruned})
+	nodeRouter.checkNodesHealth(context.Background())
+	_, _, _, err := nodeRouter.SendNodeMsg(context.Background(), nil, parsedMessage{requestedBlock: 500})
+	require.NotNil(t, err)
+}
+
+func createVerificationChainParser(t *testing.T, apiInterface string, apiName string, template string, parserArg []string, expectedValue string) ChainParser {
+	chainParser, err := NewChainParser(apiInterface)
+	require.Nil(t, err)
+	chainParser.SetSpec(spectypes.Spec{
+		Index:   "TEST1",
+		Enabled: true,
+		Apis: []spectypes.ServiceApi{{
+			Name:         apiName,
+			Enabled:      true,
+			ComputeUnits: 10,
+			BlockParsing: spectypes.BlockParser{ParserFunc: spectypes.PARSER_FUNC_DEFAULT, ParserArg: []string{"latest"}},
+			ApiInterfaces: []spectypes.ApiInterface{{
+				Interface: apiInterface,
+				Type:      "GET",
+				Verification: &spectypes.Verification{
+					FunctionTemplate: template,
+					ResultParsing:    spectypes.BlockParser{ParserFunc: spectypes.PARSER_FUNC_PARSE_CANONICAL, ParserArg: parserArg},
+					ExpectedValue:    expectedValue,
+				},
+			}},
+		}},
+	})
+	return chainParser
+}
+
+func TestNodeVerification(t *testing.T) {
+	ctx := context.Background()
+	chainParser := createVerificationChainParser(t, spectypes.APIInterfaceJsonRPC, "eth_chainId", `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`, []string{"0"}, "0x1")
+	mainnetReply := `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+	goerliReply := `{"jsonrpc":"2.0","id":1,"result":"0x5"}`
+	mainnet, mainnetProxy, _ := createTestNode("mainnet", 1, 0, 0)
+	goerli, goerliProxy, _ := createTestNode("goerli", 1, 0, 0)
+	down, downProxy, _ := createTestNode("down", 1, 0, 0)
+	mainnetProxy.setReply(mainnetReply, false)
+	goerliProxy.setReply(goerliReply, false)
+	downProxy.setReply(mainnetReply, true)
+	nodeRouter := newNodeRouter([]*routedNode{mainnet, goerli, down})
+
+	require.Nil(t, nodeRouter.VerifyNodes(ctx, chainParser))
+	require.True(t, mainnet.verified)
+	require.False(t, goerli.verified)
+	// a node that can't be verified on startup isn't relayed to until it is verified
+	require.False(t, down.verified)
+	require.Equal(t, []*routedNode{down}, nodeRouter.nodesPendingVerification())
+	goerliCalls := goerliProxy.getCalls()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, mainnetReply, sendTestRelay(t, nodeRouter, spectypes.LATEST_BLOCK))
+	}
+	require.Equal(t, goerliCalls, goerliProxy.getCalls())
+
+	// the periodic verification returns nodes that pass it and keeps the state of nodes it can't reach
+	downProxy.setReply(mainnetReply, false)
+	mainnetProxy.setReply(mainnetReply, true)
+	nodeRouter.verifyNodes(ctx, chainParser, nodeRouter.nodes, true)
+	require.True(t, down.verified)
+	require.Empty(t, nodeRouter.nodesPendingVerification())
+	require.True(t, mainnet.verified)
+	require.False(t, goerli.verified)
+
+	// a node that changed its chain is taken out
+	mainnetProxy.setReply(goerliReply, false)
+	downProxy.setReply(goerliReply, false)
+	require.True(t, NodeVerificationMismatchError.Is(nodeRouter.VerifyNodes(ctx, chainParser)))
+	_, _, _, err := nodeRouter.SendNodeMsg(ctx, nil, parsedMessage{requestedBlock: spectypes.LATEST_BLOCK})
+	require.ErrorIs(t, err, NoVerifiedNodesError)
+
+	// an endpoint whose nodes are unreachable on startup isn't refused, they are verified once they come up
+	downProxy.setReply(mainnetReply, true)
+	nodeRouter = newNodeRouter([]*routedNode{down})
+	require.ErrorIs(t, nodeRouter.VerifyNodes(ctx, chainParser), NoVerifiedNodesError)
+	require.True(t, down.verificationPending)
+	downProxy.setReply(mainnetReply, false)
+	nodeRouter.verifyNodes(ctx, chainParser, nodeRouter.nodesPendingVerification(), true)
+	require.True(t, down.verified)
+	require.Equal(t, mainnetReply, sendTestRelay(t, nodeRouter, spectypes.LATEST_BLOCK))
+}
+
+func TestNodeVerificationRest(t *testing.T) {
+	chainParser := createVerificationChainParser(t, spectypes.APIInterfaceRest, "/cosmos/base/tendermint/v1beta1/node_info", "/cosmos/base/tendermint/v1beta1/node_info", []string{"0", "default_node_info", "network"}, "cosmoshub-4")
+	nodeProxy := &mockNodeChainProxy{reply: `{"default_node_info":{"network":"cosmoshub-4","moniker":"node"}}`}
+	require.Nil(t, verifyNode(context.Background(), chainParser, nodeProxy))
+	nodeProxy.setReply(`{"default_node_info":{"network":"theta-testnet-001","moniker":"node"}}`, false)
+	require.True(t, NodeVerificationMismatchError.Is(verifyNode(context.Background(), chainParser, nodeProxy)))
+}
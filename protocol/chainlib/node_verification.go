@@ -0,0 +1,83 @@
+package chainlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lavanet/lava/relayer/parser"
+	"github.com/lavanet/lava/utils"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+)
+
+// verificationReply holds the result of a node reply so the result parsing of the verification can be applied to it
+type verificationReply struct {
+	result json.RawMessage
+}
+
+func (vr verificationReply) GetParams() interface{} {
+	return nil
+}
+
+func (vr verificationReply) GetResult() json.RawMessage {
+	return vr.result
+}
+
+func (vr verificationReply) ParseBlock(block string) (int64, error) {
+	return parser.ParseDefaultBlockParameter(block)
+}
+
+// rest replies are the result itself, the rest of the interfaces wrap it in a json rpc reply
+func verificationResult(apiInterface string, replyData []byte) (json.RawMessage, error) {
+	if apiInterface == spectypes.APIInterfaceRest {
+		return replyData, nil
+	}
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+	}
+	err := json.Unmarshal(replyData, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if len(reply.Result) == 0 {
+		return nil, fmt.Errorf("verification reply has no result: %s", string(replyData))
+	}
+	return reply.Result, nil
+}
+
+// verifyNode sends the verifications of the spec to the node, a node that serves another chain returns NodeVerificationMismatchError
+func verifyNode(ctx context.Context, chainParser ChainParser, chainProxy ChainProxy) error {
+	for _, verification := range chainParser.GetVerifications() {
+		chainMessage, err := chainParser.ParseMsg(verification.Url, verification.Data, verification.ConnectionType)
+		if err != nil {
+			return utils.LavaFormatError("failed parsing verification message", err, &map[string]string{"api": verification.ApiName})
+		}
+		reply, _, _, err := chainProxy.SendNodeMsg(ctx, nil, chainMessage)
+		if err != nil {
+			return err
+		}
+		result, err := verificationResult(verification.ApiInterface, reply.Data)
+		if err != nil {
+			return err
+		}
+		parsed, err := parser.ParseMessageResponse(verificationReply{result: result}, verification.ResultParsing)
+		if err != nil {
+			return err
+		}
+		if len(parsed) == 0 {
+			return fmt.Errorf("verification result parsing returned no value, api: %s", verification.ApiName)
+		}
+		value := fmt.Sprintf("%v", parsed[0])
+		if strings.HasPrefix(value, "\"") {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+		}
+		if value != verification.ExpectedValue {
+			return NodeVerificationMismatchError.Wrapf("api: %s, expected: %s, received: %s", verification.ApiName, verification.ExpectedValue, value)
+		}
+	}
+	return nil
+}
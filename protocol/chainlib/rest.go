@@ -123,6 +123,20 @@ func (apip *RestChainParser) SetSpec(spec spectypes.Spec) {
 	apip.taggedApis = taggedApis
 }
 
+// GetVerifications returns the calls the provider makes to verify its node serves the chain of the spec
+func (apip *RestChainParser) GetVerifications() []VerificationContainer {
+	// Guard that the RestChainParser instance exists
+	if apip == nil {
+		return nil
+	}
+
+	// Acquire read lock
+	apip.rwLock.RLock()
+	defer apip.rwLock.RUnlock()
+
+	return getVerifications(apip.spec, spectypes.APIInterfaceRest)
+}
+
 // DataReliabilityParams returns data reliability params from spec (spec.enabled and spec.dataReliabilityThreshold)
 func (apip *RestChainParser) DataReliabilityParams() (enabled bool, dataReliabilityThreshold uint32) {
 	// Guard that the RestChainParser instance exists
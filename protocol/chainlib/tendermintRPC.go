@@ -176,6 +176,20 @@ func (apip *TendermintChainParser) SetSpec(spec spectypes.Spec) {
 	apip.taggedApis = taggedApis
 }
 
+// GetVerifications returns the calls the provider makes to verify its node serves the chain of the spec
+func (apip *TendermintChainParser) GetVerifications() []VerificationContainer {
+	// Guard that the TendermintChainParser instance exists
+	if apip == nil {
+		return nil
+	}
+
+	// Acquire read lock
+	apip.rwLock.RLock()
+	defer apip.rwLock.RUnlock()
+
+	return getVerifications(apip.spec, spectypes.APIInterfaceTendermintRPC)
+}
+
 // DataReliabilityParams returns data reliability params from spec (spec.enabled and spec.dataReliabilityThreshold)
 func (apip *TendermintChainParser) DataReliabilityParams() (enabled bool, dataReliabilityThreshold uint32) {
 	// Guard that the TendermintChainParser instance exists
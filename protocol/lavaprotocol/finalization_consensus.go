@@ -6,11 +6,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gogo/status"
 	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/protocol/lavasession"
 	"github.com/lavanet/lava/utils"
 	conflicttypes "github.com/lavanet/lava/x/conflict/types"
 	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
 	"golang.org/x/exp/slices"
+	"google.golang.org/grpc/codes"
 )
 
 type FinalizationConsensus struct {
@@ -18,6 +22,14 @@ type FinalizationConsensus struct {
 	prevEpochProviderHashesConsensus []ProviderHashesConsensus
 	providerDataContainersMu         sync.RWMutex
 	currentEpoch                     uint64
+	providerLatestBlocks             map[string]providerLatestBlock // key is provider address, the latest block of every relay reply this epoch and the previous one
+	providerMissingBlocks            map[string]int64               // key is provider address, the highest block a relay failed for this epoch, the provider probably pruned up to it
+}
+
+type providerLatestBlock struct {
+	LatestBlock     int64
+	LatestBlockTime time.Time
+	Epoch           uint64
 }
 
 type ProviderHashesConsensus struct {
@@ -158,8 +170,101 @@ func (fc *FinalizationConsensus) NewEpoch(epoch uint64) {
 		// means it's time to refresh the epoch
 		fc.prevEpochProviderHashesConsensus = fc.currentProviderHashesConsensus
 		fc.currentProviderHashesConsensus = []ProviderHashesConsensus{}
+		previousEpoch := fc.currentEpoch
 		fc.currentEpoch = epoch
+		// failed blocks are retried on a new epoch, they might have failed for another reason
+		fc.providerMissingBlocks = map[string]int64{}
+		// providers that didn't reply during the previous epoch are probably no longer paired
+		for providerAddress, latest := range fc.providerLatestBlocks {
+			if latest.Epoch < previousEpoch {
+				delete(fc.providerLatestBlocks, providerAddress)
+			}
+		}
+	}
+}
+
+// UpdateProviderBlocks records the latest block of a relay reply, the provider served the requested block so it isn't missing it
+func (fc *FinalizationConsensus) UpdateProviderBlocks(providerAddress string, latestBlock int64, requestedBlock int64) {
+	fc.providerDataContainersMu.Lock()
+	defer fc.providerDataContainersMu.Unlock()
+	if fc.providerLatestBlocks == nil {
+		fc.providerLatestBlocks = map[string]providerLatestBlock{}
+	}
+	fc.providerLatestBlocks[providerAddress] = providerLatestBlock{LatestBlock: latestBlock, LatestBlockTime: time.Now(), Epoch: fc.currentEpoch}
+	if missingBlock, ok := fc.providerMissingBlocks[providerAddress]; ok && requestedBlock >= 0 && requestedBlock <= missingBlock {
+		fc.providerMissingBlocks[providerAddress] = requestedBlock - 1
+	}
+}
+
+// ProviderFailedBlock records a relay for a specific block that the provider's node failed, a node failing an old block probably pruned it
+// and the blocks before it. relays that timed out, lost their connection or that the provider refused say nothing about the node's data
+func (fc *FinalizationConsensus) ProviderFailedBlock(providerAddress string, requestedBlock int64, relayErr error) {
+	if requestedBlock < 0 {
+		return // latest and the other magic blocks aren't historical
+	}
+	if status.Code(relayErr) != codes.Code(lavasession.ProviderNodeError.ABCICode()) {
+		return
+	}
+	fc.providerDataContainersMu.Lock()
+	defer fc.providerDataContainersMu.Unlock()
+	if latest, ok := fc.providerLatestBlocks[providerAddress]; ok && requestedBlock > latest.LatestBlock {
+		return // the provider is behind the requested block, that is tracked by its latest block
+	}
+	if fc.providerMissingBlocks == nil {
+		fc.providerMissingBlocks = map[string]int64{}
+	}
+	if missingBlock, ok := fc.providerMissingBlocks[providerAddress]; !ok || missingBlock < requestedBlock {
+		fc.providerMissingBlocks[providerAddress] = requestedBlock
+	}
+}
+
+// ProvidersToAvoid returns the providers that probably can't serve the requested block: for a specific block the ones that didn't reach it
+// or failed it or an older block, and for the latest block the ones behind the expected block height. they should only be used when no other provider is left
+func (fc *FinalizationConsensus) ProvidersToAvoid(chainParser chainlib.ChainParser, requestedBlock int64) map[string]struct{} {
+	if requestedBlock < 0 && requestedBlock != spectypes.LATEST_BLOCK {
+		return nil
+	}
+	expectedBH, numOfProviders := fc.ExpectedBlockHeight(chainParser)
+	allowedBlockLagForQosSync, averageBlockTime, _, _ := chainParser.ChainBlockStats()
+	fc.providerDataContainersMu.RLock()
+	defer fc.providerDataContainersMu.RUnlock()
+	now := time.Now()
+	estimatedLatestBlocks := make(map[string]int64, len(fc.providerLatestBlocks))
+	var highestBlock int64 = 0
+	for providerAddress, latest := range fc.providerLatestBlocks {
+		estimated := latest.LatestBlock
+		if averageBlockTime > 0 {
+			estimated += int64(now.Sub(latest.LatestBlockTime) / averageBlockTime) // interpolation
+		}
+		estimatedLatestBlocks[providerAddress] = estimated
+		if highestBlock < estimated {
+			highestBlock = estimated
+		}
+	}
+	providersToAvoid := map[string]struct{}{}
+	if requestedBlock == spectypes.LATEST_BLOCK {
+		if numOfProviders == 0 {
+			// no finalization data, compare the providers with the most advanced one
+			expectedBH = highestBlock - allowedBlockLagForQosSync
+		}
+		for providerAddress, estimated := range estimatedLatestBlocks {
+			if estimated < expectedBH {
+				providersToAvoid[providerAddress] = struct{}{}
+			}
+		}
+		return providersToAvoid
+	}
+	for providerAddress, estimated := range estimatedLatestBlocks {
+		if estimated < requestedBlock {
+			providersToAvoid[providerAddress] = struct{}{}
+		}
+	}
+	for providerAddress, missingBlock := range fc.providerMissingBlocks {
+		if requestedBlock <= missingBlock {
+			providersToAvoid[providerAddress] = struct{}{}
+		}
 	}
+	return providersToAvoid
 }
 
 // returns the expected latest block, does the calculation on finalized entries then extrapolates the ending based on blockDistance
@@ -0,0 +1,74 @@
+package lavaprotocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/status"
+	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/protocol/lavasession"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+type mockChainParser struct {
+	chainlib.ChainParser
+	allowedBlockLagForQosSync int64
+	averageBlockTime          time.Duration
+}
+
+func (mcp *mockChainParser) ChainBlockStats() (allowedBlockLagForQosSync int64, averageBlockTime time.Duration, blockDistanceForFinalizedData uint32, blocksInFinalizationProof uint32) {
+	return mcp.allowedBlockLagForQosSync, mcp.averageBlockTime, 0, 0
+}
+
+func TestProvidersToAvoid(t *testing.T) {
+	chainParser := &mockChainParser{allowedBlockLagForQosSync: 2, averageBlockTime: time.Hour}
+	fc := &FinalizationConsensus{}
+	nodeError := status.Error(codes.Code(lavasession.ProviderNodeError.ABCICode()), "block pruned")
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, spectypes.LATEST_BLOCK))
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, 50))
+
+	fc.UpdateProviderBlocks("synced", 100, spectypes.LATEST_BLOCK)
+	fc.UpdateProviderBlocks("lagging", 95, spectypes.LATEST_BLOCK)
+	fc.UpdateProviderBlocks("pruned", 100, spectypes.LATEST_BLOCK)
+	// latest requests prefer the providers in sync with the most advanced one
+	require.Equal(t, map[string]struct{}{"lagging": {}}, fc.ProvidersToAvoid(chainParser, spectypes.LATEST_BLOCK))
+	// magic blocks other than latest aren't routed
+	require.Nil(t, fc.ProvidersToAvoid(chainParser, spectypes.EARLIEST_BLOCK))
+
+	// relays that failed on the way to the node say nothing about its data
+	fc.ProviderFailedBlock("pruned", 40, status.Error(codes.DeadlineExceeded, "timeout"))
+	fc.ProviderFailedBlock("pruned", 40, status.Error(codes.Unavailable, "connection lost"))
+	fc.ProviderFailedBlock("pruned", 40, status.Error(codes.Code(lavasession.ConsumerRateLimitedError.ABCICode()), "rate limited"))
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, 40))
+
+	// an old block the node failed marks the provider as missing it and the blocks before it
+	fc.ProviderFailedBlock("pruned", 40, nodeError)
+	require.Equal(t, map[string]struct{}{"pruned": {}}, fc.ProvidersToAvoid(chainParser, 40))
+	require.Equal(t, map[string]struct{}{"pruned": {}}, fc.ProvidersToAvoid(chainParser, 10))
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, 41))
+	// providers that didn't reach the requested block are avoided, their failures don't mark older blocks
+	require.Equal(t, map[string]struct{}{"lagging": {}}, fc.ProvidersToAvoid(chainParser, 98))
+	fc.ProviderFailedBlock("lagging", 98, nodeError)
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, 60))
+
+	// serving a block clears it and the blocks after it
+	fc.UpdateProviderBlocks("pruned", 100, 30)
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, 30))
+	require.Equal(t, map[string]struct{}{"pruned": {}}, fc.ProvidersToAvoid(chainParser, 29))
+
+	// failures are forgotten on a new epoch
+	fc.NewEpoch(10)
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, 10))
+
+	// providers that didn't reply during the previous epoch are forgotten
+	fc.UpdateProviderBlocks("synced", 110, spectypes.LATEST_BLOCK)
+	fc.UpdateProviderBlocks("lagging", 105, spectypes.LATEST_BLOCK)
+	fc.NewEpoch(20)
+	require.Len(t, fc.providerLatestBlocks, 2)
+	require.Equal(t, map[string]struct{}{"lagging": {}}, fc.ProvidersToAvoid(chainParser, spectypes.LATEST_BLOCK))
+	fc.NewEpoch(30)
+	require.Empty(t, fc.providerLatestBlocks)
+	require.Empty(t, fc.ProvidersToAvoid(chainParser, spectypes.LATEST_BLOCK))
+}
@@ -3,6 +3,7 @@ package lavaprotocol
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -209,3 +210,51 @@ func compareRelaysFindConflict(result1 *RelayResult, result2 *RelayResult) (conf
 	}
 	return
 }
+
+// FindMajorityResult returns the result most providers agree on, ties go to the earlier result.
+// every result disagreeing with it is returned as a conflict holding the signed requests and replies of both providers
+func FindMajorityResult(relayResults []*RelayResult) (majorityResult *RelayResult, conflicts []*conflicttypes.ResponseConflict) {
+	if len(relayResults) == 0 {
+		return nil, nil
+	}
+	canonicalReplies := make([]string, len(relayResults))
+	agreeingResults := map[string]int{}
+	for idx, relayResult := range relayResults {
+		canonicalReplies[idx] = string(CanonicalReplyData(relayResult.Reply.Data))
+		agreeingResults[canonicalReplies[idx]]++
+	}
+	majorityIdx := 0
+	for idx := range relayResults {
+		if agreeingResults[canonicalReplies[idx]] > agreeingResults[canonicalReplies[majorityIdx]] {
+			majorityIdx = idx
+		}
+	}
+	majorityResult = relayResults[majorityIdx]
+	for idx, relayResult := range relayResults {
+		if canonicalReplies[idx] == canonicalReplies[majorityIdx] {
+			continue
+		}
+		utils.LavaFormatWarning("Secure relay detected a provider disagreeing with the majority, Reporting...", nil, &map[string]string{"majorityProvider": majorityResult.ProviderAddress, "provider": relayResult.ProviderAddress, "majorityData": string(majorityResult.Reply.Data), "data": string(relayResult.Reply.Data), "agreeing": strconv.Itoa(agreeingResults[canonicalReplies[majorityIdx]]), "responses": strconv.Itoa(len(relayResults))})
+		conflicts = append(conflicts, &conflicttypes.ResponseConflict{
+			ConflictRelayData0: &conflicttypes.ConflictRelayData{Reply: majorityResult.Reply, Request: majorityResult.Request},
+			ConflictRelayData1: &conflicttypes.ConflictRelayData{Reply: relayResult.Reply, Request: relayResult.Request},
+		})
+	}
+	return majorityResult, conflicts
+}
+
+// CanonicalReplyData returns json reply data with sorted keys and no whitespace, so providers formatting the same reply differently agree.
+// numbers keep their original text, data that isn't json is returned as is
+func CanonicalReplyData(data []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil || decoder.More() {
+		return data
+	}
+	canonical, err := json.Marshal(parsed)
+	if err != nil {
+		return data
+	}
+	return canonical
+}
@@ -0,0 +1,52 @@
+package lavaprotocol
+
+import (
+	"testing"
+
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalReplyData(t *testing.T) {
+	canonical := CanonicalReplyData([]byte(`{"jsonrpc": "2.0", "result": {"b": 1, "a": 123456789012345678901234567890}, "id": 1}`))
+	require.Equal(t, `{"id":1,"jsonrpc":"2.0","result":{"a":123456789012345678901234567890,"b":1}}`, string(canonical))
+	require.Equal(t, canonical, CanonicalReplyData([]byte(`{"id":1,"result":{"a":123456789012345678901234567890,"b":1},"jsonrpc":"2.0"}`)))
+	// data that isn't json is compared as is
+	require.Equal(t, []byte{0x0a, 0x01}, CanonicalReplyData([]byte{0x0a, 0x01}))
+	require.Equal(t, `{"a":1} {"b":2}`, string(CanonicalReplyData([]byte(`{"a":1} {"b":2}`))))
+}
+
+func TestFindMajorityResult(t *testing.T) {
+	relayResult := func(provider string, data string) *RelayResult {
+		return &RelayResult{
+			ProviderAddress: provider,
+			Request:         &pairingtypes.RelayRequest{Provider: provider},
+			Reply:           &pairingtypes.RelayReply{Data: []byte(data)},
+		}
+	}
+	majorityResult, conflicts := FindMajorityResult([]*RelayResult{relayResult("provider0", `{"result":"0x1"}`)})
+	require.Equal(t, "provider0", majorityResult.ProviderAddress)
+	require.Empty(t, conflicts)
+
+	relayResults := []*RelayResult{
+		relayResult("provider0", `{"result":"0x2"}`),
+		relayResult("provider1", `{"result":"0x1", "id":1}`),
+		relayResult("provider2", `{"id":1,"result":"0x1"}`),
+	}
+	majorityResult, conflicts = FindMajorityResult(relayResults)
+	require.Equal(t, "provider1", majorityResult.ProviderAddress)
+	require.Len(t, conflicts, 1)
+	require.Equal(t, relayResults[1].Request, conflicts[0].ConflictRelayData0.Request)
+	require.Equal(t, relayResults[1].Reply, conflicts[0].ConflictRelayData0.Reply)
+	require.Equal(t, relayResults[0].Request, conflicts[0].ConflictRelayData1.Request)
+	require.Equal(t, relayResults[0].Reply, conflicts[0].ConflictRelayData1.Reply)
+
+	// on a tie the earlier result is returned and the rest are reported
+	majorityResult, conflicts = FindMajorityResult(relayResults[:2])
+	require.Equal(t, "provider0", majorityResult.ProviderAddress)
+	require.Len(t, conflicts, 1)
+
+	majorityResult, conflicts = FindMajorityResult(nil)
+	require.Nil(t, majorityResult)
+	require.Empty(t, conflicts)
+}
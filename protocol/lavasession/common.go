@@ -27,6 +27,11 @@ const (
 	LatencyThresholdStatic       = 1 * time.Second
 	LatencyThresholdSlope        = 1 * time.Millisecond
 	StaleEpochDistance           = 3 // relays done 3 epochs back are ready to be rewarded
+	RelayLatenciesToKeep         = 100
+	MinRelayLatenciesToEstimate  = 10  // a provider with fewer recent relays has no latency percentile yet
+	QoSExplorationFactor         = 0.1 // share of the sessions given to a uniformly picked provider, so providers with low scores keep being measured
+	QoSScoreSmoothingFactor      = 0.2 // weight of the latest relay in the rolling QoS score of a provider
+	InitialQoSScore              = 1.0 // providers start with the best score so new ones get relays
 
 )
 
@@ -36,6 +41,10 @@ func PrintRPCEndpoint(endpoint *RPCEndpoint) (retStr string) {
 }
 
 func PrintRPCProviderEndpoint(endpoint *RPCProviderEndpoint) (retStr string) {
-	retStr = endpoint.ChainID + ":" + endpoint.ApiInterface + " Network Address:" + endpoint.NetworkAddress + "Node: " + strings.Join(endpoint.NodeUrl, ", ") + " Geolocation:" + strconv.FormatUint(endpoint.Geolocation, 10)
+	nodes := []string{}
+	for _, node := range endpoint.NodeConfigs() {
+		nodes = append(nodes, strings.Join(node.Urls, ", "))
+	}
+	retStr = endpoint.ChainID + ":" + endpoint.ApiInterface + " Network Address:" + endpoint.NetworkAddress + "Node: " + strings.Join(nodes, " | ") + " Geolocation:" + strconv.FormatUint(endpoint.Geolocation, 10)
 	return
 }
@@ -3,6 +3,7 @@ package lavasession
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -33,6 +34,14 @@ type ConsumerSessionManager struct {
 	// pairingPurge - contains all pairings that are unwanted this epoch, keeps them in memory in order to avoid release.
 	// (if a consumer session still uses one of them or we want to report it.)
 	pairingPurge map[string]*ConsumerSessionsWithProvider
+
+	// haltedForUpgrade is set while the lava chain halts for an upgrade, relays made now can't be paid for
+	haltedForUpgrade uint32
+
+	// providerScores are the rolling QoS scores providers are picked by, key is the provider address.
+	// they have their own lock as they are updated while sessions are locked, and are kept for the providers that stay in the pairing
+	providerScoresLock sync.Mutex
+	providerScores     map[string]float64
 }
 
 func (csm *ConsumerSessionManager) RPCEndpoint() RPCEndpoint {
@@ -68,6 +77,7 @@ func (csm *ConsumerSessionManager) UpdateAllProviders(epoch uint64, pairingList
 		csm.pairing[provider.PublicLavaAddress] = provider
 	}
 	csm.setValidAddressesToDefaultValue() // the starting point is that valid addresses are equal to pairing addresses.
+	csm.removeUnpairedProviderScores()
 
 	return nil
 }
@@ -125,6 +135,9 @@ func (csm *ConsumerSessionManager) validatePairingListNotEmpty() uint64 {
 func (csm *ConsumerSessionManager) GetSession(ctx context.Context, cuNeededForSession uint64, initUnwantedProviders map[string]struct{}) (
 	consumerSession *SingleConsumerSession, epoch uint64, providerPublicAddress string, reportedProviders []byte, errRet error,
 ) {
+	if csm.IsHaltedForUpgrade() {
+		return nil, 0, "", nil, HaltedForUpgradeError
+	}
 	numberOfResets := csm.validatePairingListNotEmpty() // if pairing list is empty we reset the state.
 
 	if initUnwantedProviders == nil { // verify initUnwantedProviders is not nil
@@ -228,28 +241,78 @@ func (csm *ConsumerSessionManager) GetSession(ctx context.Context, cuNeededForSe
 	}
 }
 
-// Get a valid provider address.
+// Get a valid provider address, providers are picked by their QoS score, except for QoSExplorationFactor of the picks that are uniform.
 func (csm *ConsumerSessionManager) getValidProviderAddress(ignoredProvidersList map[string]struct{}) (address string, err error) {
 	// cs.Lock must be Rlocked here.
-	ignoredProvidersListLength := len(ignoredProvidersList)
-	validAddressesLength := len(csm.validAddresses)
-	totalValidLength := validAddressesLength - ignoredProvidersListLength
-	if totalValidLength <= 0 {
+	candidates := make([]string, 0, len(csm.validAddresses))
+	for _, validAddress := range csm.validAddresses {
+		if _, ok := ignoredProvidersList[validAddress]; !ok { // not ignored -> yes valid
+			candidates = append(candidates, validAddress)
+		}
+	}
+	if len(candidates) == 0 {
 		utils.LavaFormatDebug("Pairing list empty", &map[string]string{"Provider list": fmt.Sprintf("%v", csm.validAddresses), "IgnoredProviderList": fmt.Sprintf("%v", ignoredProvidersList)})
 		err = PairingListEmptyError
 		return
 	}
-	validAddressIndex := rand.Intn(totalValidLength) // get the N'th valid provider index, only valid providers will increase the addressIndex counter
-	validAddressesCounter := 0                       // this counter will try to reach the addressIndex
-	for index := 0; index < validAddressesLength; index++ {
-		if _, ok := ignoredProvidersList[csm.validAddresses[index]]; !ok { // not ignored -> yes valid
-			if validAddressesCounter == validAddressIndex {
-				return csm.validAddresses[index], nil
-			}
-			validAddressesCounter += 1
+	if rand.Float64() < QoSExplorationFactor {
+		return candidates[rand.Intn(len(candidates))], nil
+	}
+	scores := csm.getProviderScores(candidates)
+	scoresSum := 0.0
+	for _, score := range scores {
+		scoresSum += score
+	}
+	if scoresSum <= 0 {
+		return candidates[rand.Intn(len(candidates))], nil
+	}
+	pick := rand.Float64() * scoresSum
+	for idx, score := range scores {
+		if pick < score {
+			return candidates[idx], nil
+		}
+		pick -= score
+	}
+	return candidates[len(candidates)-1], nil // floating point leftovers
+}
+
+func (csm *ConsumerSessionManager) getProviderScores(providerAddresses []string) []float64 {
+	csm.providerScoresLock.Lock()
+	defer csm.providerScoresLock.Unlock()
+	scores := make([]float64, len(providerAddresses))
+	for idx, providerAddress := range providerAddresses {
+		score, ok := csm.providerScores[providerAddress]
+		if !ok {
+			score = InitialQoSScore
+		}
+		scores[idx] = score
+	}
+	return scores
+}
+
+// adds the QoS of a relay to the rolling score of its provider, failed relays score 0
+func (csm *ConsumerSessionManager) updateProviderScore(providerAddress string, relayScore float64) {
+	csm.providerScoresLock.Lock()
+	defer csm.providerScoresLock.Unlock()
+	if csm.providerScores == nil {
+		csm.providerScores = map[string]float64{}
+	}
+	score, ok := csm.providerScores[providerAddress]
+	if !ok {
+		score = InitialQoSScore
+	}
+	csm.providerScores[providerAddress] = (1-QoSScoreSmoothingFactor)*score + QoSScoreSmoothingFactor*relayScore
+}
+
+// the scores of providers that left the pairing are dropped, cs.Lock must be locked here.
+func (csm *ConsumerSessionManager) removeUnpairedProviderScores() {
+	csm.providerScoresLock.Lock()
+	defer csm.providerScoresLock.Unlock()
+	for providerAddress := range csm.providerScores {
+		if _, ok := csm.pairing[providerAddress]; !ok {
+			delete(csm.providerScores, providerAddress)
 		}
 	}
-	return "", UnreachableCodeError // should not reach here
 }
 
 func (csm *ConsumerSessionManager) getValidConsumerSessionsWithProvider(ignoredProviders *ignoredProviders, cuNeededForSession uint64) (consumerSessionWithProvider *ConsumerSessionsWithProvider, providerAddress string, currentEpoch uint64, err error) {
@@ -363,11 +426,18 @@ func (csm *ConsumerSessionManager) OnSessionFailure(consumerSession *SingleConsu
 		return sdkerrors.Wrapf(SessionIsAlreadyBlockListedError, "trying to report a session failure of a blocklisted consumer session")
 	}
 
-	consumerSession.QoSInfo.TotalRelays++
-	consumerSession.ConsecutiveNumberOfFailures += 1 // increase number of failures for this session
+	// a relay the consumer canceled after sending it isn't the provider's fault, but the provider may have served it and counted its cu
+	canceled := code == codes.Canceled || errors.Is(errorReceived, context.Canceled)
+	// a rate limited or denied relay never reached the provider node, the session is fine and the relay is retried on another provider
+	if !canceled && code != codes.Code(ConsumerRateLimitedError.ABCICode()) && code != codes.Code(ConsumerDeniedError.ABCICode()) {
+		consumerSession.QoSInfo.TotalRelays++
+		consumerSession.ConsecutiveNumberOfFailures += 1 // increase number of failures for this session
+		csm.updateProviderScore(consumerSession.Client.PublicLavaAddress, 0)
+	}
 
 	// if this session failed more than MaximumNumberOfFailuresAllowedPerConsumerSession times or session went out of sync we block it.
-	if consumerSession.ConsecutiveNumberOfFailures > MaximumNumberOfFailuresAllowedPerConsumerSession || code == codes.Code(SessionOutOfSyncError.ABCICode()) {
+	// a canceled session is blocked too, its cu sum may no longer match the provider's
+	if consumerSession.ConsecutiveNumberOfFailures > MaximumNumberOfFailuresAllowedPerConsumerSession || code == codes.Code(SessionOutOfSyncError.ABCICode()) || canceled {
 		utils.LavaFormatDebug("Blocking consumer session", &map[string]string{"id": strconv.FormatInt(consumerSession.SessionId, 10)})
 		consumerSession.BlockListed = true // block this session from future usages
 	}
@@ -388,7 +458,8 @@ func (csm *ConsumerSessionManager) OnSessionFailure(consumerSession *SingleConsu
 	if ReportAndBlockProviderError.Is(errorReceived) {
 		blockProvider = true
 		reportProvider = true
-	} else if BlockProviderError.Is(errorReceived) {
+	} else if BlockProviderError.Is(errorReceived) || code == codes.Code(ConsumerDeniedError.ABCICode()) {
+		// a provider that denies this consumer won't serve it, there is no reason to report it
 		blockProvider = true
 	}
 	if blockProvider {
@@ -459,8 +530,16 @@ func (csm *ConsumerSessionManager) OnSessionDone(
 	consumerSession.RelayNum += RelayNumberIncrement       // increase relayNum
 	consumerSession.ConsecutiveNumberOfFailures = 0        // reset failures.
 	consumerSession.LatestBlock = latestServicedBlock      // update latest serviced block
+	consumerSession.Client.relayLatencies.add(currentLatency)
 	// calculate QoS
 	consumerSession.CalculateQoS(specComputeUnits, currentLatency, expectedLatency, expectedBH-latestServicedBlock, numOfProviders, int64(providersCount))
+	qosScore, err := consumerSession.QoSInfo.LastQoSReport.ComputeQoS()
+	if err != nil {
+		// the relay succeeded, only the provider score isn't updated
+		utils.LavaFormatError("failed computing the session QoS", err, &map[string]string{"id": strconv.FormatInt(consumerSession.SessionId, 10)})
+		return nil
+	}
+	csm.updateProviderScore(consumerSession.Client.PublicLavaAddress, qosScore.MustFloat64())
 	return nil
 }
 
@@ -539,6 +618,9 @@ func (csm *ConsumerSessionManager) getEndpointFromConsumerSessionWithProviderFor
 
 // Get a Data Reliability Session
 func (csm *ConsumerSessionManager) GetDataReliabilitySession(ctx context.Context, originalProviderAddress string, index int64, sessionEpoch uint64) (singleConsumerSession *SingleConsumerSession, providerAddress string, epoch uint64, err error) {
+	if csm.IsHaltedForUpgrade() {
+		return nil, "", 0, HaltedForUpgradeError
+	}
 	consumerSessionWithProvider, providerAddress, currentEpoch, err := csm.getDataReliabilityProviderIndex(originalProviderAddress, uint64(index))
 	if err != nil {
 		return nil, "", 0, err
@@ -633,6 +715,21 @@ func (csm *ConsumerSessionManager) OnDataReliabilitySessionFailure(consumerSessi
 	return nil
 }
 
+// HaltForUpgrade stops giving new sessions, sessions in use are still reported when they are done
+func (csm *ConsumerSessionManager) HaltForUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	atomic.StoreUint32(&csm.haltedForUpgrade, 1)
+	utils.LavaFormatInfo("stopped new sessions until the lava chain upgrade", &map[string]string{"chainID": csm.rpcEndpoint.ChainID, "apiInterface": csm.rpcEndpoint.ApiInterface, "plan": planName, "haltHeight": strconv.FormatInt(haltHeight, 10)})
+}
+
+func (csm *ConsumerSessionManager) ResumeAfterUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	atomic.StoreUint32(&csm.haltedForUpgrade, 0)
+	utils.LavaFormatInfo("resumed new sessions after the lava chain upgrade", &map[string]string{"chainID": csm.rpcEndpoint.ChainID, "apiInterface": csm.rpcEndpoint.ApiInterface, "plan": planName})
+}
+
+func (csm *ConsumerSessionManager) IsHaltedForUpgrade() bool {
+	return atomic.LoadUint32(&csm.haltedForUpgrade) == 1
+}
+
 func NewConsumerSessionManager(rpcEndpoint *RPCEndpoint) *ConsumerSessionManager {
 	csm := ConsumerSessionManager{}
 	csm.rpcEndpoint = rpcEndpoint
@@ -10,9 +10,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gogo/status"
 	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 const (
@@ -441,6 +444,87 @@ func TestSessionFailureEpochMisMatch(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestSessionFailureRateLimited(t *testing.T) {
+	s := createGRPCServer(t) // create a grpcServer so we can connect to its endpoint and validate everything works.
+	defer s.Stop()           // stop the server when finished.
+	ctx := context.Background()
+	csm := CreateConsumerSessionManager()
+	pairingList := createPairingList()
+	err := csm.UpdateAllProviders(firstEpochHeight, pairingList) // update the providers.
+	require.Nil(t, err)
+	rateLimitedError := status.Error(codes.Code(ConsumerRateLimitedError.ABCICode()), "rate limited")
+	for failures := 0; failures <= MaximumNumberOfFailuresAllowedPerConsumerSession; failures++ {
+		cs, _, _, _, err := csm.GetSession(ctx, cuForFirstRequest, nil) // get a session
+		require.Nil(t, err)
+		err = csm.OnSessionFailure(cs, rateLimitedError)
+		require.Nil(t, err)
+		// the provider throttled the consumer, its session can be used again
+		require.False(t, cs.BlockListed)
+		require.Equal(t, uint64(0), cs.ConsecutiveNumberOfFailures)
+		require.Equal(t, cs.LatestRelayCu, latestRelayCuAfterDone)
+		require.Equal(t, cs.RelayNum, relayNumberAfterFirstFail)
+	}
+	require.Equal(t, len(csm.validAddresses), len(csm.pairingAddresses))
+}
+
+func TestSessionFailureConsumerDenied(t *testing.T) {
+	s := createGRPCServer(t) // create a grpcServer so we can connect to its endpoint and validate everything works.
+	defer s.Stop()           // stop the server when finished.
+	ctx := context.Background()
+	csm := CreateConsumerSessionManager()
+	pairingList := createPairingList()
+	err := csm.UpdateAllProviders(firstEpochHeight, pairingList) // update the providers.
+	require.Nil(t, err)
+	cs, _, _, _, err := csm.GetSession(ctx, cuForFirstRequest, nil) // get a session
+	require.Nil(t, err)
+	err = csm.OnSessionFailure(cs, status.Error(codes.Code(ConsumerDeniedError.ABCICode()), "denied"))
+	require.Nil(t, err)
+	require.False(t, cs.BlockListed)
+	require.Equal(t, uint64(0), cs.ConsecutiveNumberOfFailures)
+
+	// the provider won't serve the consumer, it's blocked but not reported
+	require.NotContains(t, csm.validAddresses, cs.Client.PublicLavaAddress)
+	require.NotContains(t, csm.addedToPurgeAndReport, cs.Client.PublicLavaAddress)
+}
+
+func TestSessionFailureCanceledAfterProviderServed(t *testing.T) {
+	s := createGRPCServer(t) // create a grpcServer so we can connect to its endpoint and validate everything works.
+	defer s.Stop()           // stop the server when finished.
+	ctx := context.Background()
+	csm := CreateConsumerSessionManager()
+	pairingList := createPairingList()
+	err := csm.UpdateAllProviders(firstEpochHeight, pairingList) // update the providers.
+	require.Nil(t, err)
+	psm, _ := createProviderSessionManager()
+
+	// the provider serves the relay of a hedge that lost, the consumer cancels it before the reply arrives
+	cs, _, _, _, err := csm.GetSession(ctx, cuForFirstRequest, nil) // get a session
+	require.Nil(t, err)
+	providerSession, err := psm.GetSession(ctx, testConsumer, providerFirstEpoch, uint64(cs.SessionId), cs.RelayNum+RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Nil(t, providerSession.PrepareSessionForUsage(cs.LatestRelayCu, cs.CuSum+cs.LatestRelayCu, cs.RelayNum+RelayNumberIncrement))
+	require.Nil(t, psm.OnSessionDone(providerSession, &pairingtypes.RelayRequest{}))
+	err = csm.OnSessionFailure(cs, context.Canceled)
+	require.Nil(t, err)
+
+	// reusing the session would send the provider a relay it already served
+	_, err = prepareProviderSession(ctx, psm, providerFirstEpoch, uint64(cs.SessionId), cs.RelayNum+RelayNumberIncrement)
+	require.True(t, SessionOutOfSyncError.Is(err))
+
+	// the provider isn't at fault, but the session is out of sync with it and isn't used again
+	require.True(t, cs.BlockListed)
+	require.Equal(t, uint64(0), cs.ConsecutiveNumberOfFailures)
+	require.Equal(t, cs.LatestRelayCu, latestRelayCuAfterDone)
+	require.Contains(t, csm.validAddresses, cs.Client.PublicLavaAddress)
+	for i := 0; i < numberOfProviders*numberOfAllowedSessionsPerConsumer; i++ {
+		next, _, _, _, err := csm.GetSession(ctx, cuForFirstRequest, nil)
+		require.Nil(t, err)
+		require.NotEqual(t, cs.SessionId, next.SessionId)
+		err = csm.OnSessionUnUsed(next)
+		require.Nil(t, err)
+	}
+}
+
 func TestAllProvidersEndpointsDisabled(t *testing.T) {
 	ctx := context.Background()
 	csm := CreateConsumerSessionManager()
@@ -497,3 +581,113 @@ func TestGetSession(t *testing.T) {
 	require.Equal(t, epoch, csm.currentEpoch)
 	require.Equal(t, cs.LatestRelayCu, uint64(cuForFirstRequest))
 }
+
+func TestHaltForUpgrade(t *testing.T) {
+	s := createGRPCServer(t) // create a grpcServer so we can connect to its endpoint and validate everything works.
+	defer s.Stop()           // stop the server when finished.
+	ctx := context.Background()
+	csm := NewConsumerSessionManager(&RPCEndpoint{NetworkAddress: grpcListener, ChainID: "LAV1", ApiInterface: "tendermintrpc", Geolocation: 1})
+	pairingList := createPairingList()
+	err := csm.UpdateAllProviders(firstEpochHeight, pairingList)
+	require.Nil(t, err)
+	csm.HaltForUpgrade(ctx, "v0.7.0", servicedBlockNumber)
+	_, _, _, _, err = csm.GetSession(ctx, cuForFirstRequest, nil)
+	require.True(t, HaltedForUpgradeError.Is(err))
+	_, _, _, err = csm.GetDataReliabilitySession(ctx, "provider0", 0, firstEpochHeight)
+	require.True(t, HaltedForUpgradeError.Is(err))
+	csm.ResumeAfterUpgrade(ctx, "v0.7.0", servicedBlockNumber)
+	cs, epoch, _, _, err := csm.GetSession(ctx, cuForFirstRequest, nil)
+	require.Nil(t, err)
+	require.NotNil(t, cs)
+	require.Equal(t, epoch, csm.currentEpoch)
+}
+
+func TestRelayLatencyPercentile(t *testing.T) {
+	s := createGRPCServer(t) // create a grpcServer so we can connect to its endpoint and validate everything works.
+	defer s.Stop()           // stop the server when finished.
+	ctx := context.Background()
+	csm := CreateConsumerSessionManager()
+	pairingList := createPairingList()
+	err := csm.UpdateAllProviders(firstEpochHeight, pairingList)
+	require.Nil(t, err)
+	cs, _, _, _, err := csm.GetSession(ctx, cuForFirstRequest, nil)
+	require.Nil(t, err)
+	cswp := cs.Client
+	err = csm.OnSessionDone(cs, firstEpochHeight, servicedBlockNumber, cuForFirstRequest, time.Millisecond, cs.CalculateExpectedLatency(2*time.Millisecond), (servicedBlockNumber - 1), numberOfProviders, numberOfProviders)
+	require.Nil(t, err)
+	// too few relays to estimate
+	_, ok := cswp.RelayLatencyPercentile(PercentileToCalculateLatency)
+	require.False(t, ok)
+
+	for latency := 2; latency <= MinRelayLatenciesToEstimate; latency++ {
+		cswp.relayLatencies.add(time.Duration(latency) * time.Millisecond)
+	}
+	latency, ok := cswp.RelayLatencyPercentile(PercentileToCalculateLatency)
+	require.True(t, ok)
+	require.Equal(t, 9*time.Millisecond, latency)
+
+	// only the latest relays are kept
+	for i := 0; i < RelayLatenciesToKeep; i++ {
+		cswp.relayLatencies.add(100 * time.Millisecond)
+	}
+	latency, ok = cswp.RelayLatencyPercentile(0)
+	require.True(t, ok)
+	require.Equal(t, 100*time.Millisecond, latency)
+}
+
+func TestQoSWeightedProviderSelection(t *testing.T) {
+	s := createGRPCServer(t) // create a grpcServer so we can connect to its endpoint and validate everything works.
+	defer s.Stop()           // stop the server when finished.
+	ctx := context.Background()
+	csm := CreateConsumerSessionManager()
+	pairingList := createPairingList()
+	err := csm.UpdateAllProviders(firstEpochHeight, pairingList)
+	require.Nil(t, err)
+
+	// every provider but provider0 keeps failing
+	for p := 1; p < numberOfProviders; p++ {
+		for i := 0; i < 20; i++ {
+			csm.updateProviderScore("provider"+strconv.Itoa(p), 0)
+		}
+	}
+	picks := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		providerAddress, err := csm.getValidProviderAddress(map[string]struct{}{})
+		require.Nil(t, err)
+		picks[providerAddress]++
+	}
+	require.Greater(t, picks["provider0"], 800)
+	// the exploration share still reaches the other providers
+	require.Greater(t, len(picks), 1)
+
+	// unwanted providers are never picked
+	for i := 0; i < 100; i++ {
+		providerAddress, err := csm.getValidProviderAddress(map[string]struct{}{"provider0": {}})
+		require.Nil(t, err)
+		require.NotEqual(t, "provider0", providerAddress)
+	}
+
+	// a successful relay raises the score
+	unwantedProviders := map[string]struct{}{}
+	for p := 0; p < numberOfProviders; p++ {
+		if p != 2 {
+			unwantedProviders["provider"+strconv.Itoa(p)] = struct{}{}
+		}
+	}
+	cs, _, providerAddress, _, err := csm.GetSession(ctx, cuForFirstRequest, unwantedProviders)
+	require.Nil(t, err)
+	require.Equal(t, "provider2", providerAddress)
+	scoreBefore := csm.getProviderScores([]string{providerAddress})[0]
+	err = csm.OnSessionDone(cs, firstEpochHeight, servicedBlockNumber, cuForFirstRequest, time.Millisecond, cs.CalculateExpectedLatency(2*time.Millisecond), (servicedBlockNumber - 1), numberOfProviders, numberOfProviders)
+	require.Nil(t, err)
+	require.Greater(t, csm.getProviderScores([]string{providerAddress})[0], scoreBefore)
+
+	// scores are kept for the providers that stay paired
+	newPairingList := createPairingList()[:numberOfProviders-1]
+	err = csm.UpdateAllProviders(firstEpochHeight+1, newPairingList)
+	require.Nil(t, err)
+	lastProvider := "provider" + strconv.Itoa(numberOfProviders-1)
+	require.Less(t, csm.getProviderScores([]string{"provider1"})[0], 0.1)
+	// the provider that left the pairing starts over if it returns
+	require.Equal(t, InitialQoSScore, csm.getProviderScores([]string{lastProvider})[0])
+}
@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"sort"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -64,6 +65,7 @@ type RPCEndpoint struct {
 	ChainID        string `yaml:"chain-id,omitempty" json:"chain-id,omitempty" mapstructure:"chain-id"`                      // spec chain identifier
 	ApiInterface   string `yaml:"api-interface,omitempty" json:"api-interface,omitempty" mapstructure:"api-interface"`
 	Geolocation    uint64 `yaml:"geolocation,omitempty" json:"geolocation,omitempty" mapstructure:"geolocation"`
+	HedgedRelays   uint64 `yaml:"hedged-relays,omitempty" json:"hedged-relays,omitempty" mapstructure:"hedged-relays"` // relays sent to more providers when a provider is slower than usual, 0 disables hedging
 }
 
 func (rpce *RPCEndpoint) New(address string, chainID string, apiInterface string, geolocation uint64) *RPCEndpoint {
@@ -88,6 +90,42 @@ type ConsumerSessionsWithProvider struct {
 	UsedComputeUnits  uint64
 	ReliabilitySent   bool
 	PairingEpoch      uint64
+	relayLatencies    relayLatencies
+}
+
+// relayLatencies keeps the latencies of the latest relays to a provider, it has its own lock so it can be updated while a session is locked
+type relayLatencies struct {
+	lock      sync.Mutex
+	latencies []time.Duration // a ring of the latest RelayLatenciesToKeep latencies
+	next      int
+}
+
+func (rl *relayLatencies) add(latency time.Duration) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	if len(rl.latencies) < RelayLatenciesToKeep {
+		rl.latencies = append(rl.latencies, latency)
+		return
+	}
+	rl.latencies[rl.next] = latency
+	rl.next = (rl.next + 1) % RelayLatenciesToKeep
+}
+
+func (rl *relayLatencies) percentile(percentile float64) (latency time.Duration, ok bool) {
+	rl.lock.Lock()
+	sorted := make([]time.Duration, len(rl.latencies))
+	copy(sorted, rl.latencies)
+	rl.lock.Unlock()
+	if len(sorted) < MinRelayLatenciesToEstimate {
+		return 0, false
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(float64(len(sorted)-1)*percentile)], true
+}
+
+// RelayLatencyPercentile returns the latency the given percentile of the latest relays to the provider finished within, ok is false until there are enough relays
+func (cswp *ConsumerSessionsWithProvider) RelayLatencyPercentile(percentile float64) (latency time.Duration, ok bool) {
+	return cswp.relayLatencies.percentile(percentile)
 }
 
 // verify data reliability session exists or not
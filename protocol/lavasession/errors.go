@@ -25,11 +25,18 @@ var ( // Consumer Side Errors
 	DataReliabilityAlreadySentThisEpochError             = sdkerrors.New("DataReliabilityAlreadySentThisEpoch Error", 682, "Trying to send data reliability more than once per provider per epoch")
 	FailedToConnectToEndPointForDataReliabilityError     = sdkerrors.New("FailedToConnectToEndPointForDataReliability Error", 683, "Failed to connect to a providers endpoints")
 	DataReliabilityEpochMismatchError                    = sdkerrors.New("DataReliabilityEpochMismatch Error", 684, "Data reliability epoch mismatch original session epoch.")
+	HaltedForUpgradeError                                = sdkerrors.New("HaltedForUpgrade Error", 685, "No new sessions while the lava chain halts for an upgrade.")
 )
 
 var ( // Provider Side Errors
-	InvalidEpochError           = sdkerrors.New("InvalidEpoch Error", 881, "Requested Epoch Is Too Old")
-	NewSessionWithRelayNumError = sdkerrors.New("NewSessionWithRelayNum Error", 882, "Requested Session With Relay Number Is Invalid")
-	ConsumerIsBlockListed       = sdkerrors.New("ConsumerIsBlockListed Error", 883, "This Consumer Is Blocked.")
-	ConsumerNotActive           = sdkerrors.New("ConsumerNotActive Error", 884, "This Consumer Is Not Active.")
+	InvalidEpochError               = sdkerrors.New("InvalidEpoch Error", 881, "Requested Epoch Is Too Old")
+	NewSessionWithRelayNumError     = sdkerrors.New("NewSessionWithRelayNum Error", 882, "Requested Session With Relay Number Is Invalid")
+	ConsumerIsBlockListed           = sdkerrors.New("ConsumerIsBlockListed Error", 883, "This Consumer Is Blocked.")
+	ConsumerNotActive               = sdkerrors.New("ConsumerNotActive Error", 884, "This Consumer Is Not Active.")
+	ConsumerNotPairedError          = sdkerrors.New("ConsumerNotPaired Error", 885, "This Consumer Is Not Paired With The Provider.")
+	DataReliabilityAlreadyUsedError = sdkerrors.New("DataReliabilityAlreadyUsed Error", 886, "Data Reliability Session Was Already Used This Epoch.")
+	ConsumerRateLimitedError        = sdkerrors.New("ConsumerRateLimited Error", 888, "Consumer Exceeded The Provider Rate Limits, Retry On Another Provider.")
+	ConsumerDeniedError             = sdkerrors.New("ConsumerDenied Error", 889, "This Consumer Is Denied By The Provider Policy.")
+	ProviderHaltedForUpgradeError   = sdkerrors.New("ProviderHaltedForUpgrade Error", 890, "No New Sessions While The Lava Chain Halts For An Upgrade.")
+	ProviderNodeError               = sdkerrors.New("ProviderNode Error", 891, "The Provider's Node Failed The Relay.")
 )
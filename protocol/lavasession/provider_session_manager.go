@@ -1,20 +1,26 @@
 package lavasession
 
 import (
-	"fmt"
+	"context"
 	"strconv"
 	"sync"
 	"sync/atomic"
 
 	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 )
 
 type ProviderSessionManager struct {
 	sessionsWithAllConsumers map[uint64]map[string]*ProviderSessionsWithConsumer // first key is epochs, second key is a consumer address
 	lock                     sync.RWMutex
 	blockedEpoch             uint64 // requests from this epoch are blocked
+	currentEpoch             uint64
+	previousEpoch            uint64 // relays of the previous epoch are still served, the consumers finish them during the epoch overlap
 	rpcProviderEndpoint      *RPCProviderEndpoint
 	stateQuery               StateQuery
+	providerAddress          string
+	// haltedForUpgrade is set while the lava chain halts for an upgrade, relays served now can't be paid for
+	haltedForUpgrade uint32
 }
 
 // reads cs.BlockedEpoch atomically
@@ -35,13 +41,21 @@ func (psm *ProviderSessionManager) IsValidEpoch(epoch uint64) bool {
 func (psm *ProviderSessionManager) IsActiveConsumer(epoch uint64, address string) (active bool, err error) {
 	_, err = psm.getActiveConsumer(epoch, address)
 	if err != nil {
-		return false, nil
+		if ConsumerNotActive.Is(err) {
+			return false, nil
+		}
+		return false, err
 	}
 	return true, nil // no error
 }
 
-func (psm *ProviderSessionManager) GetSession(address string, id uint64, epoch uint64, relayNum uint64, sessionId uint64) (*SingleProviderSession, error) {
-	if psm.IsValidEpoch(epoch) { // fast checking to see if epoch is even relevant
+// GetSession returns the consumer session for a relay, registering the consumer for the epoch after verifying its pairing on its first relay.
+// the session is returned locked, OnSessionDone or OnSessionFailure release it
+func (psm *ProviderSessionManager) GetSession(ctx context.Context, address string, epoch uint64, sessionId uint64, relayNum uint64) (*SingleProviderSession, error) {
+	if psm.IsHaltedForUpgrade() {
+		return nil, ProviderHaltedForUpgradeError
+	}
+	if !psm.IsValidEpoch(epoch) { // fast checking to see if epoch is even relevant
 		utils.LavaFormatError("GetSession", InvalidEpochError, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10)})
 		return nil, InvalidEpochError
 	}
@@ -52,15 +66,12 @@ func (psm *ProviderSessionManager) GetSession(address string, id uint64, epoch u
 	}
 	var singleProviderSession *SingleProviderSession
 	if activeConsumer {
-		singleProviderSession, err = psm.getSessionFromAnActiveConsumer(epoch, address, sessionId) // after getting session verify relayNum etc..
-	} else if relayNum == 0 {
-		// if no session found, we need to create and validate few things: pairing,
-		// return here and call a different function.
-		// in this function
-
-		singleProviderSession, err = psm.getNewSession(epoch, address) // after getting session verify relayNum etc..
+		singleProviderSession, err = psm.getSessionFromAnActiveConsumer(epoch, address, sessionId, relayNum) // after getting session verify relayNum etc..
+	} else if relayNum == RelayNumberIncrement {
+		// first relay of the consumer in this epoch, verify its pairing and register it
+		singleProviderSession, err = psm.getNewSession(ctx, epoch, address, sessionId)
 	} else {
-		utils.LavaFormatError("GetSession", NewSessionWithRelayNumError, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10)})
+		utils.LavaFormatError("GetSession", NewSessionWithRelayNumError, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10), "relayNum": strconv.FormatUint(relayNum, 10)})
 		return nil, NewSessionWithRelayNumError
 	}
 
@@ -69,24 +80,30 @@ func (psm *ProviderSessionManager) GetSession(address string, id uint64, epoch u
 		return nil, err
 	}
 
-	// validate later relayNum etc..
-
+	singleProviderSession.Lock.Lock()
 	return singleProviderSession, nil
 }
 
-func (psm *ProviderSessionManager) createNewSingleProviderSession(providerSessionWithConsumer *ProviderSessionsWithConsumer, sessionId uint64) (singleProviderSession *SingleProviderSession, err error) {
+func (psm *ProviderSessionManager) createNewSingleProviderSession(providerSessionWithConsumer *ProviderSessionsWithConsumer, epoch uint64, sessionId uint64) (singleProviderSession *SingleProviderSession, err error) {
 	providerSessionWithConsumer.Lock.Lock()
 	defer providerSessionWithConsumer.Lock.Unlock()
-
-	// TODO: create a new single provider session
-
-	return nil, fmt.Errorf("not implemented")
+	if session, ok := providerSessionWithConsumer.Sessions[sessionId]; ok {
+		// created by a concurrent relay
+		return session, nil
+	}
+	singleProviderSession = &SingleProviderSession{
+		userSessionsParent: providerSessionWithConsumer,
+		UniqueIdentifier:   sessionId,
+		PairingEpoch:       epoch,
+	}
+	providerSessionWithConsumer.Sessions[sessionId] = singleProviderSession
+	return singleProviderSession, nil
 }
 
 func (psm *ProviderSessionManager) getActiveConsumer(epoch uint64, address string) (singleProviderSession *ProviderSessionsWithConsumer, err error) {
 	psm.lock.RLock()
 	defer psm.lock.RUnlock()
-	if psm.IsValidEpoch(epoch) { // checking again because we are now locked and epoch cant change now.
+	if !psm.IsValidEpoch(epoch) { // checking again because we are now locked and epoch cant change now.
 		utils.LavaFormatError("getActiveConsumer", InvalidEpochError, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10)})
 		return nil, InvalidEpochError
 	}
@@ -103,7 +120,7 @@ func (psm *ProviderSessionManager) getActiveConsumer(epoch uint64, address strin
 	return nil, ConsumerNotActive
 }
 
-func (psm *ProviderSessionManager) getSessionFromAnActiveConsumer(epoch uint64, address string, sessionId uint64) (singleProviderSession *SingleProviderSession, err error) {
+func (psm *ProviderSessionManager) getSessionFromAnActiveConsumer(epoch uint64, address string, sessionId uint64, relayNum uint64) (singleProviderSession *SingleProviderSession, err error) {
 	providerSessionWithConsumer, err := psm.getActiveConsumer(epoch, address)
 	if err != nil {
 		return nil, err
@@ -112,39 +129,185 @@ func (psm *ProviderSessionManager) getSessionFromAnActiveConsumer(epoch uint64,
 	if err == nil {
 		return session, nil
 	}
-	// if we don't have a session we need to create a new one.
-	return psm.createNewSingleProviderSession(providerSessionWithConsumer, sessionId)
+	// if we don't have a session we need to create a new one, it has to start from the first relay
+	if relayNum != RelayNumberIncrement {
+		utils.LavaFormatError("getSessionFromAnActiveConsumer", NewSessionWithRelayNumError, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10), "sessionID": strconv.FormatUint(sessionId, 10), "relayNum": strconv.FormatUint(relayNum, 10)})
+		return nil, NewSessionWithRelayNumError
+	}
+	return psm.createNewSingleProviderSession(providerSessionWithConsumer, epoch, sessionId)
 }
 
-func (psm *ProviderSessionManager) getNewSession(epoch uint64, address string) (singleProviderSession *SingleProviderSession, err error) {
-	return
+func (psm *ProviderSessionManager) getNewSession(ctx context.Context, epoch uint64, address string, sessionId uint64) (singleProviderSession *SingleProviderSession, err error) {
+	providerSessionWithConsumer, err := psm.registerNewConsumer(ctx, epoch, address)
+	if err != nil {
+		return nil, err
+	}
+	return psm.createNewSingleProviderSession(providerSessionWithConsumer, epoch, sessionId)
 }
 
-func (psm *ProviderSessionManager) ReportConsumer() (address string, epoch uint64, err error) {
-	return "", 0, nil
+// verifies the consumer is paired with this provider in the epoch and registers it with its max compute units
+func (psm *ProviderSessionManager) registerNewConsumer(ctx context.Context, epoch uint64, address string) (*ProviderSessionsWithConsumer, error) {
+	chainID := psm.rpcProviderEndpoint.ChainID
+	valid, _, err := psm.stateQuery.QueryVerifyPairing(ctx, chainID, address, psm.providerAddress, epoch)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed verifying consumer pairing", err, &map[string]string{"consumer": address, "chainID": chainID, "epoch": strconv.FormatUint(epoch, 10)})
+	}
+	if !valid {
+		return nil, utils.LavaFormatError("invalid pairing with consumer", ConsumerNotPairedError, &map[string]string{"consumer": address, "chainID": chainID, "epoch": strconv.FormatUint(epoch, 10)})
+	}
+	vrfPk, maxCu, err := psm.stateQuery.GetVrfPkAndMaxCuForUser(ctx, chainID, address, epoch)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed to get the max allowed compute units for the consumer", err, &map[string]string{"consumer": address, "chainID": chainID, "epoch": strconv.FormatUint(epoch, 10)})
+	}
+	epochData := &ProviderSessionsEpochData{MaxComputeUnits: maxCu}
+	if vrfPk != nil {
+		epochData.VrfPk = *vrfPk
+	}
+
+	psm.lock.Lock()
+	defer psm.lock.Unlock()
+	if !psm.IsValidEpoch(epoch) { // the epoch could have been blocked while we queried the pairing
+		return nil, InvalidEpochError
+	}
+	if psm.sessionsWithAllConsumers == nil {
+		psm.sessionsWithAllConsumers = map[uint64]map[string]*ProviderSessionsWithConsumer{}
+	}
+	mapOfProviderSessionsWithConsumer, ok := psm.sessionsWithAllConsumers[epoch]
+	if !ok {
+		mapOfProviderSessionsWithConsumer = map[string]*ProviderSessionsWithConsumer{}
+		psm.sessionsWithAllConsumers[epoch] = mapOfProviderSessionsWithConsumer
+	}
+	if providerSessionWithConsumer, ok := mapOfProviderSessionsWithConsumer[address]; ok {
+		// registered by a concurrent relay
+		if providerSessionWithConsumer.atomicReadBlockedEpoch() == blockListedConsumer {
+			return nil, ConsumerIsBlockListed
+		}
+		return providerSessionWithConsumer, nil
+	}
+	providerSessionWithConsumer := &ProviderSessionsWithConsumer{
+		Sessions:  map[uint64]*SingleProviderSession{},
+		consumer:  address,
+		epochData: epochData,
+	}
+	mapOfProviderSessionsWithConsumer[address] = providerSessionWithConsumer
+	return providerSessionWithConsumer, nil
 }
 
-func (psm *ProviderSessionManager) GetDataReliabilitySession(address string, epoch uint64) (err error) {
-	return nil
+// GetDataReliabilitySession returns the session for a data reliability relay, data reliability relays don't use compute units or relay numbers
+// so the session isn't kept with the consumer sessions. a consumer gets one data reliability session per epoch,
+// the session is returned locked, OnSessionDone or OnSessionFailure release it
+func (psm *ProviderSessionManager) GetDataReliabilitySession(ctx context.Context, address string, epoch uint64) (*SingleProviderSession, error) {
+	if psm.IsHaltedForUpgrade() {
+		return nil, ProviderHaltedForUpgradeError
+	}
+	if !psm.IsValidEpoch(epoch) {
+		utils.LavaFormatError("GetDataReliabilitySession", InvalidEpochError, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10)})
+		return nil, InvalidEpochError
+	}
+	providerSessionWithConsumer, err := psm.getActiveConsumer(epoch, address)
+	if ConsumerNotActive.Is(err) {
+		providerSessionWithConsumer, err = psm.registerNewConsumer(ctx, epoch, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	singleProviderSession, err := providerSessionWithConsumer.getDataReliabilitySession(epoch)
+	if err != nil {
+		utils.LavaFormatWarning("GetDataReliabilitySession", err, &map[string]string{"RequestedEpoch": strconv.FormatUint(epoch, 10), "ConsumerAddress": address})
+		return nil, err
+	}
+	return singleProviderSession, nil
 }
 
-func (psm *ProviderSessionManager) OnSessionFailure() (epoch uint64, err error) {
-	return 0, nil
+// OnSessionFailure reverts the compute units and relay number the failed relay used and releases the session
+func (psm *ProviderSessionManager) OnSessionFailure(singleProviderSession *SingleProviderSession) error {
+	defer singleProviderSession.Lock.Unlock()
+	if singleProviderSession.UniqueIdentifier == DataReliabilitySessionId {
+		return nil // nothing was charged, the consumer can try the data reliability relay again
+	}
+	latestRelayCu := singleProviderSession.LatestRelayCu
+	singleProviderSession.LatestRelayCu = 0
+	if singleProviderSession.RelayNum < RelayNumberIncrement || singleProviderSession.CuSum < latestRelayCu {
+		utils.LavaFormatError("consumer RelayNumber or CuSum are negative values", nil, &map[string]string{
+			"RelayNum": strconv.FormatUint(singleProviderSession.RelayNum, 10),
+			"CuSum":    strconv.FormatUint(singleProviderSession.CuSum, 10),
+		})
+		singleProviderSession.RelayNum = 0
+		singleProviderSession.CuSum = 0
+		return SessionOutOfSyncError
+	}
+	singleProviderSession.RelayNum -= RelayNumberIncrement
+	singleProviderSession.CuSum -= latestRelayCu
+	return singleProviderSession.userSessionsParent.subtractUsedComputeUnits(latestRelayCu)
 }
 
-func (psm *ProviderSessionManager) OnSessionDone(proof string) (epoch uint64, err error) {
-	return 0, nil
+// OnSessionDone saves the relay request as the session proof and releases the session
+func (psm *ProviderSessionManager) OnSessionDone(singleProviderSession *SingleProviderSession, proof *pairingtypes.RelayRequest) error {
+	defer singleProviderSession.Lock.Unlock()
+	singleProviderSession.LatestRelayCu = 0
+	singleProviderSession.Proof = proof
+	if singleProviderSession.UniqueIdentifier == DataReliabilitySessionId {
+		// the data reliability session is used up for this epoch
+		singleProviderSession.RelayNum = RelayNumberIncrement
+		providerSessionWithConsumer := singleProviderSession.userSessionsParent
+		providerSessionWithConsumer.Lock.Lock()
+		providerSessionWithConsumer.epochData.DataReliability = proof.DataReliability
+		providerSessionWithConsumer.Lock.Unlock()
+	}
+	return nil
 }
 
 func (psm *ProviderSessionManager) RPCProviderEndpoint() *RPCProviderEndpoint {
 	return psm.rpcProviderEndpoint
 }
 
+// UpdateEpoch is called when a new epoch starts, relays of epochs before the previous one are blocked
+// and the sessions of epochs older than the blocked epoch are removed
 func (psm *ProviderSessionManager) UpdateEpoch(epoch uint64) {
-	// update the epoch to limit consumer usage
+	psm.lock.Lock()
+	defer psm.lock.Unlock()
+	if epoch <= psm.currentEpoch {
+		return
+	}
+	psm.atomicWriteBlockedEpoch(psm.previousEpoch)
+	psm.previousEpoch = psm.currentEpoch
+	psm.currentEpoch = epoch
+	blockedEpoch := psm.atomicReadBlockedEpoch()
+	for sessionsEpoch := range psm.sessionsWithAllConsumers {
+		// relays of the blocked epoch can still be finishing, their sessions are removed on the next epoch
+		if sessionsEpoch < blockedEpoch {
+			delete(psm.sessionsWithAllConsumers, sessionsEpoch)
+		}
+	}
+}
+
+// HaltForUpgrade stops serving new relays, relays in progress finish and their proofs are sent to the reward server.
+// the previous epoch is blocked too, its proofs are claimed before the halt so relays on its sessions after the chain resumes couldn't be paid
+func (psm *ProviderSessionManager) HaltForUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	atomic.StoreUint32(&psm.haltedForUpgrade, 1)
+	psm.lock.Lock()
+	if psm.previousEpoch > psm.atomicReadBlockedEpoch() {
+		psm.atomicWriteBlockedEpoch(psm.previousEpoch)
+	}
+	psm.lock.Unlock()
+	utils.LavaFormatInfo("stopped serving relays until the lava chain upgrade", &map[string]string{"chainID": psm.rpcProviderEndpoint.ChainID, "apiInterface": psm.rpcProviderEndpoint.ApiInterface, "plan": planName, "haltHeight": strconv.FormatInt(haltHeight, 10)})
+}
+
+func (psm *ProviderSessionManager) ResumeAfterUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	atomic.StoreUint32(&psm.haltedForUpgrade, 0)
+	utils.LavaFormatInfo("resumed serving relays after the lava chain upgrade", &map[string]string{"chainID": psm.rpcProviderEndpoint.ChainID, "apiInterface": psm.rpcProviderEndpoint.ApiInterface, "plan": planName})
+}
+
+func (psm *ProviderSessionManager) IsHaltedForUpgrade() bool {
+	return atomic.LoadUint32(&psm.haltedForUpgrade) == 1
 }
 
 // Returning a new provider session manager
-func NewProviderSessionManager(rpcProviderEndpoint *RPCProviderEndpoint, stateQuery StateQuery) *ProviderSessionManager {
-	return &ProviderSessionManager{rpcProviderEndpoint: rpcProviderEndpoint, stateQuery: stateQuery}
+func NewProviderSessionManager(rpcProviderEndpoint *RPCProviderEndpoint, stateQuery StateQuery, providerAddress string) *ProviderSessionManager {
+	return &ProviderSessionManager{
+		sessionsWithAllConsumers: map[uint64]map[string]*ProviderSessionsWithConsumer{},
+		rpcProviderEndpoint:      rpcProviderEndpoint,
+		stateQuery:               stateQuery,
+		providerAddress:          providerAddress,
+	}
 }
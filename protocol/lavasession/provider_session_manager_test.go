@@ -1 +1,334 @@
 package lavasession
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testConsumer         = "consumer"
+	testProviderAddress  = "provider"
+	providerMaxCu        = uint64(1000)
+	providerFirstEpoch   = uint64(20)
+	providerSecondEpoch  = uint64(40)
+	providerThirdEpoch   = uint64(60)
+	providerFourthEpoch  = uint64(80)
+	cuForProviderRequest = uint64(10)
+)
+
+type mockStateQuery struct {
+	lock           sync.Mutex
+	unpaired       map[string]bool
+	pairingQueries int
+}
+
+func (msq *mockStateQuery) QueryVerifyPairing(ctx context.Context, chainID string, consumer string, provider string, blockHeight uint64) (valid bool, index int64, err error) {
+	msq.lock.Lock()
+	defer msq.lock.Unlock()
+	msq.pairingQueries++
+	return !msq.unpaired[consumer], 0, nil
+}
+
+func (msq *mockStateQuery) GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, blockHeight uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error) {
+	return nil, providerMaxCu, nil
+}
+
+func createProviderSessionManager() (*ProviderSessionManager, *mockStateQuery) {
+	rand.Seed(time.Now().UnixNano())
+	stateQuery := &mockStateQuery{unpaired: map[string]bool{}}
+	psm := NewProviderSessionManager(&RPCProviderEndpoint{ChainID: "LAV1", ApiInterface: "tendermintrpc"}, stateQuery, testProviderAddress)
+	psm.UpdateEpoch(providerFirstEpoch)
+	return psm, stateQuery
+}
+
+// gets a session for the next relay of the session and charges it
+func prepareProviderSession(ctx context.Context, psm *ProviderSessionManager, epoch uint64, sessionId uint64, relayNum uint64) (*SingleProviderSession, error) {
+	session, err := psm.GetSession(ctx, testConsumer, epoch, sessionId, relayNum)
+	if err != nil {
+		return nil, err
+	}
+	err = session.PrepareSessionForUsage(cuForProviderRequest, session.CuSum+cuForProviderRequest, relayNum)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func usedComputeUnits(psm *ProviderSessionManager, epoch uint64, consumer string) uint64 {
+	providerSessionWithConsumer, err := psm.getActiveConsumer(epoch, consumer)
+	if err != nil {
+		return 0
+	}
+	providerSessionWithConsumer.Lock.RLock()
+	defer providerSessionWithConsumer.Lock.RUnlock()
+	return providerSessionWithConsumer.epochData.UsedComputeUnits
+}
+
+func TestProviderSessionHappyFlow(t *testing.T) {
+	ctx := context.Background()
+	psm, stateQuery := createProviderSessionManager()
+	session, err := prepareProviderSession(ctx, psm, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Equal(t, cuForProviderRequest, session.LatestRelayCu)
+	proof := &pairingtypes.RelayRequest{SessionId: 1, CuSum: cuForProviderRequest, RelayNum: RelayNumberIncrement}
+	require.Nil(t, psm.OnSessionDone(session, proof))
+	require.Equal(t, uint64(0), session.LatestRelayCu)
+	require.Equal(t, cuForProviderRequest, session.CuSum)
+	require.Equal(t, uint64(RelayNumberIncrement), session.RelayNum)
+	require.Equal(t, proof, session.Proof)
+
+	// the next relay continues the session without verifying the pairing again
+	session, err = prepareProviderSession(ctx, psm, providerFirstEpoch, 1, 2)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, proof))
+	require.Equal(t, 2*cuForProviderRequest, session.CuSum)
+	require.Equal(t, 1, stateQuery.pairingQueries)
+	require.Equal(t, 2*cuForProviderRequest, usedComputeUnits(psm, providerFirstEpoch, testConsumer))
+}
+
+func TestProviderSessionFailureRevertsComputeUnits(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	session, err := prepareProviderSession(ctx, psm, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+
+	session, err = prepareProviderSession(ctx, psm, providerFirstEpoch, 1, 2)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionFailure(session))
+	require.Equal(t, cuForProviderRequest, session.CuSum)
+	require.Equal(t, uint64(RelayNumberIncrement), session.RelayNum)
+	require.Equal(t, cuForProviderRequest, usedComputeUnits(psm, providerFirstEpoch, testConsumer))
+
+	// the consumer retries the same relay number
+	session, err = prepareProviderSession(ctx, psm, providerFirstEpoch, 1, 2)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+}
+
+func TestProviderSessionRelayNumChecks(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	// a new consumer starts from the first relay
+	_, err := psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, 2)
+	require.True(t, NewSessionWithRelayNumError.Is(err))
+
+	session, err := prepareProviderSession(ctx, psm, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+
+	// so does a new session of an active consumer
+	_, err = psm.GetSession(ctx, testConsumer, providerFirstEpoch, 2, 3)
+	require.True(t, NewSessionWithRelayNumError.Is(err))
+
+	// relays can't overwrite past usage
+	session, err = psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	err = session.PrepareSessionForUsage(cuForProviderRequest, 2*cuForProviderRequest, RelayNumberIncrement)
+	require.True(t, SessionOutOfSyncError.Is(err))
+
+	// the cu sum has to add up
+	session, err = psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, 2)
+	require.Nil(t, err)
+	err = session.PrepareSessionForUsage(cuForProviderRequest, 3*cuForProviderRequest, 2)
+	require.True(t, SessionOutOfSyncError.Is(err))
+	require.Equal(t, cuForProviderRequest, usedComputeUnits(psm, providerFirstEpoch, testConsumer))
+}
+
+func TestProviderSessionUnpairedConsumer(t *testing.T) {
+	ctx := context.Background()
+	psm, stateQuery := createProviderSessionManager()
+	stateQuery.unpaired[testConsumer] = true
+	_, err := psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.NotNil(t, err)
+	active, err := psm.IsActiveConsumer(providerFirstEpoch, testConsumer)
+	require.Nil(t, err)
+	require.False(t, active)
+}
+
+func TestProviderSessionBlockConsumerExceedingMaxCu(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	session, err := psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	err = session.PrepareSessionForUsage(providerMaxCu+1, providerMaxCu+1, RelayNumberIncrement)
+	require.True(t, MaxComputeUnitsExceededError.Is(err))
+
+	_, err = psm.GetSession(ctx, testConsumer, providerFirstEpoch, 2, RelayNumberIncrement)
+	require.True(t, ConsumerIsBlockListed.Is(err))
+	_, err = psm.GetDataReliabilitySession(ctx, testConsumer, providerFirstEpoch)
+	require.True(t, ConsumerIsBlockListed.Is(err))
+
+	// the block is for the epoch only
+	psm.UpdateEpoch(providerSecondEpoch)
+	session, err = prepareProviderSession(ctx, psm, providerSecondEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+}
+
+func TestProviderDataReliabilitySessionOncePerEpoch(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	session, err := psm.GetDataReliabilitySession(ctx, testConsumer, providerFirstEpoch)
+	require.Nil(t, err)
+	// a failed data reliability relay can be sent again
+	require.Nil(t, psm.OnSessionFailure(session))
+	session, err = psm.GetDataReliabilitySession(ctx, testConsumer, providerFirstEpoch)
+	require.Nil(t, err)
+	dataReliability := &pairingtypes.VRFData{Differentiator: true}
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{DataReliability: dataReliability}))
+	_, err = psm.GetDataReliabilitySession(ctx, testConsumer, providerFirstEpoch)
+	require.True(t, DataReliabilityAlreadyUsedError.Is(err))
+	require.Equal(t, uint64(0), usedComputeUnits(psm, providerFirstEpoch, testConsumer))
+
+	// regular relays aren't affected and the next epoch has a new data reliability session
+	relaySession, err := prepareProviderSession(ctx, psm, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(relaySession, &pairingtypes.RelayRequest{}))
+	psm.UpdateEpoch(providerSecondEpoch)
+	session, err = psm.GetDataReliabilitySession(ctx, testConsumer, providerSecondEpoch)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{DataReliability: dataReliability}))
+}
+
+func TestProviderSessionUpdateEpoch(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	for _, epoch := range []uint64{providerFirstEpoch, providerSecondEpoch, providerThirdEpoch} {
+		psm.UpdateEpoch(epoch)
+		session, err := prepareProviderSession(ctx, psm, epoch, 1, RelayNumberIncrement)
+		require.Nil(t, err)
+		require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+	}
+	// the previous epoch is still served during the epoch overlap
+	require.False(t, psm.IsValidEpoch(providerFirstEpoch))
+	require.True(t, psm.IsValidEpoch(providerSecondEpoch))
+	_, err := psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, 2)
+	require.True(t, InvalidEpochError.Is(err))
+	// an old epoch update is ignored
+	psm.UpdateEpoch(providerSecondEpoch)
+	require.True(t, psm.IsValidEpoch(providerSecondEpoch))
+
+	psm.UpdateEpoch(providerFourthEpoch)
+	require.False(t, psm.IsValidEpoch(providerSecondEpoch))
+	// sessions of epochs older than the blocked epoch are removed
+	require.Len(t, psm.sessionsWithAllConsumers, 2)
+	require.Contains(t, psm.sessionsWithAllConsumers, providerSecondEpoch)
+	require.Contains(t, psm.sessionsWithAllConsumers, providerThirdEpoch)
+}
+
+func TestProviderSessionHaltForUpgrade(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	session, err := prepareProviderSession(ctx, psm, providerFirstEpoch, 1, RelayNumberIncrement)
+	require.Nil(t, err)
+	psm.HaltForUpgrade(ctx, "v0.7.0", 100)
+	// the relay in progress finishes, new relays aren't served
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+	_, err = psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, 2)
+	require.True(t, ProviderHaltedForUpgradeError.Is(err))
+	_, err = psm.GetDataReliabilitySession(ctx, testConsumer, providerFirstEpoch)
+	require.True(t, ProviderHaltedForUpgradeError.Is(err))
+	psm.ResumeAfterUpgrade(ctx, "v0.7.0", 100)
+	session, err = prepareProviderSession(ctx, psm, providerFirstEpoch, 1, 2)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+
+	// the proofs of the previous epoch are claimed on the halt, its sessions aren't served after the chain resumes
+	psm.UpdateEpoch(providerSecondEpoch)
+	session, err = prepareProviderSession(ctx, psm, providerFirstEpoch, 1, 3)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+	psm.HaltForUpgrade(ctx, "v0.8.0", 200)
+	psm.ResumeAfterUpgrade(ctx, "v0.8.0", 200)
+	_, err = psm.GetSession(ctx, testConsumer, providerFirstEpoch, 1, 4)
+	require.True(t, InvalidEpochError.Is(err))
+	session, err = prepareProviderSession(ctx, psm, providerSecondEpoch, 2, RelayNumberIncrement)
+	require.Nil(t, err)
+	require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{}))
+}
+
+func successfulProviderSession(ctx context.Context, psm *ProviderSessionManager, t *testing.T, p int, ch chan int) {
+	sessionId := uint64(p + 1)
+	for relayNum := uint64(RelayNumberIncrement); relayNum <= 2; relayNum++ {
+		session, err := prepareProviderSession(ctx, psm, providerFirstEpoch, sessionId, relayNum)
+		require.Nil(t, err)
+		time.Sleep(time.Duration(rand.Intn(50)+1) * time.Millisecond)
+		require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{SessionId: sessionId, RelayNum: relayNum}))
+	}
+	ch <- p
+}
+
+func failedProviderSession(ctx context.Context, psm *ProviderSessionManager, t *testing.T, p int, ch chan int) {
+	sessionId := uint64(parallelGoRoutines + p + 1)
+	session, err := prepareProviderSession(ctx, psm, providerFirstEpoch, sessionId, RelayNumberIncrement)
+	require.Nil(t, err)
+	time.Sleep(time.Duration(rand.Intn(50)+1) * time.Millisecond)
+	require.Nil(t, psm.OnSessionFailure(session))
+	ch <- p
+}
+
+func dataReliabilityProviderSession(ctx context.Context, psm *ProviderSessionManager, t *testing.T, p int, ch chan error) {
+	session, err := psm.GetDataReliabilitySession(ctx, testConsumer, providerFirstEpoch)
+	if err == nil {
+		time.Sleep(time.Duration(rand.Intn(50)+1) * time.Millisecond)
+		require.Nil(t, psm.OnSessionDone(session, &pairingtypes.RelayRequest{DataReliability: &pairingtypes.VRFData{}}))
+	}
+	ch <- err
+}
+
+func TestProviderSessionHappyFlowMultiThreaded(t *testing.T) {
+	ctx := context.Background()
+	psm, stateQuery := createProviderSessionManager()
+	ch1 := make(chan int)
+	ch2 := make(chan int)
+	ch3 := make(chan error)
+	for p := 0; p < parallelGoRoutines; p++ { // we have x amount of successful sessions and x amount of failed. validate compute units
+		go successfulProviderSession(ctx, psm, t, p, ch1)
+		go failedProviderSession(ctx, psm, t, p, ch2)
+		go dataReliabilityProviderSession(ctx, psm, t, p, ch3)
+	}
+	dataReliabilitySessions := 0
+	for p := 0; p < parallelGoRoutines; p++ {
+		<-ch1
+		<-ch2
+		if err := <-ch3; err == nil {
+			dataReliabilitySessions++
+		} else {
+			require.True(t, DataReliabilityAlreadyUsedError.Is(err))
+		}
+	}
+	require.Equal(t, 1, dataReliabilitySessions)
+	// concurrent first relays register the consumer once
+	require.Len(t, psm.sessionsWithAllConsumers[providerFirstEpoch], 1)
+	require.Len(t, psm.sessionsWithAllConsumers[providerFirstEpoch][testConsumer].Sessions, parallelGoRoutines*2)
+	require.LessOrEqual(t, stateQuery.pairingQueries, parallelGoRoutines*3)
+	require.Equal(t, 2*cuForProviderRequest*parallelGoRoutines, usedComputeUnits(psm, providerFirstEpoch, testConsumer))
+}
+
+func TestProviderSessionMultiThreadedWithUpdateEpoch(t *testing.T) {
+	ctx := context.Background()
+	psm, _ := createProviderSessionManager()
+	ch1 := make(chan int)
+	ch2 := make(chan int)
+	for p := 0; p < parallelGoRoutines; p++ {
+		go successfulProviderSession(ctx, psm, t, p, ch1)
+		go failedProviderSession(ctx, psm, t, p, ch2)
+	}
+	for p := 0; p < parallelGoRoutines; p++ {
+		if p == parallelGoRoutines/2 { // at half of the go routines the epoch changes, the relays of the previous epoch are still served
+			go psm.UpdateEpoch(providerSecondEpoch)
+		}
+		<-ch1
+		<-ch2
+	}
+	require.True(t, psm.IsValidEpoch(providerFirstEpoch))
+	require.Equal(t, 2*cuForProviderRequest*parallelGoRoutines, usedComputeUnits(psm, providerFirstEpoch, testConsumer))
+}
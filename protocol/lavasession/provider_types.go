@@ -3,6 +3,7 @@ package lavasession
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 
@@ -24,11 +25,38 @@ type ProviderSessionsEpochData struct {
 }
 
 type RPCProviderEndpoint struct {
-	NetworkAddress string   `yaml:"network-address,omitempty" json:"network-address,omitempty" mapstructure:"network-address"` // IP:PORT
-	ChainID        string   `yaml:"chain-id,omitempty" json:"chain-id,omitempty" mapstructure:"chain-id"`                      // spec chain identifier
-	ApiInterface   string   `yaml:"api-interface,omitempty" json:"api-interface,omitempty" mapstructure:"api-interface"`
-	Geolocation    uint64   `yaml:"geolocation,omitempty" json:"geolocation,omitempty" mapstructure:"geolocation"`
-	NodeUrl        []string `yaml:"node-url,omitempty" json:"node-url,omitempty" mapstructure:"node-url"`
+	NetworkAddress string       `yaml:"network-address,omitempty" json:"network-address,omitempty" mapstructure:"network-address"` // IP:PORT
+	ChainID        string       `yaml:"chain-id,omitempty" json:"chain-id,omitempty" mapstructure:"chain-id"`                      // spec chain identifier
+	ApiInterface   string       `yaml:"api-interface,omitempty" json:"api-interface,omitempty" mapstructure:"api-interface"`
+	Geolocation    uint64       `yaml:"geolocation,omitempty" json:"geolocation,omitempty" mapstructure:"geolocation"`
+	NodeUrl        []string     `yaml:"node-url,omitempty" json:"node-url,omitempty" mapstructure:"node-url"` // a single node, kept for configs written before nodes
+	Nodes          []NodeConfig `yaml:"nodes,omitempty" json:"nodes,omitempty" mapstructure:"nodes"`
+	RateLimit      RateLimit    `yaml:"rate-limit,omitempty" json:"rate-limit,omitempty" mapstructure:"rate-limit"` // limits every consumer of the endpoint gets
+	// relays of all the consumers the endpoint serves at once, more relays wait for a free slot by their consumer priority, 0 for no limit
+	MaxConcurrentRelays uint64 `yaml:"max-concurrent-relays,omitempty" json:"max-concurrent-relays,omitempty" mapstructure:"max-concurrent-relays"`
+}
+
+// RateLimit caps the load a single consumer can put on the endpoint nodes, 0 means no limit
+type RateLimit struct {
+	CuPerSecond             uint64 `yaml:"cu-per-second,omitempty" json:"cu-per-second,omitempty" mapstructure:"cu-per-second"`
+	ConcurrentRelays        uint64 `yaml:"concurrent-relays,omitempty" json:"concurrent-relays,omitempty" mapstructure:"concurrent-relays"`
+	ConcurrentSubscriptions uint64 `yaml:"concurrent-subscriptions,omitempty" json:"concurrent-subscriptions,omitempty" mapstructure:"concurrent-subscriptions"`
+}
+
+// NodeConfig is a node the endpoint relays to, tendermint nodes need both their websocket and http urls
+type NodeConfig struct {
+	Urls          []string `yaml:"urls,omitempty" json:"urls,omitempty" mapstructure:"urls"`
+	Weight        uint64   `yaml:"weight,omitempty" json:"weight,omitempty" mapstructure:"weight"`                         // share of the relays the node gets relative to the other nodes, 0 is treated as 1
+	HistoryBlocks uint64   `yaml:"history-blocks,omitempty" json:"history-blocks,omitempty" mapstructure:"history-blocks"` // how many blocks back the node keeps, 0 for an archive node
+}
+
+// NodeConfigs returns all the nodes of the endpoint, node-url is a single archive node with the default weight
+func (rpcpe *RPCProviderEndpoint) NodeConfigs() []NodeConfig {
+	nodes := []NodeConfig{}
+	if len(rpcpe.NodeUrl) > 0 {
+		nodes = append(nodes, NodeConfig{Urls: rpcpe.NodeUrl, Weight: 1})
+	}
+	return append(nodes, rpcpe.Nodes...)
 }
 
 func (rpcpe *RPCProviderEndpoint) Key() string {
@@ -42,11 +70,12 @@ const (
 
 // holds all of the data for a consumer for a certain epoch
 type ProviderSessionsWithConsumer struct {
-	Sessions      map[uint64]*SingleProviderSession
-	isBlockListed uint32
-	consumer      string
-	epochData     *ProviderSessionsEpochData
-	Lock          sync.RWMutex
+	Sessions               map[uint64]*SingleProviderSession
+	dataReliabilitySession *SingleProviderSession // a consumer can send one data reliability relay per epoch
+	isBlockListed          uint32
+	consumer               string
+	epochData              *ProviderSessionsEpochData
+	Lock                   sync.RWMutex
 }
 
 // reads cs.BlockedEpoch atomically
@@ -59,12 +88,10 @@ func (pswc *ProviderSessionsWithConsumer) atomicReadBlockedEpoch() (blockStatus
 	return atomic.LoadUint32(&pswc.isBlockListed)
 }
 
-func (pswc *ProviderSessionsWithConsumer) readBlockListedAtomic() {
-}
-
 type SingleProviderSession struct {
 	userSessionsParent *ProviderSessionsWithConsumer
 	CuSum              uint64
+	LatestRelayCu      uint64
 	UniqueIdentifier   uint64
 	Lock               sync.RWMutex
 	Proof              *pairingtypes.RelayRequest // saves last relay request of a session as proof
@@ -89,6 +116,87 @@ func (pswc *ProviderSessionsWithConsumer) GetExistingSession(sessionId uint64) (
 	return nil, fmt.Errorf("session does not exist")
 }
 
+// returns the data reliability session of the consumer locked, fails if the consumer already used it this epoch
+func (pswc *ProviderSessionsWithConsumer) getDataReliabilitySession(epoch uint64) (*SingleProviderSession, error) {
+	pswc.Lock.Lock()
+	if pswc.dataReliabilitySession == nil {
+		pswc.dataReliabilitySession = &SingleProviderSession{
+			userSessionsParent: pswc,
+			UniqueIdentifier:   DataReliabilitySessionId,
+			PairingEpoch:       epoch,
+		}
+	}
+	dataReliabilitySession := pswc.dataReliabilitySession
+	pswc.Lock.Unlock()
+
+	dataReliabilitySession.Lock.Lock()
+	if dataReliabilitySession.RelayNum > 0 {
+		dataReliabilitySession.Lock.Unlock()
+		return nil, DataReliabilityAlreadyUsedError
+	}
+	return dataReliabilitySession, nil
+}
+
+// adds the relay compute units to the consumer usage in this epoch, fails if it exceeds the consumer max compute units
+func (pswc *ProviderSessionsWithConsumer) addUsedComputeUnits(cu uint64) error {
+	pswc.Lock.Lock()
+	defer pswc.Lock.Unlock()
+	if pswc.epochData.UsedComputeUnits+cu > pswc.epochData.MaxComputeUnits {
+		return MaxComputeUnitsExceededError
+	}
+	pswc.epochData.UsedComputeUnits += cu
+	return nil
+}
+
+func (pswc *ProviderSessionsWithConsumer) subtractUsedComputeUnits(cu uint64) error {
+	pswc.Lock.Lock()
+	defer pswc.Lock.Unlock()
+	if pswc.epochData.UsedComputeUnits < cu {
+		pswc.epochData.UsedComputeUnits = 0
+		return NegativeComputeUnitsAmountError
+	}
+	pswc.epochData.UsedComputeUnits -= cu
+	return nil
+}
+
+// PrepareSessionForUsage verifies the relay continues the session and charges its compute units,
+// on failure the session is unlocked as it will not be used
+func (sps *SingleProviderSession) PrepareSessionForUsage(cuFromSpec uint64, relayRequestTotalCU uint64, relayNum uint64) error {
+	if sps.RelayNum+RelayNumberIncrement > relayNum {
+		sps.Lock.Unlock()
+		return utils.LavaFormatError("consumer requested a smaller relay num than expected, trying to overwrite past usage", SessionOutOfSyncError, &map[string]string{
+			"sessionID": strconv.FormatUint(sps.UniqueIdentifier, 10),
+			"expected":  strconv.FormatUint(sps.RelayNum+RelayNumberIncrement, 10),
+			"received":  strconv.FormatUint(relayNum, 10),
+		})
+	}
+	if sps.CuSum+cuFromSpec != relayRequestTotalCU {
+		sps.Lock.Unlock()
+		return utils.LavaFormatError("bad CU sum", SessionOutOfSyncError, &map[string]string{
+			"sessionID":    strconv.FormatUint(sps.UniqueIdentifier, 10),
+			"cuSum":        strconv.FormatUint(sps.CuSum, 10),
+			"requestCuSum": strconv.FormatUint(relayRequestTotalCU, 10),
+			"cuFromSpec":   strconv.FormatUint(cuFromSpec, 10),
+		})
+	}
+	err := sps.userSessionsParent.addUsedComputeUnits(cuFromSpec)
+	if err != nil {
+		sps.Lock.Unlock()
+		// the consumer can't use more than it paid for this epoch, it is blocked until the epoch ends
+		sps.userSessionsParent.atomicWriteBlockedEpoch(blockListedConsumer)
+		return utils.LavaFormatError("consumer cu overflow, blocking the consumer", err, &map[string]string{
+			"sessionID":  strconv.FormatUint(sps.UniqueIdentifier, 10),
+			"consumer":   sps.userSessionsParent.consumer,
+			"cuFromSpec": strconv.FormatUint(cuFromSpec, 10),
+		})
+	}
+	sps.LatestRelayCu = cuFromSpec
+	sps.CuSum = relayRequestTotalCU
+	sps.RelayNum = relayNum
+	return nil
+}
+
 type StateQuery interface {
-	QueryVerifyPairing(ctx context.Context, consumer string, blockHeight uint64)
+	QueryVerifyPairing(ctx context.Context, chainID string, consumer string, provider string, blockHeight uint64) (valid bool, index int64, err error)
+	GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, blockHeight uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error)
 }
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lavanet/lava/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const MetricsListenFlagName = "metrics-listen-address"
+
+// ProviderMetricsManager exposes the provider metrics to prometheus, a nil manager is valid and doesn't record anything
+type ProviderMetricsManager struct {
+	registry                             *prometheus.Registry
+	consumerCuPerSecondLimit             *prometheus.GaugeVec
+	consumerConcurrentRelaysLimit        *prometheus.GaugeVec
+	consumerConcurrentSubscriptionsLimit *prometheus.GaugeVec
+	rateLimitedRelays                    *prometheus.CounterVec
+	deniedRelays                         *prometheus.CounterVec
+}
+
+func NewProviderMetricsManager() *ProviderMetricsManager {
+	endpointLabels := []string{"spec", "apiInterface"}
+	pme := &ProviderMetricsManager{
+		registry: prometheus.NewRegistry(),
+		consumerCuPerSecondLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "lava_provider_consumer_cu_per_second_limit",
+			Help: "The compute units a single consumer can use per second, 0 for no limit.",
+		}, endpointLabels),
+		consumerConcurrentRelaysLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "lava_provider_consumer_concurrent_relays_limit",
+			Help: "The relays a single consumer can have in flight, 0 for no limit.",
+		}, endpointLabels),
+		consumerConcurrentSubscriptionsLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "lava_provider_consumer_concurrent_subscriptions_limit",
+			Help: "The subscriptions a single consumer can keep open, 0 for no limit.",
+		}, endpointLabels),
+		rateLimitedRelays: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "lava_provider_rate_limited_relays",
+			Help: "The number of relays rejected for exceeding the consumer rate limits.",
+		}, append(endpointLabels, "limit")),
+		deniedRelays: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "lava_provider_denied_relays",
+			Help: "The number of relays rejected by the consumer policy.",
+		}, endpointLabels),
+	}
+	pme.registry.MustRegister(pme.consumerCuPerSecondLimit, pme.consumerConcurrentRelaysLimit, pme.consumerConcurrentSubscriptionsLimit, pme.rateLimitedRelays, pme.deniedRelays)
+	return pme
+}
+
+// StartProviderMetricsServer serves the metrics on networkAddress, returns nil when metrics are disabled
+func StartProviderMetricsServer(networkAddress string) *ProviderMetricsManager {
+	if networkAddress == "" {
+		utils.LavaFormatInfo("Running with metrics disabled", nil)
+		return nil
+	}
+	pme := NewProviderMetricsManager()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(pme.registry, promhttp.HandlerOpts{}))
+	go func() {
+		utils.LavaFormatInfo("Starting metrics server", &map[string]string{"address": networkAddress})
+		if err := http.ListenAndServe(networkAddress, mux); !errors.Is(err, http.ErrServerClosed) {
+			utils.LavaFormatError("metrics server stopped", err, &map[string]string{"address": networkAddress})
+		}
+	}()
+	return pme
+}
+
+func (pme *ProviderMetricsManager) SetConsumerRateLimits(chainID string, apiInterface string, cuPerSecond uint64, concurrentRelays uint64, concurrentSubscriptions uint64) {
+	if pme == nil {
+		return
+	}
+	pme.consumerCuPerSecondLimit.WithLabelValues(chainID, apiInterface).Set(float64(cuPerSecond))
+	pme.consumerConcurrentRelaysLimit.WithLabelValues(chainID, apiInterface).Set(float64(concurrentRelays))
+	pme.consumerConcurrentSubscriptionsLimit.WithLabelValues(chainID, apiInterface).Set(float64(concurrentSubscriptions))
+}
+
+func (pme *ProviderMetricsManager) AddRateLimitedRelay(chainID string, apiInterface string, limit string) {
+	if pme == nil {
+		return
+	}
+	pme.rateLimitedRelays.WithLabelValues(chainID, apiInterface, limit).Inc()
+}
+
+func (pme *ProviderMetricsManager) AddDeniedRelay(chainID string, apiInterface string) {
+	if pme == nil {
+		return
+	}
+	pme.deniedRelays.WithLabelValues(chainID, apiInterface).Inc()
+}
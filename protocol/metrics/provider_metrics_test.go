@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderMetricsRateLimits(t *testing.T) {
+	pme := NewProviderMetricsManager()
+	pme.SetConsumerRateLimits("LAV1", "jsonrpc", 100, 5, 2)
+	require.Equal(t, float64(100), testutil.ToFloat64(pme.consumerCuPerSecondLimit.WithLabelValues("LAV1", "jsonrpc")))
+	require.Equal(t, float64(5), testutil.ToFloat64(pme.consumerConcurrentRelaysLimit.WithLabelValues("LAV1", "jsonrpc")))
+	require.Equal(t, float64(2), testutil.ToFloat64(pme.consumerConcurrentSubscriptionsLimit.WithLabelValues("LAV1", "jsonrpc")))
+
+	pme.AddRateLimitedRelay("LAV1", "jsonrpc", "cu-per-second")
+	pme.AddRateLimitedRelay("LAV1", "jsonrpc", "cu-per-second")
+	require.Equal(t, float64(2), testutil.ToFloat64(pme.rateLimitedRelays.WithLabelValues("LAV1", "jsonrpc", "cu-per-second")))
+	pme.AddDeniedRelay("LAV1", "jsonrpc")
+	require.Equal(t, float64(1), testutil.ToFloat64(pme.deniedRelays.WithLabelValues("LAV1", "jsonrpc")))
+
+	// metrics are disabled with a nil manager
+	var disabled *ProviderMetricsManager
+	disabled.SetConsumerRateLimits("LAV1", "jsonrpc", 100, 5, 2)
+	disabled.AddRateLimitedRelay("LAV1", "jsonrpc", "cu-per-second")
+	disabled.AddDeniedRelay("LAV1", "jsonrpc")
+}
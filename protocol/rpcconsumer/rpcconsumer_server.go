@@ -22,7 +22,10 @@ import (
 )
 
 const (
-	MaxRelayRetries = 3
+	MaxRelayRetries         = 3
+	HedgeLatencyPercentile  = 0.9 // a relay slower than this percentile of its provider's recent relays is hedged
+	SecureProvidersFlagName = "secure-providers"
+	DefaultSecureProviders  = 3 // the least providers a majority can be taken between
 )
 
 // implements Relay Sender interfaced and uses an ChainListener to get it called
@@ -101,11 +104,21 @@ func (rpccs *RPCConsumerServer) SendRelay(
 	// do this in a loop with retry attempts, configurable via a flag, limited by the number of providers in CSM
 	relayRequestCommonData := lavaprotocol.NewRelayRequestCommonData(rpccs.listenEndpoint.ChainID, connectionType, url, []byte(req), chainMessage.RequestedBlock())
 
+	requiredResponses := rpccs.requiredResponses
+	if chainMessage.GetInterface().GetCategory().GetSubscription() {
+		// subscriptions stream their replies, they can't be compared between providers
+		requiredResponses = 1
+	}
 	relayResults := []*lavaprotocol.RelayResult{}
 	relayErrors := []error{}
-	for retries := 0; retries < MaxRelayRetries; retries++ {
-		// TODO: make this async between different providers
-		relayResult, err := rpccs.sendRelayToProvider(ctx, chainMessage, relayRequestCommonData, dappID, &unwantedProviders)
+	// every additional required response gets one more attempt
+	for retries := 0; retries < MaxRelayRetries+requiredResponses-1; retries++ {
+		var relayResult *lavaprotocol.RelayResult
+		if hedgedRelays := rpccs.listenEndpoint.HedgedRelays; hedgedRelays > 0 && !chainMessage.GetInterface().GetCategory().GetSubscription() {
+			relayResult, err = rpccs.sendHedgedRelays(ctx, chainMessage, relayRequestCommonData, dappID, &unwantedProviders, hedgedRelays)
+		} else {
+			relayResult, err = rpccs.sendRelayToProvider(ctx, chainMessage, relayRequestCommonData, dappID, &unwantedProviders)
+		}
 		if relayResult.ProviderAddress != "" {
 			unwantedProviders[relayResult.ProviderAddress] = struct{}{}
 		}
@@ -115,12 +128,16 @@ func (rpccs *RPCConsumerServer) SendRelay(
 				// if we ran out of pairings because unwantedProviders is too long or validProviders is too short, continue to reply handling code
 				break
 			}
+			if lavasession.HaltedForUpgradeError.Is(err) {
+				// no provider can be paid for relays while the lava chain halts for an upgrade
+				break
+			}
 			// decide if we should break here if its something retry won't solve
 			utils.LavaFormatDebug("could not send relay to provider", &map[string]string{"error": err.Error()})
 			continue
 		}
 		relayResults = append(relayResults, relayResult)
-		if len(relayResults) >= rpccs.requiredResponses {
+		if len(relayResults) >= requiredResponses {
 			break
 		}
 		// future requests need to ask for the same block height to get consensus on the reply
@@ -137,16 +154,18 @@ func (rpccs *RPCConsumerServer) SendRelay(
 		}
 	}
 
-	// TODO: secure, go over relay results to find discrepancies and choose majority, or trigger a second wallet relay
 	if len(relayResults) == 0 {
 		return nil, nil, utils.LavaFormatError("Failed all retries", nil, &map[string]string{"errors": fmt.Sprintf("Errors: %+v", relayErrors)})
 	} else if len(relayErrors) > 0 {
 		utils.LavaFormatDebug("relay succeeded but had some errors", &map[string]string{"errors": fmt.Sprintf("Errors: %+v", relayErrors)})
 	}
-	var returnedResult *lavaprotocol.RelayResult
-	for _, iteratedResult := range relayResults {
-		// TODO: go over rpccs.requiredResponses and get majority
-		returnedResult = iteratedResult
+	if len(relayResults) < requiredResponses {
+		utils.LavaFormatWarning("secure relay got less responses than required, returning the majority of the received ones", nil, &map[string]string{"responses": strconv.Itoa(len(relayResults)), "requiredResponses": strconv.Itoa(requiredResponses)})
+	}
+	returnedResult, conflicts := lavaprotocol.FindMajorityResult(relayResults)
+	for _, conflict := range conflicts {
+		// the client context may be canceled once the reply returns, the detection tx is sent regardless
+		go rpccs.consumerTxSender.TxConflictDetection(context.Background(), nil, conflict, nil)
 	}
 	return returnedResult.Reply, returnedResult.ReplyServer, nil
 }
@@ -170,51 +189,189 @@ func (rpccs *RPCConsumerServer) sendRelayToProvider(
 	// handle QoS updates
 	// in case connection totally fails, update unresponsive providers in ConsumerSessionManager
 
-	isSubscription := chainMessage.GetInterface().Category.Subscription
+	singleConsumerSession, epoch, relayResult, err := rpccs.getRelaySession(ctx, chainMessage, relayRequestCommonData, *unwantedProviders)
+	if err != nil {
+		return relayResult, err
+	}
+	return rpccs.sendRelayWithSession(ctx, chainMessage, singleConsumerSession, epoch, relayResult, dappID)
+}
+
+// sends the relay to a provider and to up to hedgedRelays more providers, each one once the relay in flight takes longer than
+// HedgeLatencyPercentile of the recent relays to its provider. the first successful reply is returned and the other relays are canceled
+func (rpccs *RPCConsumerServer) sendHedgedRelays(
+	ctx context.Context,
+	chainMessage chainlib.ChainMessage,
+	relayRequestCommonData lavaprotocol.RelayRequestCommonData,
+	dappID string,
+	unwantedProviders *map[string]struct{},
+	hedgedRelays uint64,
+) (relayResult *lavaprotocol.RelayResult, errRet error) {
+	hedgeCtx, cancelHedges := context.WithCancel(ctx)
+	defer cancelHedges() // the relays still in flight release their sessions once they are canceled
+	// buffered so relays finishing after a reply was returned don't block
+	hedgedResults := make(chan *hedgedRelayResult, hedgedRelays+1)
+	inFlight := 0
+	send := func() (relayResult *lavaprotocol.RelayResult, hedgeDelay time.Duration, err error) {
+		singleConsumerSession, epoch, relayResult, err := rpccs.getRelaySession(hedgeCtx, chainMessage, relayRequestCommonData, *unwantedProviders)
+		if relayResult.ProviderAddress != "" {
+			(*unwantedProviders)[relayResult.ProviderAddress] = struct{}{}
+		}
+		if err != nil {
+			return relayResult, 0, err
+		}
+		// read while the session is still only ours
+		hedgeDelay = rpccs.getHedgeDelay(chainMessage, singleConsumerSession)
+		inFlight++
+		go func() {
+			relayResult, err := rpccs.sendRelayWithSession(hedgeCtx, chainMessage, singleConsumerSession, epoch, relayResult, dappID)
+			hedgedResults <- &hedgedRelayResult{relayResult: relayResult, err: err}
+		}()
+		return relayResult, hedgeDelay, nil
+	}
+
+	relayResult, hedgeDelay, err := send()
+	if err != nil {
+		return relayResult, err
+	}
+	hedgeTimer := time.NewTimer(hedgeDelay)
+	defer hedgeTimer.Stop()
+	for inFlight > 0 {
+		select {
+		case hedgedResult := <-hedgedResults:
+			inFlight--
+			if hedgedResult.err == nil {
+				return hedgedResult.relayResult, nil
+			}
+			relayResult, errRet = hedgedResult.relayResult, hedgedResult.err
+		case <-hedgeTimer.C:
+			hedgedRelays--
+			hedgeResult, hedgeDelay, err := send()
+			if err != nil {
+				// no more providers to hedge to, wait for the relays in flight
+				utils.LavaFormatDebug("could not send a hedged relay", &map[string]string{"error": err.Error(), "provider": hedgeResult.ProviderAddress})
+				continue
+			}
+			if hedgedRelays > 0 {
+				hedgeTimer.Reset(hedgeDelay)
+			}
+		}
+	}
+	return relayResult, errRet
+}
+
+type hedgedRelayResult struct {
+	relayResult *lavaprotocol.RelayResult
+	err         error
+}
 
+// a relay is hedged once it takes longer than most of the recent relays to its provider, or than its expected latency while there are too few of them
+func (rpccs *RPCConsumerServer) getHedgeDelay(chainMessage chainlib.ChainMessage, singleConsumerSession *lavasession.SingleConsumerSession) time.Duration {
+	if latency, ok := singleConsumerSession.Client.RelayLatencyPercentile(HedgeLatencyPercentile); ok {
+		return latency
+	}
+	return singleConsumerSession.CalculateExpectedLatency(rpccs.getRelayTimeout(chainMessage, singleConsumerSession))
+}
+
+// gets a session and signs the relay request for it, the session stays locked until the relay is sent with sendRelayWithSession
+func (rpccs *RPCConsumerServer) getRelaySession(
+	ctx context.Context,
+	chainMessage chainlib.ChainMessage,
+	relayRequestCommonData lavaprotocol.RelayRequestCommonData,
+	unwantedProviders map[string]struct{},
+) (singleConsumerSession *lavasession.SingleConsumerSession, epoch uint64, relayResult *lavaprotocol.RelayResult, err error) {
 	// Get Session. we get session here so we can use the epoch in the callbacks
-	singleConsumerSession, epoch, providerPublicAddress, reportedProviders, err := rpccs.consumerSessionManager.GetSession(ctx, chainMessage.GetServiceApi().ComputeUnits, *unwantedProviders)
+	var providerPublicAddress string
+	var reportedProviders []byte
+	// providers that probably can't serve the requested block are only used when no other provider is left
+	providersToAvoid := rpccs.finalizationConsensus.ProvidersToAvoid(rpccs.chainParser, relayRequestCommonData.RequestBlock)
+	if len(providersToAvoid) > 0 {
+		for providerAddress := range unwantedProviders {
+			providersToAvoid[providerAddress] = struct{}{}
+		}
+		singleConsumerSession, epoch, providerPublicAddress, reportedProviders, err = rpccs.consumerSessionManager.GetSession(ctx, chainMessage.GetServiceApi().ComputeUnits, providersToAvoid)
+	}
+	if len(providersToAvoid) == 0 || err != nil {
+		// when none of the other providers could give a session the avoided ones might still serve the relay
+		singleConsumerSession, epoch, providerPublicAddress, reportedProviders, err = rpccs.consumerSessionManager.GetSession(ctx, chainMessage.GetServiceApi().ComputeUnits, unwantedProviders)
+	}
 	relayResult = &lavaprotocol.RelayResult{ProviderAddress: providerPublicAddress, Finalized: false}
 	if err != nil {
-		return relayResult, err
+		return nil, 0, relayResult, err
 	}
 	privKey := rpccs.privKey
 	chainID := rpccs.listenEndpoint.ChainID
 	relayRequest, err := lavaprotocol.ConstructRelayRequest(ctx, privKey, chainID, relayRequestCommonData, providerPublicAddress, singleConsumerSession, int64(epoch), reportedProviders)
 	if err != nil {
-		return relayResult, err
+		return nil, 0, relayResult, err
 	}
 	relayResult.Request = relayRequest
+	return singleConsumerSession, epoch, relayResult, nil
+}
+
+func (rpccs *RPCConsumerServer) getRelayTimeout(chainMessage chainlib.ChainMessage, singleConsumerSession *lavasession.SingleConsumerSession) time.Duration {
+	extraRelayTimeout := time.Duration(0)
+	if chainMessage.GetInterface().Category.HangingApi {
+		_, extraRelayTimeout, _, _ = rpccs.chainParser.ChainBlockStats()
+	}
+	return extraRelayTimeout + lavaprotocol.GetTimePerCu(singleConsumerSession.LatestRelayCu) + lavaprotocol.AverageWorldLatency
+}
+
+func (rpccs *RPCConsumerServer) sendRelayWithSession(
+	ctx context.Context,
+	chainMessage chainlib.ChainMessage,
+	singleConsumerSession *lavasession.SingleConsumerSession,
+	epoch uint64,
+	relayResult *lavaprotocol.RelayResult,
+	dappID string,
+) (relayResultRet *lavaprotocol.RelayResult, errRet error) {
+	isSubscription := chainMessage.GetInterface().Category.Subscription
+	relayRequest := relayResult.Request
+	chainID := rpccs.listenEndpoint.ChainID
 	endpointClient := *singleConsumerSession.Endpoint.Client
 
 	if isSubscription {
 		return rpccs.relaySubscriptionInner(ctx, endpointClient, singleConsumerSession, relayResult)
 	}
 
-	// try using cache before sending relay
-	var reply *pairingtypes.RelayReply
+	// try using cache before sending relay, secure relays skip it as their replies are compared between providers
+	if rpccs.requiredResponses <= 1 {
+		reply, err := rpccs.cache.GetEntry(ctx, relayRequest, chainMessage.GetInterface().Interface, nil, chainID, false) // caching in the portal doesn't care about hashes, and we don't have data on finalization yet
+		if err == nil && reply != nil {
+			// Info was fetched from cache, so we don't need to change the state
+			// so we can return here, no need to update anything and calculate as this info was fetched from the cache
+			relayResult.Reply = reply
+			err = rpccs.consumerSessionManager.OnSessionUnUsed(singleConsumerSession)
+			return relayResult, err
+		}
 
-	reply, err = rpccs.cache.GetEntry(ctx, relayRequest, chainMessage.GetInterface().Interface, nil, chainID, false) // caching in the portal doesn't care about hashes, and we don't have data on finalization yet
-	if err == nil && reply != nil {
-		// Info was fetched from cache, so we don't need to change the state
-		// so we can return here, no need to update anything and calculate as this info was fetched from the cache
-		relayResult.Reply = reply
-		err = rpccs.consumerSessionManager.OnSessionUnUsed(singleConsumerSession)
-		return relayResult, err
+		// cache failed, move on to regular relay
+		if performance.NotConnectedError.Is(err) {
+			utils.LavaFormatError("cache not connected", err, nil)
+		}
 	}
 
-	// cache failed, move on to regular relay
-	if performance.NotConnectedError.Is(err) {
-		utils.LavaFormatError("cache not connected", err, nil)
+	if ctx.Err() != nil {
+		// the relay was canceled before it was sent, a hedged relay that lost or a client that left, the session wasn't used
+		errUnUsed := rpccs.consumerSessionManager.OnSessionUnUsed(singleConsumerSession)
+		if errUnUsed != nil {
+			return relayResult, utils.LavaFormatError("canceled relay onSessionUnUsed errored", errUnUsed, &map[string]string{"original error": ctx.Err().Error()})
+		}
+		return relayResult, ctx.Err()
 	}
 
-	extraRelayTimeout := time.Duration(0)
-	if chainMessage.GetInterface().Category.HangingApi {
-		_, extraRelayTimeout, _, _ = rpccs.chainParser.ChainBlockStats()
-	}
-	relayTimeout := extraRelayTimeout + lavaprotocol.GetTimePerCu(singleConsumerSession.LatestRelayCu) + lavaprotocol.AverageWorldLatency
+	relayTimeout := rpccs.getRelayTimeout(chainMessage, singleConsumerSession)
 	relayResult, relayLatency, err := rpccs.relayInner(ctx, singleConsumerSession, relayResult, relayTimeout)
 	if err != nil {
+		if ctx.Err() == context.Canceled {
+			// the relay was canceled after it was sent, the provider may have served it and counted its cu already.
+			// the provider isn't at fault, but its session is blocked so the next relays don't go out of sync with it
+			errReport := rpccs.consumerSessionManager.OnSessionFailure(singleConsumerSession, context.Canceled)
+			if errReport != nil {
+				return relayResult, utils.LavaFormatError("canceled relay onSessionFailure errored", errReport, &map[string]string{"original error": err.Error()})
+			}
+			return relayResult, err
+		}
+		rpccs.finalizationConsensus.ProviderFailedBlock(relayResult.ProviderAddress, relayRequest.RequestBlock, err)
 		// relay failed need to fail the session advancement
 		errReport := rpccs.consumerSessionManager.OnSessionFailure(singleConsumerSession, err)
 		if errReport != nil {
@@ -223,6 +380,7 @@ func (rpccs *RPCConsumerServer) sendRelayToProvider(
 		return relayResult, err
 	}
 	// get here only if performed a regular relay successfully
+	rpccs.finalizationConsensus.UpdateProviderBlocks(relayResult.ProviderAddress, relayResult.Reply.LatestBlock, relayRequest.RequestBlock)
 	expectedBH, numOfProviders := rpccs.finalizationConsensus.ExpectedBlockHeight(rpccs.chainParser)
 	pairingAddressesLen := rpccs.consumerSessionManager.GetAtomicPairingAddressesLength()
 	latestBlock := relayResult.Reply.LatestBlock
@@ -0,0 +1,163 @@
+package ratelimiter
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/utils"
+	"github.com/spf13/viper"
+)
+
+const (
+	ConsumerPolicyFileFlag       = "consumer-policy-file"
+	ConsumerPolicyReloadInterval = 5 * time.Second
+	DefaultPriority              = 0
+)
+
+// ConsumerPolicyConfig is the consumer policy file, it is reloaded when the file changes
+type ConsumerPolicyConfig struct {
+	AllowOnly       bool            `yaml:"allow-only,omitempty" json:"allow-only,omitempty" mapstructure:"allow-only"` // only consumers in the allow list are served
+	AllowList       []string        `yaml:"allow-list,omitempty" json:"allow-list,omitempty" mapstructure:"allow-list"`
+	DenyList        []string        `yaml:"deny-list,omitempty" json:"deny-list,omitempty" mapstructure:"deny-list"` // denied consumers are never served, even when allowed
+	PriorityClasses []PriorityClass `yaml:"priority-classes,omitempty" json:"priority-classes,omitempty" mapstructure:"priority-classes"`
+}
+
+// PriorityClass consumers get their relays served before the relays of lower priority consumers when the relays queue, consumers without a class have DefaultPriority
+type PriorityClass struct {
+	Name      string   `yaml:"name,omitempty" json:"name,omitempty" mapstructure:"name"`
+	Priority  uint64   `yaml:"priority,omitempty" json:"priority,omitempty" mapstructure:"priority"`
+	Consumers []string `yaml:"consumers,omitempty" json:"consumers,omitempty" mapstructure:"consumers"`
+}
+
+// serves every consumer with the default priority
+var defaultConsumerPolicy = NewConsumerPolicy(ConsumerPolicyConfig{})
+
+// ConsumerPolicy is the policy compiled from its config, it is never modified so it can be read without locks
+type ConsumerPolicy struct {
+	allowOnly  bool
+	allowed    map[string]struct{}
+	denied     map[string]struct{}
+	priorities map[string]uint64
+}
+
+func NewConsumerPolicy(config ConsumerPolicyConfig) *ConsumerPolicy {
+	cp := &ConsumerPolicy{
+		allowOnly:  config.AllowOnly,
+		allowed:    map[string]struct{}{},
+		denied:     map[string]struct{}{},
+		priorities: map[string]uint64{},
+	}
+	for _, consumer := range config.AllowList {
+		cp.allowed[consumer] = struct{}{}
+	}
+	for _, consumer := range config.DenyList {
+		cp.denied[consumer] = struct{}{}
+	}
+	for _, priorityClass := range config.PriorityClasses {
+		for _, consumer := range priorityClass.Consumers {
+			// a consumer listed in several classes gets the highest of them
+			if priority, ok := cp.priorities[consumer]; !ok || priority < priorityClass.Priority {
+				cp.priorities[consumer] = priorityClass.Priority
+			}
+		}
+	}
+	return cp
+}
+
+// Admit returns the priority of the consumer relays or ConsumerDeniedError if the consumer isn't served
+func (cp *ConsumerPolicy) Admit(consumer string) (priority uint64, err error) {
+	if _, ok := cp.denied[consumer]; ok {
+		return 0, sdkerrors.Wrapf(lavasession.ConsumerDeniedError, "consumer: %s is in the deny list", consumer)
+	}
+	if _, ok := cp.allowed[consumer]; cp.allowOnly && !ok {
+		return 0, sdkerrors.Wrapf(lavasession.ConsumerDeniedError, "consumer: %s is not in the allow list", consumer)
+	}
+	priority, ok := cp.priorities[consumer]
+	if !ok {
+		return DefaultPriority, nil
+	}
+	return priority, nil
+}
+
+// ConsumerPolicyWatcher keeps the policy of the policy file, changes to the file apply to new relays without a restart.
+// a nil watcher serves every consumer with the default priority
+type ConsumerPolicyWatcher struct {
+	lock       sync.RWMutex
+	policyFile string
+	modTime    time.Time // of the policy file when it was last loaded
+	policy     *ConsumerPolicy
+}
+
+func NewConsumerPolicyWatcher(ctx context.Context, policyFile string) (*ConsumerPolicyWatcher, error) {
+	return newConsumerPolicyWatcher(ctx, policyFile, ConsumerPolicyReloadInterval)
+}
+
+func newConsumerPolicyWatcher(ctx context.Context, policyFile string, reloadInterval time.Duration) (*ConsumerPolicyWatcher, error) {
+	if policyFile == "" {
+		return nil, nil
+	}
+	cpw := &ConsumerPolicyWatcher{policyFile: policyFile}
+	_, err := cpw.reloadIfChanged()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reloaded, err := cpw.reloadIfChanged()
+				if err != nil {
+					utils.LavaFormatError("failed reloading the consumer policy, keeping the previous policy", err, &map[string]string{"policyFile": policyFile})
+				} else if reloaded {
+					utils.LavaFormatInfo("reloaded the consumer policy", &map[string]string{"policyFile": policyFile})
+				}
+			}
+		}
+	}()
+	return cpw, nil
+}
+
+// the file is polled rather than watched for events, editors replace files in ways file notifications miss
+func (cpw *ConsumerPolicyWatcher) reloadIfChanged() (reloaded bool, err error) {
+	fileInfo, err := os.Stat(cpw.policyFile)
+	if err != nil {
+		return false, utils.LavaFormatError("failed reading the consumer policy file", err, &map[string]string{"policyFile": cpw.policyFile})
+	}
+	if fileInfo.ModTime().Equal(cpw.modTime) {
+		return false, nil
+	}
+	policyViper := viper.New()
+	policyViper.SetConfigFile(cpw.policyFile)
+	err = policyViper.ReadInConfig()
+	if err != nil {
+		return false, utils.LavaFormatError("failed reading the consumer policy file", err, &map[string]string{"policyFile": cpw.policyFile})
+	}
+	var config ConsumerPolicyConfig
+	err = policyViper.Unmarshal(&config)
+	if err != nil {
+		return false, utils.LavaFormatError("failed parsing the consumer policy file", err, &map[string]string{"policyFile": cpw.policyFile})
+	}
+	policy := NewConsumerPolicy(config)
+	cpw.lock.Lock()
+	defer cpw.lock.Unlock()
+	cpw.modTime = fileInfo.ModTime()
+	cpw.policy = policy
+	return true, nil
+}
+
+func (cpw *ConsumerPolicyWatcher) Policy() *ConsumerPolicy {
+	if cpw == nil {
+		return defaultConsumerPolicy
+	}
+	cpw.lock.RLock()
+	defer cpw.lock.RUnlock()
+	return cpw.policy
+}
@@ -0,0 +1,75 @@
+package ratelimiter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerPolicy(t *testing.T) {
+	policy := NewConsumerPolicy(ConsumerPolicyConfig{
+		DenyList: []string{"denied"},
+		PriorityClasses: []PriorityClass{
+			{Name: "premium", Priority: 2, Consumers: []string{"premium", "both"}},
+			{Name: "partner", Priority: 1, Consumers: []string{"both"}},
+		},
+	})
+	_, err := policy.Admit("denied")
+	require.True(t, lavasession.ConsumerDeniedError.Is(err))
+	priority, err := policy.Admit("premium")
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), priority)
+	// a consumer in several classes gets the highest priority
+	priority, err = policy.Admit("both")
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), priority)
+	priority, err = policy.Admit("other")
+	require.Nil(t, err)
+	require.Equal(t, uint64(DefaultPriority), priority)
+
+	policy = NewConsumerPolicy(ConsumerPolicyConfig{AllowOnly: true, AllowList: []string{"allowed", "denied"}, DenyList: []string{"denied"}})
+	_, err = policy.Admit("allowed")
+	require.Nil(t, err)
+	_, err = policy.Admit("other")
+	require.True(t, lavasession.ConsumerDeniedError.Is(err))
+	// the deny list wins over the allow list
+	_, err = policy.Admit("denied")
+	require.True(t, lavasession.ConsumerDeniedError.Is(err))
+
+	// without a policy file everyone is served
+	var policyWatcher *ConsumerPolicyWatcher
+	_, err = policyWatcher.Policy().Admit("denied")
+	require.Nil(t, err)
+}
+
+func TestConsumerPolicyReload(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "consumer_policy.yml")
+	require.Nil(t, os.WriteFile(policyFile, []byte("deny-list:\n  - denied\n"), 0o600))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	policyWatcher, err := newConsumerPolicyWatcher(ctx, policyFile, 10*time.Millisecond)
+	require.Nil(t, err)
+	_, err = policyWatcher.Policy().Admit("denied")
+	require.True(t, lavasession.ConsumerDeniedError.Is(err))
+	_, err = policyWatcher.Policy().Admit("other")
+	require.Nil(t, err)
+
+	// the file modification time has to change
+	require.Nil(t, os.WriteFile(policyFile, []byte("allow-only: true\nallow-list:\n  - denied\n"), 0o600))
+	require.Nil(t, os.Chtimes(policyFile, time.Now(), time.Now().Add(time.Second)))
+	require.Eventually(t, func() bool {
+		_, err := policyWatcher.Policy().Admit("other")
+		return lavasession.ConsumerDeniedError.Is(err)
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = policyWatcher.Policy().Admit("denied")
+	require.Nil(t, err)
+
+	// a missing policy file fails the provider start
+	_, err = NewConsumerPolicyWatcher(ctx, filepath.Join(t.TempDir(), "missing.yml"))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,180 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/protocol/metrics"
+)
+
+// names of the limits, used in errors and metrics
+const (
+	CuPerSecondLimit             = "cu-per-second"
+	ConcurrentRelaysLimit        = "concurrent-relays"
+	ConcurrentSubscriptionsLimit = "concurrent-subscriptions"
+	RelayQueueLimit              = "relay-queue"
+)
+
+// how often the usage of idle consumers is dropped, a consumer that comes back starts with a second worth of compute units like a new one
+const consumerEvictionInterval = time.Minute
+
+type consumerUsage struct {
+	cuTokens      float64 // compute units the consumer can use right now, refilled at the cu per second limit up to a second worth of them
+	lastRefill    time.Time
+	relays        uint64
+	subscriptions uint64
+}
+
+// ConsumerRateLimiter admits the relays of an endpoint, it applies the consumer policy and the rate limits of each consumer
+// and queues relays by consumer priority when the endpoint is at its concurrent relays limit. rejected relays don't reach the node
+type ConsumerRateLimiter struct {
+	lock         sync.Mutex
+	chainID      string
+	apiInterface string
+	limits       lavasession.RateLimit
+	consumers    map[string]*consumerUsage // key is the consumer address
+	policy       *ConsumerPolicyWatcher
+	queue        *relayQueue
+	metrics      *metrics.ProviderMetricsManager
+	now          func() time.Time
+	lastEviction time.Time
+}
+
+func NewConsumerRateLimiter(rpcProviderEndpoint *lavasession.RPCProviderEndpoint, policy *ConsumerPolicyWatcher, providerMetrics *metrics.ProviderMetricsManager) *ConsumerRateLimiter {
+	limits := rpcProviderEndpoint.RateLimit
+	providerMetrics.SetConsumerRateLimits(rpcProviderEndpoint.ChainID, rpcProviderEndpoint.ApiInterface, limits.CuPerSecond, limits.ConcurrentRelays, limits.ConcurrentSubscriptions)
+	return &ConsumerRateLimiter{
+		chainID:      rpcProviderEndpoint.ChainID,
+		apiInterface: rpcProviderEndpoint.ApiInterface,
+		limits:       limits,
+		consumers:    map[string]*consumerUsage{},
+		policy:       policy,
+		queue:        &relayQueue{maxConcurrent: rpcProviderEndpoint.MaxConcurrentRelays},
+		metrics:      providerMetrics,
+		now:          time.Now,
+		lastEviction: time.Now(),
+	}
+}
+
+// TryAcquire admits a relay of the consumer, release must be called once the relay is done.
+// denied consumers get ConsumerDeniedError and consumers over their limits get ConsumerRateLimitedError, relays wait in the queue until ctx is done.
+// subscriptions hold their slot until they are closed, they are limited separately from relays and don't queue
+func (crl *ConsumerRateLimiter) TryAcquire(ctx context.Context, consumer string, cu uint64, subscription bool) (release func(), err error) {
+	priority, err := crl.policy.Policy().Admit(consumer)
+	if err != nil {
+		crl.metrics.AddDeniedRelay(crl.chainID, crl.apiInterface)
+		return nil, err
+	}
+	releaseConsumer, err := crl.acquireConsumer(consumer, cu, subscription)
+	if err != nil {
+		return nil, err
+	}
+	if subscription {
+		return releaseConsumer, nil
+	}
+	releaseQueue, err := crl.queue.acquire(ctx, priority)
+	if err != nil {
+		// the relay wasn't served, its compute units are returned to the consumer
+		releaseConsumer()
+		crl.refundCu(consumer, cu)
+		crl.metrics.AddRateLimitedRelay(crl.chainID, crl.apiInterface, RelayQueueLimit)
+		return nil, sdkerrors.Wrapf(lavasession.ConsumerRateLimitedError, "consumer: %s, limit: %s, %s", consumer, RelayQueueLimit, err)
+	}
+	return func() {
+		releaseQueue()
+		releaseConsumer()
+	}, nil
+}
+
+// applies the limits of the consumer itself
+func (crl *ConsumerRateLimiter) acquireConsumer(consumer string, cu uint64, subscription bool) (release func(), err error) {
+	crl.lock.Lock()
+	usage := crl.getConsumerUsage(consumer)
+	exceededLimit := ""
+	if subscription && crl.limits.ConcurrentSubscriptions > 0 && usage.subscriptions >= crl.limits.ConcurrentSubscriptions {
+		exceededLimit = ConcurrentSubscriptionsLimit
+	} else if !subscription && crl.limits.ConcurrentRelays > 0 && usage.relays >= crl.limits.ConcurrentRelays {
+		exceededLimit = ConcurrentRelaysLimit
+	} else if crl.limits.CuPerSecond > 0 && usage.cuTokens < float64(cu) && usage.cuTokens < float64(crl.limits.CuPerSecond) {
+		// a relay that costs more than a second worth of compute units is let through once the consumer didn't use any for a second
+		exceededLimit = CuPerSecondLimit
+	}
+	if exceededLimit != "" {
+		crl.lock.Unlock()
+		crl.metrics.AddRateLimitedRelay(crl.chainID, crl.apiInterface, exceededLimit)
+		return nil, sdkerrors.Wrapf(lavasession.ConsumerRateLimitedError, "consumer: %s, limit: %s", consumer, exceededLimit)
+	}
+	if crl.limits.CuPerSecond > 0 {
+		usage.cuTokens -= float64(cu)
+	}
+	if subscription {
+		usage.subscriptions++
+	} else {
+		usage.relays++
+	}
+	crl.lock.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			crl.lock.Lock()
+			defer crl.lock.Unlock()
+			if subscription {
+				usage.subscriptions--
+			} else {
+				usage.relays--
+			}
+		})
+	}, nil
+}
+
+func (crl *ConsumerRateLimiter) refundCu(consumer string, cu uint64) {
+	if crl.limits.CuPerSecond == 0 {
+		return
+	}
+	crl.lock.Lock()
+	defer crl.lock.Unlock()
+	usage := crl.getConsumerUsage(consumer)
+	usage.cuTokens += float64(cu)
+	if usage.cuTokens > float64(crl.limits.CuPerSecond) {
+		usage.cuTokens = float64(crl.limits.CuPerSecond)
+	}
+}
+
+// returns the usage of the consumer with its compute units refilled for the time that passed, must be called with the lock held
+func (crl *ConsumerRateLimiter) getConsumerUsage(consumer string) *consumerUsage {
+	now := crl.now()
+	if now.Sub(crl.lastEviction) >= consumerEvictionInterval {
+		crl.evictIdleConsumers(now)
+	}
+	usage, ok := crl.consumers[consumer]
+	if !ok {
+		usage = &consumerUsage{cuTokens: float64(crl.limits.CuPerSecond), lastRefill: now}
+		crl.consumers[consumer] = usage
+		return usage
+	}
+	usage.cuTokens += now.Sub(usage.lastRefill).Seconds() * float64(crl.limits.CuPerSecond)
+	if usage.cuTokens > float64(crl.limits.CuPerSecond) {
+		usage.cuTokens = float64(crl.limits.CuPerSecond)
+	}
+	usage.lastRefill = now
+	return usage
+}
+
+// drops the consumers without relays or subscriptions in flight whose compute units refilled, they are tracked again on their next relay.
+// must be called with the lock held
+func (crl *ConsumerRateLimiter) evictIdleConsumers(now time.Time) {
+	crl.lastEviction = now
+	for consumer, usage := range crl.consumers {
+		if usage.relays > 0 || usage.subscriptions > 0 {
+			continue
+		}
+		if usage.cuTokens+now.Sub(usage.lastRefill).Seconds()*float64(crl.limits.CuPerSecond) < float64(crl.limits.CuPerSecond) {
+			continue
+		}
+		delete(crl.consumers, consumer)
+	}
+}
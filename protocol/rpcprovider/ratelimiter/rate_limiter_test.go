@@ -0,0 +1,197 @@
+package ratelimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/protocol/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testConsumer      = "consumer"
+	testOtherConsumer = "otherConsumer"
+	testCu            = 10
+)
+
+func createTestRateLimiter(rateLimit lavasession.RateLimit) (*ConsumerRateLimiter, *time.Time) {
+	endpoint := &lavasession.RPCProviderEndpoint{ChainID: "LAV1", ApiInterface: "jsonrpc", RateLimit: rateLimit}
+	rateLimiter := NewConsumerRateLimiter(endpoint, nil, metrics.NewProviderMetricsManager())
+	now := time.Now()
+	rateLimiter.now = func() time.Time { return now }
+	return rateLimiter, &now
+}
+
+func TestRateLimiterCuPerSecond(t *testing.T) {
+	ctx := context.Background()
+	rateLimiter, now := createTestRateLimiter(lavasession.RateLimit{CuPerSecond: 2 * testCu})
+	for i := 0; i < 2; i++ {
+		release, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+		require.Nil(t, err)
+		release()
+	}
+	_, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+	// the limits are per consumer
+	_, err = rateLimiter.TryAcquire(ctx, testOtherConsumer, testCu, false)
+	require.Nil(t, err)
+
+	// compute units are refilled over time
+	*now = now.Add(time.Second / 2)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+
+	// a relay that costs more than the limit passes once the consumer is idle for a second
+	*now = now.Add(time.Second)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, 3*testCu, false)
+	require.Nil(t, err)
+	// the consumer owes the extra compute units
+	*now = now.Add(time.Second / 2)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+}
+
+func TestRateLimiterConcurrency(t *testing.T) {
+	ctx := context.Background()
+	rateLimiter, _ := createTestRateLimiter(lavasession.RateLimit{ConcurrentRelays: 2, ConcurrentSubscriptions: 1})
+	release, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+
+	// subscriptions have their own limit
+	releaseSubscription, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, true)
+	require.Nil(t, err)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, true)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+	releaseSubscription()
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, true)
+	require.Nil(t, err)
+
+	// releasing twice frees a single slot
+	release()
+	release()
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+}
+
+func TestRateLimiterEvictsIdleConsumers(t *testing.T) {
+	ctx := context.Background()
+	rateLimiter, now := createTestRateLimiter(lavasession.RateLimit{CuPerSecond: 2 * testCu})
+	release, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	release()
+	releaseOther, err := rateLimiter.TryAcquire(ctx, testOtherConsumer, testCu, true)
+	require.Nil(t, err)
+	require.Len(t, rateLimiter.consumers, 2)
+
+	// the idle consumer is dropped, the one with an open subscription is kept
+	*now = now.Add(consumerEvictionInterval)
+	release, err = rateLimiter.TryAcquire(ctx, testOtherConsumer, testCu, false)
+	require.Nil(t, err)
+	release()
+	require.Len(t, rateLimiter.consumers, 1)
+	require.Contains(t, rateLimiter.consumers, testOtherConsumer)
+
+	// consumers are dropped once per interval
+	releaseOther()
+	release, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	release()
+	require.Len(t, rateLimiter.consumers, 2)
+	*now = now.Add(consumerEvictionInterval)
+	release, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+	release()
+	require.Len(t, rateLimiter.consumers, 1)
+	require.Contains(t, rateLimiter.consumers, testConsumer)
+}
+
+func TestRateLimiterNoLimits(t *testing.T) {
+	ctx := context.Background()
+	rateLimiter, _ := createTestRateLimiter(lavasession.RateLimit{})
+	for i := 0; i < 100; i++ {
+		_, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, i%2 == 0)
+		require.Nil(t, err)
+	}
+}
+
+func TestRateLimiterDeniedConsumer(t *testing.T) {
+	ctx := context.Background()
+	rateLimiter, _ := createTestRateLimiter(lavasession.RateLimit{})
+	rateLimiter.policy = &ConsumerPolicyWatcher{policy: NewConsumerPolicy(ConsumerPolicyConfig{DenyList: []string{testConsumer}})}
+	_, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerDeniedError.Is(err))
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, true)
+	require.True(t, lavasession.ConsumerDeniedError.Is(err))
+	_, err = rateLimiter.TryAcquire(ctx, testOtherConsumer, testCu, false)
+	require.Nil(t, err)
+}
+
+func TestRateLimiterQueuePriority(t *testing.T) {
+	ctx := context.Background()
+	rateLimiter, _ := createTestRateLimiter(lavasession.RateLimit{})
+	rateLimiter.queue.maxConcurrent = 1
+	rateLimiter.policy = &ConsumerPolicyWatcher{policy: NewConsumerPolicy(ConsumerPolicyConfig{
+		PriorityClasses: []PriorityClass{{Name: "premium", Priority: 1, Consumers: []string{testOtherConsumer}}},
+	})}
+	release, err := rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.Nil(t, err)
+
+	served := make(chan string, 2)
+	queueRelay := func(consumer string) {
+		go func() {
+			release, err := rateLimiter.TryAcquire(ctx, consumer, testCu, false)
+			if err == nil {
+				served <- consumer
+				release()
+			}
+		}()
+	}
+	// the low priority relay queues first
+	queueRelay(testConsumer)
+	require.Eventually(t, func() bool { return queuedRelaysCount(rateLimiter.queue) == 1 }, time.Second, time.Millisecond)
+	queueRelay(testOtherConsumer)
+	require.Eventually(t, func() bool { return queuedRelaysCount(rateLimiter.queue) == 2 }, time.Second, time.Millisecond)
+	// subscriptions don't queue
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, true)
+	require.Nil(t, err)
+
+	release()
+	require.Equal(t, testOtherConsumer, <-served)
+	require.Equal(t, testConsumer, <-served)
+}
+
+func TestRateLimiterQueueTimeout(t *testing.T) {
+	rateLimiter, now := createTestRateLimiter(lavasession.RateLimit{CuPerSecond: 2 * testCu, ConcurrentRelays: 1})
+	rateLimiter.queue.maxConcurrent = 1
+	release, err := rateLimiter.TryAcquire(context.Background(), testOtherConsumer, testCu, false)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = rateLimiter.TryAcquire(ctx, testConsumer, testCu, false)
+	require.True(t, lavasession.ConsumerRateLimitedError.Is(err))
+	require.Equal(t, 0, queuedRelaysCount(rateLimiter.queue))
+
+	// the relay that wasn't served doesn't count against the consumer
+	release()
+	*now = now.Add(time.Millisecond)
+	release, err = rateLimiter.TryAcquire(context.Background(), testConsumer, 2*testCu, false)
+	require.Nil(t, err)
+	release()
+}
+
+func queuedRelaysCount(queue *relayQueue) int {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	return len(queue.waiting)
+}
@@ -0,0 +1,106 @@
+package ratelimiter
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+type queuedRelay struct {
+	priority uint64
+	sequence uint64 // relays of the same priority are served in the order they arrived
+	ready    chan struct{}
+	index    int
+}
+
+// queuedRelays is a heap of the waiting relays, the highest priority relay that waited the longest is first
+type queuedRelays []*queuedRelay
+
+func (qr queuedRelays) Len() int { return len(qr) }
+
+func (qr queuedRelays) Less(i, j int) bool {
+	if qr[i].priority != qr[j].priority {
+		return qr[i].priority > qr[j].priority
+	}
+	return qr[i].sequence < qr[j].sequence
+}
+
+func (qr queuedRelays) Swap(i, j int) {
+	qr[i], qr[j] = qr[j], qr[i]
+	qr[i].index = i
+	qr[j].index = j
+}
+
+func (qr *queuedRelays) Push(x interface{}) {
+	relay := x.(*queuedRelay)
+	relay.index = len(*qr)
+	*qr = append(*qr, relay)
+}
+
+func (qr *queuedRelays) Pop() interface{} {
+	old := *qr
+	relay := old[len(old)-1]
+	old[len(old)-1] = nil
+	relay.index = -1
+	*qr = old[:len(old)-1]
+	return relay
+}
+
+// relayQueue bounds the relays the endpoint serves at once, relays over the bound wait for a free slot by the priority of their consumer
+type relayQueue struct {
+	lock          sync.Mutex
+	maxConcurrent uint64 // 0 for no bound
+	active        uint64
+	nextSequence  uint64
+	waiting       queuedRelays
+}
+
+// acquire blocks until the relay gets a slot or ctx is done, release must be called once the relay is done
+func (rq *relayQueue) acquire(ctx context.Context, priority uint64) (release func(), err error) {
+	rq.lock.Lock()
+	if rq.maxConcurrent == 0 || (rq.active < rq.maxConcurrent && len(rq.waiting) == 0) {
+		rq.active++
+		rq.lock.Unlock()
+		return rq.releaseFunc(), nil
+	}
+	relay := &queuedRelay{priority: priority, sequence: rq.nextSequence, ready: make(chan struct{})}
+	rq.nextSequence++
+	heap.Push(&rq.waiting, relay)
+	rq.lock.Unlock()
+
+	select {
+	case <-relay.ready:
+		return rq.releaseFunc(), nil
+	case <-ctx.Done():
+		rq.lock.Lock()
+		defer rq.lock.Unlock()
+		if relay.index < 0 {
+			// the slot was handed to the relay just as it gave up, pass it on
+			rq.releaseSlot()
+		} else {
+			heap.Remove(&rq.waiting, relay.index)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (rq *relayQueue) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			rq.lock.Lock()
+			defer rq.lock.Unlock()
+			rq.releaseSlot()
+		})
+	}
+}
+
+// hands the slot to the first waiting relay, must be called with the lock held
+func (rq *relayQueue) releaseSlot() {
+	if len(rq.waiting) > 0 {
+		relay := heap.Pop(&rq.waiting).(*queuedRelay)
+		close(relay.ready)
+		return
+	}
+	rq.active--
+}
@@ -1,9 +1,73 @@
 package reliabilitymanager
 
-import "github.com/lavanet/lava/protocol/chaintracker"
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/protocol/chaintracker"
+	"github.com/lavanet/lava/relayer/sigs"
+	"github.com/lavanet/lava/utils"
+	conflicttypes "github.com/lavanet/lava/x/conflict/types"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	RevealRetries       = 3
+	RevealRetryInterval = 5 * time.Second
+)
+
+type VoteParams struct {
+	CloseVote      bool
+	ChainID        string // set only on new votes
+	ApiURL         string
+	RequestData    []byte
+	RequestBlock   uint64
+	Voters         []string
+	ConnectionType string
+	VoteDeadline   uint64
+	VoteID         string
+}
+
+func (vp *VoteParams) GetCloseVote() bool {
+	if vp == nil {
+		// default returns false
+		return false
+	}
+	return vp.CloseVote
+}
+
+// IsNewVote returns whether the vote just started, the events of the reveal and close of a vote only have its id
+func (vp *VoteParams) IsNewVote() bool {
+	return !vp.GetCloseVote() && vp.ChainID != ""
+}
+
+type VoteData struct {
+	RelayDataHash []byte
+	Nonce         int64
+	CommitHash    []byte
+}
+
+type VotesStateTracker interface {
+	TxConflictVoteCommit(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error
+	TxConflictVoteReveal(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error
+	GetConflictVote(ctx context.Context, voteID string) (*conflicttypes.ConflictVote, error) // returns nil when the vote doesn't exist
+}
 
 type ReliabilityManager struct {
-	chainTracker *chaintracker.ChainTracker
+	chainTracker    *chaintracker.ChainTracker
+	stateTracker    VotesStateTracker
+	providerAddress string
+	chainProxy      chainlib.ChainProxy
+	chainParser     chainlib.ChainParser
+	votesDB         *VotesDB
+	votesKey        string // votes of this endpoint are kept under its key in the votes db
+	lock            sync.Mutex
+	votes           map[string]*VoteData // key is the vote id
 }
 
 func (rm *ReliabilityManager) GetLatestBlockData(fromBlock int64, toBlock int64, specificBlock int64) (latestBlock int64, requestedHashes []*chaintracker.BlockStore, err error) {
@@ -14,8 +78,148 @@ func (rm *ReliabilityManager) GetLatestBlockNum() int64 {
 	return rm.chainTracker.GetLatestBlockNum()
 }
 
-func NewReliabilityManager(chainTracker *chaintracker.ChainTracker) *ReliabilityManager {
-	rm := &ReliabilityManager{}
-	rm.chainTracker = chainTracker
-	return rm
+// VoteHandler commits to the response of our node on a new vote we were chosen for, reveals the commitment when the vote moves to its reveal state
+// and clears the vote when it closes. votes of other endpoints return an error so they can be handled by the right one
+func (rm *ReliabilityManager) VoteHandler(voteParams *VoteParams, nodeHeight uint64) error {
+	ctx := context.Background()
+	voteID := voteParams.VoteID
+	if voteParams.GetCloseVote() {
+		rm.lock.Lock()
+		defer rm.lock.Unlock()
+		if _, ok := rm.votes[voteID]; ok {
+			utils.LavaFormatInfo("Received Vote termination event for vote, cleared entry", &map[string]string{"voteID": voteID})
+			rm.deleteVote(voteID)
+		}
+		return nil
+	}
+	if voteParams.VoteDeadline < nodeHeight {
+		// its too late to vote
+		return utils.LavaFormatError("Vote Event received but it's too late to vote", nil,
+			&map[string]string{"deadline": strconv.FormatUint(voteParams.VoteDeadline, 10), "nodeHeight": strconv.FormatUint(nodeHeight, 10), "voteID": voteID})
+	}
+	if !voteParams.IsNewVote() {
+		rm.lock.Lock()
+		vote, ok := rm.votes[voteID]
+		rm.lock.Unlock()
+		if !ok {
+			// we didn't commit on this vote
+			return nil
+		}
+		utils.LavaFormatInfo("Received Vote Reveal for vote, sending Reveal for result", &map[string]string{"voteID": voteID, "voteData": fmt.Sprintf("%+v", vote)})
+		return rm.sendVoteReveal(ctx, voteID, vote)
+	}
+	if !slices.Contains(voteParams.Voters, rm.providerAddress) {
+		utils.LavaFormatInfo("new vote initiated but not for this provider to vote", &map[string]string{"voteID": voteID})
+		return nil
+	}
+	rm.lock.Lock()
+	_, ok := rm.votes[voteID]
+	rm.lock.Unlock()
+	if ok {
+		return utils.LavaFormatError("new vote Request for vote had existing entry", nil, &map[string]string{"voteID": voteID})
+	}
+	// we need to send a commit, first we need to get the response of our node
+	// TODO: verify the requested block is finalized and if its not wait and try again
+	chainMessage, err := rm.chainParser.ParseMsg(voteParams.ApiURL, voteParams.RequestData, voteParams.ConnectionType)
+	if err != nil {
+		return utils.LavaFormatError("vote Request did not pass the api check on chain proxy", err, &map[string]string{"voteID": voteID, "chainID": voteParams.ChainID})
+	}
+	reply, _, _, err := rm.chainProxy.SendNodeMsg(ctx, nil, chainMessage)
+	if err != nil {
+		return utils.LavaFormatError("vote relay send has failed", err, &map[string]string{"ApiURL": voteParams.ApiURL, "RequestData": string(voteParams.RequestData), "voteID": voteID})
+	}
+	nonce := rand.Int63()
+	replyDataHash := sigs.HashMsg(reply.Data)
+	vote := &VoteData{RelayDataHash: replyDataHash, Nonce: nonce, CommitHash: conflicttypes.CommitVoteData(nonce, replyDataHash)}
+	rm.lock.Lock()
+	// the nonce is saved before the commitment is sent, without it we can't reveal and we are punished for not voting
+	rm.saveVote(voteID, vote)
+	rm.lock.Unlock()
+	utils.LavaFormatInfo("Received Vote start, sending commitment for result", &map[string]string{"voteID": voteID, "voteData": fmt.Sprintf("%+v", vote)})
+	err = rm.stateTracker.TxConflictVoteCommit(ctx, voteID, nonce, replyDataHash)
+	if err != nil {
+		return utils.LavaFormatError("failed to send vote commitment", err, &map[string]string{"voteID": voteID})
+	}
+	return nil
+}
+
+// reveal transactions are retried, a provider that committed and didn't reveal is punished like a provider that didn't vote
+func (rm *ReliabilityManager) sendVoteReveal(ctx context.Context, voteID string, vote *VoteData) (err error) {
+	for attempt := 0; attempt < RevealRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(RevealRetryInterval)
+		}
+		err = rm.stateTracker.TxConflictVoteReveal(ctx, voteID, vote.Nonce, vote.RelayDataHash)
+		if err == nil {
+			return nil
+		}
+	}
+	return utils.LavaFormatError("failed to send vote Reveal", err, &map[string]string{"voteID": voteID, "attempts": strconv.Itoa(RevealRetries)})
+}
+
+func (rm *ReliabilityManager) saveVote(voteID string, vote *VoteData) {
+	rm.votes[voteID] = vote
+	err := rm.votesDB.Save(rm.votesKey, voteID, vote)
+	if err != nil {
+		utils.LavaFormatError("failed saving vote to the votes db", err, &map[string]string{"voteID": voteID})
+	}
+}
+
+func (rm *ReliabilityManager) deleteVote(voteID string) {
+	delete(rm.votes, voteID)
+	err := rm.votesDB.Delete(rm.votesKey, voteID)
+	if err != nil {
+		utils.LavaFormatError("failed deleting vote from the votes db", err, &map[string]string{"voteID": voteID})
+	}
+}
+
+// restoreVotes reads the votes we committed on before a restart, a vote that moved to its reveal state while we were down is revealed now
+func (rm *ReliabilityManager) restoreVotes(ctx context.Context) error {
+	votes, err := rm.votesDB.ReadAll(rm.votesKey)
+	if err != nil {
+		return err
+	}
+	rm.lock.Lock()
+	defer rm.lock.Unlock()
+	for voteID, vote := range votes {
+		rm.votes[voteID] = vote
+		go rm.restoreVote(ctx, voteID, vote)
+	}
+	return nil
+}
+
+func (rm *ReliabilityManager) restoreVote(ctx context.Context, voteID string, vote *VoteData) {
+	conflictVote, err := rm.stateTracker.GetConflictVote(ctx, voteID)
+	if err != nil {
+		utils.LavaFormatError("failed querying a restored vote, waiting for its events", err, &map[string]string{"voteID": voteID})
+		return
+	}
+	if conflictVote == nil {
+		// the vote was closed while we were down
+		rm.lock.Lock()
+		rm.deleteVote(voteID)
+		rm.lock.Unlock()
+		return
+	}
+	if conflictVote.VoteState == conflicttypes.StateReveal {
+		rm.sendVoteReveal(ctx, voteID, vote)
+	}
+}
+
+func NewReliabilityManager(ctx context.Context, chainTracker *chaintracker.ChainTracker, stateTracker VotesStateTracker, providerAddress string, chainProxy chainlib.ChainProxy, chainParser chainlib.ChainParser, votesDB *VotesDB, votesKey string) (*ReliabilityManager, error) {
+	rm := &ReliabilityManager{
+		chainTracker:    chainTracker,
+		stateTracker:    stateTracker,
+		providerAddress: providerAddress,
+		chainProxy:      chainProxy,
+		chainParser:     chainParser,
+		votesDB:         votesDB,
+		votesKey:        votesKey,
+		votes:           map[string]*VoteData{},
+	}
+	err := rm.restoreVotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rm, nil
 }
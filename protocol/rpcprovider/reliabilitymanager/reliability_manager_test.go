@@ -0,0 +1,161 @@
+package reliabilitymanager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/protocol/chainlib/chainproxy/rpcclient"
+	"github.com/lavanet/lava/relayer/sigs"
+	conflicttypes "github.com/lavanet/lava/x/conflict/types"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	testChainID   = "LAV1"
+	testProvider  = "provider"
+	testVoteID    = "1"
+	testNodeReply = `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+	testDeadline  = 100
+)
+
+type mockVotesStateTracker struct {
+	lock          sync.Mutex
+	commits       map[string][]byte // key is the vote id, value is the commit hash
+	reveals       map[string]*VoteData
+	conflictVotes map[string]*conflicttypes.ConflictVote
+}
+
+func newMockVotesStateTracker() *mockVotesStateTracker {
+	return &mockVotesStateTracker{commits: map[string][]byte{}, reveals: map[string]*VoteData{}, conflictVotes: map[string]*conflicttypes.ConflictVote{}}
+}
+
+func (mst *mockVotesStateTracker) TxConflictVoteCommit(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error {
+	mst.lock.Lock()
+	defer mst.lock.Unlock()
+	mst.commits[voteID] = conflicttypes.CommitVoteData(nonce, replyDataHash)
+	return nil
+}
+
+func (mst *mockVotesStateTracker) TxConflictVoteReveal(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error {
+	mst.lock.Lock()
+	defer mst.lock.Unlock()
+	mst.reveals[voteID] = &VoteData{RelayDataHash: replyDataHash, Nonce: nonce}
+	return nil
+}
+
+func (mst *mockVotesStateTracker) GetConflictVote(ctx context.Context, voteID string) (*conflicttypes.ConflictVote, error) {
+	mst.lock.Lock()
+	defer mst.lock.Unlock()
+	return mst.conflictVotes[voteID], nil
+}
+
+func (mst *mockVotesStateTracker) getReveal(voteID string) *VoteData {
+	mst.lock.Lock()
+	defer mst.lock.Unlock()
+	return mst.reveals[voteID]
+}
+
+type mockChainProxy struct{}
+
+func (mcp *mockChainProxy) SendNodeMsg(ctx context.Context, ch chan interface{}, chainMessage chainlib.ChainMessage) (relayReply *pairingtypes.RelayReply, subscriptionID string, relayReplyServer *rpcclient.ClientSubscription, err error) {
+	return &pairingtypes.RelayReply{Data: []byte(testNodeReply)}, "", nil, nil
+}
+
+func createTestReliabilityManager(t *testing.T, stateTracker VotesStateTracker, votesDB *VotesDB) *ReliabilityManager {
+	chainParser, err := chainlib.NewChainParser(spectypes.APIInterfaceJsonRPC)
+	require.Nil(t, err)
+	chainParser.SetSpec(spectypes.Spec{
+		Index:            testChainID,
+		Enabled:          true,
+		AverageBlockTime: 1000,
+		Apis: []spectypes.ServiceApi{{
+			Name:          "eth_blockNumber",
+			Enabled:       true,
+			ComputeUnits:  10,
+			BlockParsing:  spectypes.BlockParser{ParserFunc: spectypes.PARSER_FUNC_DEFAULT, ParserArg: []string{"latest"}},
+			ApiInterfaces: []spectypes.ApiInterface{{Interface: spectypes.APIInterfaceJsonRPC, Type: "POST"}},
+		}},
+	})
+	rm, err := NewReliabilityManager(context.Background(), nil, stateTracker, testProvider, &mockChainProxy{}, chainParser, votesDB, testChainID+spectypes.APIInterfaceJsonRPC)
+	require.Nil(t, err)
+	return rm
+}
+
+func createTestVoteParams(voters []string) *VoteParams {
+	return &VoteParams{
+		ChainID:        testChainID,
+		RequestData:    []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`),
+		Voters:         voters,
+		ConnectionType: "POST",
+		VoteDeadline:   testDeadline,
+		VoteID:         testVoteID,
+	}
+}
+
+func TestVoteCommitAndReveal(t *testing.T) {
+	stateTracker := newMockVotesStateTracker()
+	votesDB := NewVotesDB(dbm.NewMemDB())
+	rm := createTestReliabilityManager(t, stateTracker, votesDB)
+	require.Nil(t, rm.VoteHandler(createTestVoteParams([]string{"other", testProvider}), testDeadline-10))
+	commitHash, ok := stateTracker.commits[testVoteID]
+	require.True(t, ok)
+
+	// the nonce is saved so the vote can be revealed after a restart
+	storedVotes, err := votesDB.ReadAll(rm.votesKey)
+	require.Nil(t, err)
+	require.Contains(t, storedVotes, testVoteID)
+
+	require.Nil(t, rm.VoteHandler(&VoteParams{VoteID: testVoteID, VoteDeadline: testDeadline + 20}, testDeadline+1))
+	reveal := stateTracker.getReveal(testVoteID)
+	require.NotNil(t, reveal)
+	require.Equal(t, sigs.HashMsg([]byte(testNodeReply)), reveal.RelayDataHash)
+	require.Equal(t, commitHash, conflicttypes.CommitVoteData(reveal.Nonce, reveal.RelayDataHash))
+
+	require.Nil(t, rm.VoteHandler(&VoteParams{VoteID: testVoteID, CloseVote: true}, testDeadline+21))
+	storedVotes, err = votesDB.ReadAll(rm.votesKey)
+	require.Nil(t, err)
+	require.Len(t, storedVotes, 0)
+}
+
+func TestVoteNotForProvider(t *testing.T) {
+	stateTracker := newMockVotesStateTracker()
+	rm := createTestReliabilityManager(t, stateTracker, NewVotesDB(dbm.NewMemDB()))
+	require.Nil(t, rm.VoteHandler(createTestVoteParams([]string{"other"}), testDeadline-10))
+	require.Len(t, stateTracker.commits, 0)
+	// a reveal of a vote we didn't commit on is ignored
+	require.Nil(t, rm.VoteHandler(&VoteParams{VoteID: testVoteID, VoteDeadline: testDeadline + 20}, testDeadline+1))
+	require.Nil(t, stateTracker.getReveal(testVoteID))
+}
+
+func TestVoteTooLate(t *testing.T) {
+	stateTracker := newMockVotesStateTracker()
+	rm := createTestReliabilityManager(t, stateTracker, NewVotesDB(dbm.NewMemDB()))
+	require.NotNil(t, rm.VoteHandler(createTestVoteParams([]string{testProvider}), testDeadline+1))
+	require.Len(t, stateTracker.commits, 0)
+}
+
+func TestVoteRevealedAfterRestart(t *testing.T) {
+	stateTracker := newMockVotesStateTracker()
+	votesDB := NewVotesDB(dbm.NewMemDB())
+	rm := createTestReliabilityManager(t, stateTracker, votesDB)
+	require.Nil(t, rm.VoteHandler(createTestVoteParams([]string{testProvider}), testDeadline-10))
+	otherVoteParams := createTestVoteParams([]string{testProvider})
+	otherVoteParams.VoteID = "2"
+	require.Nil(t, rm.VoteHandler(otherVoteParams, testDeadline-10))
+
+	// the first vote moved to its reveal state while the provider was down and the second one closed
+	stateTracker.conflictVotes[testVoteID] = &conflicttypes.ConflictVote{Index: testVoteID, VoteState: conflicttypes.StateReveal}
+	createTestReliabilityManager(t, stateTracker, votesDB)
+	require.Eventually(t, func() bool { return stateTracker.getReveal(testVoteID) != nil }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		storedVotes, err := votesDB.ReadAll(rm.votesKey)
+		return err == nil && len(storedVotes) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Nil(t, stateTracker.getReveal("2"))
+}
@@ -0,0 +1,83 @@
+package reliabilitymanager
+
+import (
+	"encoding/json"
+
+	"github.com/lavanet/lava/utils"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const VotesDBName = "provider_votes"
+
+// VotesDB persists the nonces of the vote commitments so they can be revealed after a restart.
+// keys are the endpoint key and the vote id
+type VotesDB struct {
+	db dbm.DB
+}
+
+func NewVotesDB(db dbm.DB) *VotesDB {
+	return &VotesDB{db: db}
+}
+
+// NewLevelDBVotesDB opens the votes db in dbDir, creating it if it doesn't exist
+func NewLevelDBVotesDB(dbDir string) (*VotesDB, error) {
+	db, err := dbm.NewGoLevelDB(VotesDBName, dbDir)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed opening the votes db", err, &map[string]string{"dir": dbDir})
+	}
+	return NewVotesDB(db), nil
+}
+
+func votesPrefix(key string) []byte {
+	return []byte(key + "/")
+}
+
+func voteKey(key string, voteID string) []byte {
+	return append(votesPrefix(key), []byte(voteID)...)
+}
+
+// Save writes the vote synced to disk, the commitment is sent only after its nonce is saved
+func (vdb *VotesDB) Save(key string, voteID string, vote *VoteData) error {
+	value, err := json.Marshal(vote)
+	if err != nil {
+		return err
+	}
+	return vdb.db.SetSync(voteKey(key, voteID), value)
+}
+
+func (vdb *VotesDB) Delete(key string, voteID string) error {
+	return vdb.db.Delete(voteKey(key, voteID))
+}
+
+// ReadAll returns the votes saved under the key by vote id, entries that can't be parsed are deleted
+func (vdb *VotesDB) ReadAll(key string) (map[string]*VoteData, error) {
+	prefix := votesPrefix(key)
+	iterator, err := dbm.IteratePrefix(vdb.db, prefix)
+	if err != nil {
+		return nil, err
+	}
+	votes := map[string]*VoteData{}
+	invalidKeys := [][]byte{}
+	for ; iterator.Valid(); iterator.Next() {
+		vote := &VoteData{}
+		err = json.Unmarshal(iterator.Value(), vote)
+		if err != nil {
+			invalidKeys = append(invalidKeys, iterator.Key())
+			continue
+		}
+		votes[string(iterator.Key()[len(prefix):])] = vote
+	}
+	iterator.Close()
+	for _, invalidKey := range invalidKeys {
+		utils.LavaFormatWarning("deleting invalid entry from the votes db", nil, &map[string]string{"key": string(invalidKey)})
+		err = vdb.db.Delete(invalidKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return votes, nil
+}
+
+func (vdb *VotesDB) Close() error {
+	return vdb.db.Close()
+}
@@ -2,28 +2,378 @@ package rewardserver
 
 import (
 	"context"
+	"sort"
+	"strconv"
+	"sync"
 
-	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/utils"
 	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 )
 
+const (
+	RewardServerDescription = "rpcprovider"
+)
+
 type RewardServer struct {
 	rewardsTxSender RewardsTxSender
+	lock            sync.RWMutex
+	rewards         map[uint64]*EpochRewards // key is epoch
+	epochUpdates    chan uint64
+	flushRequests   chan struct{}
+	currentEpoch    uint64 // the latest epoch update, only used by the claiming goroutine
+	rewardsDB       *RewardsDB
+}
+
+// EpochRewards holds the proofs of an epoch until the chain paid for them or they can no longer be claimed
+type EpochRewards struct {
+	epoch           uint64
+	consumerRewards map[string]*ConsumerRewards // key is consumer address
+	claimAttempts   int
+}
+
+type ConsumerRewards struct {
+	epoch                  uint64
+	consumer               string
+	proofs                 map[uint64]*pairingtypes.RelayRequest // key is session id, the proof with the highest cu sum of the session
+	dataReliabilityProofs  []*pairingtypes.VRFData               // not yet claimed with a relay proof
+	claimedDataReliability map[uint64]*pairingtypes.VRFData      // key is the session id of the relay proof claiming it
 }
 
 type RewardsTxSender interface {
-	TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest)
+	TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest, description string) error
+	IsSessionPaid(ctx context.Context, chainID string, consumer string, provider string, sessionID uint64) (bool, error)
+	GetEpochSize(ctx context.Context) (uint64, error)
+	GetRecommendedEpochNumToCollectPayment(ctx context.Context) (uint64, error)
+}
+
+// SendNewProof keeps the proof if it's the latest of its consumer session, the proof of a session with the highest cu sum pays for all its relays.
+// data reliability proofs are kept per consumer and are claimed together with the consumer's relay proofs
+func (rws *RewardServer) SendNewProof(ctx context.Context, proof *pairingtypes.RelayRequest, epoch uint64, consumerAddr string) {
+	rws.lock.Lock()
+	defer rws.lock.Unlock()
+	consumerRewards := rws.getConsumerRewards(epoch, consumerAddr)
+	if proof.DataReliability != nil {
+		if consumerRewards.addDataReliabilityProof(proof.DataReliability) {
+			rws.saveProof(epoch, consumerAddr, proof)
+		}
+		return
+	}
+	existingProof, ok := consumerRewards.proofs[proof.SessionId]
+	if ok && (existingProof.CuSum > proof.CuSum || (existingProof.CuSum == proof.CuSum && existingProof.RelayNum >= proof.RelayNum)) {
+		// relays can finish out of order, an older proof doesn't replace a newer one
+		return
+	}
+	consumerRewards.proofs[proof.SessionId] = proof
+	storedProof := proof
+	if dataReliability, ok := consumerRewards.claimedDataReliability[proof.SessionId]; ok {
+		storedProof = proof.ShallowCopy()
+		storedProof.DataReliability = dataReliability
+	}
+	rws.saveProof(epoch, consumerAddr, storedProof)
+}
+
+func (rws *RewardServer) getConsumerRewards(epoch uint64, consumerAddr string) *ConsumerRewards {
+	epochRewards, ok := rws.rewards[epoch]
+	if !ok {
+		epochRewards = &EpochRewards{epoch: epoch, consumerRewards: map[string]*ConsumerRewards{}}
+		rws.rewards[epoch] = epochRewards
+	}
+	consumerRewards, ok := epochRewards.consumerRewards[consumerAddr]
+	if !ok {
+		consumerRewards = &ConsumerRewards{epoch: epoch, consumer: consumerAddr, proofs: map[uint64]*pairingtypes.RelayRequest{}, claimedDataReliability: map[uint64]*pairingtypes.VRFData{}}
+		epochRewards.consumerRewards[consumerAddr] = consumerRewards
+	}
+	return consumerRewards
+}
+
+func (cr *ConsumerRewards) addDataReliabilityProof(dataReliability *pairingtypes.VRFData) (added bool) {
+	for _, existing := range cr.dataReliabilityProofs {
+		if existing.Differentiator == dataReliability.Differentiator {
+			return false
+		}
+	}
+	for _, existing := range cr.claimedDataReliability {
+		if existing.Differentiator == dataReliability.Differentiator {
+			return false
+		}
+	}
+	cr.dataReliabilityProofs = append(cr.dataReliabilityProofs, dataReliability)
+	return true
+}
+
+// relaysForPayment returns the consumer's proofs, data reliability proofs are set on them since they are paid as an addition to a relay payment.
+// the relays that were set a data reliability proof for the first time are returned in newlyClaimed
+func (cr *ConsumerRewards) relaysForPayment() (relays []*pairingtypes.RelayRequest, newlyClaimed []*pairingtypes.RelayRequest) {
+	sessionIDs := make([]uint64, 0, len(cr.proofs))
+	for sessionID := range cr.proofs {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	sort.Slice(sessionIDs, func(i, j int) bool { return sessionIDs[i] < sessionIDs[j] })
+	relays = make([]*pairingtypes.RelayRequest, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		relay := cr.proofs[sessionID].ShallowCopy()
+		dataReliability, ok := cr.claimedDataReliability[sessionID]
+		if !ok && len(cr.dataReliabilityProofs) > 0 {
+			dataReliability = cr.dataReliabilityProofs[0]
+			cr.dataReliabilityProofs = cr.dataReliabilityProofs[1:]
+			cr.claimedDataReliability[sessionID] = dataReliability
+			newlyClaimed = append(newlyClaimed, relay)
+		}
+		relay.DataReliability = dataReliability
+		relays = append(relays, relay)
+	}
+	if len(cr.dataReliabilityProofs) > 0 {
+		utils.LavaFormatWarning("data reliability proofs without a relay proof to claim them with", nil, &map[string]string{"consumer": cr.consumer, "epoch": strconv.FormatUint(cr.epoch, 10), "unclaimed": strconv.Itoa(len(cr.dataReliabilityProofs))})
+	}
+	return relays, newlyClaimed
+}
+
+// UpdateEpoch claims the rewards of the epochs that ended before the previous one, relays of the previous epoch can still arrive during the epoch overlap
+func (rws *RewardServer) UpdateEpoch(epoch uint64) {
+	select {
+	case rws.epochUpdates <- epoch:
+	default:
+		// still claiming, epochs that weren't claimed are claimed on the next update
+		utils.LavaFormatWarning("reward server is busy claiming rewards, skipping epoch update", nil, &map[string]string{"epoch": strconv.FormatUint(epoch, 10)})
+	}
+}
+
+func (rws *RewardServer) claimRewardsOnEpochUpdates(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case epoch := <-rws.epochUpdates:
+			rws.claimRewards(ctx, epoch)
+		case <-rws.flushRequests:
+			rws.flushRewards(ctx)
+		}
+	}
+}
+
+func (rws *RewardServer) claimRewards(ctx context.Context, currentEpoch uint64) {
+	rws.currentEpoch = currentEpoch
+	epochSize, err := rws.rewardsTxSender.GetEpochSize(ctx)
+	if err != nil {
+		utils.LavaFormatError("failed getting epoch size, claiming rewards on the next epoch", err, &map[string]string{"epoch": strconv.FormatUint(currentEpoch, 10)})
+		return
+	}
+	recommendedEpochNumToCollectPayment, err := rws.rewardsTxSender.GetRecommendedEpochNumToCollectPayment(ctx)
+	if err != nil {
+		utils.LavaFormatError("failed getting the recommended epochs to collect payment, claiming rewards on the next epoch", err, &map[string]string{"epoch": strconv.FormatUint(currentEpoch, 10)})
+		return
+	}
+	if currentEpoch < epochSize {
+		return
+	}
+	previousEpoch := currentEpoch - epochSize
+	earliestClaimableEpoch := uint64(0)
+	if currentEpoch > epochSize*recommendedEpochNumToCollectPayment {
+		earliestClaimableEpoch = currentEpoch - epochSize*recommendedEpochNumToCollectPayment
+	}
+
+	rws.claimEpochs(ctx, rws.epochsToClaim(previousEpoch, earliestClaimableEpoch), &map[string]string{"epoch": strconv.FormatUint(currentEpoch, 10)})
+}
+
+// flushRewards claims the proofs of the epochs before the current one, including the previous epoch still in its overlap. the lava chain
+// is about to halt for an upgrade and the sessions of those epochs are blocked, so their proofs are final and are paid before the halt.
+// the sessions of the current epoch are served again after the chain resumes, their proofs are claimed once the epoch ends
+func (rws *RewardServer) flushRewards(ctx context.Context) {
+	rws.claimEpochs(ctx, rws.epochsToClaim(rws.currentEpoch, 0), &map[string]string{"reason": "upgrade halt", "epoch": strconv.FormatUint(rws.currentEpoch, 10)})
+}
+
+func (rws *RewardServer) claimEpochs(ctx context.Context, epochsToClaim []*EpochRewards, logAttributes *map[string]string) {
+	relays := []*pairingtypes.RelayRequest{}
+	for _, epochRewards := range epochsToClaim {
+		if epochRewards.claimAttempts > 0 {
+			// a previous claim was sent, only the proofs the chain didn't pay for are sent again
+			rws.removePaidProofs(ctx, epochRewards)
+		}
+		epochRelays := rws.relaysForPayment(epochRewards)
+		if len(epochRelays) == 0 {
+			rws.removeEpoch(epochRewards.epoch)
+			continue
+		}
+		relays = append(relays, epochRelays...)
+	}
+	if len(relays) == 0 {
+		return
+	}
+	(*logAttributes)["relays"] = strconv.Itoa(len(relays))
+	utils.LavaFormatInfo("claiming rewards", logAttributes)
+	rws.sendRelayPayments(ctx, relays)
+}
+
+// HaltForUpgrade claims the pending proofs of the epochs before the current one before the lava chain halts for an upgrade,
+// it is registered after the provider sessions so they are halted first and no new proofs of those epochs arrive
+func (rws *RewardServer) HaltForUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	select {
+	case rws.flushRequests <- struct{}{}:
+	default:
+		// a flush is already pending
+	}
+}
+
+func (rws *RewardServer) ResumeAfterUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	// proofs are claimed again on the next epoch updates
+}
+
+// epochsToClaim returns the epochs before the previous epoch and drops the ones too old to be claimed
+func (rws *RewardServer) epochsToClaim(previousEpoch uint64, earliestClaimableEpoch uint64) []*EpochRewards {
+	rws.lock.Lock()
+	defer rws.lock.Unlock()
+	epochsToClaim := []*EpochRewards{}
+	for epoch, epochRewards := range rws.rewards {
+		if epoch >= previousEpoch {
+			continue
+		}
+		if epoch < earliestClaimableEpoch {
+			utils.LavaFormatError("rewards were not paid before the claim window ended, dropping them", nil, &map[string]string{"epoch": strconv.FormatUint(epoch, 10), "consumers": strconv.Itoa(len(epochRewards.consumerRewards)), "claimAttempts": strconv.Itoa(epochRewards.claimAttempts)})
+			delete(rws.rewards, epoch)
+			rws.deleteEpochFromDB(epoch)
+			continue
+		}
+		epochsToClaim = append(epochsToClaim, epochRewards)
+	}
+	return epochsToClaim
+}
+
+func (rws *RewardServer) relaysForPayment(epochRewards *EpochRewards) []*pairingtypes.RelayRequest {
+	rws.lock.Lock()
+	defer rws.lock.Unlock()
+	epochRewards.claimAttempts++
+	relays := []*pairingtypes.RelayRequest{}
+	for consumer, consumerRewards := range epochRewards.consumerRewards {
+		consumerRelays, newlyClaimed := consumerRewards.relaysForPayment()
+		relays = append(relays, consumerRelays...)
+		for _, relay := range newlyClaimed {
+			// the data reliability proof is stored with the relay proof claiming it from now on
+			rws.saveProof(epochRewards.epoch, consumer, relay)
+			rws.deleteProof(epochRewards.epoch, consumer, &pairingtypes.RelayRequest{DataReliability: relay.DataReliability})
+		}
+	}
+	return relays
+}
+
+func (rws *RewardServer) removePaidProofs(ctx context.Context, epochRewards *EpochRewards) {
+	rws.lock.RLock()
+	type sessionProof struct {
+		consumer string
+		proof    *pairingtypes.RelayRequest
+	}
+	sessionProofs := []sessionProof{}
+	for consumer, consumerRewards := range epochRewards.consumerRewards {
+		for _, proof := range consumerRewards.proofs {
+			sessionProofs = append(sessionProofs, sessionProof{consumer: consumer, proof: proof})
+		}
+	}
+	rws.lock.RUnlock()
+
+	for _, sessionProof := range sessionProofs {
+		proof := sessionProof.proof
+		paid, err := rws.rewardsTxSender.IsSessionPaid(ctx, proof.ChainID, sessionProof.consumer, proof.Provider, proof.SessionId)
+		if err != nil || !paid {
+			// a proof we can't check is sent again, the chain rejects a session paid twice
+			continue
+		}
+		rws.lock.Lock()
+		// the data reliability proof claimed with the session was paid with it
+		consumerRewards := epochRewards.consumerRewards[sessionProof.consumer]
+		delete(consumerRewards.proofs, proof.SessionId)
+		delete(consumerRewards.claimedDataReliability, proof.SessionId)
+		rws.deleteProof(epochRewards.epoch, sessionProof.consumer, proof)
+		if len(consumerRewards.proofs) == 0 {
+			delete(epochRewards.consumerRewards, sessionProof.consumer)
+		}
+		rws.lock.Unlock()
+	}
+}
+
+func (rws *RewardServer) removeEpoch(epoch uint64) {
+	rws.lock.Lock()
+	defer rws.lock.Unlock()
+	delete(rws.rewards, epoch)
+	rws.deleteEpochFromDB(epoch)
+}
+
+// the proofs in memory are still claimed when writing them fails, they are lost only if the provider restarts
+func (rws *RewardServer) saveProof(epoch uint64, consumer string, proof *pairingtypes.RelayRequest) {
+	err := rws.rewardsDB.Save(epoch, consumer, proof)
+	if err != nil {
+		utils.LavaFormatError("failed saving proof to the rewards db", err, &map[string]string{"epoch": strconv.FormatUint(epoch, 10), "consumer": consumer, "sessionID": strconv.FormatUint(proof.SessionId, 10)})
+	}
+}
+
+func (rws *RewardServer) deleteProof(epoch uint64, consumer string, proof *pairingtypes.RelayRequest) {
+	err := rws.rewardsDB.Delete(epoch, consumer, proof)
+	if err != nil {
+		utils.LavaFormatError("failed deleting proof from the rewards db", err, &map[string]string{"epoch": strconv.FormatUint(epoch, 10), "consumer": consumer, "sessionID": strconv.FormatUint(proof.SessionId, 10)})
+	}
+}
+
+func (rws *RewardServer) deleteEpochFromDB(epoch uint64) {
+	err := rws.rewardsDB.DeleteEpoch(epoch)
+	if err != nil {
+		utils.LavaFormatError("failed deleting epoch from the rewards db", err, &map[string]string{"epoch": strconv.FormatUint(epoch, 10)})
+	}
+}
+
+// restoreRewards reads the proofs saved before a restart, the chain may have paid for some of them already
+// so they are checked before they are claimed
+func (rws *RewardServer) restoreRewards() error {
+	storedProofs, err := rws.rewardsDB.ReadAll()
+	if err != nil {
+		return err
+	}
+	rws.lock.Lock()
+	defer rws.lock.Unlock()
+	for _, storedProof := range storedProofs {
+		proof := storedProof.Proof
+		consumerRewards := rws.getConsumerRewards(storedProof.Epoch, storedProof.Consumer)
+		switch {
+		case proof.SessionId == 0 && proof.DataReliability != nil:
+			consumerRewards.addDataReliabilityProof(proof.DataReliability)
+		case proof.DataReliability != nil:
+			// a relay proof stored with the data reliability proof it claims
+			relayProof := proof.ShallowCopy()
+			relayProof.DataReliability = nil
+			consumerRewards.proofs[proof.SessionId] = relayProof
+			consumerRewards.claimedDataReliability[proof.SessionId] = proof.DataReliability
+		default:
+			consumerRewards.proofs[proof.SessionId] = proof
+		}
+	}
+	for _, epochRewards := range rws.rewards {
+		epochRewards.claimAttempts = 1
+	}
+	if len(storedProofs) > 0 {
+		utils.LavaFormatInfo("restored proofs from the rewards db", &map[string]string{"proofs": strconv.Itoa(len(storedProofs)), "epochs": strconv.Itoa(len(rws.rewards))})
+	}
+	return nil
 }
 
-func (rws *RewardServer) SendNewProof(ctx context.Context, singleProviderSession *lavasession.SingleProviderSession, epoch uint64, consumerAddr string) {
-	// TODO: implement
-	// get the proof for this consumer for this epoch for this session, update the latest proof
-	// write to a channel the epoch
+// sendRelayPayments sends the relays in one tx, a tx that can't fit in a block or fails is split in two, so a
+// single proof the chain rejects doesn't fail the payment of the rest. failed proofs are sent again on the next epoch
+func (rws *RewardServer) sendRelayPayments(ctx context.Context, relays []*pairingtypes.RelayRequest) {
+	err := rws.rewardsTxSender.TxRelayPayment(ctx, relays, RewardServerDescription)
+	if err == nil {
+		return
+	}
+	if len(relays) == 1 {
+		utils.LavaFormatError("failed sending relay payment, retrying on the next epoch", err, &map[string]string{"chainID": relays[0].ChainID, "epoch": strconv.FormatInt(relays[0].BlockHeight, 10), "sessionID": strconv.FormatUint(relays[0].SessionId, 10)})
+		return
+	}
+	rws.sendRelayPayments(ctx, relays[:len(relays)/2])
+	rws.sendRelayPayments(ctx, relays[len(relays)/2:])
 }
 
-func NewRewardServer(rewardsTxSender RewardsTxSender) *RewardServer {
-	//
-	rws := &RewardServer{}
+func NewRewardServer(ctx context.Context, rewardsTxSender RewardsTxSender, rewardsDB *RewardsDB) (*RewardServer, error) {
+	rws := &RewardServer{rewards: map[uint64]*EpochRewards{}, epochUpdates: make(chan uint64, 1), flushRequests: make(chan struct{}, 1), rewardsDB: rewardsDB}
 	rws.rewardsTxSender = rewardsTxSender
-	return rws
+	err := rws.restoreRewards()
+	if err != nil {
+		return nil, err
+	}
+	go rws.claimRewardsOnEpochUpdates(ctx)
+	return rws, nil
 }
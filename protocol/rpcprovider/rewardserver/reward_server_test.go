@@ -0,0 +1,289 @@
+package rewardserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	testEpochSize   = 20
+	testRecommended = 3
+	testProvider    = "provider"
+)
+
+type mockRewardsTxSender struct {
+	maxRelaysInTx  int
+	failedSessions map[uint64]bool // relays of these sessions fail the tx
+	paidSessions   map[string]bool
+	txs            [][]*pairingtypes.RelayRequest
+}
+
+func newMockRewardsTxSender() *mockRewardsTxSender {
+	return &mockRewardsTxSender{maxRelaysInTx: 100, failedSessions: map[uint64]bool{}, paidSessions: map[string]bool{}}
+}
+
+func paidSessionKey(consumer string, sessionID uint64) string {
+	return fmt.Sprintf("%s-%d", consumer, sessionID)
+}
+
+func (mts *mockRewardsTxSender) TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest, description string) error {
+	if len(relayRequests) > mts.maxRelaysInTx {
+		return fmt.Errorf("tx exceeds the block gas limit")
+	}
+	for _, relay := range relayRequests {
+		if mts.failedSessions[relay.SessionId] {
+			return fmt.Errorf("invalid proof")
+		}
+	}
+	mts.txs = append(mts.txs, relayRequests)
+	for _, relay := range relayRequests {
+		// the relay chain id is the consumer in these tests
+		mts.paidSessions[paidSessionKey(relay.ChainID, relay.SessionId)] = true
+	}
+	return nil
+}
+
+func (mts *mockRewardsTxSender) IsSessionPaid(ctx context.Context, chainID string, consumer string, provider string, sessionID uint64) (bool, error) {
+	return mts.paidSessions[paidSessionKey(consumer, sessionID)], nil
+}
+
+func (mts *mockRewardsTxSender) GetEpochSize(ctx context.Context) (uint64, error) {
+	return testEpochSize, nil
+}
+
+func (mts *mockRewardsTxSender) GetRecommendedEpochNumToCollectPayment(ctx context.Context) (uint64, error) {
+	return testRecommended, nil
+}
+
+func (mts *mockRewardsTxSender) sentRelays() []*pairingtypes.RelayRequest {
+	relays := []*pairingtypes.RelayRequest{}
+	for _, tx := range mts.txs {
+		relays = append(relays, tx...)
+	}
+	return relays
+}
+
+func newTestProof(consumer string, epoch uint64, sessionID uint64, cuSum uint64, relayNum uint64) *pairingtypes.RelayRequest {
+	return &pairingtypes.RelayRequest{ChainID: consumer, Provider: testProvider, BlockHeight: int64(epoch), SessionId: sessionID, CuSum: cuSum, RelayNum: relayNum}
+}
+
+func newTestRewardServer(t *testing.T) (*RewardServer, *mockRewardsTxSender) {
+	txSender := newMockRewardsTxSender()
+	return newTestRewardServerWithDB(t, txSender, NewRewardsDB(dbm.NewMemDB())), txSender
+}
+
+func newTestRewardServerWithDB(t *testing.T, txSender *mockRewardsTxSender, rewardsDB *RewardsDB) *RewardServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	rws, err := NewRewardServer(ctx, txSender, rewardsDB)
+	require.Nil(t, err)
+	return rws
+}
+
+func TestSendNewProofKeepsLatestProof(t *testing.T) {
+	ctx := context.Background()
+	rws, txSender := newTestRewardServer(t)
+	epoch := uint64(100)
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 30, 3), epoch, "consumer")
+	// finished after the newer relay
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 20, 2), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 2, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("other", epoch, 1, 5, 1), epoch, "other")
+
+	// epochs are claimed once the next epoch ended
+	rws.claimRewards(ctx, epoch+testEpochSize)
+	require.Len(t, txSender.txs, 0)
+	rws.claimRewards(ctx, epoch+2*testEpochSize)
+	require.Len(t, txSender.txs, 1)
+	cuSums := map[string]uint64{}
+	for _, relay := range txSender.sentRelays() {
+		cuSums[paidSessionKey(relay.ChainID, relay.SessionId)] = relay.CuSum
+	}
+	require.Equal(t, map[string]uint64{"consumer-1": 30, "consumer-2": 10, "other-1": 5}, cuSums)
+
+	// paid proofs are dropped
+	rws.claimRewards(ctx, epoch+3*testEpochSize)
+	require.Len(t, txSender.txs, 1)
+	require.Len(t, rws.rewards, 0)
+}
+
+func TestDataReliabilityProofsClaimedWithRelayProofs(t *testing.T) {
+	ctx := context.Background()
+	rws, txSender := newTestRewardServer(t)
+	epoch := uint64(100)
+	dataReliabilityProof := newTestProof("consumer", epoch, 0, 0, 0)
+	dataReliabilityProof.DataReliability = &pairingtypes.VRFData{Differentiator: true}
+	rws.SendNewProof(ctx, dataReliabilityProof, epoch, "consumer")
+	// the same data reliability proof twice is claimed once
+	rws.SendNewProof(ctx, dataReliabilityProof, epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 2, 10, 1), epoch, "consumer")
+
+	rws.claimRewards(ctx, epoch+2*testEpochSize)
+	relays := txSender.sentRelays()
+	require.Len(t, relays, 2)
+	withDataReliability := 0
+	for _, relay := range relays {
+		require.NotZero(t, relay.SessionId)
+		if relay.DataReliability != nil {
+			withDataReliability++
+		}
+	}
+	require.Equal(t, 1, withDataReliability)
+}
+
+func TestRelayPaymentsSplitAndRetried(t *testing.T) {
+	ctx := context.Background()
+	rws, txSender := newTestRewardServer(t)
+	epoch := uint64(100)
+	for sessionID := uint64(1); sessionID <= 10; sessionID++ {
+		rws.SendNewProof(ctx, newTestProof("consumer", epoch, sessionID, 10, 1), epoch, "consumer")
+	}
+	txSender.maxRelaysInTx = 3
+	txSender.failedSessions[7] = true
+
+	rws.claimRewards(ctx, epoch+2*testEpochSize)
+	for _, tx := range txSender.txs {
+		require.LessOrEqual(t, len(tx), 3)
+	}
+	require.Len(t, txSender.sentRelays(), 9)
+
+	// only the unpaid proof is sent again
+	delete(txSender.failedSessions, 7)
+	txSender.txs = nil
+	rws.claimRewards(ctx, epoch+3*testEpochSize)
+	relays := txSender.sentRelays()
+	require.Len(t, relays, 1)
+	require.Equal(t, uint64(7), relays[0].SessionId)
+}
+
+func TestFlushRewardsBeforeUpgradeHalt(t *testing.T) {
+	ctx := context.Background()
+	rws, txSender := newTestRewardServer(t)
+	previousEpoch := uint64(100)
+	currentEpoch := previousEpoch + testEpochSize
+	rws.SendNewProof(ctx, newTestProof("consumer", previousEpoch, 1, 10, 1), previousEpoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", currentEpoch, 2, 20, 1), currentEpoch, "consumer")
+	rws.claimRewards(ctx, currentEpoch)
+	require.Len(t, txSender.txs, 0)
+
+	// the previous epoch is claimed though it's in its overlap, the chain halts before it would be
+	rws.flushRewards(ctx)
+	require.Len(t, txSender.sentRelays(), 1)
+	require.Equal(t, uint64(1), txSender.sentRelays()[0].SessionId)
+
+	// the upgraded chain resumed, the session of the current epoch served more relays and is paid for all of them
+	rws.SendNewProof(ctx, newTestProof("consumer", currentEpoch, 2, 40, 2), currentEpoch, "consumer")
+	txSender.txs = nil
+	rws.claimRewards(ctx, currentEpoch+2*testEpochSize)
+	require.Len(t, txSender.sentRelays(), 1)
+	require.Equal(t, uint64(2), txSender.sentRelays()[0].SessionId)
+	require.Equal(t, uint64(40), txSender.sentRelays()[0].CuSum)
+
+	// the paid proofs aren't sent again
+	txSender.txs = nil
+	rws.claimRewards(ctx, currentEpoch+3*testEpochSize)
+	require.Len(t, txSender.txs, 0)
+	require.Len(t, rws.rewards, 0)
+}
+
+func TestExpiredRewardsDropped(t *testing.T) {
+	ctx := context.Background()
+	rws, txSender := newTestRewardServer(t)
+	epoch := uint64(100)
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 10, 1), epoch, "consumer")
+	txSender.failedSessions[1] = true
+
+	rws.claimRewards(ctx, epoch+2*testEpochSize)
+	rws.claimRewards(ctx, epoch+3*testEpochSize)
+	require.Len(t, rws.rewards, 1)
+	// past the recommended epochs to collect payment
+	delete(txSender.failedSessions, 1)
+	rws.claimRewards(ctx, epoch+(testRecommended+1)*testEpochSize)
+	require.Len(t, txSender.txs, 0)
+	require.Len(t, rws.rewards, 0)
+}
+
+func TestProofsRestoredAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	rewardsDB := NewRewardsDB(dbm.NewMemDB())
+	txSender := newMockRewardsTxSender()
+	rws := newTestRewardServerWithDB(t, txSender, rewardsDB)
+	epoch := uint64(100)
+	dataReliabilityProof := newTestProof("consumer", epoch, 0, 0, 0)
+	dataReliabilityProof.DataReliability = &pairingtypes.VRFData{Differentiator: true}
+	rws.SendNewProof(ctx, dataReliabilityProof, epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 20, 2), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 2, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("other", epoch+testEpochSize, 1, 5, 1), epoch+testEpochSize, "other")
+
+	// the first claim fails, the data reliability proof is now stored with the session claiming it
+	txSender.failedSessions[1] = true
+	txSender.failedSessions[2] = true
+	rws.claimRewards(ctx, epoch+2*testEpochSize)
+	require.Len(t, txSender.txs, 0)
+
+	restarted := newTestRewardServerWithDB(t, txSender, rewardsDB)
+	txSender.failedSessions = map[uint64]bool{}
+	restarted.claimRewards(ctx, epoch+3*testEpochSize)
+	relays := txSender.sentRelays()
+	require.Len(t, relays, 3)
+	withDataReliability := 0
+	for _, relay := range relays {
+		if relay.ChainID == "consumer" && relay.SessionId == 1 {
+			require.Equal(t, uint64(20), relay.CuSum)
+		}
+		if relay.DataReliability != nil {
+			withDataReliability++
+		}
+	}
+	require.Equal(t, 1, withDataReliability)
+
+	// paid proofs are removed from the db
+	restarted.claimRewards(ctx, epoch+4*testEpochSize)
+	storedProofs, err := rewardsDB.ReadAll()
+	require.Nil(t, err)
+	require.Len(t, storedProofs, 0)
+}
+
+func TestPaidProofsNotClaimedAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	rewardsDB := NewRewardsDB(dbm.NewMemDB())
+	txSender := newMockRewardsTxSender()
+	rws := newTestRewardServerWithDB(t, txSender, rewardsDB)
+	epoch := uint64(100)
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 2, 10, 1), epoch, "consumer")
+	// the provider stopped after the payment was sent but before the proofs were removed
+	txSender.paidSessions[paidSessionKey("consumer", 1)] = true
+
+	restarted := newTestRewardServerWithDB(t, txSender, rewardsDB)
+	restarted.claimRewards(ctx, epoch+2*testEpochSize)
+	relays := txSender.sentRelays()
+	require.Len(t, relays, 1)
+	require.Equal(t, uint64(2), relays[0].SessionId)
+}
+
+func TestExpiredProofsDeletedFromDB(t *testing.T) {
+	ctx := context.Background()
+	rewardsDB := NewRewardsDB(dbm.NewMemDB())
+	txSender := newMockRewardsTxSender()
+	rws := newTestRewardServerWithDB(t, txSender, rewardsDB)
+	epoch := uint64(100)
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch, 1, 10, 1), epoch, "consumer")
+	rws.SendNewProof(ctx, newTestProof("consumer", epoch+testEpochSize, 1, 10, 1), epoch+testEpochSize, "consumer")
+	txSender.failedSessions[1] = true
+
+	rws.claimRewards(ctx, epoch+(testRecommended+1)*testEpochSize)
+	storedProofs, err := rewardsDB.ReadAll()
+	require.Nil(t, err)
+	require.Len(t, storedProofs, 1)
+	require.Equal(t, epoch+testEpochSize, storedProofs[0].Epoch)
+}
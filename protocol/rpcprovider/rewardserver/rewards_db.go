@@ -0,0 +1,157 @@
+package rewardserver
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	RewardsDBDirFlag = "rewards-db-dir"
+	RewardsDBName    = "provider_rewards"
+)
+
+// RewardsDB persists the latest proof of every consumer session so proofs that weren't paid for survive a restart.
+// keys are the epoch, the consumer and the session id, data reliability proofs are kept under session id 0 with
+// their differentiator, since the consumer can send one of each per epoch
+type RewardsDB struct {
+	db dbm.DB
+}
+
+// StoredProof is a proof read back from the db
+type StoredProof struct {
+	Epoch    uint64
+	Consumer string
+	Proof    *pairingtypes.RelayRequest
+}
+
+func NewRewardsDB(db dbm.DB) *RewardsDB {
+	return &RewardsDB{db: db}
+}
+
+// NewLevelDBRewardsDB opens the rewards db in dbDir, creating it if it doesn't exist
+func NewLevelDBRewardsDB(dbDir string) (*RewardsDB, error) {
+	db, err := dbm.NewGoLevelDB(RewardsDBName, dbDir)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed opening the rewards db", err, &map[string]string{"dir": dbDir})
+	}
+	return NewRewardsDB(db), nil
+}
+
+func uint64ToBytes(value uint64) []byte {
+	bytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(bytes, value)
+	return bytes
+}
+
+func epochPrefix(epoch uint64) []byte {
+	return uint64ToBytes(epoch)
+}
+
+func consumerPrefix(epoch uint64, consumer string) []byte {
+	key := append(epochPrefix(epoch), byte(len(consumer)))
+	return append(key, []byte(consumer)...)
+}
+
+func proofKey(epoch uint64, consumer string, proof *pairingtypes.RelayRequest) []byte {
+	key := append(consumerPrefix(epoch, consumer), uint64ToBytes(proof.SessionId)...)
+	if proof.SessionId == 0 && proof.DataReliability != nil {
+		differentiator := byte(0)
+		if proof.DataReliability.Differentiator {
+			differentiator = 1
+		}
+		key = append(key, differentiator)
+	}
+	return key
+}
+
+// Save writes the proof, replacing the stored proof of its session. writes aren't synced to disk one by one,
+// leveldb recovers them from its log if the process crashes
+func (rdb *RewardsDB) Save(epoch uint64, consumer string, proof *pairingtypes.RelayRequest) error {
+	value, err := proof.Marshal()
+	if err != nil {
+		return err
+	}
+	return rdb.db.Set(proofKey(epoch, consumer, proof), value)
+}
+
+func (rdb *RewardsDB) Delete(epoch uint64, consumer string, proof *pairingtypes.RelayRequest) error {
+	return rdb.db.Delete(proofKey(epoch, consumer, proof))
+}
+
+// DeleteEpoch deletes all the proofs of the epoch
+func (rdb *RewardsDB) DeleteEpoch(epoch uint64) error {
+	return rdb.deleteRange(epochPrefix(epoch), epochPrefix(epoch+1))
+}
+
+func (rdb *RewardsDB) deleteRange(start []byte, end []byte) error {
+	iterator, err := rdb.db.Iterator(start, end)
+	if err != nil {
+		return err
+	}
+	keys := [][]byte{}
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+	batch := rdb.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		err = batch.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// ReadAll returns the stored proofs, entries that can't be parsed are deleted
+func (rdb *RewardsDB) ReadAll() ([]StoredProof, error) {
+	iterator, err := rdb.db.Iterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	storedProofs := []StoredProof{}
+	invalidKeys := [][]byte{}
+	for ; iterator.Valid(); iterator.Next() {
+		key := iterator.Key()
+		epoch, consumer, ok := parseProofKey(key)
+		proof := &pairingtypes.RelayRequest{}
+		if ok {
+			ok = proof.Unmarshal(iterator.Value()) == nil
+		}
+		if !ok {
+			invalidKeys = append(invalidKeys, key)
+			continue
+		}
+		storedProofs = append(storedProofs, StoredProof{Epoch: epoch, Consumer: consumer, Proof: proof})
+	}
+	iterator.Close()
+	for _, key := range invalidKeys {
+		utils.LavaFormatWarning("deleting invalid entry from the rewards db", nil, &map[string]string{"key": strconv.Quote(string(key))})
+		err = rdb.db.Delete(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return storedProofs, nil
+}
+
+func parseProofKey(key []byte) (epoch uint64, consumer string, ok bool) {
+	if len(key) < 9 {
+		return 0, "", false
+	}
+	epoch = binary.BigEndian.Uint64(key[:8])
+	consumerLen := int(key[8])
+	if len(key) < 9+consumerLen+8 {
+		return 0, "", false
+	}
+	return epoch, string(key[9 : 9+consumerLen]), true
+}
+
+func (rdb *RewardsDB) Close() error {
+	return rdb.db.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -13,12 +14,15 @@ import (
 	"github.com/lavanet/lava/protocol/chainlib"
 	"github.com/lavanet/lava/protocol/chaintracker"
 	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/protocol/metrics"
+	"github.com/lavanet/lava/protocol/rpcprovider/ratelimiter"
 	"github.com/lavanet/lava/protocol/rpcprovider/reliabilitymanager"
 	"github.com/lavanet/lava/protocol/rpcprovider/rewardserver"
 	"github.com/lavanet/lava/protocol/statetracker"
 	"github.com/lavanet/lava/relayer/performance"
 	"github.com/lavanet/lava/relayer/sigs"
 	"github.com/lavanet/lava/utils"
+	conflicttypes "github.com/lavanet/lava/x/conflict/types"
 	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 	"github.com/spf13/viper"
 )
@@ -34,11 +38,20 @@ var (
 )
 
 type ProviderStateTrackerInf interface {
-	RegisterChainParserForSpecUpdates(ctx context.Context, chainParser chainlib.ChainParser)
-	RegisterReliabilityManagerForVoteUpdates(ctx context.Context, reliabilityManager *reliabilitymanager.ReliabilityManager)
-	RegisterForEpochUpdates(ctx context.Context, epochUpdatable statetracker.EpochUpdatable)
-	QueryVerifyPairing(ctx context.Context, consumer string, blockHeight uint64)
-	TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest)
+	RegisterChainParserForSpecUpdates(ctx context.Context, chainParser chainlib.ChainParser, chainID string) error
+	RegisterReliabilityManagerForVoteUpdates(ctx context.Context, voteUpdatable statetracker.VoteUpdatable, endpointP *lavasession.RPCProviderEndpoint)
+	RegisterForEpochUpdates(ctx context.Context, epochUpdatable statetracker.EpochUpdatable) error
+	RegisterForUpgradeHalt(ctx context.Context, haltable statetracker.UpgradeHaltable)
+	QueryVerifyPairing(ctx context.Context, chainID string, consumer string, provider string, blockHeight uint64) (valid bool, index int64, err error)
+	GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, blockHeight uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error)
+	GetProvidersCount(ctx context.Context) (uint64, error)
+	TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest, description string) error
+	IsSessionPaid(ctx context.Context, chainID string, consumer string, provider string, sessionID uint64) (bool, error)
+	GetEpochSize(ctx context.Context) (uint64, error)
+	GetRecommendedEpochNumToCollectPayment(ctx context.Context) (uint64, error)
+	TxConflictVoteCommit(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error
+	TxConflictVoteReveal(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error
+	GetConflictVote(ctx context.Context, voteID string) (*conflicttypes.ConflictVote, error)
 }
 
 type RPCProvider struct {
@@ -46,16 +59,46 @@ type RPCProvider struct {
 	rpcProviderServers   map[string]*RPCProviderServer
 }
 
-func (rpcp *RPCProvider) Start(ctx context.Context, txFactory tx.Factory, clientCtx client.Context, rpcProviderEndpoints []*lavasession.RPCProviderEndpoint, cache *performance.Cache, parallelConnections uint) (err error) {
+func (rpcp *RPCProvider) Start(ctx context.Context, txFactory tx.Factory, clientCtx client.Context, rpcProviderEndpoints []*lavasession.RPCProviderEndpoint, cache *performance.Cache, parallelConnections uint, rewardsDBDir string, metricsListenAddress string, consumerPolicyFile string) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	// single state tracker
-	providerStateTracker := statetracker.ProviderStateTracker{}
-	rpcp.providerStateTracker, err = providerStateTracker.New(ctx, txFactory, clientCtx)
+	lavaChainFetcher := chainlib.NewLavaChainFetcher(ctx, clientCtx)
+	providerStateTracker, err := statetracker.NewProviderStateTracker(ctx, txFactory, clientCtx, lavaChainFetcher)
 	if err != nil {
 		return err
 	}
+	rpcp.providerStateTracker = providerStateTracker
 	rpcp.rpcProviderServers = make(map[string]*RPCProviderServer, len(rpcProviderEndpoints))
-	// single reward server
-	rewardServer := rewardserver.NewRewardServer(&providerStateTracker)
+	providerMetrics := metrics.StartProviderMetricsServer(metricsListenAddress)
+	// single consumer policy, shared by all the endpoints
+	consumerPolicy, err := ratelimiter.NewConsumerPolicyWatcher(ctx, consumerPolicyFile)
+	if err != nil {
+		return err
+	}
+	// single reward server, proofs are kept in the node home by default so they survive restarts
+	if rewardsDBDir == "" {
+		rewardsDBDir = filepath.Join(clientCtx.HomeDir, "data")
+	}
+	rewardsDB, err := rewardserver.NewLevelDBRewardsDB(rewardsDBDir)
+	if err != nil {
+		return err
+	}
+	defer rewardsDB.Close()
+	// the nonces of vote commitments are kept next to the rewards
+	votesDB, err := reliabilitymanager.NewLevelDBVotesDB(rewardsDBDir)
+	if err != nil {
+		return err
+	}
+	defer votesDB.Close()
+	rewardServer, err := rewardserver.NewRewardServer(ctx, providerStateTracker, rewardsDB)
+	if err != nil {
+		return err
+	}
+	err = rpcp.providerStateTracker.RegisterForEpochUpdates(ctx, rewardServer)
+	if err != nil {
+		return err
+	}
 
 	keyName, err := sigs.GetKeyName(clientCtx)
 	if err != nil {
@@ -75,19 +118,35 @@ func (rpcp *RPCProvider) Start(ctx context.Context, txFactory tx.Factory, client
 	utils.LavaFormatInfo("RPCProvider pubkey: "+addr.String(), nil)
 	utils.LavaFormatInfo("RPCProvider setting up endpoints", &map[string]string{"length": strconv.Itoa(len(rpcProviderEndpoints))})
 	for _, rpcProviderEndpoint := range rpcProviderEndpoints {
-		providerSessionManager := lavasession.NewProviderSessionManager(rpcProviderEndpoint, &providerStateTracker)
+		providerSessionManager := lavasession.NewProviderSessionManager(rpcProviderEndpoint, providerStateTracker, addr.String())
 		key := rpcProviderEndpoint.Key()
-		rpcp.providerStateTracker.RegisterForEpochUpdates(ctx, providerSessionManager)
 		chainParser, err := chainlib.NewChainParser(rpcProviderEndpoint.ApiInterface)
 		if err != nil {
 			return err
 		}
-		providerStateTracker.RegisterChainParserForSpecUpdates(ctx, chainParser)
+		err = rpcp.providerStateTracker.RegisterChainParserForSpecUpdates(ctx, chainParser, rpcProviderEndpoint.ChainID)
+		if err != nil {
+			return err
+		}
 		_, averageBlockTime, _, _ := chainParser.ChainBlockStats()
-		chainProxy, err := chainlib.GetChainProxy(ctx, parallelConnections, rpcProviderEndpoint, averageBlockTime)
+		chainProxy, err := chainlib.NewNodeRouter(ctx, parallelConnections, rpcProviderEndpoint, averageBlockTime)
 		if err != nil {
 			utils.LavaFormatFatal("failed creating chain proxy", err, &map[string]string{"parallelConnections": strconv.FormatUint(uint64(parallelConnections), 10), "rpcProviderEndpoint": fmt.Sprintf("%+v", rpcProviderEndpoint)})
 		}
+		err = chainProxy.VerifyNodes(ctx, chainParser)
+		if chainlib.NodeVerificationMismatchError.Is(err) {
+			// serving a chain we don't have nodes of gets the provider punished, the reasons are logged per node
+			utils.LavaFormatError("no node of the endpoint serves its chain, refusing to serve the endpoint", err, &map[string]string{"endpoint": lavasession.PrintRPCProviderEndpoint(rpcProviderEndpoint)})
+			continue
+		} else if err != nil {
+			utils.LavaFormatWarning("no node of the endpoint was verified yet, they are verified by the health monitor", err, &map[string]string{"endpoint": lavasession.PrintRPCProviderEndpoint(rpcProviderEndpoint)})
+		}
+		err = rpcp.providerStateTracker.RegisterForEpochUpdates(ctx, providerSessionManager)
+		if err != nil {
+			return err
+		}
+		// no relays are served while the chain halts for an upgrade, they can't be paid for
+		rpcp.providerStateTracker.RegisterForUpgradeHalt(ctx, providerSessionManager)
 
 		_, averageBlockTime, blocksToFinalization, blocksInFinalizationData := chainParser.ChainBlockStats()
 		blocksToSaveChainTracker := uint64(blocksToFinalization + blocksInFinalizationData)
@@ -101,20 +160,28 @@ func (rpcp *RPCProvider) Start(ctx context.Context, txFactory tx.Factory, client
 		if err != nil {
 			utils.LavaFormatFatal("failed creating chain tracker", err, &map[string]string{"chainTrackerConfig": fmt.Sprintf("%+v", chainTrackerConfig)})
 		}
-		reliabilityManager := reliabilitymanager.NewReliabilityManager(chainTracker)
-		providerStateTracker.RegisterReliabilityManagerForVoteUpdates(ctx, reliabilityManager)
+		chainProxy.StartHealthMonitor(ctx, chainTracker, chainParser)
+		reliabilityManager, err := reliabilitymanager.NewReliabilityManager(ctx, chainTracker, providerStateTracker, addr.String(), chainProxy, chainParser, votesDB, key)
+		if err != nil {
+			return err
+		}
+		rpcp.providerStateTracker.RegisterReliabilityManagerForVoteUpdates(ctx, reliabilityManager, rpcProviderEndpoint)
 		if _, ok := rpcp.rpcProviderServers[key]; ok {
 			utils.LavaFormatFatal("Trying to add the same key twice to rpcProviderServers check config file.", nil,
 				&map[string]string{"key": key})
 		}
 		rpcp.rpcProviderServers[key] = &RPCProviderServer{}
+		rateLimiter := ratelimiter.NewConsumerRateLimiter(rpcProviderEndpoint, consumerPolicy, providerMetrics)
 		utils.LavaFormatInfo("RPCProvider Listening", &map[string]string{"endpoints": lavasession.PrintRPCProviderEndpoint(rpcProviderEndpoint)})
-		rpcp.rpcProviderServers[key].ServeRPCRequests(ctx, rpcProviderEndpoint, chainParser, rewardServer, providerSessionManager, reliabilityManager, privKey, cache, chainProxy)
+		rpcp.rpcProviderServers[key].ServeRPCRequests(ctx, rpcProviderEndpoint, chainParser, rewardServer, providerSessionManager, reliabilityManager, rpcp.providerStateTracker, addr, privKey, cache, chainProxy, rateLimiter)
 	}
+	// haltables run in registration order, the sessions of all the endpoints halt before the pending proofs are claimed so they are final
+	rpcp.providerStateTracker.RegisterForUpgradeHalt(ctx, rewardServer)
 
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt)
 	<-signalChan
+	// cancelling the context shuts down the servers
 	return nil
 }
 
@@ -1,16 +1,60 @@
 package rpcprovider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/gogo/status"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/protocol/chainlib/chainproxy/rpcclient"
 	"github.com/lavanet/lava/protocol/chaintracker"
+	"github.com/lavanet/lava/protocol/lavaprotocol"
 	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/protocol/rpcprovider/ratelimiter"
 	"github.com/lavanet/lava/relayer/performance"
+	"github.com/lavanet/lava/relayer/sigs"
+	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
-type RPCProviderServer struct{}
+const (
+	ShutdownTimeout = 10 * time.Second
+)
+
+// implements the pairingtypes Relayer service, serves consumer relays for a single endpoint
+type RPCProviderServer struct {
+	cache                  *performance.Cache
+	chainProxy             chainlib.ChainProxy
+	privKey                *btcec.PrivateKey
+	reliabilityManager     ReliabilityManagerInf
+	providerSessionManager *lavasession.ProviderSessionManager
+	rewardServer           RewardServerInf
+	chainParser            chainlib.ChainParser
+	rpcProviderEndpoint    *lavasession.RPCProviderEndpoint
+	stateTracker           StateTrackerInf
+	providerAddress        sdk.AccAddress
+	subscriptions          map[string]map[string]*subscription // first key is a consumer address, second key is a subscription id
+	subscriptionsLock      sync.Mutex
+	rateLimiter            *ratelimiter.ConsumerRateLimiter
+}
 
 type ReliabilityManagerInf interface {
 	GetLatestBlockData(fromBlock int64, toBlock int64, specificBlock int64) (latestBlock int64, requestedHashes []*chaintracker.BlockStore, err error)
@@ -18,7 +62,23 @@ type ReliabilityManagerInf interface {
 }
 
 type RewardServerInf interface {
-	SendNewProof(ctx context.Context, singleProviderSession *lavasession.SingleProviderSession, epoch uint64, consumerAddr string)
+	SendNewProof(ctx context.Context, proof *pairingtypes.RelayRequest, epoch uint64, consumerAddr string)
+}
+
+type StateTrackerInf interface {
+	QueryVerifyPairing(ctx context.Context, chainID string, consumer string, provider string, blockHeight uint64) (valid bool, index int64, err error)
+	GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, blockHeight uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error)
+	GetProvidersCount(ctx context.Context) (uint64, error)
+}
+
+type subscription struct {
+	id                   string
+	sub                  *rpcclient.ClientSubscription
+	subscribeRepliesChan chan interface{}
+}
+
+func (s *subscription) disconnect() {
+	s.sub.Unsubscribe()
 }
 
 func (rpcps *RPCProviderServer) ServeRPCRequests(
@@ -27,19 +87,543 @@ func (rpcps *RPCProviderServer) ServeRPCRequests(
 	rewardServer RewardServerInf,
 	providerSessionManager *lavasession.ProviderSessionManager,
 	reliabilityManager ReliabilityManagerInf,
+	stateTracker StateTrackerInf,
+	providerAddress sdk.AccAddress,
 	privKey *btcec.PrivateKey,
 	cache *performance.Cache, chainProxy chainlib.ChainProxy,
+	rateLimiter *ratelimiter.ConsumerRateLimiter,
 ) {
-	// spin up a grpc listener
-	// verify the relay metadata is valid (epoch, signature)
-	// verify the consumer is authorised
-	// create/bring a session
-	// verify the relay data is valid (cu, chainParser, requested block)
-	// check cache hit
-	// send the relay to the node using chainProxy
-	// set cache entry (async)
-	// attach data reliability finalization data
-	// sign the response
-	// send the proof to reward server
-	// finalize the session
+	rpcps.cache = cache
+	rpcps.chainProxy = chainProxy
+	rpcps.privKey = privKey
+	rpcps.reliabilityManager = reliabilityManager
+	rpcps.providerSessionManager = providerSessionManager
+	rpcps.rewardServer = rewardServer
+	rpcps.chainParser = chainParser
+	rpcps.rpcProviderEndpoint = rpcProviderEndpoint
+	rpcps.stateTracker = stateTracker
+	rpcps.providerAddress = providerAddress
+	rpcps.subscriptions = map[string]map[string]*subscription{}
+	rpcps.rateLimiter = rateLimiter
+
+	lis, err := net.Listen("tcp", rpcProviderEndpoint.NetworkAddress)
+	if err != nil {
+		utils.LavaFormatFatal("provider failure setting up listener", err, &map[string]string{"listenAddr": rpcProviderEndpoint.NetworkAddress, "ChainID": rpcProviderEndpoint.ChainID})
+	}
+	grpcServer := grpc.NewServer()
+	pairingtypes.RegisterRelayerServer(grpcServer, rpcps)
+
+	wrappedServer := grpcweb.WrapServer(grpcServer)
+	handler := func(resp http.ResponseWriter, req *http.Request) {
+		// Set CORS headers
+		resp.Header().Set("Access-Control-Allow-Origin", "*")
+		resp.Header().Set("Access-Control-Allow-Headers", "Content-Type,x-grpc-web")
+
+		wrappedServer.ServeHTTP(resp, req)
+	}
+	httpServer := http.Server{
+		Handler: h2c.NewHandler(http.HandlerFunc(handler), &http2.Server{}),
+	}
+
+	go func() {
+		<-ctx.Done()
+		utils.LavaFormatInfo("Provider Server ctx.Done", &map[string]string{"endpoint": lavasession.PrintRPCProviderEndpoint(rpcProviderEndpoint)})
+		shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer shutdownRelease()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			utils.LavaFormatFatal("Provider failed to shutdown", err, &map[string]string{})
+		}
+	}()
+
+	go func() {
+		utils.LavaFormatInfo("Server listening", &map[string]string{"Address": lis.Addr().String(), "ChainID": rpcProviderEndpoint.ChainID, "ApiInterface": rpcProviderEndpoint.ApiInterface})
+		// serve is blocking, until terminated
+		if err := httpServer.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+			utils.LavaFormatFatal("provider failed to serve", err, &map[string]string{"Address": lis.Addr().String(), "ChainID": rpcProviderEndpoint.ChainID})
+		}
+	}()
+}
+
+func (rpcps *RPCProviderServer) Relay(ctx context.Context, request *pairingtypes.RelayRequest) (*pairingtypes.RelayReply, error) {
+	utils.LavaFormatDebug("Provider got relay request", &map[string]string{
+		"request.SessionId":   strconv.FormatUint(request.SessionId, 10),
+		"request.relayNumber": strconv.FormatUint(request.RelayNum, 10),
+		"request.cu":          strconv.FormatUint(request.CuSum, 10),
+	})
+	relaySession, consumerAddress, chainMessage, release, err := rpcps.initRelay(ctx, request)
+	if err != nil {
+		return nil, rpcps.handleRelayErrorStatus(err)
+	}
+	defer release()
+	// the proof has to be the request as signed by the consumer, TryRelay replaces arbitrary requested blocks
+	proof := request.ShallowCopy()
+	reply, err := rpcps.TryRelay(ctx, request, consumerAddress, chainMessage)
+	if err != nil {
+		// failed to send relay. we need to adjust session state. cuSum and relayNumber.
+		relayFailureError := rpcps.providerSessionManager.OnSessionFailure(relaySession)
+		if relayFailureError != nil {
+			err = sdkerrors.Wrapf(relayFailureError, "On relay failure: "+err.Error())
+		}
+		utils.LavaFormatError("TryRelay Failed", err, &map[string]string{
+			"request.SessionId": strconv.FormatUint(request.SessionId, 10),
+			"request.userAddr":  consumerAddress.String(),
+		})
+		return nil, rpcps.handleRelayErrorStatus(err)
+	}
+	err = rpcps.onRelayDone(ctx, relaySession, proof, consumerAddress)
+	if err != nil {
+		return nil, rpcps.handleRelayErrorStatus(err)
+	}
+	utils.LavaFormatDebug("Provider Finished Relay Successfully", &map[string]string{
+		"request.SessionId":   strconv.FormatUint(request.SessionId, 10),
+		"request.relayNumber": strconv.FormatUint(request.RelayNum, 10),
+	})
+	return reply, nil
+}
+
+func (rpcps *RPCProviderServer) RelaySubscribe(request *pairingtypes.RelayRequest, srv pairingtypes.Relayer_RelaySubscribeServer) error {
+	utils.LavaFormatInfo("Provider got relay request subscribe", &map[string]string{
+		"request.SessionId": strconv.FormatUint(request.SessionId, 10),
+	})
+	ctx := srv.Context()
+	relaySession, consumerAddress, chainMessage, release, err := rpcps.initRelay(ctx, request)
+	if err != nil {
+		return rpcps.handleRelayErrorStatus(err)
+	}
+	// the subscription counts against the consumer limits until it is closed
+	defer release()
+	proof := request.ShallowCopy()
+	subscribeRepliesChan := make(chan interface{})
+	reply, subscriptionID, clientSub, err := rpcps.chainProxy.SendNodeMsg(ctx, subscribeRepliesChan, chainMessage)
+	if err != nil {
+		relayFailureError := rpcps.providerSessionManager.OnSessionFailure(relaySession)
+		if relayFailureError != nil {
+			err = sdkerrors.Wrapf(relayFailureError, "Relay Error: "+err.Error())
+		}
+		return rpcps.handleRelayErrorStatus(utils.LavaFormatError("Subscription failed", err, nil))
+	}
+	// the subscription compute units are charged when it is established
+	err = rpcps.onRelayDone(ctx, relaySession, proof, consumerAddress)
+	if err != nil {
+		clientSub.Unsubscribe()
+		return rpcps.handleRelayErrorStatus(err)
+	}
+	return rpcps.trySubscribe(srv, consumerAddress.String(), reply, subscriptionID, clientSub, subscribeRepliesChan)
+}
+
+func (rpcps *RPCProviderServer) trySubscribe(srv pairingtypes.Relayer_RelaySubscribeServer, consumerAddress string, reply *pairingtypes.RelayReply, subscriptionID string, clientSub *rpcclient.ClientSubscription, subscribeRepliesChan chan interface{}) error {
+	rpcps.subscriptionsLock.Lock()
+	consumerSubscriptions, ok := rpcps.subscriptions[consumerAddress]
+	if !ok {
+		consumerSubscriptions = map[string]*subscription{}
+		rpcps.subscriptions[consumerAddress] = consumerSubscriptions
+	}
+	if _, ok := consumerSubscriptions[subscriptionID]; ok {
+		rpcps.subscriptionsLock.Unlock()
+		clientSub.Unsubscribe()
+		return utils.LavaFormatError("SubscriptionID: "+subscriptionID+" exists", nil, nil)
+	}
+	consumerSubscriptions[subscriptionID] = &subscription{
+		id:                   subscriptionID,
+		sub:                  clientSub,
+		subscribeRepliesChan: subscribeRepliesChan,
+	}
+	rpcps.subscriptionsLock.Unlock()
+	defer rpcps.removeSubscription(consumerAddress, subscriptionID)
+
+	err := srv.Send(reply) // this reply contains the RPC ID
+	if err != nil {
+		utils.LavaFormatError("Error getting RPC ID", err, nil)
+	}
+
+	for {
+		select {
+		case err := <-clientSub.Err():
+			// closed without an error when the consumer unsubscribed
+			if err != nil {
+				utils.LavaFormatError("client sub", err, nil)
+			}
+			return err
+		case subscribeReply := <-subscribeRepliesChan:
+			data, err := json.Marshal(subscribeReply)
+			if err != nil {
+				return utils.LavaFormatError("client sub unmarshal", err, nil)
+			}
+			err = srv.Send(&pairingtypes.RelayReply{Data: data})
+			if err != nil {
+				// usually triggered when client closes connection
+				if strings.Contains(err.Error(), "Canceled desc = context canceled") {
+					utils.LavaFormatWarning("Client closed connection", err, nil)
+				} else {
+					utils.LavaFormatError("srv.Send", err, nil)
+				}
+				return err
+			}
+			utils.LavaFormatDebug("Sending data", &map[string]string{"data": string(data)})
+		}
+	}
+}
+
+// disconnects the subscription if it wasn't already disconnected by an unsubscribe relay
+func (rpcps *RPCProviderServer) removeSubscription(consumerAddress string, subscriptionID string) {
+	rpcps.subscriptionsLock.Lock()
+	defer rpcps.subscriptionsLock.Unlock()
+	if sub, ok := rpcps.subscriptions[consumerAddress][subscriptionID]; ok {
+		sub.disconnect()
+		delete(rpcps.subscriptions[consumerAddress], subscriptionID)
+	}
+}
+
+// verifies the relay metadata and the consumer, parses the relay data, applies the consumer rate limits and charges the relay compute units on the consumer session.
+// release frees the relay from the consumer rate limits and must be called once the relay is done
+func (rpcps *RPCProviderServer) initRelay(ctx context.Context, request *pairingtypes.RelayRequest) (relaySession *lavasession.SingleProviderSession, consumerAddress sdk.AccAddress, chainMessage chainlib.ChainMessage, release func(), err error) {
+	consumerAddress, err = rpcps.verifyRelayMetaData(request)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	// Parse message, check valid api, etc
+	chainMessage, err = rpcps.chainParser.ParseMsg(request.ApiUrl, request.Data, request.ConnectionType)
+	if err != nil {
+		return nil, nil, nil, nil, utils.LavaFormatError("failed parsing request message", err, &map[string]string{"apiInterface": rpcps.rpcProviderEndpoint.ApiInterface, "request URL": request.ApiUrl, "request data": string(request.Data), "userAddr": consumerAddress.String()})
+	}
+	// the consumer policy and limits are applied before the consumer sessions are touched so a rejected relay doesn't advance them
+	releaseRelay, err := rpcps.rateLimiter.TryAcquire(ctx, consumerAddress.String(), chainMessage.GetServiceApi().ComputeUnits, chainMessage.GetInterface().GetCategory().GetSubscription())
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			releaseRelay()
+		}
+	}()
+	epoch := uint64(request.BlockHeight)
+	if request.DataReliability != nil {
+		relaySession, err = rpcps.initDataReliabilityRelay(ctx, request, consumerAddress)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		return relaySession, consumerAddress, chainMessage, releaseRelay, nil
+	}
+	if request.SessionId == lavasession.DataReliabilitySessionId {
+		return nil, nil, nil, nil, utils.LavaFormatError("SessionID cannot be 0 for non-data reliability requests", nil,
+			&map[string]string{"epoch": strconv.FormatUint(epoch, 10), "userAddr": consumerAddress.String(), "relay request": fmt.Sprintf("%v", request)})
+	}
+	relaySession, err = rpcps.providerSessionManager.GetSession(ctx, consumerAddress.String(), epoch, request.SessionId, request.RelayNum)
+	if err != nil {
+		return nil, nil, nil, nil, utils.LavaFormatError("failed getting a session for the consumer", err, &map[string]string{"userAddr": consumerAddress.String(), "epoch": strconv.FormatUint(epoch, 10), "sessionID": strconv.FormatUint(request.SessionId, 10)})
+	}
+	err = relaySession.PrepareSessionForUsage(chainMessage.GetServiceApi().ComputeUnits, request.CuSum, request.RelayNum)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return relaySession, consumerAddress, chainMessage, releaseRelay, nil
+}
+
+// verifies the relay is for this provider and spec in a valid epoch and extracts the consumer from its signature
+func (rpcps *RPCProviderServer) verifyRelayMetaData(request *pairingtypes.RelayRequest) (sdk.AccAddress, error) {
+	if request.BlockHeight < 0 || !rpcps.providerSessionManager.IsValidEpoch(uint64(request.BlockHeight)) {
+		return nil, utils.LavaFormatError("user reported invalid lava block height", lavasession.InvalidEpochError, &map[string]string{
+			"current lava block":   strconv.FormatInt(rpcps.reliabilityManager.GetLatestBlockNum(), 10),
+			"requested lava block": strconv.FormatInt(request.BlockHeight, 10),
+		})
+	}
+	if rpcps.providerAddress.String() != request.Provider {
+		return nil, utils.LavaFormatError("User is trying to communicate with the wrong provider address.", nil, &map[string]string{
+			"ProviderWhoGotTheRequest": rpcps.providerAddress.String(),
+			"ProviderInTheRequest":     request.Provider,
+		})
+	}
+	if request.ChainID != rpcps.rpcProviderEndpoint.ChainID {
+		return nil, utils.LavaFormatError("spec not supported by server", nil, &map[string]string{"request.chainID": request.ChainID, "chainID": rpcps.rpcProviderEndpoint.ChainID})
+	}
+	pubKey, err := sigs.RecoverPubKeyFromRelay(*request)
+	if err != nil {
+		return nil, utils.LavaFormatError("get relay user", err, &map[string]string{})
+	}
+	consumerAddress, err := sdk.AccAddressFromHex(pubKey.Address().String())
+	if err != nil {
+		return nil, utils.LavaFormatError("get relay acc address", err, &map[string]string{})
+	}
+	return consumerAddress, nil
+}
+
+// data reliability relays are verified against the consumer vrf and the original provider signature, they are not charged
+func (rpcps *RPCProviderServer) initDataReliabilityRelay(ctx context.Context, request *pairingtypes.RelayRequest, consumerAddress sdk.AccAddress) (relaySession *lavasession.SingleProviderSession, err error) {
+	if request.RelayNum > lavasession.DataReliabilitySessionId {
+		return nil, utils.LavaFormatError("request's relay num is larger than the data reliability session ID", nil, &map[string]string{"relayNum": strconv.FormatUint(request.RelayNum, 10), "DataReliabilitySessionId": strconv.Itoa(lavasession.DataReliabilitySessionId)})
+	}
+	if request.CuSum != lavasession.DataReliabilityCuSum {
+		return nil, utils.LavaFormatError("request's CU sum is not equal to the data reliability CU sum", nil, &map[string]string{"cuSum": strconv.FormatUint(request.CuSum, 10), "DataReliabilityCuSum": strconv.Itoa(lavasession.DataReliabilityCuSum)})
+	}
+	epoch := uint64(request.BlockHeight)
+	relaySession, err = rpcps.providerSessionManager.GetDataReliabilitySession(ctx, consumerAddress.String(), epoch)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed getting a data reliability session for the consumer", err, &map[string]string{"userAddr": consumerAddress.String(), "epoch": strconv.FormatUint(epoch, 10)})
+	}
+	err = rpcps.verifyDataReliabilityRelay(ctx, request, consumerAddress)
+	if err != nil {
+		rpcps.providerSessionManager.OnSessionFailure(relaySession)
+		return nil, err
+	}
+	utils.LavaFormatInfo("Simulation: server got valid DataReliability request", nil)
+	return relaySession, nil
+}
+
+func (rpcps *RPCProviderServer) verifyDataReliabilityRelay(ctx context.Context, request *pairingtypes.RelayRequest, consumerAddress sdk.AccAddress) error {
+	chainID := rpcps.rpcProviderEndpoint.ChainID
+	epoch := uint64(request.BlockHeight)
+	errDetails := &map[string]string{"requested epoch": strconv.FormatInt(request.BlockHeight, 10), "userAddr": consumerAddress.String(), "dataReliability": fmt.Sprintf("%v", request.DataReliability)}
+	vrfPk, _, err := rpcps.stateTracker.GetVrfPkAndMaxCuForUser(ctx, chainID, consumerAddress.String(), epoch)
+	if err != nil {
+		return utils.LavaFormatError("failed to get vrfpk for data reliability!", err, errDetails)
+	}
+	if vrfPk == nil {
+		return utils.LavaFormatError("dataReliability Triggered with vrf_pk == nil", nil, errDetails)
+	}
+	// verify the providerSig is indeed a signature by a valid provider on this query
+	valid, err := rpcps.verifyReliabilityAddressSigning(ctx, consumerAddress, request)
+	if err != nil {
+		return utils.LavaFormatError("VerifyReliabilityAddressSigning invalid", err, errDetails)
+	}
+	if !valid {
+		return utils.LavaFormatError("invalid DataReliability Provider signing", nil, errDetails)
+	}
+	// verify data reliability fields correspond to the right vrf
+	valid = utils.VerifyVrfProof(request, *vrfPk, epoch)
+	if !valid {
+		return utils.LavaFormatError("invalid DataReliability fields, VRF wasn't verified with provided proof", nil, errDetails)
+	}
+	providersCount, err := rpcps.stateTracker.GetProvidersCount(ctx)
+	if err != nil {
+		return utils.LavaFormatError("failed to get the providers count for data reliability", err, errDetails)
+	}
+	_, dataReliabilityThreshold := rpcps.chainParser.DataReliabilityParams()
+	vrfIndex, vrfErr := utils.GetIndexForVrf(request.DataReliability.VrfValue, uint32(providersCount), dataReliabilityThreshold)
+	if vrfErr != nil {
+		return utils.LavaFormatError("Provider identified vrf value in data reliability request does not meet threshold", vrfErr, errDetails)
+	}
+	_, selfIndex, err := rpcps.stateTracker.QueryVerifyPairing(ctx, chainID, consumerAddress.String(), rpcps.providerAddress.String(), epoch)
+	if err != nil {
+		return utils.LavaFormatError("failed verifying self pairing for data reliability", err, errDetails)
+	}
+	if selfIndex != vrfIndex {
+		return utils.LavaFormatError("Provider identified invalid vrfIndex in data reliability request, the given index and self index are different", nil,
+			&map[string]string{
+				"requested epoch": strconv.FormatInt(request.BlockHeight, 10), "userAddr": consumerAddress.String(),
+				"vrfIndex":   strconv.FormatInt(vrfIndex, 10),
+				"self Index": strconv.FormatInt(selfIndex, 10),
+			})
+	}
+	return nil
+}
+
+// verifies the consumer signed the vrf data and the original provider signed the query and is paired with the consumer
+func (rpcps *RPCProviderServer) verifyReliabilityAddressSigning(ctx context.Context, consumer sdk.AccAddress, request *pairingtypes.RelayRequest) (valid bool, err error) {
+	queryHash := utils.CalculateQueryHash(*request)
+	if !bytes.Equal(queryHash, request.DataReliability.QueryHash) {
+		return false, utils.LavaFormatError("query hash mismatch on data reliability message", nil,
+			&map[string]string{"queryHash": string(queryHash), "request QueryHash": string(request.DataReliability.QueryHash)})
+	}
+
+	// validate consumer signing on VRF data
+	valid, err = sigs.ValidateSignerOnVRFData(consumer, *request.DataReliability)
+	if err != nil {
+		return false, utils.LavaFormatError("failed to Validate Signer On VRF Data", err,
+			&map[string]string{"consumer": consumer.String(), "request.DataReliability": fmt.Sprintf("%v", request.DataReliability)})
+	}
+	if !valid {
+		return false, nil
+	}
+	// validate provider signing on query data
+	pubKey, err := sigs.RecoverProviderPubKeyFromVrfDataAndQuery(request)
+	if err != nil {
+		return false, utils.LavaFormatError("failed to Recover Provider PubKey From Vrf Data And Query", err,
+			&map[string]string{"consumer": consumer.String(), "request": fmt.Sprintf("%v", request)})
+	}
+	providerAccAddress, err := sdk.AccAddressFromHex(pubKey.Address().String()) // consumer signer
+	if err != nil {
+		return false, utils.LavaFormatError("failed converting signer to address", err,
+			&map[string]string{"consumer": consumer.String(), "PubKey": pubKey.Address().String()})
+	}
+	valid, _, err = rpcps.stateTracker.QueryVerifyPairing(ctx, rpcps.rpcProviderEndpoint.ChainID, consumer.String(), providerAccAddress.String(), uint64(request.BlockHeight))
+	return valid, err // return if this pairing is authorised
+}
+
+// saves the proof on the session and hands it to the reward server
+func (rpcps *RPCProviderServer) onRelayDone(ctx context.Context, relaySession *lavasession.SingleProviderSession, proof *pairingtypes.RelayRequest, consumerAddress sdk.AccAddress) error {
+	epoch := relaySession.PairingEpoch
+	err := rpcps.providerSessionManager.OnSessionDone(relaySession, proof)
+	if err != nil {
+		return utils.LavaFormatError("OnSessionDone failed", err, &map[string]string{"userAddr": consumerAddress.String(), "sessionID": strconv.FormatUint(proof.SessionId, 10)})
+	}
+	rpcps.rewardServer.SendNewProof(ctx, proof, epoch, consumerAddress.String())
+	return nil
+}
+
+func (rpcps *RPCProviderServer) TryRelay(ctx context.Context, request *pairingtypes.RelayRequest, consumerAddress sdk.AccAddress, chainMessage chainlib.ChainMessage) (*pairingtypes.RelayReply, error) {
+	latestBlock := int64(0)
+	finalizedBlockHashes := map[int64]string{}
+	var requestedBlockHash []byte = nil
+	finalized := false
+	dataReliabilityEnabled, _ := rpcps.chainParser.DataReliabilityParams()
+	_, _, blockDistanceToFinalization, blocksInFinalizationData := rpcps.chainParser.ChainBlockStats()
+	if dataReliabilityEnabled {
+		// Add latest block and finalized data
+		var err error
+		var requestedHashes []*chaintracker.BlockStore
+		specificBlock := request.RequestBlock
+		if specificBlock < spectypes.LATEST_BLOCK {
+			// earliest and other magic blocks can't be fetched from the chain tracker
+			specificBlock = spectypes.NOT_APPLICABLE
+		}
+		// the finalization data is the blocksInFinalizationData blocks ending blockDistanceToFinalization blocks before latest, the range end is exclusive
+		toBlock := spectypes.LATEST_BLOCK - int64(blockDistanceToFinalization) + 1
+		fromBlock := toBlock - int64(blocksInFinalizationData)
+		latestBlock, requestedHashes, err = rpcps.reliabilityManager.GetLatestBlockData(fromBlock, toBlock, specificBlock)
+		if err != nil {
+			return nil, utils.LavaFormatError("Could not guarantee data reliability", err, &map[string]string{"requestedBlock": strconv.FormatInt(request.RequestBlock, 10), "latestBlock": strconv.FormatInt(latestBlock, 10)})
+		}
+		request.RequestBlock = lavaprotocol.ReplaceRequestedBlock(request.RequestBlock, latestBlock)
+		latestFinalizedBlock := latestBlock - int64(blockDistanceToFinalization)
+		for _, block := range requestedHashes {
+			if block.Block == request.RequestBlock {
+				requestedBlockHash = []byte(block.Hash)
+			}
+			if block.Block <= latestFinalizedBlock && block.Block > latestFinalizedBlock-int64(blocksInFinalizationData) {
+				finalizedBlockHashes[block.Block] = block.Hash
+			}
+		}
+		if requestedBlockHash == nil {
+			// avoid using cache, but can still service
+			utils.LavaFormatWarning("no hash data for requested block", nil, &map[string]string{"requestedBlock": strconv.FormatInt(request.RequestBlock, 10), "latestBlock": strconv.FormatInt(latestBlock, 10)})
+		}
+		finalized = spectypes.IsFinalizedBlock(request.RequestBlock, latestBlock, blockDistanceToFinalization)
+	}
+	chainID := rpcps.rpcProviderEndpoint.ChainID
+	apiInterface := rpcps.rpcProviderEndpoint.ApiInterface
+	// TODO: handle cache on fork for dataReliability = false
+	var reply *pairingtypes.RelayReply = nil
+	var err error = nil
+	if requestedBlockHash != nil || finalized {
+		reply, err = rpcps.cache.GetEntry(ctx, request, apiInterface, requestedBlockHash, chainID, finalized)
+	}
+	if err != nil || reply == nil {
+		if err != nil && performance.NotConnectedError.Is(err) {
+			utils.LavaFormatWarning("cache not connected", err, nil)
+		}
+		// cache miss or invalid
+		reply, _, _, err = rpcps.chainProxy.SendNodeMsg(ctx, nil, chainMessage)
+		if err != nil {
+			return nil, utils.LavaFormatError("Sending chainMessage failed", nodeRelayError(err), nil)
+		}
+		if requestedBlockHash != nil || finalized {
+			// set cache in a non blocking call
+			cacheRequest := request.ShallowCopy()
+			cacheReply := *reply
+			go func() {
+				cacheCtx, cancel := context.WithTimeout(context.Background(), lavaprotocol.DataReliabilityTimeoutIncrease)
+				defer cancel()
+				err := rpcps.cache.SetEntry(cacheCtx, cacheRequest, apiInterface, requestedBlockHash, chainID, consumerAddress.String(), &cacheReply, finalized)
+				if err != nil && !performance.NotInitialisedError.Is(err) {
+					utils.LavaFormatWarning("error updating cache with new entry", err, nil)
+				}
+			}()
+		}
+	}
+
+	apiName := chainMessage.GetServiceApi().Name
+	if strings.Contains(apiName, "unsubscribe") {
+		err := rpcps.processUnsubscribe(apiName, consumerAddress.String(), chainMessage.GetRPCMessage().GetParams())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	jsonStr, err := json.Marshal(finalizedBlockHashes)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed unmarshaling finalizedBlockHashes", err,
+			&map[string]string{"finalizedBlockHashes": fmt.Sprintf("%v", finalizedBlockHashes)})
+	}
+	reply.FinalizedBlocksHashes = jsonStr
+	reply.LatestBlock = latestBlock
+
+	// request is a copy of the original request, the requested block was already replaced with the one the reply is for
+	sig, err := sigs.SignRelayResponse(rpcps.privKey, reply, request)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed signing relay response", err,
+			&map[string]string{"request": fmt.Sprintf("%v", request), "reply": fmt.Sprintf("%v", reply)})
+	}
+	reply.Sig = sig
+	if dataReliabilityEnabled {
+		// update sig blocks signature
+		sigBlocks, err := sigs.SignResponseFinalizationData(rpcps.privKey, reply, request, consumerAddress)
+		if err != nil {
+			return nil, utils.LavaFormatError("failed signing finalization data", err,
+				&map[string]string{"request": fmt.Sprintf("%v", request), "reply": fmt.Sprintf("%v", reply), "userAddr": consumerAddress.String()})
+		}
+		reply.SigBlocks = sigBlocks
+	}
+	return reply, nil
+}
+
+func (rpcps *RPCProviderServer) processUnsubscribe(apiName string, consumerAddress string, reqParams interface{}) error {
+	rpcps.subscriptionsLock.Lock()
+	defer rpcps.subscriptionsLock.Unlock()
+	consumerSubscriptions := rpcps.subscriptions[consumerAddress]
+	disconnect := func(subscriptionID string) {
+		if sub, ok := consumerSubscriptions[subscriptionID]; ok {
+			sub.disconnect()
+			delete(consumerSubscriptions, subscriptionID)
+		}
+	}
+	switch p := reqParams.(type) {
+	case []interface{}:
+		if len(p) == 0 {
+			return fmt.Errorf("processUnsubscribe - missing subscription id")
+		}
+		subscriptionID, ok := p[0].(string)
+		if !ok {
+			return fmt.Errorf("processUnsubscribe - p[0].(string) - type assertion failed, type:" + fmt.Sprintf("%s", p[0]))
+		}
+		disconnect(subscriptionID)
+	case map[string]interface{}:
+		if apiName == "unsubscribe" {
+			subscriptionID, ok := p["query"].(string)
+			if !ok {
+				return fmt.Errorf("processUnsubscribe - p['query'].(string) - type assertion failed, type:" + fmt.Sprintf("%s", p["query"]))
+			}
+			disconnect(subscriptionID)
+		} else {
+			for subscriptionID := range consumerSubscriptions {
+				disconnect(subscriptionID)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeRelayError tags the errors of a node that failed the relay, the consumer takes them as the node missing the requested data.
+// a node that timed out or couldn't be reached says nothing about its data
+func nodeRelayError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.As(err, &netErr) {
+		return err
+	}
+	return sdkerrors.Wrap(lavasession.ProviderNodeError, err.Error())
+}
+
+func (rpcps *RPCProviderServer) handleRelayErrorStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if lavasession.SessionOutOfSyncError.Is(err) {
+		err = status.Error(codes.Code(lavasession.SessionOutOfSyncError.ABCICode()), err.Error())
+	} else if lavasession.ConsumerRateLimitedError.Is(err) {
+		err = status.Error(codes.Code(lavasession.ConsumerRateLimitedError.ABCICode()), err.Error())
+	} else if lavasession.ConsumerDeniedError.Is(err) {
+		err = status.Error(codes.Code(lavasession.ConsumerDeniedError.ABCICode()), err.Error())
+	} else if lavasession.ProviderNodeError.Is(err) {
+		err = status.Error(codes.Code(lavasession.ProviderNodeError.ABCICode()), err.Error())
+	}
+	return err
 }
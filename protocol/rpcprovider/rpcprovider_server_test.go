@@ -0,0 +1,285 @@
+package rpcprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/protocol/chainlib/chainproxy/rpcclient"
+	"github.com/lavanet/lava/protocol/chaintracker"
+	"github.com/lavanet/lava/protocol/lavaprotocol"
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/protocol/rpcprovider/ratelimiter"
+	"github.com/lavanet/lava/relayer/sigs"
+	"github.com/lavanet/lava/utils"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testChainID      = "LAV1"
+	testComputeUnits = 10
+	testMaxCu        = 25
+	testLatestBlock  = 1000
+	testEpoch        = 20
+)
+
+type mockStateTracker struct {
+	valid bool
+}
+
+func (mst *mockStateTracker) QueryVerifyPairing(ctx context.Context, chainID string, consumer string, provider string, blockHeight uint64) (valid bool, index int64, err error) {
+	return mst.valid, 0, nil
+}
+
+func (mst *mockStateTracker) GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, blockHeight uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error) {
+	return &utils.VrfPubKey{}, testMaxCu, nil
+}
+
+func (mst *mockStateTracker) GetProvidersCount(ctx context.Context) (uint64, error) {
+	return 5, nil
+}
+
+type mockRewardServer struct {
+	lock   sync.Mutex
+	proofs []*pairingtypes.RelayRequest
+}
+
+func (mrs *mockRewardServer) SendNewProof(ctx context.Context, proof *pairingtypes.RelayRequest, epoch uint64, consumerAddr string) {
+	mrs.lock.Lock()
+	defer mrs.lock.Unlock()
+	mrs.proofs = append(mrs.proofs, proof)
+}
+
+type mockReliabilityManager struct{}
+
+func (mrm *mockReliabilityManager) GetLatestBlockData(fromBlock int64, toBlock int64, specificBlock int64) (latestBlock int64, requestedHashes []*chaintracker.BlockStore, err error) {
+	fromBlock = chaintracker.LatestArgToBlockNum(fromBlock, testLatestBlock)
+	toBlock = chaintracker.LatestArgToBlockNum(toBlock, testLatestBlock)
+	for block := fromBlock; block < toBlock; block++ {
+		requestedHashes = append(requestedHashes, &chaintracker.BlockStore{Block: block, Hash: "hash"})
+	}
+	if specificBlock != spectypes.NOT_APPLICABLE {
+		specificBlock = chaintracker.LatestArgToBlockNum(specificBlock, testLatestBlock)
+		if specificBlock < fromBlock || specificBlock >= toBlock {
+			requestedHashes = append(requestedHashes, &chaintracker.BlockStore{Block: specificBlock, Hash: "specific"})
+		}
+	}
+	return testLatestBlock, requestedHashes, nil
+}
+
+func (mrm *mockReliabilityManager) GetLatestBlockNum() int64 {
+	return testLatestBlock
+}
+
+type mockChainProxy struct {
+	nodeErr error
+}
+
+func (mcp *mockChainProxy) SendNodeMsg(ctx context.Context, ch chan interface{}, chainMessage chainlib.ChainMessage) (relayReply *pairingtypes.RelayReply, subscriptionID string, relayReplyServer *rpcclient.ClientSubscription, err error) {
+	if mcp.nodeErr != nil {
+		return nil, "", nil, mcp.nodeErr
+	}
+	return &pairingtypes.RelayReply{Data: []byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`)}, "", nil, nil
+}
+
+func createTestSpec(dataReliabilityEnabled bool) spectypes.Spec {
+	return spectypes.Spec{
+		Index:                         testChainID,
+		Enabled:                       true,
+		DataReliabilityEnabled:        dataReliabilityEnabled,
+		ReliabilityThreshold:          268435455,
+		BlockDistanceForFinalizedData: 2,
+		BlocksInFinalizationProof:     3,
+		AverageBlockTime:              1000,
+		Apis: []spectypes.ServiceApi{{
+			Name:          "eth_blockNumber",
+			Enabled:       true,
+			ComputeUnits:  testComputeUnits,
+			BlockParsing:  spectypes.BlockParser{ParserFunc: spectypes.PARSER_FUNC_DEFAULT, ParserArg: []string{"latest"}},
+			ApiInterfaces: []spectypes.ApiInterface{{Interface: spectypes.APIInterfaceJsonRPC, Type: "POST"}},
+		}},
+	}
+}
+
+func createTestServer(t *testing.T, dataReliabilityEnabled bool) (*RPCProviderServer, *mockRewardServer, sdk.AccAddress) {
+	chainParser, err := chainlib.NewChainParser(spectypes.APIInterfaceJsonRPC)
+	require.Nil(t, err)
+	chainParser.SetSpec(createTestSpec(dataReliabilityEnabled))
+	providerKey, providerAddress := sigs.GenerateFloatingKey()
+	rpcProviderEndpoint := &lavasession.RPCProviderEndpoint{NetworkAddress: "127.0.0.1:0", ChainID: testChainID, ApiInterface: spectypes.APIInterfaceJsonRPC}
+	stateTracker := &mockStateTracker{valid: true}
+	rewardServer := &mockRewardServer{}
+	rpcps := &RPCProviderServer{
+		chainProxy:             &mockChainProxy{},
+		privKey:                providerKey,
+		reliabilityManager:     &mockReliabilityManager{},
+		providerSessionManager: lavasession.NewProviderSessionManager(rpcProviderEndpoint, stateTracker, providerAddress.String()),
+		rewardServer:           rewardServer,
+		chainParser:            chainParser,
+		rpcProviderEndpoint:    rpcProviderEndpoint,
+		stateTracker:           stateTracker,
+		providerAddress:        providerAddress,
+		subscriptions:          map[string]map[string]*subscription{},
+		rateLimiter:            ratelimiter.NewConsumerRateLimiter(rpcProviderEndpoint, nil, nil),
+	}
+	return rpcps, rewardServer, providerAddress
+}
+
+func createTestRelayRequest(t *testing.T, consumerKey *btcec.PrivateKey, provider sdk.AccAddress, sessionID uint64, relayNum uint64, cuSum uint64) *pairingtypes.RelayRequest {
+	request := &pairingtypes.RelayRequest{
+		ChainID:        testChainID,
+		ConnectionType: "POST",
+		Data:           []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`),
+		SessionId:      sessionID,
+		CuSum:          cuSum,
+		Provider:       provider.String(),
+		BlockHeight:    testEpoch,
+		RelayNum:       relayNum,
+		RequestBlock:   spectypes.LATEST_BLOCK,
+	}
+	sig, err := sigs.SignRelay(consumerKey, *request)
+	require.Nil(t, err)
+	request.Sig = sig
+	return request
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+	rpcps, rewardServer, providerAddress := createTestServer(t, false)
+	consumerKey, _ := sigs.GenerateFloatingKey()
+
+	request := createTestRelayRequest(t, consumerKey, providerAddress, 1, 1, testComputeUnits)
+	reply, err := rpcps.Relay(ctx, request)
+	require.Nil(t, err)
+	require.Nil(t, lavaprotocol.VerifyRelayReply(reply, request, providerAddress.String()))
+	require.Len(t, rewardServer.proofs, 1)
+	require.Equal(t, request.Sig, rewardServer.proofs[0].Sig)
+
+	// the next relay on the session has to add its compute units to the session cu sum
+	request = createTestRelayRequest(t, consumerKey, providerAddress, 1, 2, testComputeUnits)
+	_, err = rpcps.Relay(ctx, request)
+	require.NotNil(t, err)
+	require.Len(t, rewardServer.proofs, 1)
+
+	request = createTestRelayRequest(t, consumerKey, providerAddress, 1, 2, 2*testComputeUnits)
+	_, err = rpcps.Relay(ctx, request)
+	require.Nil(t, err)
+	require.Len(t, rewardServer.proofs, 2)
+
+	// the consumer max cu for the epoch is exceeded, across sessions
+	request = createTestRelayRequest(t, consumerKey, providerAddress, 2, 1, testComputeUnits)
+	_, err = rpcps.Relay(ctx, request)
+	require.NotNil(t, err)
+	require.Len(t, rewardServer.proofs, 2)
+
+	// a relay signed for another provider is rejected
+	_, otherProvider := sigs.GenerateFloatingKey()
+	request = createTestRelayRequest(t, consumerKey, otherProvider, 3, 1, testComputeUnits)
+	_, err = rpcps.Relay(ctx, request)
+	require.NotNil(t, err)
+}
+
+func TestRelayFailureRevertsSession(t *testing.T) {
+	ctx := context.Background()
+	rpcps, rewardServer, providerAddress := createTestServer(t, false)
+	consumerKey, _ := sigs.GenerateFloatingKey()
+
+	rpcps.chainProxy = &mockChainProxy{nodeErr: fmt.Errorf("missing trie node")}
+	request := createTestRelayRequest(t, consumerKey, providerAddress, 1, 1, testComputeUnits)
+	_, err := rpcps.Relay(ctx, request)
+	require.Equal(t, codes.Code(lavasession.ProviderNodeError.ABCICode()), status.Code(err))
+	require.Len(t, rewardServer.proofs, 0)
+
+	// a node that timed out says nothing about its data, the failure isn't tagged as a node error
+	rpcps.chainProxy = &mockChainProxy{nodeErr: context.DeadlineExceeded}
+	_, err = rpcps.Relay(ctx, request)
+	require.NotNil(t, err)
+	require.NotEqual(t, codes.Code(lavasession.ProviderNodeError.ABCICode()), status.Code(err))
+
+	// the failed relay didn't use the session, the consumer retries it
+	rpcps.chainProxy = &mockChainProxy{}
+	reply, err := rpcps.Relay(ctx, request)
+	require.Nil(t, err)
+	require.Nil(t, lavaprotocol.VerifyRelayReply(reply, request, providerAddress.String()))
+	require.Len(t, rewardServer.proofs, 1)
+}
+
+func TestRelayRateLimited(t *testing.T) {
+	ctx := context.Background()
+	rpcps, rewardServer, providerAddress := createTestServer(t, false)
+	rpcps.rpcProviderEndpoint.RateLimit = lavasession.RateLimit{CuPerSecond: testComputeUnits}
+	rpcps.rateLimiter = ratelimiter.NewConsumerRateLimiter(rpcps.rpcProviderEndpoint, nil, nil)
+	consumerKey, _ := sigs.GenerateFloatingKey()
+
+	request := createTestRelayRequest(t, consumerKey, providerAddress, 1, 1, testComputeUnits)
+	_, err := rpcps.Relay(ctx, request)
+	require.Nil(t, err)
+	request = createTestRelayRequest(t, consumerKey, providerAddress, 1, 2, 2*testComputeUnits)
+	_, err = rpcps.Relay(ctx, request)
+	require.Equal(t, codes.Code(lavasession.ConsumerRateLimitedError.ABCICode()), status.Code(err))
+	require.Len(t, rewardServer.proofs, 1)
+
+	// the rejected relay didn't use the session
+	rpcps.rateLimiter = ratelimiter.NewConsumerRateLimiter(&lavasession.RPCProviderEndpoint{ChainID: testChainID}, nil, nil)
+	_, err = rpcps.Relay(ctx, request)
+	require.Nil(t, err)
+	require.Len(t, rewardServer.proofs, 2)
+}
+
+func TestRelayDeniedConsumer(t *testing.T) {
+	ctx := context.Background()
+	rpcps, rewardServer, providerAddress := createTestServer(t, false)
+	consumerKey, consumerAddress := sigs.GenerateFloatingKey()
+	policyFile := filepath.Join(t.TempDir(), "consumer_policy.yml")
+	require.Nil(t, os.WriteFile(policyFile, []byte("deny-list:\n  - "+consumerAddress.String()+"\n"), 0o600))
+	consumerPolicy, err := ratelimiter.NewConsumerPolicyWatcher(ctx, policyFile)
+	require.Nil(t, err)
+	rpcps.rateLimiter = ratelimiter.NewConsumerRateLimiter(rpcps.rpcProviderEndpoint, consumerPolicy, nil)
+
+	request := createTestRelayRequest(t, consumerKey, providerAddress, 1, 1, testComputeUnits)
+	_, err = rpcps.Relay(ctx, request)
+	require.Equal(t, codes.Code(lavasession.ConsumerDeniedError.ABCICode()), status.Code(err))
+	require.Len(t, rewardServer.proofs, 0)
+}
+
+func TestRelayUnpairedConsumer(t *testing.T) {
+	ctx := context.Background()
+	rpcps, rewardServer, providerAddress := createTestServer(t, false)
+	rpcps.providerSessionManager = lavasession.NewProviderSessionManager(rpcps.rpcProviderEndpoint, &mockStateTracker{valid: false}, providerAddress.String())
+	consumerKey, _ := sigs.GenerateFloatingKey()
+
+	request := createTestRelayRequest(t, consumerKey, providerAddress, 1, 1, testComputeUnits)
+	_, err := rpcps.Relay(ctx, request)
+	require.NotNil(t, err)
+	require.Len(t, rewardServer.proofs, 0)
+}
+
+func TestRelayFinalizationData(t *testing.T) {
+	ctx := context.Background()
+	rpcps, _, providerAddress := createTestServer(t, true)
+	consumerKey, _ := sigs.GenerateFloatingKey()
+
+	request := createTestRelayRequest(t, consumerKey, providerAddress, 1, 1, testComputeUnits)
+	reply, err := rpcps.Relay(ctx, request)
+	require.Nil(t, err)
+	require.Equal(t, int64(testLatestBlock), reply.LatestBlock)
+
+	// the consumer verifies the reply against the requested block the provider replied for
+	lavaprotocol.UpdateRequestedBlock(request, reply)
+	require.Equal(t, int64(testLatestBlock), request.RequestBlock)
+	require.Nil(t, lavaprotocol.VerifyRelayReply(reply, request, providerAddress.String()))
+	_, _, blockDistanceForFinalizedData, blocksInFinalizationProof := rpcps.chainParser.ChainBlockStats()
+	finalizedBlocks, _, err := lavaprotocol.VerifyFinalizationData(reply, request, providerAddress.String(), 0, blockDistanceForFinalizedData)
+	require.Nil(t, err)
+	require.Len(t, finalizedBlocks, int(blocksInFinalizationProof))
+}
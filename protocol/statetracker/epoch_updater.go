@@ -0,0 +1,88 @@
+package statetracker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/lavanet/lava/utils"
+)
+
+const (
+	CallbackKeyForEpochUpdate = "epoch-update"
+)
+
+type epochQuery interface {
+	CurrentEpochStart(ctx context.Context) (epoch uint64, nextEpochStart uint64, err error)
+}
+
+type EpochUpdater struct {
+	lock               sync.RWMutex
+	epochUpdatables    []EpochUpdatable
+	currentEpoch       uint64
+	nextBlockForUpdate uint64
+	stateQuery         epochQuery
+}
+
+func NewEpochUpdater(stateQuery epochQuery) *EpochUpdater {
+	return &EpochUpdater{epochUpdatables: []EpochUpdatable{}, stateQuery: stateQuery}
+}
+
+// RegisterEpochUpdatable sets the current epoch on the updatable and updates it on every new epoch, an updatable is registered once
+func (eu *EpochUpdater) RegisterEpochUpdatable(ctx context.Context, epochUpdatable EpochUpdatable) error {
+	eu.lock.Lock()
+	defer eu.lock.Unlock()
+	for _, registered := range eu.epochUpdatables {
+		if registered == epochUpdatable {
+			return nil
+		}
+	}
+	if eu.currentEpoch == 0 {
+		epoch, nextEpochStart, err := eu.stateQuery.CurrentEpochStart(ctx)
+		if err != nil {
+			return err
+		}
+		eu.currentEpoch = epoch
+		eu.nextBlockForUpdate = nextEpochStart
+	}
+	epochUpdatable.UpdateEpoch(eu.currentEpoch)
+	eu.epochUpdatables = append(eu.epochUpdatables, epochUpdatable)
+	return nil
+}
+
+func (eu *EpochUpdater) UpdaterKey() string {
+	return CallbackKeyForEpochUpdate
+}
+
+func (eu *EpochUpdater) Update(latestBlock int64) {
+	eu.lock.Lock()
+	defer eu.lock.Unlock()
+	if int64(eu.nextBlockForUpdate) > latestBlock {
+		return
+	}
+	eu.updateInner(context.Background(), latestBlock)
+}
+
+// RefreshAfterUpgrade reads the epoch of the upgraded chain, epoch params can change in the upgrade handler
+func (eu *EpochUpdater) RefreshAfterUpgrade(ctx context.Context, latestBlock int64) error {
+	eu.lock.Lock()
+	defer eu.lock.Unlock()
+	return eu.updateInner(ctx, latestBlock)
+}
+
+func (eu *EpochUpdater) updateInner(ctx context.Context, latestBlock int64) error {
+	epoch, nextEpochStart, err := eu.stateQuery.CurrentEpochStart(ctx)
+	if err != nil {
+		eu.nextBlockForUpdate = uint64(latestBlock) + 1
+		return utils.LavaFormatError("could not get the current epoch, trying again next block", err, &map[string]string{"latestBlock": strconv.FormatInt(latestBlock, 10)})
+	}
+	eu.nextBlockForUpdate = nextEpochStart
+	if epoch == eu.currentEpoch {
+		return nil
+	}
+	eu.currentEpoch = epoch
+	for _, epochUpdatable := range eu.epochUpdatables {
+		epochUpdatable.UpdateEpoch(epoch)
+	}
+	return nil
+}
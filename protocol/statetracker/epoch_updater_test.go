@@ -0,0 +1,81 @@
+package statetracker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockEpochQuery struct {
+	epoch       uint64
+	epochBlocks uint64
+	queries     int
+	err         error
+}
+
+func (mq *mockEpochQuery) CurrentEpochStart(ctx context.Context) (epoch uint64, nextEpochStart uint64, err error) {
+	mq.queries++
+	return mq.epoch, mq.epoch + mq.epochBlocks, mq.err
+}
+
+type mockEpochUpdatable struct {
+	epochs []uint64
+}
+
+func (mu *mockEpochUpdatable) UpdateEpoch(epoch uint64) {
+	mu.epochs = append(mu.epochs, epoch)
+}
+
+func TestEpochUpdaterUpdatesOnNewEpoch(t *testing.T) {
+	ctx := context.Background()
+	epochQuery := &mockEpochQuery{epoch: 100, epochBlocks: 20}
+	epochUpdater := NewEpochUpdater(epochQuery)
+	epochUpdatable := &mockEpochUpdatable{}
+	require.Nil(t, epochUpdater.RegisterEpochUpdatable(ctx, epochUpdatable))
+	require.Equal(t, []uint64{100}, epochUpdatable.epochs)
+
+	// registering the same updatable again doesn't update it twice
+	require.Nil(t, epochUpdater.RegisterEpochUpdatable(ctx, epochUpdatable))
+	require.Equal(t, []uint64{100}, epochUpdatable.epochs)
+
+	// no queries until the next epoch starts
+	queries := epochQuery.queries
+	epochUpdater.Update(105)
+	epochUpdater.Update(119)
+	require.Equal(t, queries, epochQuery.queries)
+	require.Equal(t, []uint64{100}, epochUpdatable.epochs)
+
+	epochQuery.epoch = 120
+	epochUpdater.Update(120)
+	require.Equal(t, []uint64{100, 120}, epochUpdatable.epochs)
+
+	// a late updatable starts on the current epoch
+	lateUpdatable := &mockEpochUpdatable{}
+	require.Nil(t, epochUpdater.RegisterEpochUpdatable(ctx, lateUpdatable))
+	require.Equal(t, []uint64{120}, lateUpdatable.epochs)
+}
+
+func TestEpochUpdaterRetriesFailedQueries(t *testing.T) {
+	ctx := context.Background()
+	epochQuery := &mockEpochQuery{epoch: 100, epochBlocks: 20}
+	epochUpdater := NewEpochUpdater(epochQuery)
+	epochUpdatable := &mockEpochUpdatable{}
+	require.Nil(t, epochUpdater.RegisterEpochUpdatable(ctx, epochUpdatable))
+
+	epochQuery.epoch = 120
+	epochQuery.err = fmt.Errorf("node unavailable")
+	epochUpdater.Update(120)
+	require.Equal(t, []uint64{100}, epochUpdatable.epochs)
+
+	// the next block queries again
+	epochQuery.err = nil
+	epochUpdater.Update(121)
+	require.Equal(t, []uint64{100, 120}, epochUpdatable.epochs)
+
+	// the epoch of the upgraded chain is read even when the epoch didn't end
+	epochQuery.epoch = 125
+	require.Nil(t, epochUpdater.RefreshAfterUpgrade(ctx, 125))
+	require.Equal(t, []uint64{100, 120, 125}, epochUpdatable.epochs)
+}
@@ -37,14 +37,24 @@ func (fcu *FinalizationConsensusUpdater) Update(latestBlock int64) {
 	if int64(fcu.nextBlockForUpdate) > latestBlock {
 		return
 	}
+	fcu.updateInner(ctx, latestBlock)
+}
+
+// RefreshAfterUpgrade starts a new epoch for the finalization consensuses with the epoch of the upgraded chain
+func (fcu *FinalizationConsensusUpdater) RefreshAfterUpgrade(ctx context.Context, latestBlock int64) error {
+	fcu.stateQuery.ExpireCachedPairings()
+	return fcu.updateInner(ctx, latestBlock)
+}
+
+func (fcu *FinalizationConsensusUpdater) updateInner(ctx context.Context, latestBlock int64) error {
 	_, epoch, nextBlockForUpdate, err := fcu.stateQuery.GetPairing(ctx, "", latestBlock)
 	if err != nil {
-		utils.LavaFormatError("could not get block stats for finzalizationConsensus, trying again later", err, &map[string]string{"latestBlock": strconv.FormatInt(latestBlock, 10)})
 		fcu.nextBlockForUpdate += 1
-		return
+		return utils.LavaFormatError("could not get block stats for finzalizationConsensus, trying again later", err, &map[string]string{"latestBlock": strconv.FormatInt(latestBlock, 10)})
 	}
 	fcu.nextBlockForUpdate = nextBlockForUpdate
 	for _, finalizationConsensus := range fcu.registeredFinalizationConsensuses {
 		finalizationConsensus.NewEpoch(epoch)
 	}
+	return nil
 }
@@ -51,11 +51,21 @@ func (pu *PairingUpdater) Update(latestBlock int64) {
 	if int64(pu.nextBlockForUpdate) > latestBlock {
 		return
 	}
+	pu.updateInner(ctx, latestBlock)
+}
+
+// RefreshAfterUpgrade drops the pairings from before the upgrade and queries them again
+func (pu *PairingUpdater) RefreshAfterUpgrade(ctx context.Context, latestBlock int64) error {
+	pu.stateQuery.ExpireCachedPairings()
+	return pu.updateInner(ctx, latestBlock)
+}
+
+func (pu *PairingUpdater) updateInner(ctx context.Context, latestBlock int64) (errRet error) {
 	nextBlockForUpdateList := []uint64{}
 	for chainID, consumerSessionManagerList := range pu.consumerSessionManagersMap {
 		pairingList, epoch, nextBlockForUpdate, err := pu.stateQuery.GetPairing(ctx, chainID, latestBlock)
 		if err != nil {
-			utils.LavaFormatError("could not update pairing for chain, trying again next block", err, &map[string]string{"chain": chainID})
+			errRet = utils.LavaFormatError("could not update pairing for chain, trying again next block", err, &map[string]string{"chain": chainID})
 			nextBlockForUpdateList = append(nextBlockForUpdateList, pu.nextBlockForUpdate+1)
 			continue
 		} else {
@@ -77,6 +87,7 @@ func (pu *PairingUpdater) Update(latestBlock int64) {
 		}
 	}
 	pu.nextBlockForUpdate = nextBlockForUpdateMin
+	return errRet
 }
 
 func (pu *PairingUpdater) updateConsummerSessionManager(ctx context.Context, pairingList []epochstoragetypes.StakeEntry, consumerSessionManager *lavasession.ConsumerSessionManager, epoch uint64) (err error) {
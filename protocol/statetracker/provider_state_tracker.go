@@ -2,45 +2,111 @@ package statetracker
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	"github.com/lavanet/lava/protocol/chainlib"
-	"github.com/lavanet/lava/protocol/rpcprovider/reliabilitymanager"
+	"github.com/lavanet/lava/protocol/chaintracker"
+	"github.com/lavanet/lava/protocol/lavasession"
+	"github.com/lavanet/lava/utils"
+	conflicttypes "github.com/lavanet/lava/x/conflict/types"
 	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 )
 
 // ProviderStateTracker PST is a class for tracking provider data from the lava blockchain, such as epoch changes.
 // it allows also to query specific data form the blockchain and acts as a single place to send transactions
 type ProviderStateTracker struct {
-	// TODO: embed stateTracker
+	stateQuery *ProviderStateQuery
+	txSender   *ProviderTxSender
+	*StateTracker
 }
 
-func (pst *ProviderStateTracker) New(ctx context.Context, txFactory tx.Factory, clientCtx client.Context) (ret *ProviderStateTracker, err error) {
-	// set up StateQuery
-	// Spin up chain tracker on the lava node, its address is in the --node flag (or its default), on new block call to newLavaBlock
-	// use StateQuery to get the lava spec and spin up the chain tracker with the right params
-	// set up txSender the same way
-	return pst, nil
+func NewProviderStateTracker(ctx context.Context, txFactory tx.Factory, clientCtx client.Context, chainFetcher chaintracker.ChainFetcher) (ret *ProviderStateTracker, err error) {
+	stateTrackerBase, err := NewStateTracker(ctx, txFactory, clientCtx, chainFetcher)
+	if err != nil {
+		return nil, err
+	}
+	txSender, err := NewProviderTxSender(ctx, clientCtx, txFactory)
+	if err != nil {
+		return nil, err
+	}
+	pst := &ProviderStateTracker{StateTracker: stateTrackerBase, stateQuery: NewProviderStateQuery(ctx, clientCtx), txSender: txSender}
+	// cached pairings are dropped once their epoch is no longer served
+	err = pst.RegisterForEpochUpdates(ctx, pst.stateQuery)
+	return pst, err
 }
 
-func (pst *ProviderStateTracker) RegisterForEpochUpdates(ctx context.Context, epochUpdatable EpochUpdatable) {
-	// create an epoch updater
-	// add epoch updater to the updater map
+func (pst *ProviderStateTracker) RegisterForEpochUpdates(ctx context.Context, epochUpdatable EpochUpdatable) error {
+	epochUpdater := NewEpochUpdater(pst.stateQuery)
+	epochUpdaterRaw := pst.StateTracker.RegisterForUpdates(ctx, epochUpdater)
+	epochUpdater, ok := epochUpdaterRaw.(*EpochUpdater)
+	if !ok {
+		utils.LavaFormatFatal("invalid updater type returned from RegisterForUpdates", nil, &map[string]string{"updater": fmt.Sprintf("%+v", epochUpdaterRaw)})
+	}
+	return epochUpdater.RegisterEpochUpdatable(ctx, epochUpdatable)
 }
 
-func (pst *ProviderStateTracker) RegisterChainParserForSpecUpdates(ctx context.Context, chainParser chainlib.ChainParser) {
-	// can be moved to base class
+// RegisterChainParserForSpecUpdates sets the spec on the chain parser, and sets it again when it changes on chain, specs are also checked on every new epoch
+func (pst *ProviderStateTracker) RegisterChainParserForSpecUpdates(ctx context.Context, chainParser chainlib.ChainParser, chainID string) error {
+	specUpdater, created := pst.StateTracker.registerSpecUpdater(ctx)
+	if created {
+		// the endpoints share the spec updater, it is registered for epoch updates once
+		err := pst.RegisterForEpochUpdates(ctx, specUpdater)
+		if err != nil {
+			return err
+		}
+	}
+	return specUpdater.RegisterChainParser(ctx, chainParser, chainID)
 }
 
-func (pst *ProviderStateTracker) RegisterReliabilityManagerForVoteUpdates(ctx context.Context, reliabilityManager *reliabilitymanager.ReliabilityManager) {
-	// TODO: change to an interface instead of reliabilitymanager.ReliabilityManager
+// RegisterReliabilityManagerForVoteUpdates hands the conflict votes of the endpoint chain to the vote updatable
+func (pst *ProviderStateTracker) RegisterReliabilityManagerForVoteUpdates(ctx context.Context, voteUpdatable VoteUpdatable, endpointP *lavasession.RPCProviderEndpoint) {
+	voteUpdater := NewVoteUpdater(pst.stateQuery)
+	voteUpdaterRaw := pst.StateTracker.RegisterForUpdates(ctx, voteUpdater)
+	voteUpdater, ok := voteUpdaterRaw.(*VoteUpdater)
+	if !ok {
+		utils.LavaFormatFatal("invalid updater type returned from RegisterForUpdates", nil, &map[string]string{"updater": fmt.Sprintf("%+v", voteUpdaterRaw)})
+	}
+	voteUpdater.RegisterVoteUpdatable(ctx, voteUpdatable, endpointP.ChainID)
 }
 
-func (pst *ProviderStateTracker) QueryVerifyPairing(ctx context.Context, consumer string, blockHeight uint64) {
-	// TODO: implement
+func (pst *ProviderStateTracker) QueryVerifyPairing(ctx context.Context, chainID string, consumer string, provider string, blockHeight uint64) (valid bool, index int64, err error) {
+	return pst.stateQuery.VerifyPairing(ctx, chainID, consumer, provider, blockHeight)
 }
 
-func (pst *ProviderStateTracker) TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest) {
-	// TODO: implement
+func (pst *ProviderStateTracker) GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, blockHeight uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error) {
+	return pst.stateQuery.GetVrfPkAndMaxCuForUser(ctx, chainID, consumer, blockHeight)
+}
+
+func (pst *ProviderStateTracker) GetProvidersCount(ctx context.Context) (uint64, error) {
+	return pst.stateQuery.GetProvidersCount(ctx)
+}
+
+func (pst *ProviderStateTracker) TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest, description string) error {
+	return pst.txSender.TxRelayPayment(ctx, relayRequests, description)
+}
+
+func (pst *ProviderStateTracker) IsSessionPaid(ctx context.Context, chainID string, consumer string, provider string, sessionID uint64) (bool, error) {
+	return pst.stateQuery.IsSessionPaid(ctx, chainID, consumer, provider, sessionID)
+}
+
+func (pst *ProviderStateTracker) GetEpochSize(ctx context.Context) (uint64, error) {
+	return pst.stateQuery.GetEpochSize(ctx)
+}
+
+func (pst *ProviderStateTracker) GetRecommendedEpochNumToCollectPayment(ctx context.Context) (uint64, error) {
+	return pst.stateQuery.GetRecommendedEpochNumToCollectPayment(ctx)
+}
+
+func (pst *ProviderStateTracker) TxConflictVoteCommit(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error {
+	return pst.txSender.TxConflictVoteCommit(ctx, voteID, nonce, replyDataHash)
+}
+
+func (pst *ProviderStateTracker) TxConflictVoteReveal(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error {
+	return pst.txSender.TxConflictVoteReveal(ctx, voteID, nonce, replyDataHash)
+}
+
+func (pst *ProviderStateTracker) GetConflictVote(ctx context.Context, voteID string) (*conflicttypes.ConflictVote, error) {
+	return pst.stateQuery.GetConflictVote(ctx, voteID)
 }
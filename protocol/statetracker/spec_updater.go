@@ -0,0 +1,110 @@
+package statetracker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/lavanet/lava/protocol/chainlib"
+	"github.com/lavanet/lava/utils"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+)
+
+const (
+	CallbackKeyForSpecUpdate = "spec-update"
+	BlocksToCheckSpecUpdates = 10 // specs are queried at this interval to catch updates made by proposals
+)
+
+type specQuery interface {
+	GetSpec(ctx context.Context, chainID string) (*spectypes.Spec, error)
+}
+
+type SpecUpdater struct {
+	lock               sync.RWMutex
+	chainParsers       map[string][]chainlib.ChainParser // key is chainID so we query every spec once
+	blockLastUpdated   map[string]uint64                 // key is chainID, the block the spec set on the chain parsers was last updated at
+	nextBlockForUpdate int64
+	stateQuery         specQuery
+}
+
+func NewSpecUpdater(stateQuery specQuery) *SpecUpdater {
+	return &SpecUpdater{chainParsers: map[string][]chainlib.ChainParser{}, blockLastUpdated: map[string]uint64{}, stateQuery: stateQuery}
+}
+
+func (su *SpecUpdater) RegisterChainParser(ctx context.Context, chainParser chainlib.ChainParser, chainID string) error {
+	spec, err := su.stateQuery.GetSpec(ctx, chainID)
+	if err != nil {
+		return err
+	}
+	su.lock.Lock()
+	defer su.lock.Unlock()
+	if blockLastUpdated, ok := su.blockLastUpdated[chainID]; ok && blockLastUpdated != spec.BlockLastUpdated {
+		// the spec changed since the chain parsers of this chain were set, they all get the new one
+		for _, registered := range su.chainParsers[chainID] {
+			registered.SetSpec(*spec)
+		}
+	}
+	chainParser.SetSpec(*spec)
+	su.chainParsers[chainID] = append(su.chainParsers[chainID], chainParser)
+	su.blockLastUpdated[chainID] = spec.BlockLastUpdated
+	return nil
+}
+
+func (su *SpecUpdater) UpdaterKey() string {
+	return CallbackKeyForSpecUpdate
+}
+
+// Update sets the specs that were updated on chain, they are checked every BlocksToCheckSpecUpdates blocks.
+// chain parsers swap their spec under their own lock, relays in flight finish with the spec they were parsed with
+func (su *SpecUpdater) Update(latestBlock int64) {
+	su.lock.Lock()
+	if su.nextBlockForUpdate > latestBlock {
+		su.lock.Unlock()
+		return
+	}
+	su.nextBlockForUpdate = latestBlock + BlocksToCheckSpecUpdates
+	su.lock.Unlock()
+	err := su.refreshSpecs(context.Background(), false)
+	if err != nil {
+		utils.LavaFormatError("could not update specs, trying again next block", err, &map[string]string{"latestBlock": strconv.FormatInt(latestBlock, 10)})
+		su.lock.Lock()
+		su.nextBlockForUpdate = latestBlock + 1
+		su.lock.Unlock()
+	}
+}
+
+// UpdateEpoch sets the specs that changed since they were last set, spec proposals take effect by the next epoch
+func (su *SpecUpdater) UpdateEpoch(epoch uint64) {
+	err := su.refreshSpecs(context.Background(), false)
+	if err != nil {
+		utils.LavaFormatError("could not update specs on a new epoch, trying again next epoch", err, &map[string]string{"epoch": strconv.FormatUint(epoch, 10)})
+	}
+}
+
+// RefreshAfterUpgrade sets the specs of the upgraded chain, upgrade handlers can patch them
+func (su *SpecUpdater) RefreshAfterUpgrade(ctx context.Context, latestBlock int64) error {
+	return su.refreshSpecs(ctx, true)
+}
+
+func (su *SpecUpdater) refreshSpecs(ctx context.Context, force bool) error {
+	su.lock.Lock()
+	defer su.lock.Unlock()
+	for chainID, chainParsers := range su.chainParsers {
+		spec, err := su.stateQuery.GetSpec(ctx, chainID)
+		if err != nil {
+			return err
+		}
+		changed := spec.BlockLastUpdated != su.blockLastUpdated[chainID]
+		if !changed && !force {
+			continue
+		}
+		if changed {
+			utils.LavaFormatInfo("spec was updated on chain, setting it", &map[string]string{"chainID": chainID, "blockLastUpdated": strconv.FormatUint(spec.BlockLastUpdated, 10)})
+		}
+		for _, chainParser := range chainParsers {
+			chainParser.SetSpec(*spec)
+		}
+		su.blockLastUpdated[chainID] = spec.BlockLastUpdated
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package statetracker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lavanet/lava/protocol/chainlib"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSpecQuery struct {
+	specs   map[string]*spectypes.Spec
+	queries int
+	err     error
+}
+
+func (mq *mockSpecQuery) GetSpec(ctx context.Context, chainID string) (*spectypes.Spec, error) {
+	mq.queries++
+	if mq.err != nil {
+		return nil, mq.err
+	}
+	spec := *mq.specs[chainID]
+	return &spec, nil
+}
+
+type mockChainParser struct {
+	lock sync.RWMutex
+	spec spectypes.Spec
+}
+
+func (mcp *mockChainParser) ParseMsg(url string, data []byte, connectionType string) (chainlib.ChainMessage, error) {
+	return nil, nil
+}
+
+func (mcp *mockChainParser) SetSpec(spec spectypes.Spec) {
+	mcp.lock.Lock()
+	defer mcp.lock.Unlock()
+	mcp.spec = spec
+}
+
+func (mcp *mockChainParser) DataReliabilityParams() (enabled bool, dataReliabilityThreshold uint32) {
+	return false, 0
+}
+
+func (mcp *mockChainParser) ChainBlockStats() (allowedBlockLagForQosSync int64, averageBlockTime time.Duration, blockDistanceForFinalizedData uint32, blocksInFinalizationProof uint32) {
+	return 0, 0, 0, 0
+}
+
+func (mcp *mockChainParser) GetVerifications() []chainlib.VerificationContainer {
+	return nil
+}
+
+func (mcp *mockChainParser) blockLastUpdated() uint64 {
+	mcp.lock.RLock()
+	defer mcp.lock.RUnlock()
+	return mcp.spec.BlockLastUpdated
+}
+
+func TestSpecUpdaterUpdatesChangedSpecs(t *testing.T) {
+	ctx := context.Background()
+	specQuery := &mockSpecQuery{specs: map[string]*spectypes.Spec{
+		"LAV1": {Index: "LAV1", BlockLastUpdated: 10},
+		"ETH1": {Index: "ETH1", BlockLastUpdated: 20},
+	}}
+	specUpdater := NewSpecUpdater(specQuery)
+	lavaJsonRPC, lavaRest, ethJsonRPC := &mockChainParser{}, &mockChainParser{}, &mockChainParser{}
+	require.Nil(t, specUpdater.RegisterChainParser(ctx, lavaJsonRPC, "LAV1"))
+	require.Nil(t, specUpdater.RegisterChainParser(ctx, lavaRest, "LAV1"))
+	require.Nil(t, specUpdater.RegisterChainParser(ctx, ethJsonRPC, "ETH1"))
+	require.Equal(t, uint64(10), lavaJsonRPC.blockLastUpdated())
+	require.Equal(t, uint64(20), ethJsonRPC.blockLastUpdated())
+
+	specUpdater.Update(100)
+	queries := specQuery.queries
+	// a proposal updated the spec, it is set on every chain parser of the chain once it is checked
+	specQuery.specs["LAV1"] = &spectypes.Spec{Index: "LAV1", BlockLastUpdated: 105}
+	specUpdater.Update(100 + BlocksToCheckSpecUpdates - 1)
+	require.Equal(t, queries, specQuery.queries)
+	require.Equal(t, uint64(10), lavaJsonRPC.blockLastUpdated())
+	specUpdater.Update(100 + BlocksToCheckSpecUpdates)
+	require.Equal(t, uint64(105), lavaJsonRPC.blockLastUpdated())
+	require.Equal(t, uint64(105), lavaRest.blockLastUpdated())
+	require.Equal(t, uint64(20), ethJsonRPC.blockLastUpdated())
+
+	// a failed query is retried on the next block
+	specQuery.err = fmt.Errorf("node unavailable")
+	specUpdater.Update(100 + 2*BlocksToCheckSpecUpdates)
+	specQuery.err = nil
+	specQuery.specs["ETH1"] = &spectypes.Spec{Index: "ETH1", BlockLastUpdated: 121}
+	specUpdater.Update(100 + 2*BlocksToCheckSpecUpdates + 1)
+	require.Equal(t, uint64(121), ethJsonRPC.blockLastUpdated())
+
+	// a chain parser registered after an update brings the chain parsers of its chain up to date
+	specQuery.specs["LAV1"] = &spectypes.Spec{Index: "LAV1", BlockLastUpdated: 130}
+	lavaGrpc := &mockChainParser{}
+	require.Nil(t, specUpdater.RegisterChainParser(ctx, lavaGrpc, "LAV1"))
+	require.Equal(t, uint64(130), lavaGrpc.blockLastUpdated())
+	require.Equal(t, uint64(130), lavaJsonRPC.blockLastUpdated())
+}
+
+func TestSpecUpdaterIsShared(t *testing.T) {
+	ctx := context.Background()
+	stateTracker := &StateTracker{newLavaBlockUpdaters: map[string]Updater{}, upgradeUpdater: NewUpgradeUpdater(nil)}
+	specUpdater, created := stateTracker.registerSpecUpdater(ctx)
+	require.True(t, created)
+	// the next endpoints get the same updater, only the first one registers it for epoch updates
+	sharedSpecUpdater, created := stateTracker.registerSpecUpdater(ctx)
+	require.False(t, created)
+	require.Same(t, specUpdater, sharedSpecUpdater)
+}
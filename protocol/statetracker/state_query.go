@@ -3,18 +3,27 @@ package statetracker
 import (
 	"context"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/lavanet/lava/protocol/rpcprovider/reliabilitymanager"
 	"github.com/lavanet/lava/utils"
+	conflicttypes "github.com/lavanet/lava/x/conflict/types"
 	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
 	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 	spectypes "github.com/lavanet/lava/x/spec/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type StateQuery struct {
 	SpecQueryClient         spectypes.QueryClient
 	PairingQueryClient      pairingtypes.QueryClient
 	EpochStorageQueryClient epochstoragetypes.QueryClient
+	UpgradeQueryClient      upgradetypes.QueryClient
 }
 
 func NewStateQuery(ctx context.Context, clientCtx client.Context) *StateQuery {
@@ -22,9 +31,38 @@ func NewStateQuery(ctx context.Context, clientCtx client.Context) *StateQuery {
 	sq.SpecQueryClient = spectypes.NewQueryClient(clientCtx)
 	sq.PairingQueryClient = pairingtypes.NewQueryClient(clientCtx)
 	sq.EpochStorageQueryClient = epochstoragetypes.NewQueryClient(clientCtx)
+	sq.UpgradeQueryClient = upgradetypes.NewQueryClient(clientCtx)
 	return sq
 }
 
+// GetUpgradePlan returns the upgrade plan scheduled on the lava chain, nil when there is none
+func (sq *StateQuery) GetUpgradePlan(ctx context.Context) (*upgradetypes.Plan, error) {
+	currentPlan, err := sq.UpgradeQueryClient.CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{})
+	if err != nil {
+		return nil, utils.LavaFormatError("failed querying the current upgrade plan", err, &map[string]string{})
+	}
+	return currentPlan.Plan, nil
+}
+
+// GetAppliedPlanHeight returns the height an upgrade was applied at, 0 when it wasn't applied
+func (sq *StateQuery) GetAppliedPlanHeight(ctx context.Context, planName string) (int64, error) {
+	appliedPlan, err := sq.UpgradeQueryClient.AppliedPlan(ctx, &upgradetypes.QueryAppliedPlanRequest{Name: planName})
+	if err != nil {
+		return 0, utils.LavaFormatError("failed querying the applied upgrade plan", err, &map[string]string{"plan": planName})
+	}
+	return appliedPlan.Height, nil
+}
+
+func (sq *StateQuery) GetSpec(ctx context.Context, chainID string) (*spectypes.Spec, error) {
+	spec, err := sq.SpecQueryClient.Spec(ctx, &spectypes.QueryGetSpecRequest{
+		ChainID: chainID,
+	})
+	if err != nil {
+		return nil, utils.LavaFormatError("Failed Querying spec for chain", err, &map[string]string{"ChainID": chainID})
+	}
+	return &spec.Spec, nil
+}
+
 type ConsumerStateQuery struct {
 	StateQuery
 	clientCtx      client.Context
@@ -65,6 +103,13 @@ func (csq *ConsumerStateQuery) GetPairing(ctx context.Context, chainID string, l
 	return pairingResp.Providers, pairingResp.CurrentEpoch, pairingResp.BlockOfNextPairing, nil
 }
 
+// ExpireCachedPairings makes the next GetPairing of every cached chain query the chain again
+func (csq *ConsumerStateQuery) ExpireCachedPairings() {
+	for _, cachedResp := range csq.cachedPairings {
+		cachedResp.BlockOfNextPairing = 0
+	}
+}
+
 func (csq *ConsumerStateQuery) GetMaxCUForUser(ctx context.Context, chainID string, epoch uint64) (maxCu uint64, err error) {
 	address := csq.clientCtx.FromAddress.String()
 	UserEntryRes, err := csq.PairingQueryClient.UserEntry(ctx, &pairingtypes.QueryUserEntryRequest{ChainID: chainID, Address: address, Block: epoch})
@@ -74,12 +119,204 @@ func (csq *ConsumerStateQuery) GetMaxCUForUser(ctx context.Context, chainID stri
 	return UserEntryRes.GetMaxCU(), nil
 }
 
-func (csq *ConsumerStateQuery) GetSpec(ctx context.Context, chainID string) (*spectypes.Spec, error) {
-	spec, err := csq.SpecQueryClient.Spec(ctx, &spectypes.QueryGetSpecRequest{
-		ChainID: chainID,
+type ProviderStateQuery struct {
+	StateQuery
+	ConflictQueryClient conflicttypes.QueryClient
+	clientCtx           client.Context
+	verifyPairingLock   sync.RWMutex
+	verifyPairingCache  map[uint64]map[string]*pairingtypes.QueryVerifyPairingResponse // first key is the epoch, second key is chainID, consumer and provider
+}
+
+func NewProviderStateQuery(ctx context.Context, clientCtx client.Context) *ProviderStateQuery {
+	psq := &ProviderStateQuery{StateQuery: *NewStateQuery(ctx, clientCtx), ConflictQueryClient: conflicttypes.NewQueryClient(clientCtx), clientCtx: clientCtx, verifyPairingCache: map[uint64]map[string]*pairingtypes.QueryVerifyPairingResponse{}}
+	return psq
+}
+
+// CurrentEpochStart returns the block the current epoch started at and the block the next one starts at
+func (psq *ProviderStateQuery) CurrentEpochStart(ctx context.Context) (epoch uint64, nextEpochStart uint64, err error) {
+	epochDetails, err := psq.EpochStorageQueryClient.EpochDetails(ctx, &epochstoragetypes.QueryGetEpochDetailsRequest{})
+	if err != nil {
+		return 0, 0, utils.LavaFormatError("failed querying epoch details", err, &map[string]string{})
+	}
+	params, err := psq.EpochStorageQueryClient.Params(ctx, &epochstoragetypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, 0, utils.LavaFormatError("failed querying epochstorage params", err, &map[string]string{})
+	}
+	epoch = epochDetails.EpochDetails.StartBlock
+	return epoch, epoch + params.Params.EpochBlocks, nil
+}
+
+// VerifyPairing returns whether the consumer and provider are paired in the epoch, and the index of the provider in the pairing.
+// the pairing of an epoch doesn't change so the results are cached until the epoch is no longer served
+func (psq *ProviderStateQuery) VerifyPairing(ctx context.Context, chainID string, consumer string, provider string, epoch uint64) (valid bool, index int64, err error) {
+	key := chainID + consumer + provider
+	psq.verifyPairingLock.RLock()
+	cachedResp, ok := psq.verifyPairingCache[epoch][key]
+	psq.verifyPairingLock.RUnlock()
+	if ok {
+		return cachedResp.Valid, cachedResp.Index, nil
+	}
+
+	verifyResp, err := psq.PairingQueryClient.VerifyPairing(ctx, &pairingtypes.QueryVerifyPairingRequest{
+		ChainID:  chainID,
+		Client:   consumer,
+		Provider: provider,
+		Block:    epoch,
 	})
 	if err != nil {
-		return nil, utils.LavaFormatError("Failed Querying spec for chain", err, &map[string]string{"ChainID": chainID})
+		return false, 0, utils.LavaFormatError("failed querying verify pairing", err, &map[string]string{"chainID": chainID, "consumer": consumer, "provider": provider, "epoch": strconv.FormatUint(epoch, 10)})
 	}
-	return &spec.Spec, nil
+	psq.verifyPairingLock.Lock()
+	defer psq.verifyPairingLock.Unlock()
+	if _, ok := psq.verifyPairingCache[epoch]; !ok {
+		psq.verifyPairingCache[epoch] = map[string]*pairingtypes.QueryVerifyPairingResponse{}
+	}
+	psq.verifyPairingCache[epoch][key] = verifyResp
+	return verifyResp.Valid, verifyResp.Index, nil
+}
+
+// UpdateEpoch drops the cached pairings of epochs older than the previous one, relays from them are no longer served
+func (psq *ProviderStateQuery) UpdateEpoch(epoch uint64) {
+	psq.verifyPairingLock.Lock()
+	defer psq.verifyPairingLock.Unlock()
+	previousEpoch := uint64(0)
+	for cachedEpoch := range psq.verifyPairingCache {
+		if cachedEpoch < epoch && cachedEpoch > previousEpoch {
+			previousEpoch = cachedEpoch
+		}
+	}
+	for cachedEpoch := range psq.verifyPairingCache {
+		if cachedEpoch < previousEpoch {
+			delete(psq.verifyPairingCache, cachedEpoch)
+		}
+	}
+}
+
+func (psq *ProviderStateQuery) GetVrfPkAndMaxCuForUser(ctx context.Context, chainID string, consumer string, epoch uint64) (vrfPk *utils.VrfPubKey, maxCu uint64, err error) {
+	userEntryRes, err := psq.PairingQueryClient.UserEntry(ctx, &pairingtypes.QueryUserEntryRequest{ChainID: chainID, Address: consumer, Block: epoch})
+	if err != nil {
+		return nil, 0, utils.LavaFormatError("failed querying StakeEntry for consumer", err, &map[string]string{"chainID": chainID, "address": consumer, "block": strconv.FormatUint(epoch, 10)})
+	}
+	vrfPk = &utils.VrfPubKey{}
+	vrfPk, err = vrfPk.DecodeFromBech32(userEntryRes.GetConsumer().Vrfpk)
+	if err != nil {
+		return nil, 0, utils.LavaFormatError("failed decoding vrfpk from bech32", err, &map[string]string{"chainID": chainID, "address": consumer, "block": strconv.FormatUint(epoch, 10), "vrfpk": userEntryRes.GetConsumer().Vrfpk})
+	}
+	return vrfPk, userEntryRes.GetMaxCU(), nil
+}
+
+func (psq *ProviderStateQuery) GetProvidersCount(ctx context.Context) (uint64, error) {
+	res, err := psq.PairingQueryClient.Params(ctx, &pairingtypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, utils.LavaFormatError("failed querying pairing params", err, &map[string]string{})
+	}
+	return res.GetParams().ServicersToPairCount, nil
+}
+
+func (psq *ProviderStateQuery) GetEpochSize(ctx context.Context) (uint64, error) {
+	res, err := psq.EpochStorageQueryClient.Params(ctx, &epochstoragetypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, utils.LavaFormatError("failed querying epochstorage params", err, &map[string]string{})
+	}
+	return res.GetParams().EpochBlocks, nil
+}
+
+func (psq *ProviderStateQuery) GetRecommendedEpochNumToCollectPayment(ctx context.Context) (uint64, error) {
+	res, err := psq.PairingQueryClient.Params(ctx, &pairingtypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, utils.LavaFormatError("failed querying pairing params", err, &map[string]string{})
+	}
+	return res.GetParams().RecommendedEpochNumToCollectPayment, nil
+}
+
+// IsSessionPaid returns whether the chain paid for the session, a paid session has a unique payment entry
+func (psq *ProviderStateQuery) IsSessionPaid(ctx context.Context, chainID string, consumer string, provider string, sessionID uint64) (bool, error) {
+	// same key as the pairing keeper's EncodeUniquePaymentKey, the unique identifier is the session id in hex
+	key := string(rune(len(consumer))) + consumer + provider + strconv.FormatUint(sessionID, 16) + chainID
+	_, err := psq.PairingQueryClient.UniquePaymentStorageClientProvider(ctx, &pairingtypes.QueryGetUniquePaymentStorageClientProviderRequest{Index: key})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, utils.LavaFormatError("failed querying unique payment storage", err, &map[string]string{"chainID": chainID, "consumer": consumer, "sessionID": strconv.FormatUint(sessionID, 10)})
+	}
+	return true, nil
+}
+
+// GetConflictVote returns the conflict vote, nil when it doesn't exist
+func (psq *ProviderStateQuery) GetConflictVote(ctx context.Context, voteID string) (*conflicttypes.ConflictVote, error) {
+	conflictVote, err := psq.ConflictQueryClient.ConflictVote(ctx, &conflicttypes.QueryGetConflictVoteRequest{Index: voteID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, utils.LavaFormatError("failed querying conflict vote", err, &map[string]string{"voteID": voteID})
+	}
+	return &conflictVote.ConflictVote, nil
+}
+
+// VoteEvents returns the conflict vote events of the block, new votes are started by detection transactions
+// and votes move to their reveal state or close at the beginning of a block
+func (psq *ProviderStateQuery) VoteEvents(ctx context.Context, blockHeight int64) (votes []*reliabilitymanager.VoteParams, err error) {
+	blockResults, err := psq.clientCtx.Client.BlockResults(ctx, &blockHeight)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed querying block results", err, &map[string]string{"block": strconv.FormatInt(blockHeight, 10)})
+	}
+	for _, txResult := range blockResults.TxsResults {
+		for _, event := range txResult.Events {
+			if event.Type != utils.EventPrefix+conflicttypes.ConflictVoteDetectionEventName {
+				continue
+			}
+			vote, err := newVoteParams(eventAttributes(event))
+			if err != nil {
+				utils.LavaFormatError("failed parsing vote detection event", err, &map[string]string{"block": strconv.FormatInt(blockHeight, 10)})
+				continue
+			}
+			votes = append(votes, vote)
+		}
+	}
+	for _, event := range blockResults.BeginBlockEvents {
+		switch event.Type {
+		case utils.EventPrefix + conflicttypes.ConflictVoteRevealEventName:
+			attributes := eventAttributes(event)
+			voteDeadline, err := strconv.ParseUint(attributes["voteDeadline"], 10, 64)
+			if err != nil {
+				utils.LavaFormatError("parsing vote deadline", err, &map[string]string{"VoteDeadline": attributes["voteDeadline"]})
+				continue
+			}
+			votes = append(votes, &reliabilitymanager.VoteParams{VoteID: attributes["voteID"], VoteDeadline: voteDeadline})
+		case utils.EventPrefix + conflicttypes.ConflictVoteResolvedEventName, utils.EventPrefix + conflicttypes.ConflictVoteUnresolvedEventName:
+			votes = append(votes, &reliabilitymanager.VoteParams{VoteID: eventAttributes(event)["voteID"], CloseVote: true})
+		}
+	}
+	return votes, nil
+}
+
+func eventAttributes(event abci.Event) map[string]string {
+	attributes := map[string]string{}
+	for _, attribute := range event.Attributes {
+		attributes[string(attribute.Key)] = string(attribute.Value)
+	}
+	return attributes
+}
+
+func newVoteParams(attributes map[string]string) (*reliabilitymanager.VoteParams, error) {
+	requestBlock, err := strconv.ParseUint(attributes["requestBlock"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	voteDeadline, err := strconv.ParseUint(attributes["voteDeadline"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &reliabilitymanager.VoteParams{
+		ChainID:        attributes["chainID"],
+		ApiURL:         attributes["apiURL"],
+		RequestData:    []byte(attributes["requestData"]),
+		RequestBlock:   requestBlock,
+		Voters:         strings.Split(attributes["voters"], ","),
+		ConnectionType: attributes["connectionType"],
+		VoteDeadline:   voteDeadline,
+		VoteID:         attributes["voteID"],
+	}, nil
 }
@@ -2,12 +2,15 @@ package statetracker
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/lavanet/lava/protocol/chainlib"
 	"github.com/lavanet/lava/protocol/chaintracker"
+	"github.com/lavanet/lava/utils"
 )
 
 const (
@@ -21,6 +24,8 @@ type StateTracker struct {
 	chainTracker         *chaintracker.ChainTracker
 	registrationLock     sync.RWMutex
 	newLavaBlockUpdaters map[string]Updater
+	upgradeUpdater       *UpgradeUpdater
+	stateQuery           *StateQuery
 }
 
 type Updater interface {
@@ -29,7 +34,10 @@ type Updater interface {
 }
 
 func NewStateTracker(ctx context.Context, txFactory tx.Factory, clientCtx client.Context, chainFetcher chaintracker.ChainFetcher) (ret *StateTracker, err error) {
-	cst := &StateTracker{newLavaBlockUpdaters: map[string]Updater{}}
+	cst := &StateTracker{newLavaBlockUpdaters: map[string]Updater{}, stateQuery: NewStateQuery(ctx, clientCtx)}
+	// the upgrade updater halts and refreshes the other updaters around chain upgrades
+	cst.upgradeUpdater = NewUpgradeUpdater(cst.stateQuery)
+	cst.newLavaBlockUpdaters[cst.upgradeUpdater.UpdaterKey()] = cst.upgradeUpdater
 	resultConsensusParams, err := clientCtx.Client.ConsensusParams(ctx, nil) // nil returns latest
 	if err != nil {
 		return nil, err
@@ -60,10 +68,39 @@ func (cst *StateTracker) RegisterForUpdates(ctx context.Context, updater Updater
 	if !ok {
 		cst.newLavaBlockUpdaters[updater.UpdaterKey()] = updater
 		existingUpdater = updater
+		if refreshable, ok := updater.(UpgradeRefreshable); ok {
+			cst.upgradeUpdater.RegisterRefreshable(refreshable)
+		}
 	}
 	return existingUpdater
 }
 
+// RegisterForUpgradeHalt halts the haltable near the height the lava chain halts for an upgrade
+// and resumes it once the upgraded chain resumed and the registered updaters refreshed
+func (cst *StateTracker) RegisterForUpgradeHalt(ctx context.Context, haltable UpgradeHaltable) {
+	cst.registrationLock.Lock()
+	defer cst.registrationLock.Unlock()
+	cst.upgradeUpdater.RegisterHaltable(ctx, haltable)
+}
+
+// RegisterChainParserForSpecUpdates sets the spec on the chain parser, and sets it again whenever it is updated on chain
+func (cst *StateTracker) RegisterChainParserForSpecUpdates(ctx context.Context, chainParser chainlib.ChainParser, chainID string) error {
+	specUpdater, _ := cst.registerSpecUpdater(ctx)
+	return specUpdater.RegisterChainParser(ctx, chainParser, chainID)
+}
+
+// registerSpecUpdater returns the spec updater of the tracker, all chain parsers are registered on the same one.
+// created is true for the call that registered it
+func (cst *StateTracker) registerSpecUpdater(ctx context.Context) (specUpdater *SpecUpdater, created bool) {
+	newSpecUpdater := NewSpecUpdater(cst.stateQuery)
+	specUpdaterRaw := cst.RegisterForUpdates(ctx, newSpecUpdater)
+	specUpdater, ok := specUpdaterRaw.(*SpecUpdater)
+	if !ok {
+		utils.LavaFormatFatal("invalid updater type returned from RegisterForUpdates", nil, &map[string]string{"updater": fmt.Sprintf("%+v", specUpdaterRaw)})
+	}
+	return specUpdater, specUpdater == newSpecUpdater
+}
+
 type EpochUpdatable interface {
 	UpdateEpoch(epoch uint64)
 }
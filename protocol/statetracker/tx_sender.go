@@ -2,22 +2,31 @@ package statetracker
 
 import (
 	"context"
+	"regexp"
+	"strconv"
+	"sync"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/lavanet/lava/utils"
 	conflicttypes "github.com/lavanet/lava/x/conflict/types"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
 )
 
 const (
-	defaultGasPrice      = "0.000000001ulava"
-	defaultGasAdjustment = 1.5
+	defaultGasPrice           = "0.000000001ulava"
+	defaultGasAdjustment      = 1.5
+	RetriesOnSequenceMismatch = 2
 )
 
+var sequenceMismatchRegex = regexp.MustCompile(`account sequence mismatch, expected (\d+), got (\d+)`)
+
 type TxSender struct {
-	txFactory tx.Factory
-	clientCtx client.Context
+	txFactory    tx.Factory
+	clientCtx    client.Context
+	lock         sync.Mutex
+	nextSequence uint64 // the sequence after the last tx we sent, txs sent in the same block need consecutive sequences
 }
 
 func NewTxSender(ctx context.Context, clientCtx client.Context, txFactory tx.Factory) (ret *TxSender, err error) {
@@ -28,6 +37,9 @@ func NewTxSender(ctx context.Context, clientCtx client.Context, txFactory tx.Fac
 }
 
 func (ts *TxSender) SimulateAndBroadCastTxWithRetryOnSeqMismatch(msg sdk.Msg) error {
+	// txs are sent one at a time so each gets the next sequence
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
 	txf := ts.txFactory.WithGasPrices(defaultGasPrice)
 	txf = txf.WithGasAdjustment(defaultGasAdjustment)
 	if err := msg.ValidateBasic(); err != nil {
@@ -39,20 +51,73 @@ func (ts *TxSender) SimulateAndBroadCastTxWithRetryOnSeqMismatch(msg sdk.Msg) er
 		return err
 	}
 
-	_, gasUsed, err := tx.CalculateGas(clientCtx, txf, msg)
-	if err != nil {
-		return err
+	for retry := 0; ; retry++ {
+		var txResponse *sdk.TxResponse
+		_, gasUsed, err := tx.CalculateGas(clientCtx, txf, msg)
+		if err == nil {
+			err = ts.verifyBlockGasLimit(gasUsed)
+			if err != nil {
+				return err
+			}
+			txResponse, err = ts.broadcastTx(txf.WithGas(gasUsed), msg)
+			if err == nil && txResponse.Code == 0 {
+				ts.nextSequence = txf.Sequence() + 1
+				return nil
+			}
+			if err == nil {
+				err = utils.LavaFormatError("tx was rejected", nil, &map[string]string{"code": strconv.FormatUint(uint64(txResponse.Code), 10), "rawLog": txResponse.RawLog, "txHash": txResponse.TxHash})
+			}
+		}
+		// a previous tx that isn't in a block yet used this sequence, the error has the one the chain expects
+		sequence, found := findExpectedSequence(err.Error())
+		if !found || retry >= RetriesOnSequenceMismatch {
+			return err
+		}
+		utils.LavaFormatInfo("account sequence mismatch, retrying with the expected sequence", &map[string]string{"sequence": strconv.FormatUint(sequence, 10)})
+		txf = txf.WithSequence(sequence)
 	}
+}
 
-	txf = txf.WithGas(gasUsed)
+func (ts *TxSender) broadcastTx(txf tx.Factory, msg sdk.Msg) (*sdk.TxResponse, error) {
+	clientCtx := ts.clientCtx
+	txBuilder, err := tx.BuildUnsignedTx(txf, msg)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Sign(txf, clientCtx.GetFromName(), txBuilder, true)
+	if err != nil {
+		return nil, err
+	}
+	txBytes, err := clientCtx.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+	return clientCtx.BroadcastTx(txBytes)
+}
 
-	err = tx.GenerateOrBroadcastTxWithFactory(clientCtx, txf, msg)
+// verifyBlockGasLimit returns an error for a tx that can't fit in a block
+func (ts *TxSender) verifyBlockGasLimit(gas uint64) error {
+	resultConsensusParams, err := ts.clientCtx.Client.ConsensusParams(context.Background(), nil) // nil returns latest
 	if err != nil {
 		return err
 	}
+	maxGas := resultConsensusParams.ConsensusParams.Block.MaxGas
+	if maxGas > 0 && gas > uint64(maxGas) {
+		return utils.LavaFormatWarning("tx exceeds the block gas limit", nil, &map[string]string{"gas": strconv.FormatUint(gas, 10), "maxGas": strconv.FormatInt(maxGas, 10)})
+	}
 	return nil
 }
 
+// extract the sequence the chain expects from a tx error
+func findExpectedSequence(txError string) (sequence uint64, found bool) {
+	match := sequenceMismatchRegex.FindStringSubmatch(txError)
+	if match == nil {
+		return 0, false
+	}
+	sequence, err := strconv.ParseUint(match[1], 10, 64)
+	return sequence, err == nil
+}
+
 // this function is extracted from the tx package so that we can use it locally to set the tx factory correctly
 func (ts *TxSender) prepareFactory(txf tx.Factory) (tx.Factory, error) {
 	clientCtx := ts.clientCtx
@@ -74,6 +139,10 @@ func (ts *TxSender) prepareFactory(txf tx.Factory) (tx.Factory, error) {
 		}
 
 		if initSeq == 0 {
+			if ts.nextSequence > seq {
+				// the last tx we sent isn't in a block yet
+				seq = ts.nextSequence
+			}
 			txf = txf.WithSequence(seq)
 		}
 	}
@@ -104,3 +173,44 @@ func (ts *ConsumerTxSender) TxConflictDetection(ctx context.Context, finalizatio
 	}
 	return nil
 }
+
+type ProviderTxSender struct {
+	*TxSender
+}
+
+func NewProviderTxSender(ctx context.Context, clientCtx client.Context, txFactory tx.Factory) (ret *ProviderTxSender, err error) {
+	txSender, err := NewTxSender(ctx, clientCtx, txFactory)
+	if err != nil {
+		return nil, err
+	}
+	ts := &ProviderTxSender{TxSender: txSender}
+	return ts, nil
+}
+
+func (pts *ProviderTxSender) TxRelayPayment(ctx context.Context, relayRequests []*pairingtypes.RelayRequest, description string) error {
+	msg := pairingtypes.NewMsgRelayPayment(pts.clientCtx.FromAddress.String(), relayRequests, description)
+	err := pts.SimulateAndBroadCastTxWithRetryOnSeqMismatch(msg)
+	if err != nil {
+		return utils.LavaFormatError("relay payment - SimulateAndBroadCastTx Failed", err, &map[string]string{"relaysLen": strconv.Itoa(len(relayRequests))})
+	}
+	return nil
+}
+
+func (pts *ProviderTxSender) TxConflictVoteCommit(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error {
+	commitHash := conflicttypes.CommitVoteData(nonce, replyDataHash)
+	msg := conflicttypes.NewMsgConflictVoteCommit(pts.clientCtx.FromAddress.String(), voteID, commitHash)
+	err := pts.SimulateAndBroadCastTxWithRetryOnSeqMismatch(msg)
+	if err != nil {
+		return utils.LavaFormatError("failed to send vote commitment", err, &map[string]string{"voteID": voteID})
+	}
+	return nil
+}
+
+func (pts *ProviderTxSender) TxConflictVoteReveal(ctx context.Context, voteID string, nonce int64, replyDataHash []byte) error {
+	msg := conflicttypes.NewMsgConflictVoteReveal(pts.clientCtx.FromAddress.String(), voteID, nonce, replyDataHash)
+	err := pts.SimulateAndBroadCastTxWithRetryOnSeqMismatch(msg)
+	if err != nil {
+		return utils.LavaFormatError("failed to send vote reveal", err, &map[string]string{"voteID": voteID})
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package statetracker
+
+import (
+	"context"
+	"strconv"
+
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/lavanet/lava/utils"
+)
+
+const (
+	CallbackKeyForUpgradeUpdate = "upgrade-update"
+	// relays made this close to the upgrade height can't be paid for before the chain halts
+	BlocksBeforeUpgradeHalt = 5
+)
+
+// UpgradeHaltable is a protocol component that stops before the lava chain halts for an upgrade,
+// a consumer stops opening new sessions and a provider flushes its pending proofs
+type UpgradeHaltable interface {
+	HaltForUpgrade(ctx context.Context, planName string, haltHeight int64)
+	ResumeAfterUpgrade(ctx context.Context, planName string, haltHeight int64)
+}
+
+// UpgradeRefreshable is an updater that re-reads the state it tracks once the upgraded chain resumes,
+// specs, params and pairings can change in the upgrade handler
+type UpgradeRefreshable interface {
+	RefreshAfterUpgrade(ctx context.Context, latestBlock int64) error
+}
+
+type upgradePlanQuery interface {
+	GetUpgradePlan(ctx context.Context) (*upgradetypes.Plan, error)
+	GetAppliedPlanHeight(ctx context.Context, planName string) (int64, error)
+}
+
+type UpgradeUpdater struct {
+	haltables    []UpgradeHaltable
+	refreshables []UpgradeRefreshable
+	haltedFor    *upgradetypes.Plan // the plan the haltables are halted for, nil when not halted
+	stateQuery   upgradePlanQuery
+}
+
+func NewUpgradeUpdater(stateQuery upgradePlanQuery) *UpgradeUpdater {
+	return &UpgradeUpdater{haltables: []UpgradeHaltable{}, refreshables: []UpgradeRefreshable{}, stateQuery: stateQuery}
+}
+
+func (uu *UpgradeUpdater) UpdaterKey() string {
+	return CallbackKeyForUpgradeUpdate
+}
+
+func (uu *UpgradeUpdater) RegisterHaltable(ctx context.Context, haltable UpgradeHaltable) {
+	uu.haltables = append(uu.haltables, haltable)
+	if uu.haltedFor != nil {
+		// registered while the chain halts, it waits for the upgrade like the rest
+		haltable.HaltForUpgrade(ctx, uu.haltedFor.Name, uu.haltedFor.Height)
+	}
+}
+
+func (uu *UpgradeUpdater) RegisterRefreshable(refreshable UpgradeRefreshable) {
+	uu.refreshables = append(uu.refreshables, refreshable)
+}
+
+func (uu *UpgradeUpdater) Update(latestBlock int64) {
+	ctx := context.Background()
+	if uu.haltedFor != nil && latestBlock >= uu.haltedFor.Height {
+		// the upgraded binary committed the upgrade height, the chain resumed
+		uu.resumeAfterUpgrade(ctx, latestBlock)
+		return
+	}
+	plan, err := uu.stateQuery.GetUpgradePlan(ctx)
+	if err != nil {
+		utils.LavaFormatError("could not get the upgrade plan, trying again next block", err, &map[string]string{"latestBlock": strconv.FormatInt(latestBlock, 10)})
+		return
+	}
+	if uu.haltedFor != nil {
+		if plan == nil || plan.Name != uu.haltedFor.Name || plan.Height != uu.haltedFor.Height {
+			// the plan was cancelled or rescheduled before the halt, nothing changed on chain
+			utils.LavaFormatInfo("upgrade plan was cancelled, resuming", &map[string]string{"plan": uu.haltedFor.Name, "haltHeight": strconv.FormatInt(uu.haltedFor.Height, 10)})
+			uu.resumeHaltables(ctx)
+		}
+		return
+	}
+	if plan == nil || latestBlock < plan.Height-BlocksBeforeUpgradeHalt {
+		return
+	}
+	utils.LavaFormatInfo("lava chain is about to halt for an upgrade, halting until it resumes", &map[string]string{"plan": plan.Name, "haltHeight": strconv.FormatInt(plan.Height, 10), "latestBlock": strconv.FormatInt(latestBlock, 10)})
+	uu.haltedFor = plan
+	for _, haltable := range uu.haltables {
+		haltable.HaltForUpgrade(ctx, plan.Name, plan.Height)
+	}
+}
+
+func (uu *UpgradeUpdater) resumeAfterUpgrade(ctx context.Context, latestBlock int64) {
+	appliedHeight, err := uu.stateQuery.GetAppliedPlanHeight(ctx, uu.haltedFor.Name)
+	if err != nil {
+		utils.LavaFormatError("could not get the applied upgrade plan, trying again next block", err, &map[string]string{"plan": uu.haltedFor.Name})
+		return
+	}
+	for _, refreshable := range uu.refreshables {
+		// a failed refresh keeps the haltables halted, state from before the upgrade can't be trusted
+		err := refreshable.RefreshAfterUpgrade(ctx, latestBlock)
+		if err != nil {
+			utils.LavaFormatError("could not refresh state after the upgrade, trying again next block", err, &map[string]string{"plan": uu.haltedFor.Name, "latestBlock": strconv.FormatInt(latestBlock, 10)})
+			return
+		}
+	}
+	utils.LavaFormatInfo("lava chain resumed after the upgrade", &map[string]string{"plan": uu.haltedFor.Name, "appliedHeight": strconv.FormatInt(appliedHeight, 10), "latestBlock": strconv.FormatInt(latestBlock, 10)})
+	uu.resumeHaltables(ctx)
+}
+
+func (uu *UpgradeUpdater) resumeHaltables(ctx context.Context) {
+	plan := uu.haltedFor
+	uu.haltedFor = nil
+	for _, haltable := range uu.haltables {
+		haltable.ResumeAfterUpgrade(ctx, plan.Name, plan.Height)
+	}
+}
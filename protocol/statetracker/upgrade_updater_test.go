@@ -0,0 +1,122 @@
+package statetracker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUpgradePlanQuery struct {
+	plan         *upgradetypes.Plan
+	appliedPlans map[string]int64
+	err          error
+}
+
+func (mq *mockUpgradePlanQuery) GetUpgradePlan(ctx context.Context) (*upgradetypes.Plan, error) {
+	return mq.plan, mq.err
+}
+
+func (mq *mockUpgradePlanQuery) GetAppliedPlanHeight(ctx context.Context, planName string) (int64, error) {
+	return mq.appliedPlans[planName], mq.err
+}
+
+type mockHaltable struct {
+	halted  bool
+	halts   int
+	resumes int
+}
+
+func (mh *mockHaltable) HaltForUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	mh.halted = true
+	mh.halts++
+}
+
+func (mh *mockHaltable) ResumeAfterUpgrade(ctx context.Context, planName string, haltHeight int64) {
+	mh.halted = false
+	mh.resumes++
+}
+
+type mockRefreshable struct {
+	refreshes int
+	err       error
+}
+
+func (mr *mockRefreshable) RefreshAfterUpgrade(ctx context.Context, latestBlock int64) error {
+	mr.refreshes++
+	return mr.err
+}
+
+func TestUpgradeUpdaterHaltsAndResumes(t *testing.T) {
+	planQuery := &mockUpgradePlanQuery{appliedPlans: map[string]int64{}}
+	upgradeUpdater := NewUpgradeUpdater(planQuery)
+	haltable := &mockHaltable{}
+	refreshable := &mockRefreshable{}
+	upgradeUpdater.RegisterHaltable(context.Background(), haltable)
+	upgradeUpdater.RegisterRefreshable(refreshable)
+
+	upgradeUpdater.Update(90)
+	require.False(t, haltable.halted)
+
+	planQuery.plan = &upgradetypes.Plan{Name: "v0.7.0", Height: 100}
+	upgradeUpdater.Update(100 - BlocksBeforeUpgradeHalt - 1)
+	require.False(t, haltable.halted)
+	upgradeUpdater.Update(100 - BlocksBeforeUpgradeHalt)
+	require.True(t, haltable.halted)
+	upgradeUpdater.Update(99)
+	require.True(t, haltable.halted)
+	require.Equal(t, 1, haltable.halts)
+
+	// a haltable registered during the halt waits for the upgrade too
+	lateHaltable := &mockHaltable{}
+	upgradeUpdater.RegisterHaltable(context.Background(), lateHaltable)
+	require.True(t, lateHaltable.halted)
+
+	// the upgraded chain resumed, the refresh fails once so the halt holds until it succeeds
+	planQuery.plan = nil
+	planQuery.appliedPlans["v0.7.0"] = 100
+	refreshable.err = fmt.Errorf("pairing query failed")
+	upgradeUpdater.Update(100)
+	require.True(t, haltable.halted)
+	refreshable.err = nil
+	upgradeUpdater.Update(101)
+	require.False(t, haltable.halted)
+	require.False(t, lateHaltable.halted)
+	require.Equal(t, 2, refreshable.refreshes)
+	require.Equal(t, 1, haltable.resumes)
+
+	upgradeUpdater.Update(102)
+	require.False(t, haltable.halted)
+	require.Equal(t, 1, haltable.halts)
+}
+
+func TestUpgradeUpdaterCancelledPlan(t *testing.T) {
+	planQuery := &mockUpgradePlanQuery{plan: &upgradetypes.Plan{Name: "v0.7.0", Height: 100}}
+	upgradeUpdater := NewUpgradeUpdater(planQuery)
+	haltable := &mockHaltable{}
+	refreshable := &mockRefreshable{}
+	upgradeUpdater.RegisterHaltable(context.Background(), haltable)
+	upgradeUpdater.RegisterRefreshable(refreshable)
+
+	upgradeUpdater.Update(97)
+	require.True(t, haltable.halted)
+	// query errors don't resume, the plan might still be scheduled
+	planQuery.err = fmt.Errorf("query failed")
+	upgradeUpdater.Update(98)
+	require.True(t, haltable.halted)
+
+	planQuery.err = nil
+	planQuery.plan = nil
+	upgradeUpdater.Update(98)
+	require.False(t, haltable.halted)
+	require.Zero(t, refreshable.refreshes)
+
+	// the plan was rescheduled to a later height
+	planQuery.plan = &upgradetypes.Plan{Name: "v0.7.0", Height: 200}
+	upgradeUpdater.Update(100)
+	require.False(t, haltable.halted)
+	upgradeUpdater.Update(200 - BlocksBeforeUpgradeHalt)
+	require.True(t, haltable.halted)
+}
@@ -0,0 +1,86 @@
+package statetracker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/lavanet/lava/protocol/rpcprovider/reliabilitymanager"
+	"github.com/lavanet/lava/utils"
+)
+
+const (
+	CallbackKeyForVoteUpdate = "vote-update"
+)
+
+type VoteUpdatable interface {
+	VoteHandler(voteParams *reliabilitymanager.VoteParams, nodeHeight uint64) error
+}
+
+type voteQuery interface {
+	VoteEvents(ctx context.Context, blockHeight int64) ([]*reliabilitymanager.VoteParams, error)
+}
+
+// VoteUpdater reads the conflict vote events of every lava block and hands them to the vote updatables of the vote chain
+type VoteUpdater struct {
+	lock           sync.RWMutex
+	voteUpdatables map[string][]VoteUpdatable // key is the chain id
+	lastBlock      int64                      // the last block we read the events of
+	stateQuery     voteQuery
+}
+
+func NewVoteUpdater(stateQuery voteQuery) *VoteUpdater {
+	return &VoteUpdater{voteUpdatables: map[string][]VoteUpdatable{}, stateQuery: stateQuery}
+}
+
+func (vu *VoteUpdater) RegisterVoteUpdatable(ctx context.Context, voteUpdatable VoteUpdatable, chainID string) {
+	vu.lock.Lock()
+	defer vu.lock.Unlock()
+	vu.voteUpdatables[chainID] = append(vu.voteUpdatables[chainID], voteUpdatable)
+}
+
+func (vu *VoteUpdater) UpdaterKey() string {
+	return CallbackKeyForVoteUpdate
+}
+
+// Update reads the events of the blocks since the last update, a block we failed to read is read again on the next update
+func (vu *VoteUpdater) Update(latestBlock int64) {
+	ctx := context.Background()
+	vu.lock.Lock()
+	defer vu.lock.Unlock()
+	if vu.lastBlock == 0 {
+		vu.lastBlock = latestBlock - 1
+	}
+	for ; vu.lastBlock < latestBlock; vu.lastBlock++ {
+		blockHeight := vu.lastBlock + 1
+		votes, err := vu.stateQuery.VoteEvents(ctx, blockHeight)
+		if err != nil {
+			utils.LavaFormatError("could not get vote events, trying again later", err, &map[string]string{"block": strconv.FormatInt(blockHeight, 10)})
+			return
+		}
+		for _, voteParams := range votes {
+			go vu.handleVote(voteParams, uint64(latestBlock), vu.updatablesForVote(voteParams))
+		}
+	}
+}
+
+// new votes are handled by the first updatable of the chain that accepts them, reveals and closes are sent to all the updatables
+func (vu *VoteUpdater) handleVote(voteParams *reliabilitymanager.VoteParams, nodeHeight uint64, voteUpdatables []VoteUpdatable) {
+	for _, voteUpdatable := range voteUpdatables {
+		err := voteUpdatable.VoteHandler(voteParams, nodeHeight)
+		if err == nil && voteParams.IsNewVote() {
+			return
+		}
+	}
+}
+
+func (vu *VoteUpdater) updatablesForVote(voteParams *reliabilitymanager.VoteParams) []VoteUpdatable {
+	if voteParams.IsNewVote() {
+		return append([]VoteUpdatable{}, vu.voteUpdatables[voteParams.ChainID]...)
+	}
+	voteUpdatables := []VoteUpdatable{}
+	for _, chainVoteUpdatables := range vu.voteUpdatables {
+		voteUpdatables = append(voteUpdatables, chainVoteUpdatables...)
+	}
+	return voteUpdatables
+}
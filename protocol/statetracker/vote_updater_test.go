@@ -0,0 +1,98 @@
+package statetracker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lavanet/lava/protocol/rpcprovider/reliabilitymanager"
+	"github.com/stretchr/testify/require"
+)
+
+type mockVoteQuery struct {
+	lock    sync.Mutex
+	events  map[int64][]*reliabilitymanager.VoteParams
+	queried []int64
+	err     error
+}
+
+func (mq *mockVoteQuery) VoteEvents(ctx context.Context, blockHeight int64) ([]*reliabilitymanager.VoteParams, error) {
+	mq.lock.Lock()
+	defer mq.lock.Unlock()
+	if mq.err != nil {
+		return nil, mq.err
+	}
+	mq.queried = append(mq.queried, blockHeight)
+	return mq.events[blockHeight], nil
+}
+
+type mockVoteUpdatable struct {
+	lock   sync.Mutex
+	accept bool
+	votes  []string
+}
+
+func (mu *mockVoteUpdatable) VoteHandler(voteParams *reliabilitymanager.VoteParams, nodeHeight uint64) error {
+	mu.lock.Lock()
+	defer mu.lock.Unlock()
+	mu.votes = append(mu.votes, voteParams.VoteID)
+	if !mu.accept {
+		return fmt.Errorf("vote not for this endpoint")
+	}
+	return nil
+}
+
+func (mu *mockVoteUpdatable) handledVotes() []string {
+	mu.lock.Lock()
+	defer mu.lock.Unlock()
+	return append([]string{}, mu.votes...)
+}
+
+func TestVoteUpdaterReadsEveryBlock(t *testing.T) {
+	ctx := context.Background()
+	voteQuery := &mockVoteQuery{events: map[int64][]*reliabilitymanager.VoteParams{}}
+	voteUpdater := NewVoteUpdater(voteQuery)
+	voteUpdatable := &mockVoteUpdatable{accept: true}
+	voteUpdater.RegisterVoteUpdatable(ctx, voteUpdatable, "LAV1")
+
+	voteUpdater.Update(100)
+	require.Equal(t, []int64{100}, voteQuery.queried)
+	// blocks the chain tracker skipped are read too
+	voteUpdater.Update(103)
+	require.Equal(t, []int64{100, 101, 102, 103}, voteQuery.queried)
+
+	// a block that failed is read again on the next update
+	voteQuery.err = fmt.Errorf("node unavailable")
+	voteUpdater.Update(104)
+	voteQuery.err = nil
+	voteQuery.events[104] = []*reliabilitymanager.VoteParams{{ChainID: "LAV1", VoteID: "1"}}
+	voteUpdater.Update(105)
+	require.Equal(t, []int64{100, 101, 102, 103, 104, 105}, voteQuery.queried)
+	require.Eventually(t, func() bool { return len(voteUpdatable.handledVotes()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestVoteUpdaterRoutesVotes(t *testing.T) {
+	ctx := context.Background()
+	voteQuery := &mockVoteQuery{events: map[int64][]*reliabilitymanager.VoteParams{}}
+	voteUpdater := NewVoteUpdater(voteQuery)
+	rejecting := &mockVoteUpdatable{}
+	accepting := &mockVoteUpdatable{accept: true}
+	otherChain := &mockVoteUpdatable{accept: true}
+	voteUpdater.RegisterVoteUpdatable(ctx, rejecting, "LAV1")
+	voteUpdater.RegisterVoteUpdatable(ctx, accepting, "LAV1")
+	voteUpdater.RegisterVoteUpdatable(ctx, otherChain, "ETH1")
+
+	voteQuery.events[100] = []*reliabilitymanager.VoteParams{{ChainID: "LAV1", VoteID: "1"}}
+	voteUpdater.Update(100)
+	require.Eventually(t, func() bool { return len(accepting.handledVotes()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"1"}, rejecting.handledVotes())
+	require.Len(t, otherChain.handledVotes(), 0)
+
+	// reveals and closes don't have a chain, all the updatables get them
+	voteQuery.events[101] = []*reliabilitymanager.VoteParams{{VoteID: "1", VoteDeadline: 120}}
+	voteUpdater.Update(101)
+	require.Eventually(t, func() bool { return len(otherChain.handledVotes()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return len(accepting.handledVotes()) == 2 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,184 @@
+package upgradewatcher
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lavanet/lava/utils"
+)
+
+const (
+	WatcherDirName  = "upgrade-watcher"
+	GenesisDirName  = "genesis"
+	UpgradesDirName = "upgrades"
+	CurrentLinkName = "current"
+	BinDirName      = "bin"
+)
+
+// BinaryLayout manages the staged binaries under <home>/upgrade-watcher:
+//
+//	genesis/bin/<daemon>         the binary the watcher was first started with
+//	upgrades/<name>/bin/<daemon> binaries staged for upgrade plans
+//	current -> genesis | upgrades/<name>
+type BinaryLayout struct {
+	root       string
+	daemonName string
+}
+
+func NewBinaryLayout(home string, daemonName string) *BinaryLayout {
+	return &BinaryLayout{root: filepath.Join(home, WatcherDirName), daemonName: daemonName}
+}
+
+func (bl *BinaryLayout) GenesisBinary() string {
+	return filepath.Join(bl.root, GenesisDirName, BinDirName, bl.daemonName)
+}
+
+func (bl *BinaryLayout) UpgradeDir(upgradeName string) string {
+	return filepath.Join(bl.root, UpgradesDirName, upgradeName)
+}
+
+func (bl *BinaryLayout) UpgradeBinary(upgradeName string) string {
+	return filepath.Join(bl.UpgradeDir(upgradeName), BinDirName, bl.daemonName)
+}
+
+func (bl *BinaryLayout) CurrentBinary() string {
+	return filepath.Join(bl.root, CurrentLinkName, BinDirName, bl.daemonName)
+}
+
+// CurrentUpgrade returns the upgrade name the current link points to, empty for genesis
+func (bl *BinaryLayout) CurrentUpgrade() (string, error) {
+	target, err := os.Readlink(filepath.Join(bl.root, CurrentLinkName))
+	if err != nil {
+		return "", err
+	}
+	if filepath.Base(target) == GenesisDirName {
+		return "", nil
+	}
+	return filepath.Base(target), nil
+}
+
+// Init creates the genesis binary from genesisBinary and points current to it, if current doesn't exist yet
+func (bl *BinaryLayout) Init(genesisBinary string) error {
+	if _, err := os.Lstat(filepath.Join(bl.root, CurrentLinkName)); err == nil {
+		return nil
+	}
+	if err := copyExecutable(genesisBinary, bl.GenesisBinary()); err != nil {
+		return utils.LavaFormatError("failed creating genesis binary", err, &map[string]string{"source": genesisBinary})
+	}
+	return bl.setCurrent(filepath.Join(bl.root, GenesisDirName))
+}
+
+// IsStaged returns true if the binary for upgradeName already exists and matches the checksum
+func (bl *BinaryLayout) IsStaged(upgradeName string, planBinary *PlanBinary) bool {
+	return verifyChecksum(bl.UpgradeBinary(upgradeName), planBinary) == nil
+}
+
+// Stage copies the matching artifact into the upgrade directory after verifying it against the plan checksum
+func (bl *BinaryLayout) Stage(upgradeName string, planBinary *PlanBinary, artifactsDir string) (string, error) {
+	artifact, err := bl.findArtifact(upgradeName, planBinary, artifactsDir)
+	if err != nil {
+		return "", err
+	}
+	if err := verifyChecksum(artifact, planBinary); err != nil {
+		return "", err
+	}
+	destination := bl.UpgradeBinary(upgradeName)
+	if err := copyExecutable(artifact, destination); err != nil {
+		return "", utils.LavaFormatError("failed staging upgrade binary", err, &map[string]string{"source": artifact, "destination": destination})
+	}
+	// verify again so a partially written file is never used
+	if err := verifyChecksum(destination, planBinary); err != nil {
+		os.Remove(destination)
+		return "", err
+	}
+	return destination, nil
+}
+
+// Swap points current at the staged binary of upgradeName
+func (bl *BinaryLayout) Swap(upgradeName string) error {
+	if _, err := os.Stat(bl.UpgradeBinary(upgradeName)); err != nil {
+		return utils.LavaFormatError("upgrade binary was not staged", err, &map[string]string{"upgrade": upgradeName})
+	}
+	return bl.setCurrent(bl.UpgradeDir(upgradeName))
+}
+
+// findArtifact looks for, in order: <artifacts>/<upgrade>/<daemon>, <artifacts>/<upgrade>/bin/<daemon>, <artifacts>/<url file name>
+func (bl *BinaryLayout) findArtifact(upgradeName string, planBinary *PlanBinary, artifactsDir string) (string, error) {
+	candidates := []string{
+		filepath.Join(artifactsDir, upgradeName, bl.daemonName),
+		filepath.Join(artifactsDir, upgradeName, BinDirName, bl.daemonName),
+	}
+	if planBinary.FileName != "" && planBinary.FileName != "." && planBinary.FileName != "/" {
+		candidates = append(candidates, filepath.Join(artifactsDir, planBinary.FileName))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", utils.LavaFormatError("could not find upgrade binary", ArtifactNotFoundError, &map[string]string{"upgrade": upgradeName, "artifactsDir": artifactsDir})
+}
+
+// setCurrent replaces the current link atomically so a crash never leaves it missing
+func (bl *BinaryLayout) setCurrent(target string) error {
+	tmpLink := filepath.Join(bl.root, CurrentLinkName+".tmp")
+	os.Remove(tmpLink)
+	if err := os.Symlink(target, tmpLink); err != nil {
+		return err
+	}
+	return os.Rename(tmpLink, filepath.Join(bl.root, CurrentLinkName))
+}
+
+func verifyChecksum(file string, planBinary *PlanBinary) error {
+	var hasher hash.Hash
+	switch planBinary.ChecksumType {
+	case ChecksumSHA256:
+		hasher = sha256.New()
+	case ChecksumSHA512:
+		hasher = sha512.New()
+	default:
+		return utils.LavaFormatError("checksum type not supported", UnsupportedChecksumError, &map[string]string{"checksumType": planBinary.ChecksumType})
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if sum != planBinary.Checksum {
+		return utils.LavaFormatError("binary checksum mismatch", ChecksumMismatchError, &map[string]string{"file": file, "expected": planBinary.Checksum, "actual": sum})
+	}
+	return nil
+}
+
+func copyExecutable(source string, destination string) error {
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmpDestination := destination + ".tmp"
+	out, err := os.OpenFile(tmpDestination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpDestination, destination)
+}
@@ -0,0 +1,52 @@
+package upgradewatcher
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/lavanet/lava/utils"
+)
+
+// DaemonProcess is a running "<daemon> start" child process
+type DaemonProcess struct {
+	cmd    *exec.Cmd
+	exited chan error
+}
+
+func StartDaemon(binary string, args []string) (*DaemonProcess, error) {
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, utils.LavaFormatError("failed starting daemon", err, &map[string]string{"binary": binary})
+	}
+	dp := &DaemonProcess{cmd: cmd, exited: make(chan error, 1)}
+	go func() {
+		dp.exited <- cmd.Wait()
+		close(dp.exited)
+	}()
+	utils.LavaFormatInfo("started daemon", &map[string]string{"binary": binary, "pid": strconv.Itoa(cmd.Process.Pid)})
+	return dp, nil
+}
+
+// Exited is closed after the process exits, the exit error is sent before closing
+func (dp *DaemonProcess) Exited() <-chan error {
+	return dp.exited
+}
+
+// Stop interrupts the process and kills it if it didn't exit after timeout
+func (dp *DaemonProcess) Stop(timeout time.Duration) {
+	if err := dp.cmd.Process.Signal(os.Interrupt); err != nil {
+		// already exited
+		return
+	}
+	select {
+	case <-dp.exited:
+	case <-time.After(timeout):
+		utils.LavaFormatWarning("daemon did not stop after interrupt, killing it", nil, &map[string]string{"timeout": timeout.String()})
+		dp.cmd.Process.Kill()
+		<-dp.exited
+	}
+}
@@ -0,0 +1,15 @@
+package upgradewatcher
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var (
+	InvalidPlanInfoError          = sdkerrors.New("InvalidPlanInfo Error", 10901, "upgrade plan info is not a valid binaries json")
+	MissingBinaryForPlatformError = sdkerrors.New("MissingBinaryForPlatform Error", 10902, "upgrade plan info has no binary for this platform")
+	MissingChecksumError          = sdkerrors.New("MissingChecksum Error", 10903, "upgrade plan binary has no checksum")
+	UnsupportedChecksumError      = sdkerrors.New("UnsupportedChecksum Error", 10904, "upgrade plan binary checksum type is not supported")
+	ChecksumMismatchError         = sdkerrors.New("ChecksumMismatch Error", 10905, "staged binary checksum does not match the upgrade plan")
+	ArtifactNotFoundError         = sdkerrors.New("ArtifactNotFound Error", 10906, "no binary for the upgrade was found in the artifacts directory")
+	DaemonExitedError             = sdkerrors.New("DaemonExited Error", 10907, "daemon process exited without reaching an upgrade height")
+)
@@ -0,0 +1,82 @@
+package upgradewatcher
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/lavanet/lava/utils"
+)
+
+const (
+	AnyPlatform    = "any"
+	ChecksumParam  = "checksum"
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
+)
+
+// PlanInfo is the json saved in the Info field of a software-upgrade plan, same format cosmovisor uses:
+// {"binaries":{"linux/amd64":"https://host/lavad-linux-amd64?checksum=sha256:<hex>"}}
+type PlanInfo struct {
+	Binaries map[string]string `json:"binaries"`
+}
+
+// PlanBinary is the binary a plan expects this platform to run
+type PlanBinary struct {
+	Platform     string
+	Url          string
+	FileName     string // last path element of the url, used to look it up in the artifacts directory
+	ChecksumType string
+	Checksum     string
+}
+
+func CurrentPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+func ParsePlanInfo(info string) (*PlanInfo, error) {
+	planInfo := &PlanInfo{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(info)), planInfo); err != nil {
+		return nil, utils.LavaFormatError("failed parsing upgrade plan info", InvalidPlanInfoError, &map[string]string{"info": info, "error": err.Error()})
+	}
+	if len(planInfo.Binaries) == 0 {
+		return nil, utils.LavaFormatError("upgrade plan info has no binaries", InvalidPlanInfoError, &map[string]string{"info": info})
+	}
+	return planInfo, nil
+}
+
+// BinaryForPlatform returns the binary matching platform, falling back to the "any" entry
+func (pi *PlanInfo) BinaryForPlatform(platform string) (*PlanBinary, error) {
+	binaryUrl, ok := pi.Binaries[platform]
+	if !ok {
+		binaryUrl, ok = pi.Binaries[AnyPlatform]
+		if !ok {
+			return nil, utils.LavaFormatError("no binary in upgrade plan info", MissingBinaryForPlatformError, &map[string]string{"platform": platform})
+		}
+	}
+	parsedUrl, err := url.Parse(binaryUrl)
+	if err != nil {
+		return nil, utils.LavaFormatError("failed parsing binary url", InvalidPlanInfoError, &map[string]string{"url": binaryUrl, "error": err.Error()})
+	}
+	checksum := parsedUrl.Query().Get(ChecksumParam)
+	if checksum == "" {
+		return nil, utils.LavaFormatError("binary url has no checksum", MissingChecksumError, &map[string]string{"url": binaryUrl})
+	}
+	checksumType, checksumValue, found := strings.Cut(checksum, ":")
+	if !found || checksumValue == "" {
+		return nil, utils.LavaFormatError("checksum should be in the form <type>:<hex>", MissingChecksumError, &map[string]string{"checksum": checksum})
+	}
+	checksumType = strings.ToLower(checksumType)
+	if checksumType != ChecksumSHA256 && checksumType != ChecksumSHA512 {
+		return nil, utils.LavaFormatError("checksum type not supported", UnsupportedChecksumError, &map[string]string{"checksumType": checksumType})
+	}
+	return &PlanBinary{
+		Platform:     platform,
+		Url:          binaryUrl,
+		FileName:     path.Base(parsedUrl.Path),
+		ChecksumType: checksumType,
+		Checksum:     strings.ToLower(checksumValue),
+	}, nil
+}
@@ -0,0 +1,256 @@
+package upgradewatcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradekeeper "github.com/cosmos/cosmos-sdk/x/upgrade/keeper"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/lavanet/lava/utils"
+)
+
+const (
+	ArtifactsDirFlag = "artifacts-dir"
+	PollIntervalFlag = "poll-interval"
+	StopTimeoutFlag  = "stop-timeout"
+	DaemonStartCmd   = "start"
+
+	DefaultPollInterval = 5 * time.Second
+	DefaultStopTimeout  = 30 * time.Second
+)
+
+type UpgradeWatcherConfig struct {
+	Home         string        // node home, upgrade-info.json is read from <home>/data
+	DaemonName   string        // binary file name inside the bin directories
+	ArtifactsDir string        // local directory binaries are staged from
+	PollInterval time.Duration // how often to query the current plan
+	StopTimeout  time.Duration // how long to wait for the daemon to exit after an interrupt
+	StartArgs    []string      // extra arguments passed to "<daemon> start"
+}
+
+// PlanFetcher returns the currently scheduled upgrade plan, nil if there is none
+type PlanFetcher interface {
+	CurrentPlan(ctx context.Context) (*upgradetypes.Plan, error)
+}
+
+type LavaPlanFetcher struct {
+	queryClient upgradetypes.QueryClient
+}
+
+func NewLavaPlanFetcher(clientCtx client.Context) *LavaPlanFetcher {
+	return &LavaPlanFetcher{queryClient: upgradetypes.NewQueryClient(clientCtx)}
+}
+
+func (lpf *LavaPlanFetcher) CurrentPlan(ctx context.Context) (*upgradetypes.Plan, error) {
+	res, err := lpf.queryClient.CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Plan, nil
+}
+
+// UpgradeWatcher runs "<daemon> start" as a child process, stages the binary of every scheduled plan
+// and once the node halts at the plan height it swaps the binary and restarts the daemon
+type UpgradeWatcher struct {
+	config      UpgradeWatcherConfig
+	planFetcher PlanFetcher
+	layout      *BinaryLayout
+	stagedPlan  *upgradetypes.Plan
+	// the upgrade of an upgrade-info.json left over from a halt the node already upgraded from, it isn't a halt
+	appliedUpgrade string
+}
+
+func NewUpgradeWatcher(config UpgradeWatcherConfig, planFetcher PlanFetcher) *UpgradeWatcher {
+	if config.PollInterval == 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.StopTimeout == 0 {
+		config.StopTimeout = DefaultStopTimeout
+	}
+	return &UpgradeWatcher{
+		config:      config,
+		planFetcher: planFetcher,
+		layout:      NewBinaryLayout(config.Home, config.DaemonName),
+	}
+}
+
+func (uw *UpgradeWatcher) Layout() *BinaryLayout {
+	return uw.layout
+}
+
+// Run blocks until ctx is done or the daemon exits for a reason other than an upgrade
+func (uw *UpgradeWatcher) Run(ctx context.Context, genesisBinary string) error {
+	if err := uw.layout.Init(genesisBinary); err != nil {
+		return err
+	}
+	uw.skipAppliedUpgrade()
+	daemon, err := uw.startDaemon()
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(uw.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			daemon.Stop(uw.config.StopTimeout)
+			return nil
+		case exitErr := <-daemon.Exited():
+			// the node exits on its own at the halt height in some configurations (halt-height, panics)
+			uw.skipAppliedUpgrade()
+			upgraded, err := uw.upgradeIfHalted(ctx)
+			if err != nil {
+				return err
+			}
+			if !upgraded {
+				return utils.LavaFormatError("daemon exited", DaemonExitedError, &map[string]string{"exitError": fmt.Sprintf("%v", exitErr)})
+			}
+			daemon, err = uw.startDaemon()
+			if err != nil {
+				return err
+			}
+		case <-ticker.C:
+			uw.stagePlan(ctx)
+			planName, halted := uw.haltedForUpgrade()
+			if !halted {
+				continue
+			}
+			utils.LavaFormatInfo("node reached upgrade halt height, stopping daemon", &map[string]string{"upgrade": planName})
+			daemon.Stop(uw.config.StopTimeout)
+			if _, err := uw.upgradeIfHalted(ctx); err != nil {
+				return err
+			}
+			daemon, err = uw.startDaemon()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// stagePlan stages the binary of the current plan if it wasn't staged yet, failures are retried on the next poll
+func (uw *UpgradeWatcher) stagePlan(ctx context.Context) {
+	plan, err := uw.planFetcher.CurrentPlan(ctx)
+	if err != nil {
+		utils.LavaFormatWarning("failed querying current upgrade plan", err, nil)
+		return
+	}
+	if plan == nil {
+		uw.stagedPlan = nil
+		return
+	}
+	if uw.stagedPlan != nil && uw.stagedPlan.Name == plan.Name {
+		return
+	}
+	if err := uw.Stage(plan); err != nil {
+		utils.LavaFormatError("failed staging binary for upgrade plan, will retry", err, &map[string]string{"upgrade": plan.Name, "height": strconv.FormatInt(plan.Height, 10)})
+		return
+	}
+	uw.stagedPlan = plan
+}
+
+// Stage verifies and copies the binary for plan from the artifacts directory
+func (uw *UpgradeWatcher) Stage(plan *upgradetypes.Plan) error {
+	planInfo, err := ParsePlanInfo(plan.Info)
+	if err != nil {
+		return err
+	}
+	planBinary, err := planInfo.BinaryForPlatform(CurrentPlatform())
+	if err != nil {
+		return err
+	}
+	if uw.layout.IsStaged(plan.Name, planBinary) {
+		return nil
+	}
+	staged, err := uw.layout.Stage(plan.Name, planBinary, uw.config.ArtifactsDir)
+	if err != nil {
+		return err
+	}
+	utils.LavaFormatInfo("staged binary for upgrade plan", &map[string]string{"upgrade": plan.Name, "height": strconv.FormatInt(plan.Height, 10), "binary": staged})
+	return nil
+}
+
+// haltedForUpgrade returns the upgrade name if the node wrote upgrade-info.json for an upgrade it isn't running yet
+func (uw *UpgradeWatcher) haltedForUpgrade() (string, bool) {
+	upgradeInfo, err := uw.readUpgradeInfo()
+	if err != nil || upgradeInfo.Name == "" || upgradeInfo.Name == uw.appliedUpgrade {
+		return "", false
+	}
+	current, err := uw.layout.CurrentUpgrade()
+	if err != nil || current == upgradeInfo.Name {
+		return "", false
+	}
+	return upgradeInfo.Name, true
+}
+
+// upgradeIfHalted swaps the current binary if the node halted for an upgrade, returns true if it did
+func (uw *UpgradeWatcher) upgradeIfHalted(ctx context.Context) (bool, error) {
+	planName, halted := uw.haltedForUpgrade()
+	if !halted {
+		return false, nil
+	}
+	if uw.stagedPlan == nil || uw.stagedPlan.Name != planName {
+		// the plan could have been staged by a previous run, or missed between polls
+		uw.stagePlan(ctx)
+	}
+	if err := uw.layout.Swap(planName); err != nil {
+		return false, utils.LavaFormatError("failed swapping binary at upgrade height", err, &map[string]string{"upgrade": planName})
+	}
+	utils.LavaFormatInfo("swapped daemon binary for upgrade", &map[string]string{"upgrade": planName, "binary": uw.layout.CurrentBinary()})
+	return true, nil
+}
+
+// skipAppliedUpgrade ignores the upgrade-info.json of an upgrade the node committed blocks after, the node halts
+// with its last committed block right before the plan height. the application db is locked while the daemon runs
+func (uw *UpgradeWatcher) skipAppliedUpgrade() {
+	upgradeInfo, err := uw.readUpgradeInfo()
+	if err != nil || upgradeInfo.Name == "" {
+		return
+	}
+	lastCommittedHeight, err := uw.lastCommittedHeight()
+	if err != nil {
+		utils.LavaFormatWarning("failed reading the last committed height of the node", err, &map[string]string{"upgrade": upgradeInfo.Name})
+		return
+	}
+	if lastCommittedHeight >= upgradeInfo.Height {
+		utils.LavaFormatInfo("upgrade was already applied, ignoring its upgrade info", &map[string]string{"upgrade": upgradeInfo.Name, "height": strconv.FormatInt(upgradeInfo.Height, 10), "lastCommittedHeight": strconv.FormatInt(lastCommittedHeight, 10)})
+		uw.appliedUpgrade = upgradeInfo.Name
+	}
+}
+
+// lastCommittedHeight reads the latest version of the node's application db, the way the node opens it
+func (uw *UpgradeWatcher) lastCommittedHeight() (int64, error) {
+	db, err := sdk.NewLevelDB("application", filepath.Join(uw.config.Home, "data"))
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+	return rootmulti.GetLatestVersion(db), nil
+}
+
+// readUpgradeInfo reads the upgrade-info.json the upgrade module dumps when the node halts for a plan
+func (uw *UpgradeWatcher) readUpgradeInfo() (*storetypes.UpgradeInfo, error) {
+	bz, err := os.ReadFile(filepath.Join(uw.config.Home, "data", upgradekeeper.UpgradeInfoFileName))
+	if err != nil {
+		return nil, err
+	}
+	upgradeInfo := &storetypes.UpgradeInfo{}
+	if err := json.Unmarshal(bz, upgradeInfo); err != nil {
+		return nil, err
+	}
+	return upgradeInfo, nil
+}
+
+func (uw *UpgradeWatcher) startDaemon() (*DaemonProcess, error) {
+	args := append([]string{DaemonStartCmd, "--home", uw.config.Home}, uw.config.StartArgs...)
+	return StartDaemon(uw.layout.CurrentBinary(), args)
+}
@@ -0,0 +1,266 @@
+package upgradewatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	testDaemonName  = "lavad"
+	testUpgradeName = "v0.7.0"
+)
+
+type mockPlanFetcher struct {
+	plan  *upgradetypes.Plan
+	mutex sync.Mutex
+}
+
+func (mpf *mockPlanFetcher) CurrentPlan(ctx context.Context) (*upgradetypes.Plan, error) {
+	mpf.mutex.Lock()
+	defer mpf.mutex.Unlock()
+	return mpf.plan, nil
+}
+
+func writeScript(t *testing.T, path string, script string) string {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	content := "#!/bin/sh\n" + script + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o755))
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func planInfoWithChecksum(checksum string) string {
+	return fmt.Sprintf(`{"binaries":{"%s":"https://example.com/lavad-linux-amd64?checksum=sha256:%s"}}`, CurrentPlatform(), checksum)
+}
+
+func TestParsePlanInfo(t *testing.T) {
+	checksum := "ABCDEF0123"
+	planInfo, err := ParsePlanInfo(planInfoWithChecksum(checksum))
+	require.NoError(t, err)
+	planBinary, err := planInfo.BinaryForPlatform(CurrentPlatform())
+	require.NoError(t, err)
+	require.Equal(t, ChecksumSHA256, planBinary.ChecksumType)
+	require.Equal(t, "abcdef0123", planBinary.Checksum)
+	require.Equal(t, "lavad-linux-amd64", planBinary.FileName)
+
+	// fallback to any
+	planInfo, err = ParsePlanInfo(`{"binaries":{"any":"https://example.com/lavad?checksum=sha512:aa"}}`)
+	require.NoError(t, err)
+	planBinary, err = planInfo.BinaryForPlatform("plan9/386")
+	require.NoError(t, err)
+	require.Equal(t, ChecksumSHA512, planBinary.ChecksumType)
+
+	_, err = ParsePlanInfo("not json")
+	require.Error(t, err)
+	_, err = ParsePlanInfo(`{"binaries":{}}`)
+	require.Error(t, err)
+
+	planInfo, err = ParsePlanInfo(`{"binaries":{"any":"https://example.com/lavad"}}`)
+	require.NoError(t, err)
+	_, err = planInfo.BinaryForPlatform(CurrentPlatform())
+	require.ErrorIs(t, err, MissingChecksumError)
+
+	planInfo, err = ParsePlanInfo(`{"binaries":{"any":"https://example.com/lavad?checksum=md5:aa"}}`)
+	require.NoError(t, err)
+	_, err = planInfo.BinaryForPlatform(CurrentPlatform())
+	require.ErrorIs(t, err, UnsupportedChecksumError)
+
+	planInfo, err = ParsePlanInfo(`{"binaries":{"windows/amd64":"https://example.com/lavad?checksum=sha256:aa"}}`)
+	require.NoError(t, err)
+	_, err = planInfo.BinaryForPlatform("linux/arm64")
+	require.ErrorIs(t, err, MissingBinaryForPlatformError)
+}
+
+func TestStageAndSwap(t *testing.T) {
+	home := t.TempDir()
+	artifacts := t.TempDir()
+	checksum := writeScript(t, filepath.Join(artifacts, testUpgradeName, testDaemonName), "echo upgraded")
+
+	layout := NewBinaryLayout(home, testDaemonName)
+	genesisSource := filepath.Join(t.TempDir(), testDaemonName)
+	writeScript(t, genesisSource, "echo genesis")
+	require.NoError(t, layout.Init(genesisSource))
+	current, err := layout.CurrentUpgrade()
+	require.NoError(t, err)
+	require.Equal(t, "", current)
+
+	planInfo, err := ParsePlanInfo(planInfoWithChecksum(checksum))
+	require.NoError(t, err)
+	planBinary, err := planInfo.BinaryForPlatform(CurrentPlatform())
+	require.NoError(t, err)
+	require.False(t, layout.IsStaged(testUpgradeName, planBinary))
+
+	// swapping before staging fails
+	require.Error(t, layout.Swap(testUpgradeName))
+
+	staged, err := layout.Stage(testUpgradeName, planBinary, artifacts)
+	require.NoError(t, err)
+	require.Equal(t, layout.UpgradeBinary(testUpgradeName), staged)
+	require.True(t, layout.IsStaged(testUpgradeName, planBinary))
+
+	require.NoError(t, layout.Swap(testUpgradeName))
+	current, err = layout.CurrentUpgrade()
+	require.NoError(t, err)
+	require.Equal(t, testUpgradeName, current)
+	content, err := os.ReadFile(layout.CurrentBinary())
+	require.NoError(t, err)
+	require.Contains(t, string(content), "echo upgraded")
+
+	// Init doesn't override an existing current link
+	require.NoError(t, layout.Init(genesisSource))
+	current, err = layout.CurrentUpgrade()
+	require.NoError(t, err)
+	require.Equal(t, testUpgradeName, current)
+}
+
+func TestStageChecksumMismatch(t *testing.T) {
+	home := t.TempDir()
+	artifacts := t.TempDir()
+	writeScript(t, filepath.Join(artifacts, testUpgradeName, testDaemonName), "echo tampered")
+	planInfo, err := ParsePlanInfo(planInfoWithChecksum(hex.EncodeToString(make([]byte, sha256.Size))))
+	require.NoError(t, err)
+	planBinary, err := planInfo.BinaryForPlatform(CurrentPlatform())
+	require.NoError(t, err)
+
+	layout := NewBinaryLayout(home, testDaemonName)
+	_, err = layout.Stage(testUpgradeName, planBinary, artifacts)
+	require.ErrorIs(t, err, ChecksumMismatchError)
+	_, err = os.Stat(layout.UpgradeBinary(testUpgradeName))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestStageArtifactByUrlFileName(t *testing.T) {
+	home := t.TempDir()
+	artifacts := t.TempDir()
+	checksum := writeScript(t, filepath.Join(artifacts, "lavad-linux-amd64"), "echo upgraded")
+	planInfo, err := ParsePlanInfo(planInfoWithChecksum(checksum))
+	require.NoError(t, err)
+	planBinary, err := planInfo.BinaryForPlatform(CurrentPlatform())
+	require.NoError(t, err)
+
+	layout := NewBinaryLayout(home, testDaemonName)
+	_, err = layout.Stage(testUpgradeName, planBinary, artifacts)
+	require.NoError(t, err)
+
+	_, err = layout.Stage(testUpgradeName, planBinary, t.TempDir())
+	require.ErrorIs(t, err, ArtifactNotFoundError)
+}
+
+func TestUpgradeWatcherRun(t *testing.T) {
+	home := t.TempDir()
+	artifacts := t.TempDir()
+	upgradedMarker := filepath.Join(home, "upgraded")
+	// the genesis daemon halts by writing upgrade-info.json like the upgrade module does, and waits to be stopped
+	genesisSource := filepath.Join(t.TempDir(), testDaemonName)
+	writeScript(t, genesisSource, fmt.Sprintf(`mkdir -p "$3/data"
+sleep 0.2
+echo '{"name":"%s","height":100}' > "$3/data/upgrade-info.json"
+while true; do sleep 0.05; done`, testUpgradeName))
+	checksum := writeScript(t, filepath.Join(artifacts, testUpgradeName, testDaemonName), fmt.Sprintf(`touch %s
+while true; do sleep 0.05; done`, upgradedMarker))
+
+	planFetcher := &mockPlanFetcher{plan: &upgradetypes.Plan{Name: testUpgradeName, Height: 100, Info: planInfoWithChecksum(checksum)}}
+	upgradeWatcher := NewUpgradeWatcher(UpgradeWatcherConfig{
+		Home:         home,
+		DaemonName:   testDaemonName,
+		ArtifactsDir: artifacts,
+		PollInterval: 20 * time.Millisecond,
+		StopTimeout:  time.Second,
+	}, planFetcher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- upgradeWatcher.Run(ctx, genesisSource)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(upgradedMarker)
+		return err == nil
+	}, 10*time.Second, 20*time.Millisecond)
+	current, err := upgradeWatcher.Layout().CurrentUpgrade()
+	require.NoError(t, err)
+	require.Equal(t, testUpgradeName, current)
+
+	cancel()
+	select {
+	case err := <-runErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("upgrade watcher did not stop")
+	}
+}
+
+func TestUpgradeWatcherIgnoresAppliedUpgrade(t *testing.T) {
+	home := t.TempDir()
+	artifacts := t.TempDir()
+	upgradedMarker := filepath.Join(home, "upgraded")
+
+	// the node committed blocks after an upgrade it halted for, its upgrade-info.json was left in the data directory
+	dataDir := filepath.Join(home, "data")
+	db, err := sdk.NewLevelDB("application", dataDir)
+	require.NoError(t, err)
+	multiStore := rootmulti.NewStore(db, log.NewNopLogger())
+	multiStore.MountStoreWithDB(storetypes.NewKVStoreKey("test"), storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, multiStore.LoadLatestVersion())
+	for i := 0; i < 5; i++ {
+		multiStore.Commit()
+	}
+	require.NoError(t, db.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "upgrade-info.json"), []byte(`{"name":"v0.6.0","height":3}`), 0o644))
+
+	// the running binary halts later for the next upgrade
+	genesisSource := filepath.Join(t.TempDir(), testDaemonName)
+	writeScript(t, genesisSource, fmt.Sprintf(`sleep 0.3
+echo '{"name":"%s","height":100}' > "$3/data/upgrade-info.json"
+while true; do sleep 0.05; done`, testUpgradeName))
+	checksum := writeScript(t, filepath.Join(artifacts, testUpgradeName, testDaemonName), fmt.Sprintf(`touch %s
+while true; do sleep 0.05; done`, upgradedMarker))
+
+	planFetcher := &mockPlanFetcher{plan: &upgradetypes.Plan{Name: testUpgradeName, Height: 100, Info: planInfoWithChecksum(checksum)}}
+	upgradeWatcher := NewUpgradeWatcher(UpgradeWatcherConfig{
+		Home:         home,
+		DaemonName:   testDaemonName,
+		ArtifactsDir: artifacts,
+		PollInterval: 20 * time.Millisecond,
+		StopTimeout:  time.Second,
+	}, planFetcher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- upgradeWatcher.Run(ctx, genesisSource)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(upgradedMarker)
+		return err == nil
+	}, 10*time.Second, 20*time.Millisecond)
+	current, err := upgradeWatcher.Layout().CurrentUpgrade()
+	require.NoError(t, err)
+	require.Equal(t, testUpgradeName, current)
+
+	cancel()
+	select {
+	case err := <-runErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("upgrade watcher did not stop")
+	}
+}
@@ -2,6 +2,6 @@
 export DAEMON_NAME=lavad
 export CHAIN_ID=lava
 export DAEMON_HOME=$HOME/.lava
-export DAEMON_RESTART_AFTER_UPGRADE=true
+export ARTIFACTS_DIR=$DAEMON_HOME/artifacts
 export UPRADE_NAME=v2
 echo "env vars set"
\ No newline at end of file
@@ -9,18 +9,14 @@ ignite chain build
 ignite chain init
 
 echo "binary name: $DAEMON_NAME"
-echo "cosmovisor path: $DAEMON_NAME"
-echo "removing old cosmovisor dir $DAEMON_HOME/cosmovisor"
-rm -rf $DAEMON_HOME/cosmovisor
-echo "creating $DAEMON_HOME/cosmovisor/genesis/bin"
-mkdir -p $DAEMON_HOME/cosmovisor/genesis/bin
-echo "copying /home/user/go/bin/lavad to $DAEMON_HOME/cosmovisor/genesis/bin"
-cp $HOME/go/bin/lavad $DAEMON_HOME/cosmovisor/genesis/bin
+echo "creating artifacts dir $ARTIFACTS_DIR"
+mkdir -p $ARTIFACTS_DIR
 
-cosmovisor start start
+# the upgrade watcher runs "lavad start", stages binaries from $ARTIFACTS_DIR and swaps them at the upgrade height
+lavad upgrade-watcher --artifacts-dir $ARTIFACTS_DIR --home $DAEMON_HOME
 
-# option 1. rebuild, put new binary into the cosmovisor directory of upgrades
-# option 2. fetch the binary from a server.
+# option 1. rebuild, put new binary into $ARTIFACTS_DIR/<upgrade-name>/lavad
+# option 2. put the released binary into $ARTIFACTS_DIR under the file name used in the plan binary url
 
 # next step:
 # run upgrade a running module from a different terminal.
@@ -43,8 +39,7 @@ cosmovisor start start
 
 
 
-# an example for a proposal with upgrade binaries with remote path to binary file. (this requires that the wont be a binary file in
-# the cosmovisor upgrade path)
+# an example for a proposal with upgrade binaries, the checksum in the url is verified before the binary is staged
 
 #  tx gov submit-proposal software-upgrade Vega \
 # --title Vega \
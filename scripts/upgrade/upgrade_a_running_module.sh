@@ -8,10 +8,12 @@ git checkout upgrade_module_to
 ignite chain build
 GASPRICE="0.000000001ulava"
 
-echo "mkdir -p $DAEMON_HOME/cosmovisor/upgrades/$UPRADE_NAME/bin"
-mkdir -p $DAEMON_HOME/cosmovisor/upgrades/$UPRADE_NAME/bin
-cp $HOME/go/bin/lavad $DAEMON_HOME/cosmovisor/upgrades/$UPRADE_NAME/bin
-echo "cp $HOME/go/bin/lavad $DAEMON_HOME/cosmovisor/upgrades/$UPRADE_NAME/bin"
+echo "mkdir -p $ARTIFACTS_DIR/$UPRADE_NAME"
+mkdir -p $ARTIFACTS_DIR/$UPRADE_NAME
+cp $HOME/go/bin/lavad $ARTIFACTS_DIR/$UPRADE_NAME
+echo "cp $HOME/go/bin/lavad $ARTIFACTS_DIR/$UPRADE_NAME"
+CHECKSUM=$(sha256sum $ARTIFACTS_DIR/$UPRADE_NAME/lavad | cut -d ' ' -f 1)
+UPGRADE_INFO="{\"binaries\":{\"any\":\"lavad?checksum=sha256:$CHECKSUM\"}}"
 
 for i in $(lavad q block | tr "," "\n");
 do
@@ -23,7 +25,7 @@ done
 
 BLOCK_HEIGHT_CHOSEN=$(echo "$((BLOCK_HEIGHT + 60))")
 
-lavad tx gov submit-proposal software-upgrade $UPRADE_NAME --title upgrade --description upgrade --upgrade-height $BLOCK_HEIGHT_CHOSEN --from alice --yes --gas-adjustment "1.5" --gas "auto" --gas-prices $GASPRICE
+lavad tx gov submit-proposal software-upgrade $UPRADE_NAME --title upgrade --description upgrade --upgrade-height $BLOCK_HEIGHT_CHOSEN --upgrade-info "$UPGRADE_INFO" --from alice --yes --gas-adjustment "1.5" --gas "auto" --gas-prices $GASPRICE
 lavad tx gov deposit 1 10000000ulava --from alice --yes --gas-adjustment "1.5" --gas "auto" --gas-prices $GASPRICE
 lavad tx gov vote 1 yes --from alice --yes --gas-adjustment "1.5" --gas "auto" --gas-prices $GASPRICE
 echo "chosen block for upgrade: $BLOCK_HEIGHT_CHOSEN"
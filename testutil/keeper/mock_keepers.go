@@ -6,6 +6,8 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 	tenderminttypes "github.com/tendermint/tendermint/types"
 )
 
@@ -111,6 +113,67 @@ func (k *mockBankKeeper) SubFromBalance(addr sdk.AccAddress, amounts sdk.Coins)
 	return nil
 }
 
+// staking keeper mock, validators are kept in insertion order and not sorted by power
+type MockStakingKeeper struct {
+	validators []stakingtypes.Validator
+}
+
+func (k *MockStakingKeeper) SetValidator(validator stakingtypes.Validator) {
+	for i := range k.validators {
+		if k.validators[i].OperatorAddress == validator.OperatorAddress {
+			k.validators[i] = validator
+			return
+		}
+	}
+	k.validators = append(k.validators, validator)
+}
+
+func (k *MockStakingKeeper) GetValidator(ctx sdk.Context, addr sdk.ValAddress) (validator stakingtypes.Validator, found bool) {
+	for _, validator := range k.validators {
+		if validator.OperatorAddress == addr.String() {
+			return validator, true
+		}
+	}
+	return stakingtypes.Validator{}, false
+}
+
+func (k *MockStakingKeeper) IterateBondedValidatorsByPower(ctx sdk.Context, fn func(index int64, validator stakingtypes.ValidatorI) (stop bool)) {
+	index := int64(0)
+	for _, validator := range k.validators {
+		if !validator.IsBonded() {
+			continue
+		}
+		if fn(index, validator) {
+			return
+		}
+		index++
+	}
+}
+
+func (k *MockStakingKeeper) TotalBondedTokens(ctx sdk.Context) sdk.Int {
+	total := sdk.ZeroInt()
+	for _, validator := range k.validators {
+		total = total.Add(validator.GetBondedTokens())
+	}
+	return total
+}
+
+// upgrade keeper mock
+type MockUpgradeKeeper struct {
+	plan *upgradetypes.Plan
+}
+
+func (k *MockUpgradeKeeper) SetPlan(plan *upgradetypes.Plan) {
+	k.plan = plan
+}
+
+func (k *MockUpgradeKeeper) GetUpgradePlan(ctx sdk.Context) (plan upgradetypes.Plan, havePlan bool) {
+	if k.plan == nil {
+		return plan, false
+	}
+	return *k.plan, true
+}
+
 type MockBlockStore struct {
 	height       int64
 	blockHistory map[int64]*tenderminttypes.Block
@@ -0,0 +1,86 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	typesparams "github.com/cosmos/cosmos-sdk/x/params/types"
+	epochstoragekeeper "github.com/lavanet/lava/x/epochstorage/keeper"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	speckeeper "github.com/lavanet/lava/x/spec/keeper"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/lavanet/lava/x/upgrades/keeper"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmdb "github.com/tendermint/tm-db"
+)
+
+type UpgradesKeepers struct {
+	Upgrades     *keeper.Keeper
+	Staking      *MockStakingKeeper
+	Upgrade      *MockUpgradeKeeper
+	Epochstorage *epochstoragekeeper.Keeper
+	Spec         *speckeeper.Keeper
+}
+
+func UpgradesKeeper(t testing.TB, supportedUpgrades []types.SupportedUpgrade) (*keeper.Keeper, sdk.Context) {
+	ks, ctx := UpgradesKeeperWithDeps(t, supportedUpgrades)
+	return ks.Upgrades, ctx
+}
+
+// UpgradesKeeperWithDeps returns the upgrades keeper with mocked staking and upgrade keepers and real spec and epochstorage keepers
+func UpgradesKeeperWithDeps(t testing.TB, supportedUpgrades []types.SupportedUpgrade) (*UpgradesKeepers, sdk.Context) {
+	db := tmdb.NewMemDB()
+	stateStore := store.NewCommitMultiStore(db)
+
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+	stateStore.MountStoreWithDB(storeKey, sdk.StoreTypeIAVL, db)
+
+	specStoreKey := sdk.NewKVStoreKey(spectypes.StoreKey)
+	specMemStoreKey := storetypes.NewMemoryStoreKey(spectypes.MemStoreKey)
+	stateStore.MountStoreWithDB(specStoreKey, sdk.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(specMemStoreKey, sdk.StoreTypeMemory, nil)
+
+	epochStoreKey := sdk.NewKVStoreKey(epochstoragetypes.StoreKey)
+	epochMemStoreKey := storetypes.NewMemoryStoreKey(epochstoragetypes.MemStoreKey)
+	stateStore.MountStoreWithDB(epochStoreKey, sdk.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(epochMemStoreKey, sdk.StoreTypeMemory, nil)
+
+	require.NoError(t, stateStore.LoadLatestVersion())
+
+	registry := codectypes.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(registry)
+
+	specParamsSubspace := typesparams.NewSubspace(cdc, spectypes.Amino, specStoreKey, specMemStoreKey, "SpecParams")
+	epochParamsSubspace := typesparams.NewSubspace(cdc, epochstoragetypes.Amino, epochStoreKey, epochMemStoreKey, "EpochstorageParams")
+
+	ks := UpgradesKeepers{}
+	ks.Staking = &MockStakingKeeper{}
+	ks.Upgrade = &MockUpgradeKeeper{}
+	ks.Spec = speckeeper.NewKeeper(cdc, specStoreKey, specMemStoreKey, specParamsSubspace)
+	ks.Epochstorage = epochstoragekeeper.NewKeeper(cdc, epochStoreKey, epochMemStoreKey, epochParamsSubspace, nil, nil, ks.Spec)
+	ks.Upgrades = keeper.NewKeeper(
+		cdc,
+		storeKey,
+		supportedUpgrades,
+
+		ks.Staking,
+		ks.Upgrade,
+		ks.Epochstorage,
+		ks.Spec,
+	)
+
+	ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+	// Initialize params
+	ks.Spec.SetParams(ctx, spectypes.DefaultParams())
+	ks.Epochstorage.SetParams(ctx, epochstoragetypes.DefaultParams())
+
+	return &ks, ctx
+}
@@ -0,0 +1,212 @@
+// Package upgradetest runs upgrades end to end in a go test: an in-process chain with a populated state
+// passes an upgrade proposal, halts at the upgrade height and continues with the upgraded binary
+package upgradetest
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/simapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/cosmos/cosmos-sdk/x/upgrade"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/ignite-hq/cli/ignite/pkg/cosmoscmd"
+	"github.com/lavanet/lava/app"
+	"github.com/lavanet/lava/testutil/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// upgradeRegistry holds every upgrade of the binary, the harness opens the app with the upgrades that were run
+var upgradeRegistry = app.UpgradeRegistry
+
+// Config describes the chain the harness boots and the state it populates
+type Config struct {
+	ChainID      string
+	BlockTime    time.Duration
+	VotingPeriod time.Duration // governance voting period, the upgrade proposal passes after it
+	CookbookDir  string
+	// the spec add proposals in CookbookDir whose specs are added, the default is the list scripts/init_chain_commands.sh adds
+	CookbookSpecs []string
+	StakedSpecs   []string // every provider and client stakes on each of these specs
+	Providers     int
+	Clients       int
+	// Genesis modifies the genesis state the harness built before the chain starts
+	Genesis func(genesisState app.GenesisState)
+	// ModuleVersions overrides the module versions stored at genesis, to start from older versions of the modules
+	ModuleVersions module.VersionMap
+	// Upgrades are the registered upgrades the chain runs, the genesis binary predates them: it has no handlers
+	// for them and doesn't mount the stores they add. the binary swap of each upgrade brings its handler and stores
+	Upgrades []string
+}
+
+func DefaultConfig() Config {
+	_, file, _, _ := runtime.Caller(0)
+	return Config{
+		ChainID:      "lava-upgrade-test",
+		BlockTime:    6 * time.Second,
+		VotingPeriod: time.Minute,
+		CookbookDir:  filepath.Join(filepath.Dir(file), "..", "..", "cookbook"),
+		CookbookSpecs: []string{
+			"spec_add_ethereum.json", "spec_add_cosmoshub.json", "spec_add_lava.json", "spec_add_osmosis.json", "spec_add_fantom.json", "spec_add_celo.json",
+			"spec_add_arbitrum.json", "spec_add_starknet.json", "spec_add_aptos.json", "spec_add_juno.json", "spec_add_polygon.json",
+		},
+		StakedSpecs: []string{"LAV1", "ETH1"},
+		Providers:   3,
+		Clients:     2,
+	}
+}
+
+// Harness runs the lava app in process block by block, on top of a realistic state: the cookbook specs,
+// staked providers and clients and epoch payments. Upgrades are scheduled through governance and the binary
+// swap at the halt height is emulated by reopening the app on the same database, like a node restarting
+type Harness struct {
+	t              *testing.T
+	cfg            Config
+	db             dbm.DB
+	home           string
+	encodingConfig cosmoscmd.EncodingConfig
+	pending        []string // upgrades of Config.Upgrades that weren't run yet
+	blockTime      time.Time
+
+	App       *app.LavaApp
+	Validator common.Account
+	Providers []common.Account
+	Clients   []common.Account
+	Specs     []string // indexes of the specs added from the cookbook
+}
+
+// New boots the app at genesis and populates the state, it returns after the epoch payments were committed
+func New(t *testing.T, cfg Config) *Harness {
+	h := &Harness{
+		t:              t,
+		cfg:            cfg,
+		db:             dbm.NewMemDB(),
+		home:           t.TempDir(),
+		encodingConfig: cosmoscmd.MakeEncodingConfig(app.ModuleBasics),
+		pending:        append([]string{}, cfg.Upgrades...),
+		blockTime:      time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, upgradeName := range cfg.Upgrades {
+		_, found := upgradeRegistry.Get(upgradeName)
+		require.True(t, found, "upgrade %s is not registered", upgradeName)
+	}
+	h.App = h.newApp()
+	h.initChain()
+	h.populate()
+	return h
+}
+
+// newApp starts the binary of the current upgrade, it predates the pending upgrades: they have no handler
+// and the stores they add aren't mounted
+func (h *Harness) newApp() *app.LavaApp {
+	app.UpgradeRegistry = upgradeRegistry.Without(h.pending...)
+	h.t.Cleanup(func() { app.UpgradeRegistry = upgradeRegistry })
+	return app.New(log.NewNopLogger(), h.db, nil, true, map[int64]bool{}, h.home, 0, h.encodingConfig, simapp.EmptyAppOptions{}).(*app.LavaApp)
+}
+
+// Context returns a read only context on the last committed state
+func (h *Harness) Context() sdk.Context {
+	return h.App.NewUncachedContext(false, tmproto.Header{ChainID: h.cfg.ChainID, Height: h.App.LastBlockHeight(), Time: h.blockTime})
+}
+
+// NextBlock runs and commits a block, deliver runs between BeginBlock and EndBlock with the block's context
+func (h *Harness) NextBlock(deliver func(ctx sdk.Context)) {
+	h.blockTime = h.blockTime.Add(h.cfg.BlockTime)
+	header := tmproto.Header{ChainID: h.cfg.ChainID, Height: h.App.LastBlockHeight() + 1, Time: h.blockTime}
+	h.App.BeginBlock(abci.RequestBeginBlock{Header: header})
+	if deliver != nil {
+		deliver(h.App.NewContext(false, header))
+	}
+	h.App.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	h.App.Commit()
+}
+
+func (h *Harness) AdvanceBlocks(blocks int) {
+	for i := 0; i < blocks; i++ {
+		h.NextBlock(nil)
+	}
+}
+
+// AdvanceToHeight commits blocks until height is the last committed block
+func (h *Harness) AdvanceToHeight(height int64) {
+	require.GreaterOrEqual(h.t, height, h.App.LastBlockHeight(), "can't advance backwards")
+	for h.App.LastBlockHeight() < height {
+		h.NextBlock(nil)
+	}
+}
+
+// AdvanceEpoch commits blocks until a new epoch starts
+func (h *Harness) AdvanceEpoch() {
+	epochStart := h.App.EpochstorageKeeper.GetEpochStart(h.Context())
+	for h.App.EpochstorageKeeper.GetEpochStart(h.Context()) == epochStart {
+		h.NextBlock(nil)
+	}
+}
+
+// ScheduleUpgrade submits a software upgrade proposal, the validator deposits and votes yes
+// and blocks are committed until the proposal passed and the plan is scheduled
+func (h *Harness) ScheduleUpgrade(plan upgradetypes.Plan) {
+	var proposalID uint64
+	h.NextBlock(func(ctx sdk.Context) {
+		content := upgradetypes.NewSoftwareUpgradeProposal("upgrade "+plan.Name, "upgrade test harness proposal", plan)
+		proposal, err := h.App.GovKeeper.SubmitProposal(ctx, content)
+		require.NoError(h.t, err)
+		proposalID = proposal.ProposalId
+		_, err = h.App.GovKeeper.AddDeposit(ctx, proposalID, h.Validator.Addr, h.App.GovKeeper.GetDepositParams(ctx).MinDeposit)
+		require.NoError(h.t, err)
+		require.NoError(h.t, h.App.GovKeeper.AddVote(ctx, proposalID, h.Validator.Addr, govtypes.NewNonSplitVoteOption(govtypes.OptionYes)))
+	})
+	for {
+		proposal, found := h.App.GovKeeper.GetProposal(h.Context(), proposalID)
+		require.True(h.t, found)
+		if proposal.Status != govtypes.StatusVotingPeriod {
+			require.Equal(h.t, govtypes.StatusPassed, proposal.Status, "upgrade proposal %s didn't pass", plan.Name)
+			break
+		}
+		h.NextBlock(nil)
+	}
+	scheduled, found := h.App.UpgradeKeeper.GetUpgradePlan(h.Context())
+	require.True(h.t, found)
+	require.Equal(h.t, plan.Name, scheduled.Name)
+}
+
+// Upgrade runs the next upgrade of Config.Upgrades the way a chain does: the plan passes governance, the chain halts
+// at the upgrade height and dumps the upgrade info, the new binary starts on the same database (applying the store
+// upgrades) and runs the upgrade handler at the halt height. it returns the halt height
+func (h *Harness) Upgrade() int64 {
+	require.NotEmpty(h.t, h.pending, "no pending upgrades")
+	upgradeName := h.pending[0]
+
+	// leave room for the proposal block, the voting period and the block that ends it
+	votingBlocks := int64(h.cfg.VotingPeriod / h.cfg.BlockTime)
+	plan := upgradetypes.Plan{Name: upgradeName, Height: h.App.LastBlockHeight() + votingBlocks + 5}
+	h.ScheduleUpgrade(plan)
+	h.AdvanceToHeight(plan.Height - 1)
+
+	// the binary without the handler halts at the upgrade height
+	blockTime := h.blockTime
+	require.PanicsWithValue(h.t, upgrade.BuildUpgradeNeededMsg(plan), func() { h.NextBlock(nil) }, "the chain didn't halt for %s", upgradeName)
+	h.blockTime = blockTime
+
+	h.pending = h.pending[1:]
+	h.App = h.newApp()
+	require.Equal(h.t, plan.Height-1, h.App.LastBlockHeight())
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				h.t.Fatalf("upgrade %s failed at height %d: %v", upgradeName, plan.Height, r)
+			}
+		}()
+		h.NextBlock(nil)
+	}()
+	require.Equal(h.t, plan.Height, h.App.UpgradeKeeper.GetDoneHeight(h.Context(), upgradeName), "upgrade %s wasn't applied", upgradeName)
+	return plan.Height
+}
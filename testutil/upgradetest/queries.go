@@ -0,0 +1,91 @@
+package upgradetest
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/lavanet/lava/app"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	pairingtypes "github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	upgradestypes "github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Query runs a gRPC query through the app's ABCI query handler on the last committed state,
+// the same route a node serves the clients on
+func (h *Harness) Query(path string, request codec.ProtoMarshaler, response codec.ProtoMarshaler) error {
+	data, err := request.Marshal()
+	if err != nil {
+		return err
+	}
+	res := h.App.Query(abci.RequestQuery{Path: path, Data: data})
+	if !res.IsOK() {
+		return fmt.Errorf("query %s failed with code %d: %s", path, res.Code, res.Log)
+	}
+	return response.Unmarshal(res.Value)
+}
+
+// AssertQueries checks the queries of the lava modules serve the state the harness populated
+func (h *Harness) AssertQueries() {
+	specs := &spectypes.QueryAllSpecResponse{}
+	require.NoError(h.t, h.Query("/lavanet.lava.spec.Query/SpecAll", &spectypes.QueryAllSpecRequest{}, specs))
+	require.Len(h.t, specs.Spec, len(h.Specs))
+	for _, index := range h.Specs {
+		spec := &spectypes.QueryGetSpecResponse{}
+		require.NoError(h.t, h.Query("/lavanet.lava.spec.Query/Spec", &spectypes.QueryGetSpecRequest{ChainID: index}, spec), "spec %s", index)
+		require.Equal(h.t, index, spec.Spec.Index)
+		specVersion := &spectypes.QueryGetSpecVersionResponse{}
+		require.NoError(h.t, h.Query("/lavanet.lava.spec.Query/SpecVersion", &spectypes.QueryGetSpecVersionRequest{ChainID: index, Version: spec.Spec.Version}, specVersion), "spec %s version %d", index, spec.Spec.Version)
+		require.Equal(h.t, spec.Spec.BlockLastUpdated, specVersion.Spec.BlockLastUpdated)
+	}
+
+	stakeStorages := &epochstoragetypes.QueryAllStakeStorageResponse{}
+	require.NoError(h.t, h.Query("/lavanet.lava.epochstorage.Query/StakeStorageAll", &epochstoragetypes.QueryAllStakeStorageRequest{}, stakeStorages))
+	require.NotEmpty(h.t, stakeStorages.StakeStorage)
+
+	for _, chainID := range h.cfg.StakedSpecs {
+		providers := &pairingtypes.QueryProvidersResponse{}
+		require.NoError(h.t, h.Query("/lavanet.lava.pairing.Query/Providers", &pairingtypes.QueryProvidersRequest{ChainID: chainID}, providers))
+		require.Len(h.t, providers.StakeEntry, len(h.Providers), "providers of %s", chainID)
+		clients := &pairingtypes.QueryClientsResponse{}
+		require.NoError(h.t, h.Query("/lavanet.lava.pairing.Query/Clients", &pairingtypes.QueryClientsRequest{ChainID: chainID}, clients))
+		require.Len(h.t, clients.StakeEntry, len(h.Clients), "clients of %s", chainID)
+		// GetPairing estimates the next epoch time from the tendermint block store, which isn't running in process
+		block := uint64(h.App.LastBlockHeight())
+		for _, client := range h.Clients {
+			paired := 0
+			for _, provider := range h.Providers {
+				verify := &pairingtypes.QueryVerifyPairingResponse{}
+				request := &pairingtypes.QueryVerifyPairingRequest{ChainID: chainID, Client: client.Addr.String(), Provider: provider.Addr.String(), Block: block}
+				require.NoError(h.t, h.Query("/lavanet.lava.pairing.Query/VerifyPairing", request, verify))
+				if verify.Valid {
+					paired++
+				}
+			}
+			require.NotZero(h.t, paired, "no provider is paired with %s on %s", client.Addr, chainID)
+		}
+	}
+
+	supported := &upgradestypes.QuerySupportedUpgradesResponse{}
+	require.NoError(h.t, h.Query("/lavanet.lava.upgrades.Query/SupportedUpgrades", &upgradestypes.QuerySupportedUpgradesRequest{}, supported))
+	// the binary supports the upgrades that were run and the ones it was released with
+	supportedNames := []string{}
+	for _, upgrade := range supported.Upgrades {
+		supportedNames = append(supportedNames, upgrade.Name)
+	}
+	require.Equal(h.t, app.UpgradeRegistry.Names(), supportedNames)
+}
+
+// AssertEpochPayments checks the epoch payments the harness added are still served, they are removed
+// by the pairing module once they are older than the epochs it keeps
+func (h *Harness) AssertEpochPayments() {
+	epochPayments := &pairingtypes.QueryAllEpochPaymentsResponse{}
+	require.NoError(h.t, h.Query("/lavanet.lava.pairing.Query/EpochPaymentsAll", &pairingtypes.QueryAllEpochPaymentsRequest{}, epochPayments))
+	// all the payments were added in the same epoch
+	require.Len(h.t, epochPayments.EpochPayments, 1)
+	uniquePayments := &pairingtypes.QueryAllUniquePaymentStorageClientProviderResponse{}
+	require.NoError(h.t, h.Query("/lavanet.lava.pairing.Query/UniquePaymentStorageClientProviderAll", &pairingtypes.QueryAllUniquePaymentStorageClientProviderRequest{}, uniquePayments))
+	require.Len(h.t, uniquePayments.UniquePaymentStorageClientProvider, len(h.cfg.StakedSpecs)*len(h.Clients)*len(h.Providers))
+}
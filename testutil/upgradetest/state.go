@@ -0,0 +1,207 @@
+package upgradetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/simapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/lavanet/lava/app"
+	"github.com/lavanet/lava/relayer/sigs"
+	"github.com/lavanet/lava/testutil/common"
+	"github.com/lavanet/lava/utils"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	"github.com/lavanet/lava/x/spec"
+	specutils "github.com/lavanet/lava/x/spec/client/utils"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+var (
+	validatorBond  = sdk.NewInt(1_000_000_000_000)
+	accountBalance = sdk.NewInt(100_000_000_000_000)
+	// above the min stakes of the cookbook specs and of the spec patches of the registered upgrades
+	providerStake = sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(1_000_000_000_000))
+	clientStake   = sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(10_000_000_000))
+)
+
+const (
+	epochPaymentCU = 10
+	geolocation    = 1
+)
+
+// initChain commits a genesis with a single bonded validator and funded provider and client accounts
+func (h *Harness) initChain() {
+	cdc := h.encodingConfig.Marshaler
+	genesisState := app.NewDefaultGenesisState(cdc)
+
+	h.Validator.SK, h.Validator.Addr = sigs.GenerateFloatingKey()
+	for i := 0; i < h.cfg.Providers; i++ {
+		provider := common.Account{}
+		provider.SK, provider.Addr = sigs.GenerateFloatingKey()
+		h.Providers = append(h.Providers, provider)
+	}
+	for i := 0; i < h.cfg.Clients; i++ {
+		client := common.Account{}
+		client.SK, client.Addr = sigs.GenerateFloatingKey()
+		h.Clients = append(h.Clients, client)
+	}
+
+	genAccounts := authtypes.GenesisAccounts{}
+	balances := []banktypes.Balance{}
+	supply := sdk.NewCoins()
+	funded := append(append([]common.Account{h.Validator}, h.Providers...), h.Clients...)
+	for _, account := range funded {
+		genAccounts = append(genAccounts, authtypes.NewBaseAccount(account.Addr, nil, 0, 0))
+		coins := sdk.NewCoins(sdk.NewCoin(epochstoragetypes.TokenDenom, accountBalance))
+		balances = append(balances, banktypes.Balance{Address: account.Addr.String(), Coins: coins})
+		supply = supply.Add(coins...)
+	}
+	bonded := sdk.NewCoins(sdk.NewCoin(epochstoragetypes.TokenDenom, validatorBond))
+	balances = append(balances, banktypes.Balance{Address: authtypes.NewModuleAddress(stakingtypes.BondedPoolName).String(), Coins: bonded})
+	supply = supply.Add(bonded...)
+
+	authGenesis := authtypes.NewGenesisState(authtypes.DefaultParams(), genAccounts)
+	genesisState[authtypes.ModuleName] = cdc.MustMarshalJSON(authGenesis)
+	bankGenesis := banktypes.NewGenesisState(banktypes.DefaultGenesisState().Params, balances, supply, []banktypes.Metadata{})
+	genesisState[banktypes.ModuleName] = cdc.MustMarshalJSON(bankGenesis)
+
+	consensusKey := ed25519.GenPrivKey()
+	validator, err := stakingtypes.NewValidator(sdk.ValAddress(h.Validator.Addr), consensusKey.PubKey(), stakingtypes.Description{Moniker: "validator"})
+	require.NoError(h.t, err)
+	validator.Status = stakingtypes.Bonded
+	validator.Tokens = validatorBond
+	validator.DelegatorShares = validatorBond.ToDec()
+	validator.Commission = stakingtypes.NewCommission(sdk.ZeroDec(), sdk.ZeroDec(), sdk.ZeroDec())
+	delegation := stakingtypes.NewDelegation(h.Validator.Addr, validator.GetOperator(), validatorBond.ToDec())
+	stakingParams := stakingtypes.DefaultParams()
+	stakingParams.BondDenom = epochstoragetypes.TokenDenom
+	stakingGenesis := stakingtypes.NewGenesisState(stakingParams, []stakingtypes.Validator{validator}, []stakingtypes.Delegation{delegation})
+	genesisState[stakingtypes.ModuleName] = cdc.MustMarshalJSON(stakingGenesis)
+
+	mintGenesis := minttypes.DefaultGenesisState()
+	mintGenesis.Params.MintDenom = epochstoragetypes.TokenDenom
+	genesisState[minttypes.ModuleName] = cdc.MustMarshalJSON(mintGenesis)
+
+	govGenesis := govtypes.DefaultGenesisState()
+	govGenesis.DepositParams.MinDeposit = sdk.NewCoins(sdk.NewCoin(epochstoragetypes.TokenDenom, govtypes.DefaultMinDepositTokens))
+	govGenesis.VotingParams.VotingPeriod = h.cfg.VotingPeriod
+	genesisState[govtypes.ModuleName] = cdc.MustMarshalJSON(govGenesis)
+
+	if h.cfg.Genesis != nil {
+		h.cfg.Genesis(genesisState)
+	}
+	appState, err := json.Marshal(genesisState)
+	require.NoError(h.t, err)
+	h.App.InitChain(abci.RequestInitChain{
+		Time:            h.blockTime,
+		ChainId:         h.cfg.ChainID,
+		ConsensusParams: simapp.DefaultConsensusParams,
+		AppStateBytes:   appState,
+	})
+	if len(h.cfg.ModuleVersions) > 0 {
+		ctx := h.App.NewContext(false, tmproto.Header{ChainID: h.cfg.ChainID, Height: h.App.LastBlockHeight() + 1, Time: h.blockTime})
+		versions := h.App.UpgradeKeeper.GetModuleVersionMap(ctx)
+		for moduleName, version := range h.cfg.ModuleVersions {
+			versions[moduleName] = version
+		}
+		h.App.UpgradeKeeper.SetModuleVersionMap(ctx, versions)
+	}
+	h.App.Commit()
+}
+
+// populate adds the cookbook specs, stakes the providers and clients on the staked specs
+// and once they are paired adds an epoch payment for every client and provider
+func (h *Harness) populate() {
+	specs := h.loadCookbookSpecs()
+	h.NextBlock(func(ctx sdk.Context) {
+		h.addSpecs(ctx, specs)
+	})
+
+	_, vrfPk, err := utils.GeneratePrivateVRFKey()
+	require.NoError(h.t, err)
+	clientVrfPk := &utils.VrfPubKey{}
+	require.NoError(h.t, clientVrfPk.Unmarshal(vrfPk))
+	h.NextBlock(func(ctx sdk.Context) {
+		for _, chainID := range h.cfg.StakedSpecs {
+			endpoints := []epochstoragetypes.Endpoint{}
+			for apiInterface := range h.App.SpecKeeper.GetExpectedInterfacesForSpec(ctx, chainID) {
+				endpoints = append(endpoints, epochstoragetypes.Endpoint{IPPORT: "127.0.0.1:2221", UseType: apiInterface, Geolocation: geolocation})
+			}
+			for i, provider := range h.Providers {
+				err := h.App.PairingKeeper.StakeNewEntry(ctx, true, provider.Addr.String(), chainID, providerStake, endpoints, geolocation, "", "provider"+strconv.Itoa(i))
+				require.NoError(h.t, err, "staking provider on %s", chainID)
+			}
+			for _, client := range h.Clients {
+				err := h.App.PairingKeeper.StakeNewEntry(ctx, false, client.Addr.String(), chainID, clientStake, nil, geolocation, clientVrfPk.String(), "")
+				require.NoError(h.t, err, "staking client on %s", chainID)
+			}
+		}
+	})
+
+	// stake entries take effect in the next epoch
+	h.AdvanceEpoch()
+	h.NextBlock(func(ctx sdk.Context) {
+		epoch := h.App.EpochstorageKeeper.GetEpochStart(ctx)
+		for _, chainID := range h.cfg.StakedSpecs {
+			for _, client := range h.Clients {
+				for _, provider := range h.Providers {
+					uniqueIdentifier := fmt.Sprintf("%s-%s-%s", chainID, client.Addr, provider.Addr)
+					_, err := h.App.PairingKeeper.AddEpochPayment(ctx, chainID, epoch, client.Addr, provider.Addr, epochPaymentCU, uniqueIdentifier)
+					require.NoError(h.t, err)
+				}
+			}
+		}
+	})
+}
+
+func (h *Harness) loadCookbookSpecs() []spectypes.Spec {
+	specs := []spectypes.Spec{}
+	for _, fileName := range h.cfg.CookbookSpecs {
+		file := filepath.Join(h.cfg.CookbookDir, fileName)
+		proposal, err := specutils.ParseSpecAddProposalJSON(h.App.LegacyAmino(), file)
+		require.NoError(h.t, err, "parsing %s", file)
+		specs = append(specs, proposal.Proposal.Specs...)
+	}
+	return specs
+}
+
+// addSpecs applies the specs through the spec proposal handler, a spec is added once the specs it imports were added
+func (h *Harness) addSpecs(ctx sdk.Context, specs []spectypes.Spec) {
+	handler := spec.NewSpecProposalsHandler(h.App.SpecKeeper)
+	added := map[string]struct{}{}
+	for len(specs) > 0 {
+		ready, pending := []spectypes.Spec{}, []spectypes.Spec{}
+		for _, cookbookSpec := range specs {
+			importsAdded := true
+			for _, imported := range cookbookSpec.Imports {
+				if _, ok := added[imported]; !ok {
+					importsAdded = false
+				}
+			}
+			if importsAdded {
+				ready = append(ready, cookbookSpec)
+			} else {
+				pending = append(pending, cookbookSpec)
+			}
+		}
+		require.NotEmpty(h.t, ready, "cookbook specs import specs that don't exist")
+		proposal := spectypes.NewSpecAddProposal("cookbook specs", "upgrade test harness specs", ready)
+		require.NoError(h.t, handler(ctx, proposal))
+		for _, cookbookSpec := range ready {
+			added[cookbookSpec.Index] = struct{}{}
+			h.Specs = append(h.Specs, cookbookSpec.Index)
+		}
+		specs = pending
+	}
+}
@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/conflict/types"
+)
+
+// RegisterInvariants registers all conflict invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "conflict-votes", ConflictVotesInvariant(k))
+}
+
+// ConflictVotesInvariant checks every open conflict vote can be handled by CheckAndHandleAllVotes:
+// it is in the commit or reveal state, its voters are unique and their results are valid for the vote state
+func ConflictVotesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		problems := []string{}
+		conflictVotes := k.GetAllConflictVote(ctx)
+		for _, conflictVote := range conflictVotes {
+			if conflictVote.VoteState != types.StateCommit && conflictVote.VoteState != types.StateReveal {
+				problems = append(problems, fmt.Sprintf("\tconflict vote %s: unknown vote state %d\n", conflictVote.Index, conflictVote.VoteState))
+			}
+			if conflictVote.VoteDeadline < conflictVote.VoteStartBlock {
+				problems = append(problems, fmt.Sprintf("\tconflict vote %s: vote deadline %d is before the vote start block %d\n", conflictVote.Index, conflictVote.VoteDeadline, conflictVote.VoteStartBlock))
+			}
+			voters := map[string]struct{}{}
+			for _, vote := range conflictVote.Votes {
+				if _, ok := voters[vote.Address]; ok {
+					problems = append(problems, fmt.Sprintf("\tconflict vote %s: voter %s appears more than once\n", conflictVote.Index, vote.Address))
+				}
+				voters[vote.Address] = struct{}{}
+				// results are revealed only in the reveal state
+				validResult := vote.Result == types.NoVote || vote.Result == types.Commit
+				if conflictVote.VoteState == types.StateReveal {
+					validResult = vote.Result >= types.NoVote && vote.Result <= types.NoneOfTheProviders
+				}
+				if !validResult {
+					problems = append(problems, fmt.Sprintf("\tconflict vote %s: voter %s has result %d in vote state %d\n", conflictVote.Index, vote.Address, vote.Result, conflictVote.VoteState))
+				}
+			}
+		}
+
+		broken := len(problems) > 0
+		return sdk.FormatInvariant(types.ModuleName, "conflict-votes",
+			fmt.Sprintf("\tconflict votes: %d\n\tproblems: %d\n%s", len(conflictVotes), len(problems), strings.Join(problems, ""))), broken
+	}
+}
@@ -0,0 +1,51 @@
+package keeper_test
+
+import (
+	"testing"
+
+	keepertest "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/x/conflict/keeper"
+	"github.com/lavanet/lava/x/conflict/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflictVotesInvariant(t *testing.T) {
+	k, ctx := keepertest.ConflictKeeper(t)
+	invariant := keeper.ConflictVotesInvariant(*k)
+
+	conflictVote := types.ConflictVote{
+		Index:          "vote",
+		VoteState:      types.StateCommit,
+		VoteStartBlock: 10,
+		VoteDeadline:   40,
+		Votes:          []types.Vote{{Address: "voter0", Result: types.Commit}, {Address: "voter1", Result: types.NoVote}},
+	}
+	k.SetConflictVote(ctx, conflictVote)
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	// a revealed result before the reveal state
+	conflictVote.Votes[1].Result = types.Provider0
+	k.SetConflictVote(ctx, conflictVote)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "voter voter1 has result 2 in vote state 0")
+	conflictVote.VoteState = types.StateReveal
+	k.SetConflictVote(ctx, conflictVote)
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	// a voter listed twice
+	conflictVote.Votes = append(conflictVote.Votes, types.Vote{Address: "voter0", Result: types.Provider1})
+	k.SetConflictVote(ctx, conflictVote)
+	msg, broken = invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "voter voter0 appears more than once")
+	conflictVote.Votes = conflictVote.Votes[:2]
+
+	conflictVote.VoteState = 2
+	k.SetConflictVote(ctx, conflictVote)
+	msg, broken = invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "unknown vote state 2")
+}
@@ -0,0 +1,39 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/epochstorage/types"
+)
+
+// RegisterInvariants registers all epochstorage invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "unstake-queue-order", UnstakeQueueOrderInvariant(k))
+}
+
+// UnstakeQueueOrderInvariant checks the unstake queues are sorted by deadline,
+// PopUnstakeEntries stops at the first entry with a future deadline so an unsorted queue holds funds forever
+func UnstakeQueueOrderInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		problems := []string{}
+		for _, storageType := range []string{types.ProviderKey, types.ClientKey} {
+			stakeStorage, found := k.GetStakeStorageUnstake(ctx, storageType)
+			if !found {
+				continue
+			}
+			for i := 1; i < len(stakeStorage.StakeEntries); i++ {
+				previous, current := stakeStorage.StakeEntries[i-1], stakeStorage.StakeEntries[i]
+				if current.Deadline < previous.Deadline {
+					problems = append(problems, fmt.Sprintf("\t%s unstake entry %d (%s, deadline %d) is before entry %d (%s, deadline %d)\n",
+						storageType, i, current.Address, current.Deadline, i-1, previous.Address, previous.Deadline))
+				}
+			}
+		}
+
+		broken := len(problems) > 0
+		return sdk.FormatInvariant(types.ModuleName, "unstake-queue-order",
+			fmt.Sprintf("\tunordered unstake entries: %d\n%s", len(problems), strings.Join(problems, ""))), broken
+	}
+}
@@ -0,0 +1,32 @@
+package keeper_test
+
+import (
+	"testing"
+
+	testkeeper "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/x/epochstorage/keeper"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnstakeQueueOrderInvariant(t *testing.T) {
+	k, ctx := testkeeper.EpochstorageKeeper(t)
+	invariant := keeper.UnstakeQueueOrderInvariant(*k)
+
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	for _, holdBlocks := range []uint64{30, 10, 20} {
+		require.NoError(t, k.AppendUnstakeEntry(ctx, epochstoragetypes.ProviderKey, epochstoragetypes.StakeEntry{}, holdBlocks))
+	}
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	unstakeStorage, found := k.GetStakeStorageUnstake(ctx, epochstoragetypes.ProviderKey)
+	require.True(t, found)
+	unstakeStorage.StakeEntries[0], unstakeStorage.StakeEntries[2] = unstakeStorage.StakeEntries[2], unstakeStorage.StakeEntries[0]
+	k.SetStakeStorageUnstake(ctx, epochstoragetypes.ProviderKey, unstakeStorage)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "unordered unstake entries: 2")
+}
@@ -0,0 +1,96 @@
+package keeper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	"github.com/lavanet/lava/x/pairing/types"
+)
+
+// RegisterInvariants registers all pairing invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "module-account-stake", ModuleAccountStakeInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "epoch-payments", EpochPaymentsInvariant(k))
+}
+
+// ModuleAccountStakeInvariant checks the pairing module account holds at least the stake of all staked and unstaking entries,
+// the module account also receives minted rewards and bails so it can hold more than the total stake
+func ModuleAccountStakeInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		totalStake := sdk.ZeroInt()
+		for _, storageType := range []string{epochstoragetypes.ProviderKey, epochstoragetypes.ClientKey} {
+			for _, chainID := range k.specKeeper.GetAllChainIDs(ctx) {
+				stakeStorage, found := k.epochStorageKeeper.GetStakeStorageCurrent(ctx, storageType, chainID)
+				if !found {
+					continue
+				}
+				for _, stakeEntry := range stakeStorage.StakeEntries {
+					totalStake = totalStake.Add(stakeEntry.Stake.Amount)
+				}
+			}
+			unstakeStorage, found := k.epochStorageKeeper.GetStakeStorageUnstake(ctx, storageType)
+			if !found {
+				continue
+			}
+			for _, stakeEntry := range unstakeStorage.StakeEntries {
+				totalStake = totalStake.Add(stakeEntry.Stake.Amount)
+			}
+		}
+		moduleBalance := k.bankKeeper.GetBalance(ctx, k.accountKeeper.GetModuleAddress(types.ModuleName), epochstoragetypes.TokenDenom)
+
+		broken := moduleBalance.Amount.LT(totalStake)
+		return sdk.FormatInvariant(types.ModuleName, "module-account-stake",
+			fmt.Sprintf("\tmodule account balance: %s\n\ttotal stake: %s%s\n", moduleBalance, totalStake, epochstoragetypes.TokenDenom)), broken
+	}
+}
+
+// EpochPaymentsInvariant checks the payment objects of every epoch reference each other consistently:
+// provider payment storages listed in an epoch payments object exist and belong to its epoch, and the unique payments
+// they list exist and aren't newer than the epoch (RemoveAllEpochPaymentsForBlock panics on newer entries)
+func EpochPaymentsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		problems := []string{}
+		epochPaymentsList := k.GetAllEpochPayments(ctx)
+		for _, epochPayments := range epochPaymentsList {
+			epoch, err := strconv.ParseUint(epochPayments.Index, 16, 64)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("\tepoch payments %s: index is not a hex epoch\n", epochPayments.Index))
+				continue
+			}
+			seen := map[string]struct{}{}
+			for _, providerPaymentStorageKey := range epochPayments.ProviderPaymentStorageKeys {
+				if _, ok := seen[providerPaymentStorageKey]; ok {
+					problems = append(problems, fmt.Sprintf("\tepoch payments %d: provider payment storage %s is listed more than once\n", epoch, providerPaymentStorageKey))
+					continue
+				}
+				seen[providerPaymentStorageKey] = struct{}{}
+
+				providerPaymentStorage, found := k.GetProviderPaymentStorage(ctx, providerPaymentStorageKey)
+				if !found {
+					problems = append(problems, fmt.Sprintf("\tepoch payments %d: provider payment storage %s is missing\n", epoch, providerPaymentStorageKey))
+					continue
+				}
+				if providerPaymentStorage.Epoch != epoch {
+					problems = append(problems, fmt.Sprintf("\tepoch payments %d: provider payment storage %s belongs to epoch %d\n", epoch, providerPaymentStorageKey, providerPaymentStorage.Epoch))
+				}
+				for _, uniquePaymentKey := range providerPaymentStorage.UniquePaymentStorageClientProviderKeys {
+					uniquePayment, found := k.GetUniquePaymentStorageClientProvider(ctx, uniquePaymentKey)
+					if !found {
+						problems = append(problems, fmt.Sprintf("\tprovider payment storage %s: unique payment %q is missing\n", providerPaymentStorageKey, uniquePaymentKey))
+						continue
+					}
+					if uniquePayment.Block > providerPaymentStorage.Epoch {
+						problems = append(problems, fmt.Sprintf("\tprovider payment storage %s: unique payment %q block %d is after epoch %d\n", providerPaymentStorageKey, uniquePaymentKey, uniquePayment.Block, providerPaymentStorage.Epoch))
+					}
+				}
+			}
+		}
+
+		broken := len(problems) > 0
+		return sdk.FormatInvariant(types.ModuleName, "epoch-payments",
+			fmt.Sprintf("\tepoch payments: %d\n\tinconsistencies: %d\n%s", len(epochPaymentsList), len(problems), strings.Join(problems, ""))), broken
+	}
+}
@@ -0,0 +1,73 @@
+package keeper_test
+
+import (
+	"strconv"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	testkeeper "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/testutil/sample"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	"github.com/lavanet/lava/x/pairing/keeper"
+	"github.com/lavanet/lava/x/pairing/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleAccountStakeInvariant(t *testing.T) {
+	_, keepers, goCtx := testkeeper.InitAllKeepers(t)
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	invariant := keeper.ModuleAccountStakeInvariant(keepers.Pairing)
+
+	keepers.Spec.SetSpec(ctx, spectypes.Spec{Index: "ETH1", Enabled: true})
+	stake := func(amount int64) epochstoragetypes.StakeEntry {
+		return epochstoragetypes.StakeEntry{Address: sample.AccAddress(), Chain: "ETH1", Stake: sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(amount))}
+	}
+	keepers.Epochstorage.AppendStakeEntryCurrent(ctx, epochstoragetypes.ProviderKey, "ETH1", stake(100))
+	keepers.Epochstorage.AppendStakeEntryCurrent(ctx, epochstoragetypes.ClientKey, "ETH1", stake(50))
+	require.NoError(t, keepers.Epochstorage.AppendUnstakeEntry(ctx, epochstoragetypes.ProviderKey, stake(25), 10))
+
+	moduleAddress := keepers.AccountKeeper.GetModuleAddress(types.ModuleName)
+	keepers.BankKeeper.SetBalance(ctx, moduleAddress, sdk.NewCoins(sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(174))))
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "total stake: 175")
+
+	keepers.BankKeeper.SetBalance(ctx, moduleAddress, sdk.NewCoins(sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(175))))
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+}
+
+func TestEpochPaymentsInvariant(t *testing.T) {
+	_, keepers, goCtx := testkeeper.InitAllKeepers(t)
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	invariant := keeper.EpochPaymentsInvariant(keepers.Pairing)
+
+	consumer, provider := sample.AccAddress(), sample.AccAddress()
+	consumerAddr, err := sdk.AccAddressFromBech32(consumer)
+	require.NoError(t, err)
+	providerAddr, err := sdk.AccAddressFromBech32(provider)
+	require.NoError(t, err)
+	for i, epoch := range []uint64{20, 20, 40} {
+		_, err = keepers.Pairing.AddEpochPayment(ctx, "ETH1", epoch, consumerAddr, providerAddr, 10, strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	// a unique payment newer than its epoch can't be removed with the epoch
+	uniquePaymentKey := keepers.Pairing.EncodeUniquePaymentKey(ctx, consumerAddr, providerAddr, "1", "ETH1")
+	uniquePayment, found := keepers.Pairing.GetUniquePaymentStorageClientProvider(ctx, uniquePaymentKey)
+	require.True(t, found)
+	uniquePayment.Block = 30
+	keepers.Pairing.SetUniquePaymentStorageClientProvider(ctx, uniquePayment)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "block 30 is after epoch 20")
+
+	// remove a unique payment that is still referenced
+	keepers.Pairing.RemoveUniquePaymentStorageClientProvider(ctx, uniquePaymentKey)
+	msg, broken = invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "is missing")
+}
@@ -27,6 +27,8 @@ func GetQueryCmd(queryRoute string) *cobra.Command {
 	cmd.AddCommand(CmdQueryParams())
 	cmd.AddCommand(CmdListSpec())
 	cmd.AddCommand(CmdShowSpec())
+	cmd.AddCommand(CmdShowSpecVersion())
+	cmd.AddCommand(CmdListSpecVersions())
 
 	cmd.AddCommand(CmdShowAllChains())
 
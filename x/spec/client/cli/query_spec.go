@@ -91,3 +91,84 @@ func CmdShowSpec() *cobra.Command {
 
 	return cmd
 }
+
+const (
+	FlagVersion = "version"
+	FlagBlock   = "block"
+)
+
+func CmdShowSpecVersion() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show-spec-version [index]",
+		Short: "shows a version of a Spec, by --version or the version that was active at --block",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			version, err := cmd.Flags().GetUint64(FlagVersion)
+			if err != nil {
+				return err
+			}
+			block, err := cmd.Flags().GetUint64(FlagBlock)
+			if err != nil {
+				return err
+			}
+
+			params := &types.QueryGetSpecVersionRequest{
+				ChainID: args[0],
+				Version: version,
+				Block:   block,
+			}
+
+			res, err := queryClient.SpecVersion(context.Background(), params)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().Uint64(FlagVersion, 0, "The version of the Spec, takes precedence over --block")
+	cmd.Flags().Uint64(FlagBlock, 0, "Show the version of the Spec that was active at this block, the latest block when not set")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+func CmdListSpecVersions() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-spec-versions [index]",
+		Short: "list all the versions of a Spec (raw format)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			params := &types.QueryAllSpecVersionRequest{
+				ChainID:    args[0],
+				Pagination: pageReq,
+			}
+
+			res, err := queryClient.SpecVersionAll(context.Background(), params)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddPaginationFlagsToCmd(cmd, cmd.Use)
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
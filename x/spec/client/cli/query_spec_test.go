@@ -29,7 +29,8 @@ func networkWithSpecObjects(t *testing.T, n int) (*network.Network, []types.Spec
 
 	for i := 0; i < n; i++ {
 		Spec := types.Spec{
-			Index: strconv.Itoa(i),
+			Index:   strconv.Itoa(i),
+			Version: 1, // genesis specs without a version are set to their first version
 		}
 		nullify.Fill(&Spec)
 		state.SpecList = append(state.SpecList, Spec)
@@ -13,6 +13,12 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState)
 	for _, elem := range genState.SpecList {
 		k.SetSpec(ctx, elem)
 	}
+	// Set all the spec versions
+	for _, elem := range genState.SpecVersionList {
+		k.SetSpecVersion(ctx, elem)
+	}
+	// specs from genesis files that predate spec versions start at their first version
+	k.InitSpecVersions(ctx)
 
 	// this line is used by starport scaffolding # genesis/module/init
 	k.SetParams(ctx, genState.Params)
@@ -25,6 +31,7 @@ func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
 
 	genesis.SpecList = k.GetAllSpec(ctx)
 	genesis.SpecCount = uint64(len(genesis.SpecList))
+	genesis.SpecVersionList = k.GetAllSpecVersion(ctx)
 
 	// this line is used by starport scaffolding # genesis/module/export
 
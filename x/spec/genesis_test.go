@@ -16,13 +16,29 @@ func TestGenesis(t *testing.T) {
 
 		SpecList: []types.Spec{
 			{
-				Index: "0",
+				Index:   "0",
+				Version: 2,
 			},
 			{
-				Index: "1",
+				Index:   "1",
+				Version: 1,
 			},
 		},
 		SpecCount: 2,
+		SpecVersionList: []types.Spec{
+			{
+				Index:   "0",
+				Version: 1,
+			},
+			{
+				Index:   "0",
+				Version: 2,
+			},
+			{
+				Index:   "1",
+				Version: 1,
+			},
+		},
 
 		// this line is used by starport scaffolding # genesis/test/state
 	}
@@ -37,5 +53,6 @@ func TestGenesis(t *testing.T) {
 
 	require.ElementsMatch(t, genesisState.SpecList, got.SpecList)
 	require.Equal(t, genesisState.SpecCount, got.SpecCount)
+	require.ElementsMatch(t, genesisState.SpecVersionList, got.SpecVersionList)
 	// this line is used by starport scaffolding # genesis/test/assert
 }
@@ -0,0 +1,71 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/lavanet/lava/x/spec/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (k Keeper) SpecVersion(c context.Context, req *types.QueryGetSpecVersionRequest) (*types.QueryGetSpecVersionResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	ctx := sdk.UnwrapSDKContext(c)
+
+	var spec types.Spec
+	var found bool
+	block := req.Block
+	if block == 0 {
+		block = uint64(ctx.BlockHeight())
+	}
+	if req.Version != 0 {
+		spec, found = k.GetSpecVersion(ctx, req.ChainID, req.Version)
+		// the imports are expanded as they were when the version became active
+		block = spec.BlockLastUpdated
+	} else {
+		spec, found = k.GetSpecVersionAtBlock(ctx, req.ChainID, block)
+	}
+	if !found {
+		return nil, status.Error(codes.InvalidArgument, "not found")
+	}
+
+	spec, err := k.ExpandSpecAtBlock(ctx, spec, block)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryGetSpecVersionResponse{Spec: spec}, nil
+}
+
+func (k Keeper) SpecVersionAll(c context.Context, req *types.QueryAllSpecVersionRequest) (*types.QueryAllSpecVersionResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	var specs []types.Spec
+	ctx := sdk.UnwrapSDKContext(c)
+
+	store := ctx.KVStore(k.storeKey)
+	specVersionStore := prefix.NewStore(store, append(types.KeyPrefix(types.SpecVersionKeyPrefix), types.SpecVersionIndexKey(req.ChainID)...))
+
+	pageRes, err := query.Paginate(specVersionStore, req.Pagination, func(key []byte, value []byte) error {
+		var spec types.Spec
+
+		if err := k.cdc.Unmarshal(value, &spec); err != nil {
+			return err
+		}
+
+		specs = append(specs, spec)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryAllSpecVersionResponse{Spec: specs, Pagination: pageRes}, nil
+}
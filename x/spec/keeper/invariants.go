@@ -0,0 +1,82 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/spec/types"
+)
+
+// RegisterInvariants registers all spec invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "spec-imports", SpecImportsInvariant(k))
+}
+
+// SpecImportsInvariant checks every spec import refers to an existing spec and that imports don't form a cycle,
+// ExpandSpec relies on both when a spec is added so a migration must not break them
+func SpecImportsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		imports := map[string][]string{}
+		for _, spec := range k.GetAllSpec(ctx) {
+			imports[spec.Index] = spec.Imports
+		}
+		indexes := make([]string, 0, len(imports))
+		for index := range imports {
+			indexes = append(indexes, index)
+		}
+		sort.Strings(indexes)
+
+		problems := []string{}
+		for _, index := range indexes {
+			for _, imported := range imports[index] {
+				if _, ok := imports[imported]; !ok {
+					problems = append(problems, fmt.Sprintf("\tspec %s imports unknown spec %s\n", index, imported))
+				}
+			}
+		}
+
+		// depth first search, a spec reached again while it is still on the path closes a cycle
+		const (
+			unvisited = iota
+			onPath
+			done
+		)
+		state := map[string]int{}
+		path := []string{}
+		var visit func(index string)
+		visit = func(index string) {
+			state[index] = onPath
+			path = append(path, index)
+			for _, imported := range imports[index] {
+				switch state[imported] {
+				case onPath:
+					cycleStart := 0
+					for i, onPathIndex := range path {
+						if onPathIndex == imported {
+							cycleStart = i
+						}
+					}
+					cycle := append(append([]string{}, path[cycleStart:]...), imported)
+					problems = append(problems, fmt.Sprintf("\timport cycle %s\n", strings.Join(cycle, "->")))
+				case unvisited:
+					if _, ok := imports[imported]; ok {
+						visit(imported)
+					}
+				}
+			}
+			path = path[:len(path)-1]
+			state[index] = done
+		}
+		for _, index := range indexes {
+			if state[index] == unvisited {
+				visit(index)
+			}
+		}
+
+		broken := len(problems) > 0
+		return sdk.FormatInvariant(types.ModuleName, "spec-imports",
+			fmt.Sprintf("\tspecs: %d\n\tbroken imports: %d\n%s", len(indexes), len(problems), strings.Join(problems, ""))), broken
+	}
+}
@@ -0,0 +1,34 @@
+package keeper_test
+
+import (
+	"testing"
+
+	keepertest "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/x/spec/keeper"
+	"github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecImportsInvariant(t *testing.T) {
+	k, ctx := keepertest.SpecKeeper(t)
+	invariant := keeper.SpecImportsInvariant(*k)
+
+	k.SetSpec(ctx, types.Spec{Index: "A"})
+	k.SetSpec(ctx, types.Spec{Index: "B", Imports: []string{"A"}})
+	k.SetSpec(ctx, types.Spec{Index: "C", Imports: []string{"A", "B"}})
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	// an import of an unknown spec
+	k.SetSpec(ctx, types.Spec{Index: "D", Imports: []string{"X"}})
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "spec D imports unknown spec X")
+	k.RemoveSpec(ctx, "D")
+
+	// A imports C which imports A
+	k.SetSpec(ctx, types.Spec{Index: "A", Imports: []string{"C"}})
+	msg, broken = invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "import cycle A->C->A")
+}
@@ -69,9 +69,17 @@ func (k Keeper) GetAllSpec(ctx sdk.Context) (list []types.Spec) {
 // -if needed, recursively- to add to the current Spec those additional APIs
 // from the imported Spec(s). It returns the expanded Spec.
 func (k Keeper) ExpandSpec(ctx sdk.Context, spec types.Spec) (types.Spec, error) {
+	getSpec := func(index string) (types.Spec, bool) {
+		return k.GetSpec(ctx, index)
+	}
+	return k.expandSpec(ctx, spec, getSpec)
+}
+
+// expandSpec expands the imports of spec with the imported specs getSpec returns
+func (k Keeper) expandSpec(ctx sdk.Context, spec types.Spec, getSpec func(index string) (types.Spec, bool)) (types.Spec, error) {
 	depends := map[string]bool{spec.Index: true}
 
-	details, err := k.doExpandSpec(ctx, &spec, depends, spec.Index)
+	details, err := k.doExpandSpec(getSpec, &spec, depends, spec.Index)
 	if err != nil {
 		details := map[string]string{"imports": details}
 		return spec, utils.LavaError(ctx, k.Logger(ctx), "spec expand failed", details, err.Error())
@@ -80,7 +88,7 @@ func (k Keeper) ExpandSpec(ctx sdk.Context, spec types.Spec) (types.Spec, error)
 }
 
 // doExpandSpec performs the actual work and recusion for ExpandSpec above.
-func (k Keeper) doExpandSpec(ctx sdk.Context, spec *types.Spec, depends map[string]bool, details string) (string, error) {
+func (k Keeper) doExpandSpec(getSpec func(index string) (types.Spec, bool), spec *types.Spec, depends map[string]bool, details string) (string, error) {
 	if len(spec.Imports) == 0 {
 		return details, nil
 	}
@@ -93,7 +101,7 @@ func (k Keeper) doExpandSpec(ctx sdk.Context, spec *types.Spec, depends map[stri
 	// recursion to get all parent specs (DFS)
 	comma := ""
 	for _, index := range spec.Imports {
-		imported, found := k.GetSpec(ctx, index)
+		imported, found := getSpec(index)
 		// import of unknown Spec not allowed
 		if !found {
 			details += fmt.Sprintf("%s%s(unknown)", comma, index)
@@ -108,7 +116,7 @@ func (k Keeper) doExpandSpec(ctx sdk.Context, spec *types.Spec, depends map[stri
 		}
 
 		depends[index] = true
-		details, err := k.doExpandSpec(ctx, &imported, depends, details)
+		details, err := k.doExpandSpec(getSpec, &imported, depends, details)
 		if err != nil {
 			return details, err
 		}
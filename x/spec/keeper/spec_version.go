@@ -0,0 +1,133 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/spec/types"
+)
+
+// SetSpecNewVersion sets spec as the next version of its index, active from the current block. The version and
+// block_last_updated of spec are overwritten, the previous versions stay in the spec versions history
+func (k Keeper) SetSpecNewVersion(ctx sdk.Context, spec types.Spec) types.Spec {
+	spec.Version = k.latestSpecVersion(ctx, spec.Index) + 1
+	spec.BlockLastUpdated = uint64(ctx.BlockHeight())
+	k.SetSpec(ctx, spec)
+	k.SetSpecVersion(ctx, spec)
+	return spec
+}
+
+// InitSpecVersions makes the specs that have no version their first version, active from the block they were last updated at
+func (k Keeper) InitSpecVersions(ctx sdk.Context) {
+	for _, spec := range k.GetAllSpec(ctx) {
+		if spec.Version != 0 {
+			continue
+		}
+		spec.Version = k.latestSpecVersion(ctx, spec.Index) + 1
+		k.SetSpec(ctx, spec)
+		k.SetSpecVersion(ctx, spec)
+	}
+}
+
+// latestSpecVersion returns the last version a spec had, a removed spec that is added again continues from it
+func (k Keeper) latestSpecVersion(ctx sdk.Context, index string) uint64 {
+	latest := uint64(0)
+	if spec, found := k.GetSpec(ctx, index); found {
+		latest = spec.Version
+	}
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.SpecVersionKeyPrefix))
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.SpecVersionIndexKey(index))
+	defer iterator.Close()
+	if iterator.Valid() {
+		var val types.Spec
+		k.cdc.MustUnmarshal(iterator.Value(), &val)
+		if val.Version > latest {
+			latest = val.Version
+		}
+	}
+	return latest
+}
+
+// SetSpecVersion set a version of a spec in the spec versions history
+func (k Keeper) SetSpecVersion(ctx sdk.Context, spec types.Spec) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.SpecVersionKeyPrefix))
+	b := k.cdc.MustMarshal(&spec)
+	store.Set(types.SpecVersionKey(
+		spec.Index,
+		spec.Version,
+	), b)
+}
+
+// GetSpecVersion returns a version of a spec
+func (k Keeper) GetSpecVersion(
+	ctx sdk.Context,
+	index string,
+	version uint64,
+) (val types.Spec, found bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.SpecVersionKeyPrefix))
+
+	b := store.Get(types.SpecVersionKey(
+		index,
+		version,
+	))
+	if b == nil {
+		return val, false
+	}
+
+	k.cdc.MustUnmarshal(b, &val)
+	return val, true
+}
+
+// RemoveSpecVersion removes a version of a spec from the spec versions history
+func (k Keeper) RemoveSpecVersion(
+	ctx sdk.Context,
+	index string,
+	version uint64,
+) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.SpecVersionKeyPrefix))
+	store.Delete(types.SpecVersionKey(
+		index,
+		version,
+	))
+}
+
+// GetSpecVersionAtBlock returns the version of a spec that was active at block
+func (k Keeper) GetSpecVersionAtBlock(ctx sdk.Context, index string, block uint64) (val types.Spec, found bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.SpecVersionKeyPrefix))
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.SpecVersionIndexKey(index))
+
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var version types.Spec
+		k.cdc.MustUnmarshal(iterator.Value(), &version)
+		if version.BlockLastUpdated <= block {
+			return version, true
+		}
+	}
+
+	return val, false
+}
+
+// GetAllSpecVersion returns all the versions of all the specs
+func (k Keeper) GetAllSpecVersion(ctx sdk.Context) (list []types.Spec) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.SpecVersionKeyPrefix))
+	iterator := sdk.KVStorePrefixIterator(store, []byte{})
+
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var val types.Spec
+		k.cdc.MustUnmarshal(iterator.Value(), &val)
+		list = append(list, val)
+	}
+
+	return
+}
+
+// ExpandSpecAtBlock expands the imports of a spec with the versions of the imported specs that were active at block
+func (k Keeper) ExpandSpecAtBlock(ctx sdk.Context, spec types.Spec, block uint64) (types.Spec, error) {
+	getSpec := func(index string) (types.Spec, bool) {
+		return k.GetSpecVersionAtBlock(ctx, index, block)
+	}
+	return k.expandSpec(ctx, spec, getSpec)
+}
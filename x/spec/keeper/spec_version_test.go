@@ -0,0 +1,102 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	keepertest "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSpecNewVersion(t *testing.T) {
+	keeper, ctx := keepertest.SpecKeeper(t)
+	apis := prepareMockApis()
+
+	spec := keeper.SetSpecNewVersion(ctx.WithBlockHeight(10), types.Spec{Index: "ETH1", Apis: selectMockApis(apis, []int{0})})
+	require.Equal(t, uint64(1), spec.Version)
+	require.Equal(t, uint64(10), spec.BlockLastUpdated)
+	spec.Apis = selectMockApis(apis, []int{0, 2})
+	spec = keeper.SetSpecNewVersion(ctx.WithBlockHeight(20), spec)
+	require.Equal(t, uint64(2), spec.Version)
+
+	current, found := keeper.GetSpec(ctx, "ETH1")
+	require.True(t, found)
+	require.Equal(t, spec.Version, current.Version)
+	require.Equal(t, spec.Apis, current.Apis)
+
+	first, found := keeper.GetSpecVersion(ctx, "ETH1", 1)
+	require.True(t, found)
+	require.Len(t, first.Apis, 1)
+
+	_, found = keeper.GetSpecVersionAtBlock(ctx, "ETH1", 9)
+	require.False(t, found)
+	for block, version := range map[uint64]uint64{10: 1, 19: 1, 20: 2, 100: 2} {
+		atBlock, found := keeper.GetSpecVersionAtBlock(ctx, "ETH1", block)
+		require.True(t, found)
+		require.Equal(t, version, atBlock.Version, "block %d", block)
+	}
+
+	// a removed spec that is added again continues from its last version
+	keeper.RemoveSpec(ctx, "ETH1")
+	spec = keeper.SetSpecNewVersion(ctx.WithBlockHeight(30), types.Spec{Index: "ETH1"})
+	require.Equal(t, uint64(3), spec.Version)
+	require.Len(t, keeper.GetAllSpecVersion(ctx), 3)
+}
+
+func TestInitSpecVersions(t *testing.T) {
+	keeper, ctx := keepertest.SpecKeeper(t)
+	keeper.SetSpec(ctx, types.Spec{Index: "ETH1", BlockLastUpdated: 5})
+	keeper.SetSpecNewVersion(ctx.WithBlockHeight(7), types.Spec{Index: "LAV1"})
+
+	keeper.InitSpecVersions(ctx.WithBlockHeight(50))
+	spec, found := keeper.GetSpec(ctx, "ETH1")
+	require.True(t, found)
+	require.Equal(t, uint64(1), spec.Version)
+	// the content didn't change, it was active since it was last updated
+	require.Equal(t, uint64(5), spec.BlockLastUpdated)
+	_, found = keeper.GetSpecVersion(ctx, "ETH1", 1)
+	require.True(t, found)
+
+	spec, found = keeper.GetSpec(ctx, "LAV1")
+	require.True(t, found)
+	require.Equal(t, uint64(1), spec.Version)
+	require.Len(t, keeper.GetAllSpecVersion(ctx), 2)
+}
+
+func TestSpecVersionQuery(t *testing.T) {
+	keeper, ctx := keepertest.SpecKeeper(t)
+	apis := prepareMockApis()
+
+	// the imported spec changes after the importing spec, the imports expand as they were at the queried block
+	keeper.SetSpecNewVersion(ctx.WithBlockHeight(10), types.Spec{Index: "COS3", Apis: selectMockApis(apis, []int{0})})
+	keeper.SetSpecNewVersion(ctx.WithBlockHeight(10), types.Spec{Index: "COS4", Imports: []string{"COS3"}, Apis: selectMockApis(apis, []int{2})})
+	keeper.SetSpecNewVersion(ctx.WithBlockHeight(20), types.Spec{Index: "COS3", Apis: selectMockApis(apis, []int{0, 4})})
+	wctx := sdk.WrapSDKContext(ctx.WithBlockHeight(30))
+
+	response, err := keeper.SpecVersion(wctx, &types.QueryGetSpecVersionRequest{ChainID: "COS4", Block: 15})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), response.Spec.Version)
+	require.Len(t, response.Spec.Apis, 2)
+
+	response, err = keeper.SpecVersion(wctx, &types.QueryGetSpecVersionRequest{ChainID: "COS4"})
+	require.NoError(t, err)
+	require.Len(t, response.Spec.Apis, 3)
+
+	response, err = keeper.SpecVersion(wctx, &types.QueryGetSpecVersionRequest{ChainID: "COS3", Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), response.Spec.BlockLastUpdated)
+
+	_, err = keeper.SpecVersion(wctx, &types.QueryGetSpecVersionRequest{ChainID: "COS3", Version: 3})
+	require.ErrorIs(t, err, status.Error(codes.InvalidArgument, "not found"))
+	_, err = keeper.SpecVersion(wctx, &types.QueryGetSpecVersionRequest{ChainID: "COS4", Block: 5})
+	require.ErrorIs(t, err, status.Error(codes.InvalidArgument, "not found"))
+
+	all, err := keeper.SpecVersionAll(wctx, &types.QueryAllSpecVersionRequest{ChainID: "COS3"})
+	require.NoError(t, err)
+	require.Len(t, all.Spec, 2)
+	require.Equal(t, uint64(1), all.Spec[0].Version)
+	require.Equal(t, uint64(2), all.Spec[1].Version)
+}
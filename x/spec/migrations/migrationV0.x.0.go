@@ -17,6 +17,12 @@ func (m Migrator) MigrateToV0X0(ctx sdk.Context) error {
 	return updateSpecsVersion(ctx, m.keeper)
 }
 
+// MigrateToVersionedSpecs migrates the spec module from consensus version 2 to 3, the existing specs become their first version
+func (m Migrator) MigrateToVersionedSpecs(ctx sdk.Context) error {
+	m.keeper.InitSpecVersions(ctx)
+	return nil
+}
+
 func updateSpecsVersion(ctx sdk.Context, k keeper.Keeper) error {
 	specs := k.GetAllSpec(ctx)
 	for spec := range specs {
@@ -85,17 +85,18 @@ func handleSpecProposal(ctx sdk.Context, k keeper.Keeper, p *types.SpecAddPropos
 			return utils.LavaError(ctx, logger, "invalid_spec", details, err.Error())
 		}
 
-		spec.BlockLastUpdated = uint64(ctx.BlockHeight())
-		k.SetSpec(ctx, spec)
+		spec = k.SetSpecNewVersion(ctx, spec)
 
 		name := types.SpecAddEventName
 		if found {
 			// re-validate all the specs, in case the modified spec is imported by
 			// other specs and the new version creates a conflict.
-			for _, spec := range k.GetAllSpec(ctx) {
-				if _, err = k.ValidateSpec(ctx, spec); err != nil {
+			for _, otherSpec := range k.GetAllSpec(ctx) {
+				if _, err = k.ValidateSpec(ctx, otherSpec); err != nil {
+					// roll back to the previous version, the rejected version leaves no history
 					k.SetSpec(ctx, bak)
-					details["invalidates"] = spec.Index
+					k.RemoveSpecVersion(ctx, spec.Index, spec.Version)
+					details["invalidates"] = otherSpec.Index
 					return utils.LavaError(ctx, logger, "invalidated_spec", details, err.Error())
 				}
 			}
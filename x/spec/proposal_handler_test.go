@@ -0,0 +1,61 @@
+package spec_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	keepertest "github.com/lavanet/lava/testutil/keeper"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	"github.com/lavanet/lava/x/spec"
+	"github.com/lavanet/lava/x/spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func proposalSpec(index string, imports []string, apiNames ...string) types.Spec {
+	spec := types.Spec{
+		Index:                     index,
+		Name:                      index,
+		Enabled:                   true,
+		Imports:                   imports,
+		ReliabilityThreshold:      268435455,
+		BlocksInFinalizationProof: 1,
+		AverageBlockTime:          1000,
+		AllowedBlockLagForQosSync: 1,
+		MinStakeClient:            sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(100)),
+		MinStakeProvider:          sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(100)),
+	}
+	for _, apiName := range apiNames {
+		spec.Apis = append(spec.Apis, types.ServiceApi{Name: apiName, Enabled: true, ComputeUnits: 10})
+	}
+	return spec
+}
+
+func TestSpecProposalInvalidatesImportingSpec(t *testing.T) {
+	keeper, ctx := keepertest.SpecKeeper(t)
+	handler := spec.NewSpecProposalsHandler(*keeper)
+
+	err := handler(ctx, &types.SpecAddProposal{Specs: []types.Spec{
+		proposalSpec("A", nil, "api-a"),
+		proposalSpec("B", nil, "api-b"),
+		proposalSpec("C", []string{"A", "B"}),
+	}})
+	require.Nil(t, err)
+
+	// A is valid on its own, but C would import api-b from both A and B
+	err = handler(ctx.WithBlockHeight(10), &types.SpecAddProposal{Specs: []types.Spec{
+		proposalSpec("A", nil, "api-a", "api-b"),
+	}})
+	require.NotNil(t, err)
+
+	// the rejected version of A is rolled back, the versions of the other specs are kept
+	specA, found := keeper.GetSpec(ctx, "A")
+	require.True(t, found)
+	require.Equal(t, uint64(1), specA.Version)
+	require.Len(t, specA.Apis, 1)
+	_, found = keeper.GetSpecVersion(ctx, "A", 2)
+	require.False(t, found)
+	_, found = keeper.GetSpecVersion(ctx, "C", 1)
+	require.True(t, found)
+	_, found = keeper.GetSpecVersion(ctx, "A", 1)
+	require.True(t, found)
+}
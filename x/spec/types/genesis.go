@@ -10,7 +10,8 @@ const DefaultIndex uint64 = 1
 // DefaultGenesis returns the default Capability genesis state
 func DefaultGenesis() *GenesisState {
 	return &GenesisState{
-		SpecList: []Spec{},
+		SpecList:        []Spec{},
+		SpecVersionList: []Spec{},
 		// this line is used by starport scaffolding # genesis/types/default
 		Params: DefaultParams(),
 	}
@@ -33,6 +34,17 @@ func (gs GenesisState) Validate() error {
 	if gs.SpecCount != uint64(len(gs.SpecList)) {
 		return fmt.Errorf("Spec count mismatch spec list")
 	}
+
+	// Check for duplicated versions in spec versions
+	specVersionIndexMap := make(map[string]struct{})
+
+	for _, elem := range gs.SpecVersionList {
+		index := string(SpecVersionKey(elem.Index, elem.Version))
+		if _, ok := specVersionIndexMap[index]; ok {
+			return fmt.Errorf("duplicated version for Spec %s", elem.Index)
+		}
+		specVersionIndexMap[index] = struct{}{}
+	}
 	// this line is used by starport scaffolding # genesis/types/validate
 
 	return gs.Params.Validate()
@@ -50,6 +50,23 @@ func TestGenesisState_Validate(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			desc: "duplicated spec version",
+			genState: &types.GenesisState{
+				Params: types.DefaultParams(),
+				SpecVersionList: []types.Spec{
+					{
+						Index:   "0",
+						Version: 1,
+					},
+					{
+						Index:   "0",
+						Version: 1,
+					},
+				},
+			},
+			valid: false,
+		},
 		{
 			desc: "invalid spec count",
 			genState: &types.GenesisState{
@@ -2,8 +2,6 @@ package types
 
 import "encoding/binary"
 
-var _ binary.ByteOrder
-
 const (
 	// SpecKeyPrefix is the prefix to retrieve all Spec
 	SpecKeyPrefix = "Spec/value/"
@@ -21,3 +19,29 @@ func SpecKey(
 
 	return key
 }
+
+const (
+	// SpecVersionKeyPrefix is the prefix to retrieve all the versions of all the specs
+	SpecVersionKeyPrefix = "SpecVersion/value/"
+)
+
+// SpecVersionIndexKey returns the store key prefix of all the versions of a spec
+func SpecVersionIndexKey(
+	index string,
+) []byte {
+	return SpecKey(index)
+}
+
+// SpecVersionKey returns the store key to retrieve a version of a spec, versions of a spec iterate in increasing order
+func SpecVersionKey(
+	index string,
+	version uint64,
+) []byte {
+	key := SpecVersionIndexKey(index)
+
+	versionBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(versionBytes, version)
+	key = append(key, versionBytes...)
+
+	return key
+}
@@ -593,6 +593,214 @@ func (m *QueryShowChainInfoResponse) GetSupportedApisInterfaceList() []*ApiList
 	return nil
 }
 
+type QueryGetSpecVersionRequest struct {
+	ChainID string `protobuf:"bytes,1,opt,name=ChainID,proto3" json:"ChainID,omitempty"`
+	Version uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Block   uint64 `protobuf:"varint,3,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (m *QueryGetSpecVersionRequest) Reset()         { *m = QueryGetSpecVersionRequest{} }
+func (m *QueryGetSpecVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryGetSpecVersionRequest) ProtoMessage()    {}
+func (*QueryGetSpecVersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6723cd4498ae5af7, []int{12}
+}
+func (m *QueryGetSpecVersionRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryGetSpecVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryGetSpecVersionRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryGetSpecVersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryGetSpecVersionRequest.Merge(m, src)
+}
+func (m *QueryGetSpecVersionRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryGetSpecVersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryGetSpecVersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryGetSpecVersionRequest proto.InternalMessageInfo
+
+func (m *QueryGetSpecVersionRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *QueryGetSpecVersionRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *QueryGetSpecVersionRequest) GetBlock() uint64 {
+	if m != nil {
+		return m.Block
+	}
+	return 0
+}
+
+type QueryGetSpecVersionResponse struct {
+	Spec Spec `protobuf:"bytes,1,opt,name=Spec,proto3" json:"Spec"`
+}
+
+func (m *QueryGetSpecVersionResponse) Reset()         { *m = QueryGetSpecVersionResponse{} }
+func (m *QueryGetSpecVersionResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryGetSpecVersionResponse) ProtoMessage()    {}
+func (*QueryGetSpecVersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6723cd4498ae5af7, []int{13}
+}
+func (m *QueryGetSpecVersionResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryGetSpecVersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryGetSpecVersionResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryGetSpecVersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryGetSpecVersionResponse.Merge(m, src)
+}
+func (m *QueryGetSpecVersionResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryGetSpecVersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryGetSpecVersionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryGetSpecVersionResponse proto.InternalMessageInfo
+
+func (m *QueryGetSpecVersionResponse) GetSpec() Spec {
+	if m != nil {
+		return m.Spec
+	}
+	return Spec{}
+}
+
+type QueryAllSpecVersionRequest struct {
+	ChainID    string             `protobuf:"bytes,1,opt,name=ChainID,proto3" json:"ChainID,omitempty"`
+	Pagination *query.PageRequest `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+func (m *QueryAllSpecVersionRequest) Reset()         { *m = QueryAllSpecVersionRequest{} }
+func (m *QueryAllSpecVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryAllSpecVersionRequest) ProtoMessage()    {}
+func (*QueryAllSpecVersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6723cd4498ae5af7, []int{14}
+}
+func (m *QueryAllSpecVersionRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryAllSpecVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryAllSpecVersionRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryAllSpecVersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryAllSpecVersionRequest.Merge(m, src)
+}
+func (m *QueryAllSpecVersionRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryAllSpecVersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryAllSpecVersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryAllSpecVersionRequest proto.InternalMessageInfo
+
+func (m *QueryAllSpecVersionRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *QueryAllSpecVersionRequest) GetPagination() *query.PageRequest {
+	if m != nil {
+		return m.Pagination
+	}
+	return nil
+}
+
+type QueryAllSpecVersionResponse struct {
+	Spec       []Spec              `protobuf:"bytes,1,rep,name=Spec,proto3" json:"Spec"`
+	Pagination *query.PageResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+func (m *QueryAllSpecVersionResponse) Reset()         { *m = QueryAllSpecVersionResponse{} }
+func (m *QueryAllSpecVersionResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryAllSpecVersionResponse) ProtoMessage()    {}
+func (*QueryAllSpecVersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6723cd4498ae5af7, []int{15}
+}
+func (m *QueryAllSpecVersionResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryAllSpecVersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryAllSpecVersionResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryAllSpecVersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryAllSpecVersionResponse.Merge(m, src)
+}
+func (m *QueryAllSpecVersionResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryAllSpecVersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryAllSpecVersionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryAllSpecVersionResponse proto.InternalMessageInfo
+
+func (m *QueryAllSpecVersionResponse) GetSpec() []Spec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *QueryAllSpecVersionResponse) GetPagination() *query.PageResponse {
+	if m != nil {
+		return m.Pagination
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*QueryParamsRequest)(nil), "lavanet.lava.spec.QueryParamsRequest")
 	proto.RegisterType((*QueryParamsResponse)(nil), "lavanet.lava.spec.QueryParamsResponse")
@@ -606,63 +814,75 @@ func init() {
 	proto.RegisterType((*QueryShowChainInfoRequest)(nil), "lavanet.lava.spec.QueryShowChainInfoRequest")
 	proto.RegisterType((*ApiList)(nil), "lavanet.lava.spec.apiList")
 	proto.RegisterType((*QueryShowChainInfoResponse)(nil), "lavanet.lava.spec.QueryShowChainInfoResponse")
+	proto.RegisterType((*QueryGetSpecVersionRequest)(nil), "lavanet.lava.spec.QueryGetSpecVersionRequest")
+	proto.RegisterType((*QueryGetSpecVersionResponse)(nil), "lavanet.lava.spec.QueryGetSpecVersionResponse")
+	proto.RegisterType((*QueryAllSpecVersionRequest)(nil), "lavanet.lava.spec.QueryAllSpecVersionRequest")
+	proto.RegisterType((*QueryAllSpecVersionResponse)(nil), "lavanet.lava.spec.QueryAllSpecVersionResponse")
 }
 
 func init() { proto.RegisterFile("spec/query.proto", fileDescriptor_6723cd4498ae5af7) }
 
 var fileDescriptor_6723cd4498ae5af7 = []byte{
-	// 807 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x96, 0xcf, 0x4e, 0xdb, 0x4a,
-	0x14, 0xc6, 0xe3, 0x24, 0x90, 0xcb, 0x5c, 0xa1, 0x0b, 0x73, 0x23, 0x91, 0x18, 0x1a, 0xc0, 0xfc,
-	0x2d, 0x2a, 0x76, 0xa1, 0x52, 0xab, 0x6e, 0x2a, 0x05, 0xaa, 0x22, 0xaa, 0x16, 0x51, 0xb3, 0x43,
-	0xaa, 0xa2, 0x89, 0x3b, 0x24, 0x96, 0x1c, 0x8f, 0x89, 0x27, 0xa4, 0x14, 0xb1, 0x41, 0xea, 0xa2,
-	0xbb, 0xaa, 0xdd, 0xf4, 0x01, 0xfa, 0x04, 0x7d, 0x0a, 0x96, 0x48, 0xdd, 0xb0, 0xaa, 0x2a, 0xe8,
-	0x83, 0x54, 0x3e, 0x33, 0x09, 0x36, 0xb1, 0x03, 0xaa, 0xba, 0x89, 0xf1, 0xcc, 0x39, 0xdf, 0xf7,
-	0x9b, 0x73, 0xc6, 0x47, 0xa0, 0x11, 0xdf, 0xa3, 0x96, 0xb1, 0xdf, 0xa2, 0xcd, 0x43, 0xdd, 0x6b,
-	0x32, 0xce, 0xf0, 0xa8, 0x43, 0x0e, 0x88, 0x4b, 0xb9, 0x1e, 0x3c, 0xf5, 0x60, 0x5b, 0xcd, 0xd7,
-	0x58, 0x8d, 0xc1, 0xae, 0x11, 0xfc, 0x25, 0x02, 0xd5, 0x89, 0x1a, 0x63, 0x35, 0x87, 0x1a, 0xc4,
-	0xb3, 0x0d, 0xe2, 0xba, 0x8c, 0x13, 0x6e, 0x33, 0xd7, 0x97, 0xbb, 0x4b, 0x16, 0xf3, 0x1b, 0xcc,
-	0x37, 0xaa, 0xc4, 0xa7, 0x42, 0xdf, 0x38, 0x58, 0xa9, 0x52, 0x4e, 0x56, 0x0c, 0x8f, 0xd4, 0x6c,
-	0x17, 0x82, 0x65, 0xec, 0x28, 0x40, 0x78, 0xa4, 0x49, 0x1a, 0x9d, 0xf4, 0xff, 0x60, 0x29, 0xf8,
-	0x11, 0x0b, 0x5a, 0x1e, 0xe1, 0x57, 0x81, 0xca, 0x36, 0x44, 0x99, 0x74, 0xbf, 0x45, 0x7d, 0xae,
-	0x6d, 0xa1, 0xff, 0x23, 0xab, 0xbe, 0xc7, 0x5c, 0x9f, 0xe2, 0x47, 0x68, 0x50, 0xa8, 0x15, 0x94,
-	0x29, 0x65, 0xf1, 0xdf, 0xd5, 0xa2, 0xde, 0x73, 0x28, 0x5d, 0xa4, 0xac, 0x65, 0x4f, 0x7f, 0x4c,
-	0xa6, 0x4c, 0x19, 0xae, 0x19, 0x52, 0x6f, 0x83, 0xf2, 0x1d, 0x8f, 0x5a, 0xd2, 0x06, 0x17, 0x50,
-	0x6e, 0xbd, 0x4e, 0x6c, 0x77, 0xf3, 0x29, 0x08, 0x0e, 0x99, 0x9d, 0x57, 0x6d, 0x13, 0xe5, 0xa3,
-	0x09, 0x92, 0x60, 0x05, 0x65, 0x83, 0x77, 0xe9, 0x3f, 0x16, 0xe3, 0x1f, 0x6c, 0x4b, 0x77, 0x08,
-	0xd5, 0x5e, 0x4b, 0xef, 0xb2, 0xe3, 0x84, 0xbd, 0x9f, 0x21, 0x74, 0x55, 0x30, 0xa9, 0x37, 0xaf,
-	0x8b, 0xea, 0xea, 0x41, 0x75, 0x75, 0xd1, 0x3d, 0x59, 0x5d, 0x7d, 0x9b, 0xd4, 0xa8, 0xcc, 0x35,
-	0x43, 0x99, 0xda, 0x27, 0x45, 0xa2, 0x76, 0xf5, 0x7b, 0x50, 0x33, 0xb7, 0x44, 0xc5, 0x1b, 0x11,
-	0xa6, 0x34, 0x30, 0x2d, 0xdc, 0xc8, 0x24, 0xfc, 0x22, 0x50, 0xe3, 0xa8, 0x08, 0x4c, 0x3b, 0x75,
-	0xd6, 0x2e, 0x3b, 0x0e, 0x54, 0xb5, 0xdb, 0x5c, 0x8e, 0xd4, 0xb8, 0x4d, 0x89, 0xbd, 0x8d, 0x86,
-	0x2d, 0x68, 0x82, 0xbb, 0xc7, 0x5e, 0xd8, 0x3e, 0x2f, 0xa4, 0x81, 0x7f, 0x29, 0x86, 0xdf, 0x0f,
-	0x0b, 0x04, 0xf1, 0x3b, 0xbc, 0xd9, 0xb2, 0xb8, 0x19, 0x15, 0x78, 0x9e, 0xfd, 0x47, 0x19, 0x49,
-	0x6b, 0xef, 0x15, 0x34, 0x96, 0x90, 0x80, 0x27, 0xd0, 0x10, 0xa4, 0x6c, 0x91, 0x06, 0x95, 0x37,
-	0xe1, 0x6a, 0x21, 0xb8, 0x25, 0x96, 0xbc, 0x25, 0x69, 0x71, 0x4b, 0xe4, 0x2b, 0x5e, 0x45, 0x79,
-	0xea, 0x92, 0xaa, 0x43, 0xdf, 0x94, 0x3d, 0x7b, 0xd3, 0xe5, 0xb4, 0xb9, 0x47, 0x2c, 0xea, 0x17,
-	0x32, 0x53, 0x99, 0xc5, 0x21, 0x33, 0x76, 0x4f, 0x7b, 0x1c, 0x2a, 0xcd, 0x7a, 0x87, 0xb3, 0x73,
-	0x29, 0xfa, 0x82, 0x68, 0x2f, 0x51, 0x8e, 0x78, 0x76, 0x70, 0xa6, 0x20, 0xd0, 0xee, 0x68, 0x16,
-	0xb2, 0x22, 0xb0, 0xbb, 0x80, 0x67, 0xd1, 0xb0, 0xdf, 0xf2, 0x3c, 0xd6, 0xe4, 0xe0, 0xee, 0x17,
-	0x06, 0x00, 0x28, 0xba, 0xa8, 0x7d, 0x53, 0x42, 0x8d, 0x08, 0xa1, 0xc8, 0x46, 0x84, 0x8e, 0xad,
-	0x44, 0x8f, 0x5d, 0x42, 0xc8, 0xbe, 0x3a, 0x6c, 0x1a, 0xb4, 0x43, 0x2b, 0x78, 0x17, 0xa9, 0x11,
-	0xa7, 0xee, 0xe9, 0xa1, 0x9f, 0x19, 0xe8, 0xa7, 0x1a, 0xd3, 0x4f, 0x79, 0x38, 0xb3, 0x4f, 0xf6,
-	0xea, 0x79, 0x0e, 0x0d, 0x00, 0x34, 0x7e, 0x87, 0x06, 0xc5, 0xb7, 0x8e, 0xe7, 0x62, 0xb4, 0x7a,
-	0x87, 0x8a, 0x3a, 0x7f, 0x53, 0x98, 0x38, 0xb8, 0x36, 0x7d, 0xf2, 0xfd, 0xd7, 0xe7, 0xf4, 0x38,
-	0x2e, 0x1a, 0x32, 0x1e, 0x9e, 0x46, 0x68, 0x98, 0xe1, 0x13, 0x45, 0x7c, 0x5c, 0x38, 0x51, 0x33,
-	0x3a, 0x69, 0xd4, 0x85, 0x1b, 0xe3, 0xa4, 0xf9, 0x5d, 0x30, 0x9f, 0xc1, 0xd3, 0x31, 0xe6, 0xf0,
-	0x73, 0x24, 0x47, 0xd4, 0x31, 0x3e, 0x42, 0xb9, 0x20, 0xb5, 0xec, 0x38, 0xc9, 0x18, 0xd1, 0xa1,
-	0x93, 0x8c, 0x71, 0x6d, 0x78, 0x68, 0x93, 0x80, 0x51, 0xc4, 0x63, 0x09, 0x18, 0xf8, 0x83, 0x22,
-	0xdc, 0x4d, 0xd2, 0xfe, 0xfb, 0x45, 0x58, 0x06, 0xf7, 0x05, 0x3c, 0x97, 0xe0, 0x5e, 0x69, 0x92,
-	0x76, 0xa8, 0x10, 0x27, 0x0a, 0x42, 0xb2, 0x12, 0x7d, 0x71, 0xfe, 0xb4, 0x18, 0x33, 0x80, 0x73,
-	0x07, 0x8f, 0xf7, 0xc1, 0xc1, 0x5f, 0x14, 0x34, 0x1c, 0x99, 0x68, 0xf8, 0x5e, 0x92, 0x7e, 0xdc,
-	0x54, 0x54, 0x97, 0x6f, 0x19, 0x2d, 0x99, 0x96, 0x80, 0x69, 0x16, 0x6b, 0x71, 0x4c, 0x75, 0xd6,
-	0xae, 0x10, 0xc7, 0xa9, 0x58, 0x02, 0xe4, 0xab, 0x44, 0xeb, 0x7e, 0xe3, 0xfd, 0xd1, 0xae, 0x4f,
-	0xa5, 0xfe, 0x68, 0x3d, 0x83, 0x43, 0x7b, 0x08, 0x68, 0xf7, 0xb1, 0x9e, 0x84, 0x06, 0x58, 0x15,
-	0xdb, 0xdd, 0x63, 0xc6, 0x51, 0x77, 0xba, 0x1d, 0xaf, 0x3d, 0x39, 0xbd, 0x28, 0x29, 0x67, 0x17,
-	0x25, 0xe5, 0xe7, 0x45, 0x49, 0xf9, 0x78, 0x59, 0x4a, 0x9d, 0x5d, 0x96, 0x52, 0xe7, 0x97, 0xa5,
-	0xd4, 0xee, 0x6c, 0xcd, 0xe6, 0xf5, 0x56, 0x55, 0xb7, 0x58, 0x23, 0xaa, 0xf9, 0x56, 0xa8, 0xf2,
-	0x43, 0x8f, 0xfa, 0xd5, 0x41, 0xf8, 0x8f, 0xe2, 0xc1, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x26,
-	0x0b, 0xc4, 0xda, 0xfc, 0x08, 0x00, 0x00,
+	// 938 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x96, 0x41, 0x6f, 0x1b, 0x45,
+	0x14, 0xc7, 0x33, 0xb6, 0x93, 0x90, 0x57, 0x05, 0xda, 0xc1, 0x52, 0x9c, 0x75, 0x71, 0xdb, 0x6d,
+	0xda, 0xb4, 0xa1, 0xde, 0x25, 0x41, 0x02, 0x71, 0x41, 0x4a, 0x8b, 0x5a, 0x05, 0x95, 0x2a, 0x6c,
+	0x24, 0x0e, 0x95, 0x50, 0x34, 0x5e, 0x26, 0xf6, 0x8a, 0xf5, 0xce, 0x76, 0x67, 0x1d, 0x53, 0xa2,
+	0x72, 0x88, 0xc4, 0x81, 0x1b, 0x82, 0x0b, 0x9c, 0x40, 0x82, 0x4f, 0xc0, 0xa7, 0xe8, 0x31, 0x12,
+	0x17, 0x4e, 0x08, 0x25, 0x7c, 0x10, 0xb4, 0x6f, 0xc6, 0xf6, 0x2e, 0xd9, 0xf5, 0x5a, 0x11, 0x12,
+	0x17, 0x6f, 0x66, 0xe6, 0xbd, 0xf7, 0xff, 0xcd, 0x7b, 0x33, 0x6f, 0x02, 0x97, 0x65, 0xc8, 0x5d,
+	0xfb, 0xd9, 0x80, 0x47, 0xcf, 0xad, 0x30, 0x12, 0xb1, 0xa0, 0x57, 0x7c, 0x76, 0xc8, 0x02, 0x1e,
+	0x5b, 0xc9, 0xd7, 0x4a, 0x96, 0x8d, 0x7a, 0x57, 0x74, 0x05, 0xae, 0xda, 0xc9, 0x5f, 0xca, 0xd0,
+	0xb8, 0xda, 0x15, 0xa2, 0xeb, 0x73, 0x9b, 0x85, 0x9e, 0xcd, 0x82, 0x40, 0xc4, 0x2c, 0xf6, 0x44,
+	0x20, 0xf5, 0xea, 0x86, 0x2b, 0x64, 0x5f, 0x48, 0xbb, 0xc3, 0x24, 0x57, 0xf1, 0xed, 0xc3, 0xcd,
+	0x0e, 0x8f, 0xd9, 0xa6, 0x1d, 0xb2, 0xae, 0x17, 0xa0, 0xb1, 0xb6, 0xbd, 0x82, 0x10, 0x21, 0x8b,
+	0x58, 0x7f, 0xe4, 0xfe, 0x1a, 0x4e, 0x25, 0x3f, 0x6a, 0xc2, 0xac, 0x03, 0xfd, 0x38, 0x89, 0xb2,
+	0x8b, 0x56, 0x0e, 0x7f, 0x36, 0xe0, 0x32, 0x36, 0x9f, 0xc0, 0xeb, 0x99, 0x59, 0x19, 0x8a, 0x40,
+	0x72, 0xfa, 0x2e, 0x2c, 0xa8, 0x68, 0x0d, 0x72, 0x9d, 0xdc, 0xb9, 0xb4, 0xb5, 0x6a, 0x9d, 0xdb,
+	0x94, 0xa5, 0x5c, 0xee, 0xd7, 0x5e, 0xfe, 0x79, 0x6d, 0xce, 0xd1, 0xe6, 0xa6, 0xad, 0xe3, 0x3d,
+	0xe2, 0xf1, 0x5e, 0xc8, 0x5d, 0x2d, 0x43, 0x1b, 0xb0, 0xf8, 0xa0, 0xc7, 0xbc, 0x60, 0xe7, 0x03,
+	0x0c, 0xb8, 0xe4, 0x8c, 0x86, 0xe6, 0x0e, 0xd4, 0xb3, 0x0e, 0x9a, 0x60, 0x13, 0x6a, 0xc9, 0x58,
+	0xeb, 0xaf, 0xe4, 0xe8, 0x27, 0xcb, 0x5a, 0x1d, 0x4d, 0xcd, 0x4f, 0xb5, 0xf6, 0xb6, 0xef, 0xa7,
+	0xb5, 0x1f, 0x02, 0x4c, 0x12, 0xa6, 0xe3, 0xdd, 0xb6, 0x54, 0x76, 0xad, 0x24, 0xbb, 0x96, 0xaa,
+	0x9e, 0xce, 0xae, 0xb5, 0xcb, 0xba, 0x5c, 0xfb, 0x3a, 0x29, 0x4f, 0xf3, 0x3b, 0x02, 0xf5, 0x6c,
+	0xfc, 0x73, 0xa8, 0xd5, 0x19, 0x51, 0xe9, 0xa3, 0x0c, 0x53, 0x05, 0x99, 0xd6, 0x4b, 0x99, 0x94,
+	0x5e, 0x06, 0xaa, 0x09, 0xab, 0xc8, 0xb4, 0xd7, 0x13, 0xc3, 0x6d, 0xdf, 0xc7, 0xac, 0x8e, 0x8b,
+	0x1b, 0x83, 0x91, 0xb7, 0xa8, 0xb1, 0x77, 0x61, 0xd9, 0xc5, 0x22, 0x04, 0x07, 0xe2, 0xb1, 0x27,
+	0xe3, 0x46, 0x05, 0xf9, 0x37, 0x72, 0xf8, 0x65, 0x3a, 0x40, 0x62, 0xbf, 0x17, 0x47, 0x03, 0x37,
+	0x76, 0xb2, 0x01, 0x3e, 0xac, 0xbd, 0x42, 0x2e, 0x57, 0xcc, 0xaf, 0x09, 0xac, 0x14, 0x38, 0xd0,
+	0xab, 0xb0, 0x84, 0x2e, 0x4f, 0x58, 0x9f, 0xeb, 0x93, 0x30, 0x99, 0x48, 0x4e, 0x89, 0xab, 0x4f,
+	0x49, 0x45, 0x9d, 0x12, 0x3d, 0xa4, 0x5b, 0x50, 0xe7, 0x01, 0xeb, 0xf8, 0xfc, 0xb3, 0xed, 0xd0,
+	0xdb, 0x09, 0x62, 0x1e, 0x1d, 0x30, 0x97, 0xcb, 0x46, 0xf5, 0x7a, 0xf5, 0xce, 0x92, 0x93, 0xbb,
+	0x66, 0xbe, 0x97, 0x4a, 0xcd, 0x83, 0x11, 0xe7, 0xe8, 0x50, 0x4c, 0x05, 0x31, 0x3f, 0x82, 0x45,
+	0x16, 0x7a, 0x8f, 0x3d, 0x65, 0xe8, 0x8d, 0x62, 0x36, 0x6a, 0xca, 0x70, 0x3c, 0x41, 0xd7, 0x60,
+	0x59, 0x0e, 0xc2, 0x50, 0x44, 0x31, 0xaa, 0xcb, 0xc6, 0x3c, 0x02, 0x65, 0x27, 0xcd, 0xdf, 0x08,
+	0x18, 0x79, 0x28, 0xba, 0x10, 0xa9, 0x6d, 0x93, 0xec, 0xb6, 0x5b, 0x00, 0xde, 0x64, 0xb3, 0x15,
+	0x8c, 0x9d, 0x9a, 0xa1, 0x4f, 0xc1, 0xc8, 0x28, 0x8d, 0x77, 0x8f, 0xf5, 0xac, 0x62, 0x3d, 0x8d,
+	0x9c, 0x7a, 0xea, 0xcd, 0x39, 0x53, 0xbc, 0xcd, 0x03, 0x30, 0xd2, 0x17, 0xf3, 0x13, 0x1e, 0x49,
+	0x4f, 0x04, 0xa5, 0x17, 0x3a, 0x59, 0x39, 0x54, 0xb6, 0x58, 0xc4, 0x9a, 0x33, 0x1a, 0xd2, 0x3a,
+	0xcc, 0x77, 0x7c, 0xe1, 0x7e, 0xde, 0xa8, 0xe2, 0xbc, 0x1a, 0x98, 0xbb, 0xd0, 0xcc, 0xd5, 0xb9,
+	0x78, 0x1f, 0xf8, 0x0a, 0x8c, 0xf4, 0x3d, 0x9d, 0x99, 0xfc, 0x61, 0xce, 0xa5, 0xbc, 0x48, 0xa3,
+	0xf8, 0x91, 0x40, 0x33, 0x17, 0xe0, 0xff, 0xef, 0x17, 0x5b, 0x3f, 0x03, 0xcc, 0x23, 0x1b, 0xfd,
+	0x12, 0x16, 0x54, 0x07, 0xa7, 0xb7, 0x72, 0x08, 0xce, 0x3f, 0x15, 0xc6, 0xed, 0x32, 0x33, 0x25,
+	0x67, 0xde, 0x38, 0xfe, 0xfd, 0xef, 0xef, 0x2b, 0x4d, 0xba, 0x6a, 0x6b, 0x7b, 0xfc, 0xda, 0xa9,
+	0x27, 0x8a, 0x1e, 0x13, 0x95, 0x02, 0x5a, 0x18, 0x33, 0xfb, 0x7e, 0x18, 0xeb, 0xa5, 0x76, 0x5a,
+	0xfc, 0x2e, 0x8a, 0xdf, 0xa4, 0x37, 0x72, 0xc4, 0xf1, 0xe7, 0x48, 0x57, 0xfb, 0x05, 0x3d, 0x82,
+	0xc5, 0xc4, 0x75, 0xdb, 0xf7, 0x8b, 0x31, 0xb2, 0x4f, 0x89, 0xb1, 0x5e, 0x6a, 0xa7, 0x31, 0xae,
+	0x21, 0xc6, 0x2a, 0x5d, 0x29, 0xc0, 0xa0, 0xdf, 0x10, 0xa5, 0xee, 0xb0, 0xe1, 0x7f, 0x9f, 0x84,
+	0x36, 0xaa, 0xaf, 0xd3, 0x5b, 0x05, 0xea, 0xfb, 0x11, 0x1b, 0xa6, 0x12, 0x71, 0x4c, 0x00, 0x74,
+	0x26, 0xa6, 0xe2, 0x5c, 0x34, 0x19, 0x37, 0x11, 0xe7, 0x0d, 0xda, 0x9c, 0x82, 0x43, 0x7f, 0x20,
+	0xb0, 0x9c, 0x79, 0xa7, 0xe8, 0xbd, 0xa2, 0xf8, 0x79, 0x6f, 0x9d, 0xd1, 0x9e, 0xd1, 0x5a, 0x33,
+	0x6d, 0x20, 0xd3, 0x1a, 0x35, 0xf3, 0x98, 0x7a, 0x62, 0xb8, 0xcf, 0x7c, 0x7f, 0xdf, 0x55, 0x20,
+	0xbf, 0x6a, 0xb4, 0x71, 0xe7, 0x9e, 0x8e, 0xf6, 0xef, 0xb7, 0xc6, 0x68, 0xcf, 0x68, 0xad, 0xd1,
+	0xde, 0x41, 0xb4, 0xb7, 0xa8, 0x55, 0x84, 0x86, 0x58, 0xfb, 0x5e, 0x70, 0x20, 0xec, 0xa3, 0xf1,
+	0x9b, 0xf5, 0x82, 0xfe, 0x44, 0xe0, 0x52, 0xaa, 0xdd, 0xd0, 0x76, 0xc9, 0x71, 0xc9, 0xf6, 0x45,
+	0xc3, 0x9a, 0xd5, 0x5c, 0x63, 0x6e, 0x22, 0xe6, 0x9b, 0xf4, 0x6e, 0x51, 0x55, 0x75, 0xdb, 0x4f,
+	0x1d, 0xb4, 0x5f, 0x08, 0xbc, 0x9a, 0x0a, 0x95, 0xdc, 0xbc, 0x76, 0xc9, 0x21, 0x9a, 0x15, 0x32,
+	0xbf, 0xd5, 0x9a, 0x5b, 0x08, 0x79, 0x8f, 0x6e, 0x94, 0x40, 0xca, 0x09, 0xe5, 0xfd, 0xf7, 0x5f,
+	0x9e, 0xb6, 0xc8, 0xc9, 0x69, 0x8b, 0xfc, 0x75, 0xda, 0x22, 0xdf, 0x9e, 0xb5, 0xe6, 0x4e, 0xce,
+	0x5a, 0x73, 0x7f, 0x9c, 0xb5, 0xe6, 0x9e, 0xae, 0x75, 0xbd, 0xb8, 0x37, 0xe8, 0x58, 0xae, 0xe8,
+	0x67, 0xe3, 0x7d, 0xa1, 0x22, 0xc6, 0xcf, 0x43, 0x2e, 0x3b, 0x0b, 0xf8, 0xff, 0xf6, 0xdb, 0xff,
+	0x0c, 0x00, 0x82, 0xf7, 0x42, 0x11, 0x1a, 0x0c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -691,6 +911,10 @@ type QueryClient interface {
 	ShowAllChains(ctx context.Context, in *QueryShowAllChainsRequest, opts ...grpc.CallOption) (*QueryShowAllChainsResponse, error)
 	// Queries a list of ShowChainInfo items.
 	ShowChainInfo(ctx context.Context, in *QueryShowChainInfoRequest, opts ...grpc.CallOption) (*QueryShowChainInfoResponse, error)
+	// Queries a version of a Spec, by its version or the version active at a block, with the imports expanded as they were at that block.
+	SpecVersion(ctx context.Context, in *QueryGetSpecVersionRequest, opts ...grpc.CallOption) (*QueryGetSpecVersionResponse, error)
+	// Queries all the versions of a Spec (raw form), oldest first.
+	SpecVersionAll(ctx context.Context, in *QueryAllSpecVersionRequest, opts ...grpc.CallOption) (*QueryAllSpecVersionResponse, error)
 }
 
 type queryClient struct {
@@ -764,6 +988,24 @@ func (c *queryClient) ShowChainInfo(ctx context.Context, in *QueryShowChainInfoR
 	return out, nil
 }
 
+func (c *queryClient) SpecVersion(ctx context.Context, in *QueryGetSpecVersionRequest, opts ...grpc.CallOption) (*QueryGetSpecVersionResponse, error) {
+	out := new(QueryGetSpecVersionResponse)
+	err := c.cc.Invoke(ctx, "/lavanet.lava.spec.Query/SpecVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) SpecVersionAll(ctx context.Context, in *QueryAllSpecVersionRequest, opts ...grpc.CallOption) (*QueryAllSpecVersionResponse, error) {
+	out := new(QueryAllSpecVersionResponse)
+	err := c.cc.Invoke(ctx, "/lavanet.lava.spec.Query/SpecVersionAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServer is the server API for Query service.
 type QueryServer interface {
 	// Parameters queries the parameters of the module.
@@ -780,6 +1022,10 @@ type QueryServer interface {
 	ShowAllChains(context.Context, *QueryShowAllChainsRequest) (*QueryShowAllChainsResponse, error)
 	// Queries a list of ShowChainInfo items.
 	ShowChainInfo(context.Context, *QueryShowChainInfoRequest) (*QueryShowChainInfoResponse, error)
+	// Queries a version of a Spec, by its version or the version active at a block, with the imports expanded as they were at that block.
+	SpecVersion(context.Context, *QueryGetSpecVersionRequest) (*QueryGetSpecVersionResponse, error)
+	// Queries all the versions of a Spec (raw form), oldest first.
+	SpecVersionAll(context.Context, *QueryAllSpecVersionRequest) (*QueryAllSpecVersionResponse, error)
 }
 
 // UnimplementedQueryServer can be embedded to have forward compatible implementations.
@@ -807,6 +1053,12 @@ func (*UnimplementedQueryServer) ShowAllChains(ctx context.Context, req *QuerySh
 func (*UnimplementedQueryServer) ShowChainInfo(ctx context.Context, req *QueryShowChainInfoRequest) (*QueryShowChainInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ShowChainInfo not implemented")
 }
+func (*UnimplementedQueryServer) SpecVersion(ctx context.Context, req *QueryGetSpecVersionRequest) (*QueryGetSpecVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpecVersion not implemented")
+}
+func (*UnimplementedQueryServer) SpecVersionAll(ctx context.Context, req *QueryAllSpecVersionRequest) (*QueryAllSpecVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpecVersionAll not implemented")
+}
 
 func RegisterQueryServer(s grpc1.Server, srv QueryServer) {
 	s.RegisterService(&_Query_serviceDesc, srv)
@@ -938,6 +1190,42 @@ func _Query_ShowChainInfo_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Query_SpecVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryGetSpecVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).SpecVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lavanet.lava.spec.Query/SpecVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).SpecVersion(ctx, req.(*QueryGetSpecVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_SpecVersionAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAllSpecVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).SpecVersionAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lavanet.lava.spec.Query/SpecVersionAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).SpecVersionAll(ctx, req.(*QueryAllSpecVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Query_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "lavanet.lava.spec.Query",
 	HandlerType: (*QueryServer)(nil),
@@ -970,6 +1258,14 @@ var _Query_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ShowChainInfo",
 			Handler:    _Query_ShowChainInfo_Handler,
 		},
+		{
+			MethodName: "SpecVersion",
+			Handler:    _Query_SpecVersion_Handler,
+		},
+		{
+			MethodName: "SpecVersionAll",
+			Handler:    _Query_SpecVersionAll_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "spec/query.proto",
@@ -1406,68 +1702,232 @@ func (m *QueryShowChainInfoResponse) MarshalToSizedBuffer(dAtA []byte) (int, err
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
-	offset -= sovQuery(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *QueryParamsRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *QueryGetSpecVersionRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *QueryParamsResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = m.Params.Size()
-	n += 1 + l + sovQuery(uint64(l))
-	return n
+func (m *QueryGetSpecVersionRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *QueryGetSpecRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *QueryGetSpecVersionRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.ChainID)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
+	if m.Block != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Block))
+		i--
+		dAtA[i] = 0x18
 	}
-	return n
+	if m.Version != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ChainID) > 0 {
+		i -= len(m.ChainID)
+		copy(dAtA[i:], m.ChainID)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ChainID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *QueryGetSpecResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *QueryGetSpecVersionResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	l = m.Spec.Size()
-	n += 1 + l + sovQuery(uint64(l))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *QueryAllSpecRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *QueryGetSpecVersionResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryGetSpecVersionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Pagination != nil {
+	{
+		size, err := m.Spec.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQuery(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryAllSpecVersionRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryAllSpecVersionRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryAllSpecVersionRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Pagination != nil {
+		{
+			size, err := m.Pagination.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ChainID) > 0 {
+		i -= len(m.ChainID)
+		copy(dAtA[i:], m.ChainID)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ChainID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryAllSpecVersionResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryAllSpecVersionResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryAllSpecVersionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Pagination != nil {
+		{
+			size, err := m.Pagination.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Spec) > 0 {
+		for iNdEx := len(m.Spec) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Spec[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQuery(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	offset -= sovQuery(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *QueryParamsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *QueryParamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Params.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	return n
+}
+
+func (m *QueryGetSpecRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryGetSpecResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Spec.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	return n
+}
+
+func (m *QueryAllSpecRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Pagination != nil {
 		l = m.Pagination.Size()
 		n += 1 + l + sovQuery(uint64(l))
 	}
@@ -1597,6 +2057,72 @@ func (m *QueryShowChainInfoResponse) Size() (n int) {
 	return n
 }
 
+func (m *QueryGetSpecVersionRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovQuery(uint64(m.Version))
+	}
+	if m.Block != 0 {
+		n += 1 + sovQuery(uint64(m.Block))
+	}
+	return n
+}
+
+func (m *QueryGetSpecVersionResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Spec.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	return n
+}
+
+func (m *QueryAllSpecVersionRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Pagination != nil {
+		l = m.Pagination.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryAllSpecVersionResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Spec) > 0 {
+		for _, e := range m.Spec {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.Pagination != nil {
+		l = m.Pagination.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
 func sovQuery(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -2731,6 +3257,447 @@ func (m *QueryShowChainInfoResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *QueryGetSpecVersionRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryGetSpecVersionRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryGetSpecVersionRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
+			}
+			m.Block = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Block |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryGetSpecVersionResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryGetSpecVersionResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryGetSpecVersionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Spec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryAllSpecVersionRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryAllSpecVersionRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryAllSpecVersionRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pagination", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Pagination == nil {
+				m.Pagination = &query.PageRequest{}
+			}
+			if err := m.Pagination.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryAllSpecVersionResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryAllSpecVersionResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryAllSpecVersionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Spec = append(m.Spec, Spec{})
+			if err := m.Spec[len(m.Spec)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pagination", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Pagination == nil {
+				m.Pagination = &query.PageResponse{}
+			}
+			if err := m.Pagination.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipQuery(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
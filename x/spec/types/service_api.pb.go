@@ -221,6 +221,7 @@ type ApiInterface struct {
 	ExtraComputeUnits     uint64        `protobuf:"varint,3,opt,name=extra_compute_units,json=extraComputeUnits,proto3" json:"extra_compute_units,omitempty"`
 	Category              *SpecCategory `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
 	OverwriteBlockParsing *BlockParser  `protobuf:"bytes,5,opt,name=overwrite_block_parsing,json=overwriteBlockParsing,proto3" json:"overwrite_block_parsing,omitempty"`
+	Verification          *Verification `protobuf:"bytes,6,opt,name=verification,proto3" json:"verification,omitempty"`
 }
 
 func (m *ApiInterface) Reset()         { *m = ApiInterface{} }
@@ -291,6 +292,73 @@ func (m *ApiInterface) GetOverwriteBlockParsing() *BlockParser {
 	return nil
 }
 
+func (m *ApiInterface) GetVerification() *Verification {
+	if m != nil {
+		return m.Verification
+	}
+	return nil
+}
+
+type Verification struct {
+	FunctionTemplate string      `protobuf:"bytes,1,opt,name=function_template,json=functionTemplate,proto3" json:"function_template,omitempty"`
+	ResultParsing    BlockParser `protobuf:"bytes,2,opt,name=result_parsing,json=resultParsing,proto3" json:"result_parsing"`
+	ExpectedValue    string      `protobuf:"bytes,3,opt,name=expected_value,json=expectedValue,proto3" json:"expected_value,omitempty"`
+}
+
+func (m *Verification) Reset()         { *m = Verification{} }
+func (m *Verification) String() string { return proto.CompactTextString(m) }
+func (*Verification) ProtoMessage()    {}
+func (*Verification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3323a3ad252c5ed4, []int{3}
+}
+func (m *Verification) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Verification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Verification.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Verification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Verification.Merge(m, src)
+}
+func (m *Verification) XXX_Size() int {
+	return m.Size()
+}
+func (m *Verification) XXX_DiscardUnknown() {
+	xxx_messageInfo_Verification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Verification proto.InternalMessageInfo
+
+func (m *Verification) GetFunctionTemplate() string {
+	if m != nil {
+		return m.FunctionTemplate
+	}
+	return ""
+}
+
+func (m *Verification) GetResultParsing() BlockParser {
+	if m != nil {
+		return m.ResultParsing
+	}
+	return BlockParser{}
+}
+
+func (m *Verification) GetExpectedValue() string {
+	if m != nil {
+		return m.ExpectedValue
+	}
+	return ""
+}
+
 type BlockParser struct {
 	ParserArg  []string    `protobuf:"bytes,1,rep,name=parser_arg,json=parserArg,proto3" json:"parser_arg,omitempty"`
 	ParserFunc PARSER_FUNC `protobuf:"varint,2,opt,name=parser_func,json=parserFunc,proto3,enum=lavanet.lava.spec.PARSER_FUNC" json:"parser_func,omitempty"`
@@ -300,7 +368,7 @@ func (m *BlockParser) Reset()         { *m = BlockParser{} }
 func (m *BlockParser) String() string { return proto.CompactTextString(m) }
 func (*BlockParser) ProtoMessage()    {}
 func (*BlockParser) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3323a3ad252c5ed4, []int{3}
+	return fileDescriptor_3323a3ad252c5ed4, []int{4}
 }
 func (m *BlockParser) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -355,7 +423,7 @@ func (m *SpecCategory) Reset()         { *m = SpecCategory{} }
 func (m *SpecCategory) String() string { return proto.CompactTextString(m) }
 func (*SpecCategory) ProtoMessage()    {}
 func (*SpecCategory) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3323a3ad252c5ed4, []int{4}
+	return fileDescriptor_3323a3ad252c5ed4, []int{5}
 }
 func (m *SpecCategory) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -424,6 +492,7 @@ func init() {
 	proto.RegisterType((*ServiceApi)(nil), "lavanet.lava.spec.ServiceApi")
 	proto.RegisterType((*Parsing)(nil), "lavanet.lava.spec.Parsing")
 	proto.RegisterType((*ApiInterface)(nil), "lavanet.lava.spec.ApiInterface")
+	proto.RegisterType((*Verification)(nil), "lavanet.lava.spec.Verification")
 	proto.RegisterType((*BlockParser)(nil), "lavanet.lava.spec.BlockParser")
 	proto.RegisterType((*SpecCategory)(nil), "lavanet.lava.spec.SpecCategory")
 }
@@ -431,53 +500,57 @@ func init() {
 func init() { proto.RegisterFile("spec/service_api.proto", fileDescriptor_3323a3ad252c5ed4) }
 
 var fileDescriptor_3323a3ad252c5ed4 = []byte{
-	// 734 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcb, 0x6e, 0xdb, 0x38,
-	0x14, 0xb5, 0xfc, 0x88, 0xe5, 0x2b, 0x3b, 0xa3, 0x30, 0x99, 0x19, 0x23, 0x33, 0x23, 0x7b, 0x3c,
-	0x59, 0x18, 0x33, 0x80, 0x0d, 0x64, 0x76, 0xcd, 0xa2, 0x90, 0x1f, 0x29, 0x8c, 0xa6, 0x76, 0xc0,
-	0x38, 0x05, 0xd2, 0x8d, 0x40, 0x2b, 0x8c, 0x42, 0x54, 0x96, 0x04, 0x8a, 0x4a, 0x93, 0x0f, 0xe8,
-	0xbe, 0x1f, 0x51, 0x14, 0x05, 0xfa, 0x23, 0x59, 0x66, 0xd9, 0x55, 0x51, 0x38, 0x5f, 0xd0, 0x3f,
-	0x28, 0x44, 0x4b, 0x8e, 0xd3, 0xba, 0x40, 0xba, 0x12, 0x79, 0x78, 0x8e, 0x78, 0xef, 0x39, 0x17,
-	0x84, 0xdf, 0xc2, 0x80, 0xda, 0xed, 0x90, 0xf2, 0x0b, 0x66, 0x53, 0x8b, 0x04, 0xac, 0x15, 0x70,
-	0x5f, 0xf8, 0x68, 0xc3, 0x25, 0x17, 0xc4, 0xa3, 0xa2, 0x15, 0x7f, 0x5b, 0x31, 0x69, 0x7b, 0xcb,
-	0xf1, 0x1d, 0x5f, 0x9e, 0xb6, 0xe3, 0xd5, 0x9c, 0xd8, 0xf8, 0x92, 0x05, 0x38, 0x9a, 0xcb, 0xcd,
-	0x80, 0x21, 0x04, 0x79, 0x8f, 0x4c, 0x69, 0x55, 0xa9, 0x2b, 0xcd, 0x12, 0x96, 0x6b, 0x34, 0x80,
-	0xca, 0xc4, 0xf5, 0xed, 0x97, 0x56, 0x40, 0x78, 0xc8, 0x3c, 0xa7, 0x9a, 0xad, 0x2b, 0x4d, 0x6d,
-	0xd7, 0x68, 0x7d, 0x77, 0x47, 0xab, 0x13, 0xf3, 0x0e, 0x09, 0x0f, 0x29, 0xef, 0xe4, 0xaf, 0x3f,
-	0xd5, 0x32, 0xb8, 0x3c, 0x49, 0x21, 0xe6, 0x39, 0xe8, 0x1f, 0xa8, 0xd8, 0xfe, 0x34, 0x88, 0x04,
-	0xb5, 0x22, 0x8f, 0x89, 0xb0, 0x9a, 0xab, 0x2b, 0xcd, 0x3c, 0x2e, 0x27, 0xe0, 0x71, 0x8c, 0xa1,
-	0x2a, 0x14, 0xa9, 0x47, 0x26, 0x2e, 0x3d, 0xad, 0xe6, 0xeb, 0x4a, 0x53, 0xc5, 0xe9, 0x16, 0x1d,
-	0xc0, 0x3a, 0x09, 0x98, 0xc5, 0x3c, 0x41, 0xf9, 0x19, 0xb1, 0x69, 0x58, 0x2d, 0xd4, 0x73, 0x4d,
-	0x6d, 0xb7, 0xb6, 0xa2, 0x14, 0x33, 0x60, 0x83, 0x94, 0x97, 0xd4, 0x52, 0x21, 0x4b, 0x58, 0x88,
-	0xf6, 0x40, 0xe5, 0x34, 0xb6, 0x8e, 0x9e, 0x56, 0xd7, 0x64, 0x4b, 0xab, 0xfe, 0x73, 0x14, 0x50,
-	0xbb, 0x4b, 0x04, 0x75, 0x7c, 0x7e, 0x85, 0x17, 0x02, 0xf4, 0x08, 0x8a, 0xa9, 0x1d, 0x45, 0xa9,
-	0xdd, 0x5e, 0xa1, 0x4d, 0xda, 0x4e, 0xae, 0x4f, 0x05, 0x8d, 0x77, 0x0a, 0x14, 0x53, 0x47, 0xfe,
-	0x86, 0xf2, 0x59, 0xe4, 0xd9, 0x82, 0xf9, 0x9e, 0x25, 0x88, 0x93, 0x18, 0xaf, 0xa5, 0xd8, 0x98,
-	0x38, 0xe8, 0x3f, 0xd8, 0xb8, 0xa3, 0xd0, 0x69, 0xe0, 0x12, 0x41, 0x65, 0x06, 0x25, 0xac, 0x2f,
-	0x78, 0x09, 0x8e, 0x9e, 0xc2, 0x3a, 0xa7, 0x61, 0xe4, 0x8a, 0x45, 0x5a, 0xb9, 0x9f, 0x48, 0xab,
-	0x32, 0xd7, 0x26, 0xc5, 0x35, 0x5e, 0x67, 0xa1, 0xbc, 0xec, 0x23, 0xfa, 0x13, 0x4a, 0x0b, 0xf3,
-	0x93, 0x52, 0xef, 0x80, 0x78, 0x78, 0xc4, 0x55, 0x90, 0xd6, 0x26, 0xd7, 0xa8, 0x05, 0x9b, 0xf4,
-	0x52, 0x70, 0x62, 0xad, 0xca, 0x7d, 0x43, 0x1e, 0x75, 0x97, 0xc3, 0xdf, 0x03, 0xd5, 0x4e, 0xdc,
-	0x96, 0xe9, 0x3f, 0x24, 0x94, 0x54, 0x80, 0x9e, 0xc3, 0xef, 0xfe, 0x05, 0xe5, 0xaf, 0x38, 0x13,
-	0xd4, 0xba, 0x3f, 0xb3, 0x85, 0x87, 0xb8, 0x80, 0x7f, 0x5d, 0xc8, 0x3b, 0x4b, 0x63, 0xdb, 0x98,
-	0x82, 0xb6, 0xc4, 0x42, 0x7f, 0x01, 0x04, 0x72, 0x65, 0x11, 0x1e, 0x27, 0x96, 0x8b, 0x6d, 0x98,
-	0x23, 0x26, 0x77, 0xd0, 0x63, 0xd0, 0x92, 0xe3, 0x38, 0x1d, 0xe9, 0xc6, 0xfa, 0xca, 0x9b, 0x0f,
-	0x4d, 0x7c, 0xd4, 0xc7, 0xd6, 0xfe, 0xf1, 0xb0, 0x8b, 0x93, 0x3f, 0xee, 0x47, 0x9e, 0xdd, 0xf8,
-	0xa0, 0x40, 0x79, 0xb9, 0x43, 0xb4, 0x03, 0x95, 0x53, 0x2a, 0x28, 0x9f, 0x32, 0x8f, 0x85, 0x82,
-	0xd9, 0xd2, 0x7a, 0x15, 0xdf, 0x07, 0xd1, 0x16, 0x14, 0x5c, 0xdf, 0x26, 0xae, 0xbc, 0x51, 0xc5,
-	0xf3, 0x0d, 0x6a, 0x40, 0x39, 0x8c, 0x26, 0xa1, 0xcd, 0x59, 0x10, 0x0f, 0x8a, 0x74, 0x5e, 0xc5,
-	0xf7, 0x30, 0xb4, 0x0d, 0x6a, 0x28, 0x88, 0xa0, 0x67, 0x91, 0x2b, 0x4d, 0xaf, 0xe0, 0xc5, 0x1e,
-	0xd5, 0x40, 0x3b, 0x27, 0x9e, 0xc3, 0x3c, 0x27, 0x7e, 0x5e, 0xa4, 0x8f, 0x2a, 0x86, 0x04, 0x32,
-	0x03, 0xf6, 0xef, 0x5b, 0x05, 0xb4, 0xa5, 0x4e, 0x50, 0x09, 0x0a, 0xfd, 0x67, 0x87, 0xe3, 0x13,
-	0x3d, 0x83, 0x74, 0x28, 0xcb, 0x13, 0xab, 0x73, 0x62, 0x99, 0xf8, 0x89, 0xae, 0xa0, 0x4d, 0xf8,
-	0x65, 0x8e, 0x74, 0xcd, 0xe1, 0x68, 0x38, 0xe8, 0x9a, 0x07, 0x7a, 0x16, 0x6d, 0x81, 0x3e, 0x07,
-	0x7b, 0x83, 0xee, 0x78, 0x30, 0x1a, 0x9a, 0xf8, 0x44, 0xcf, 0xa1, 0x1a, 0xfc, 0xf1, 0x2d, 0x6a,
-	0x8d, 0xb0, 0x35, 0xc2, 0xbd, 0x3e, 0xee, 0xf7, 0xf4, 0xfc, 0x8f, 0x08, 0xbd, 0xfe, 0xbe, 0x79,
-	0x7c, 0x30, 0xd6, 0x0b, 0x48, 0x83, 0x62, 0xba, 0x59, 0xeb, 0x74, 0xde, 0xcf, 0x0c, 0xe5, 0x7a,
-	0x66, 0x28, 0x37, 0x33, 0x43, 0xf9, 0x3c, 0x33, 0x94, 0x37, 0xb7, 0x46, 0xe6, 0xe6, 0xd6, 0xc8,
-	0x7c, 0xbc, 0x35, 0x32, 0x2f, 0x76, 0x1c, 0x26, 0xce, 0xa3, 0x49, 0xcb, 0xf6, 0xa7, 0xed, 0x24,
-	0x28, 0xf9, 0x6d, 0x5f, 0xb6, 0xe5, 0x0b, 0x1b, 0xcf, 0x72, 0x38, 0x59, 0x93, 0x6f, 0xe6, 0xff,
-	0x5f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x0a, 0xe8, 0x30, 0x02, 0x76, 0x05, 0x00, 0x00,
+	// 788 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
+	0x18, 0xcd, 0xe4, 0xa7, 0x49, 0x3e, 0x3b, 0xc5, 0x9d, 0x2d, 0x60, 0x15, 0x70, 0x43, 0x58, 0xa4,
+	0x08, 0xa4, 0x44, 0x5a, 0xee, 0xd8, 0x0b, 0xe4, 0xa4, 0x29, 0x8a, 0x28, 0x6d, 0x35, 0x4d, 0x2b,
+	0x95, 0x1b, 0x6b, 0xe2, 0x4c, 0xbc, 0x23, 0x1c, 0xdb, 0x1a, 0x8f, 0x43, 0xf7, 0x2d, 0x78, 0x08,
+	0x84, 0x90, 0xb8, 0xe0, 0x35, 0x56, 0x5c, 0xed, 0x25, 0x57, 0x08, 0xa5, 0x4f, 0xc0, 0x1b, 0x20,
+	0x4f, 0xec, 0xac, 0x03, 0x29, 0x2a, 0x5c, 0x79, 0xe6, 0x7c, 0xdf, 0x99, 0x39, 0x3e, 0xe7, 0x4b,
+	0x0c, 0xef, 0xc4, 0x11, 0x73, 0xfb, 0x31, 0x13, 0x4b, 0xee, 0x32, 0x87, 0x46, 0xbc, 0x17, 0x89,
+	0x50, 0x86, 0xf8, 0xc0, 0xa7, 0x4b, 0x1a, 0x30, 0xd9, 0x4b, 0x9f, 0xbd, 0xb4, 0xe9, 0xe8, 0xd0,
+	0x0b, 0xbd, 0x50, 0x55, 0xfb, 0xe9, 0x6a, 0xdd, 0xd8, 0xf9, 0xb3, 0x0c, 0x70, 0xb5, 0xa6, 0xdb,
+	0x11, 0xc7, 0x18, 0xaa, 0x01, 0x5d, 0x30, 0x13, 0xb5, 0x51, 0xb7, 0x49, 0xd4, 0x1a, 0x8f, 0xa1,
+	0x35, 0xf5, 0x43, 0xf7, 0x5b, 0x27, 0xa2, 0x22, 0xe6, 0x81, 0x67, 0x96, 0xdb, 0xa8, 0xab, 0x3d,
+	0xb3, 0x7a, 0xff, 0xb8, 0xa3, 0x37, 0x48, 0xfb, 0x2e, 0xa9, 0x88, 0x99, 0x18, 0x54, 0x5f, 0xfd,
+	0x7e, 0x5c, 0x22, 0xfa, 0x34, 0x87, 0x78, 0xe0, 0xe1, 0x8f, 0xa0, 0xe5, 0x86, 0x8b, 0x28, 0x91,
+	0xcc, 0x49, 0x02, 0x2e, 0x63, 0xb3, 0xd2, 0x46, 0xdd, 0x2a, 0xd1, 0x33, 0xf0, 0x3a, 0xc5, 0xb0,
+	0x09, 0x75, 0x16, 0xd0, 0xa9, 0xcf, 0x66, 0x66, 0xb5, 0x8d, 0xba, 0x0d, 0x92, 0x6f, 0xf1, 0x19,
+	0xec, 0xd3, 0x88, 0x3b, 0x3c, 0x90, 0x4c, 0xcc, 0xa9, 0xcb, 0x62, 0xb3, 0xd6, 0xae, 0x74, 0xb5,
+	0x67, 0xc7, 0x3b, 0xa4, 0xd8, 0x11, 0x1f, 0xe7, 0x7d, 0x99, 0x96, 0x16, 0x2d, 0x60, 0x31, 0x7e,
+	0x0e, 0x0d, 0xc1, 0x52, 0xeb, 0xd8, 0xcc, 0xdc, 0x6b, 0xa3, 0x07, 0xce, 0xb9, 0x8a, 0x98, 0x3b,
+	0xa4, 0x92, 0x79, 0xa1, 0x78, 0x49, 0x36, 0x04, 0xfc, 0x39, 0xd4, 0x73, 0x3b, 0xea, 0x8a, 0x7b,
+	0xb4, 0x83, 0x9b, 0xbd, 0x76, 0x76, 0x7d, 0x4e, 0xe8, 0xfc, 0x88, 0xa0, 0x9e, 0x3b, 0xf2, 0x21,
+	0xe8, 0xf3, 0x24, 0x70, 0x25, 0x0f, 0x03, 0x47, 0x52, 0x2f, 0x33, 0x5e, 0xcb, 0xb1, 0x09, 0xf5,
+	0xf0, 0xa7, 0x70, 0xf0, 0xa6, 0x85, 0x2d, 0x22, 0x9f, 0x4a, 0xa6, 0x32, 0x68, 0x12, 0x63, 0xd3,
+	0x97, 0xe1, 0xf8, 0x2b, 0xd8, 0x17, 0x2c, 0x4e, 0x7c, 0xb9, 0x49, 0xab, 0xf2, 0x1f, 0xd2, 0x6a,
+	0xad, 0xb9, 0x99, 0xb8, 0xce, 0xaf, 0x65, 0xd0, 0x8b, 0x3e, 0xe2, 0xf7, 0xa1, 0xb9, 0x31, 0x3f,
+	0x93, 0xfa, 0x06, 0x48, 0x87, 0x47, 0xbe, 0x8c, 0x72, 0x6d, 0x6a, 0x8d, 0x7b, 0xf0, 0x84, 0xdd,
+	0x49, 0x41, 0x9d, 0x5d, 0xb9, 0x1f, 0xa8, 0xd2, 0xb0, 0x18, 0xfe, 0x73, 0x68, 0xb8, 0x99, 0xdb,
+	0x66, 0xf5, 0x91, 0xa1, 0xe4, 0x04, 0x7c, 0x03, 0xef, 0x86, 0x4b, 0x26, 0xbe, 0x13, 0x5c, 0x32,
+	0x67, 0x7b, 0x66, 0x6b, 0x8f, 0x71, 0x81, 0xbc, 0xbd, 0xa1, 0x0f, 0x8a, 0x63, 0x3b, 0x04, 0x7d,
+	0xc9, 0x04, 0x9f, 0x73, 0x97, 0xa6, 0x66, 0xff, 0xcb, 0xb4, 0xdc, 0x14, 0xda, 0xc8, 0x16, 0xa9,
+	0xf3, 0x0b, 0x02, 0xbd, 0x58, 0xde, 0x9d, 0x2b, 0x7a, 0x74, 0xae, 0xe5, 0xff, 0x9d, 0x2b, 0xfe,
+	0x18, 0xf6, 0xd9, 0x5d, 0xc4, 0x5c, 0xc9, 0x66, 0xce, 0x92, 0xfa, 0x09, 0x53, 0x79, 0x34, 0x49,
+	0x2b, 0x47, 0x6f, 0x52, 0xb0, 0xb3, 0x00, 0xad, 0x70, 0x14, 0xfe, 0x00, 0x20, 0x52, 0x2b, 0x87,
+	0x8a, 0x74, 0x50, 0x2b, 0x69, 0xfa, 0x6b, 0xc4, 0x16, 0x1e, 0xfe, 0x02, 0xb4, 0xac, 0x9c, 0x8a,
+	0x57, 0xf2, 0xf6, 0x77, 0xca, 0xbb, 0xb4, 0xc9, 0xd5, 0x88, 0x38, 0xa7, 0xd7, 0xe7, 0x43, 0x92,
+	0x9d, 0x78, 0x9a, 0x04, 0x6e, 0xe7, 0x67, 0x04, 0x7a, 0x31, 0x58, 0xfc, 0x14, 0x5a, 0x33, 0x26,
+	0x99, 0x58, 0xf0, 0x80, 0xc7, 0x92, 0xbb, 0xca, 0x9c, 0x06, 0xd9, 0x06, 0xf1, 0x21, 0xd4, 0xfc,
+	0xd0, 0xa5, 0xbe, 0xba, 0xb1, 0x41, 0xd6, 0x1b, 0xdc, 0x01, 0x3d, 0x4e, 0xa6, 0xb1, 0x2b, 0x78,
+	0xa4, 0x22, 0xab, 0xa8, 0xe2, 0x16, 0x86, 0x8f, 0xa0, 0x11, 0x4b, 0x2a, 0xd9, 0x3c, 0xf1, 0xd5,
+	0xac, 0xb5, 0xc8, 0x66, 0x8f, 0x8f, 0x41, 0x7b, 0x41, 0x03, 0x8f, 0x07, 0x5e, 0xfa, 0xaf, 0xaa,
+	0xc6, 0xa7, 0x41, 0x20, 0x83, 0xec, 0x88, 0x7f, 0xf2, 0x03, 0x02, 0xad, 0xf0, 0x26, 0xb8, 0x09,
+	0xb5, 0xd1, 0xd7, 0x97, 0x93, 0x5b, 0xa3, 0x84, 0x0d, 0xd0, 0x55, 0xc5, 0x19, 0xdc, 0x3a, 0x36,
+	0xf9, 0xd2, 0x40, 0xf8, 0x09, 0xbc, 0xb5, 0x46, 0x86, 0xf6, 0xf9, 0xc5, 0xf9, 0x78, 0x68, 0x9f,
+	0x19, 0x65, 0x7c, 0x08, 0xc6, 0x1a, 0x3c, 0x19, 0x0f, 0x27, 0xe3, 0x8b, 0x73, 0x9b, 0xdc, 0x1a,
+	0x15, 0x7c, 0x0c, 0xef, 0xfd, 0x1d, 0x75, 0x2e, 0x88, 0x73, 0x41, 0x4e, 0x46, 0x64, 0x74, 0x62,
+	0x54, 0x1f, 0x6a, 0x38, 0x19, 0x9d, 0xda, 0xd7, 0x67, 0x13, 0xa3, 0x86, 0x35, 0xa8, 0xe7, 0x9b,
+	0xbd, 0xc1, 0xe0, 0xa7, 0x95, 0x85, 0x5e, 0xad, 0x2c, 0xf4, 0x7a, 0x65, 0xa1, 0x3f, 0x56, 0x16,
+	0xfa, 0xfe, 0xde, 0x2a, 0xbd, 0xbe, 0xb7, 0x4a, 0xbf, 0xdd, 0x5b, 0xa5, 0x6f, 0x9e, 0x7a, 0x5c,
+	0xbe, 0x48, 0xa6, 0x3d, 0x37, 0x5c, 0xf4, 0xb3, 0xa0, 0xd4, 0xb3, 0x7f, 0xd7, 0x57, 0x1f, 0x96,
+	0xf4, 0x27, 0x1c, 0x4f, 0xf7, 0xd4, 0xa7, 0xe2, 0xb3, 0xbf, 0x06, 0x00, 0x0f, 0x81, 0x6b, 0xe9,
+	0x6d, 0x06, 0x00, 0x00,
 }
 
 func (this *ServiceApi) Equal(that interface{}) bool {
@@ -591,6 +664,39 @@ func (this *ApiInterface) Equal(that interface{}) bool {
 	if !this.OverwriteBlockParsing.Equal(that1.OverwriteBlockParsing) {
 		return false
 	}
+	if !this.Verification.Equal(that1.Verification) {
+		return false
+	}
+	return true
+}
+func (this *Verification) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Verification)
+	if !ok {
+		that2, ok := that.(Verification)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.FunctionTemplate != that1.FunctionTemplate {
+		return false
+	}
+	if !this.ResultParsing.Equal(&that1.ResultParsing) {
+		return false
+	}
+	if this.ExpectedValue != that1.ExpectedValue {
+		return false
+	}
 	return true
 }
 func (this *BlockParser) Equal(that interface{}) bool {
@@ -819,6 +925,18 @@ func (m *ApiInterface) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Verification != nil {
+		{
+			size, err := m.Verification.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintServiceApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
 	if m.OverwriteBlockParsing != nil {
 		{
 			size, err := m.OverwriteBlockParsing.MarshalToSizedBuffer(dAtA[:i])
@@ -865,6 +983,53 @@ func (m *ApiInterface) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *Verification) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Verification) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Verification) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ExpectedValue) > 0 {
+		i -= len(m.ExpectedValue)
+		copy(dAtA[i:], m.ExpectedValue)
+		i = encodeVarintServiceApi(dAtA, i, uint64(len(m.ExpectedValue)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	{
+		size, err := m.ResultParsing.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintServiceApi(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.FunctionTemplate) > 0 {
+		i -= len(m.FunctionTemplate)
+		copy(dAtA[i:], m.FunctionTemplate)
+		i = encodeVarintServiceApi(dAtA, i, uint64(len(m.FunctionTemplate)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *BlockParser) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1058,6 +1223,29 @@ func (m *ApiInterface) Size() (n int) {
 		l = m.OverwriteBlockParsing.Size()
 		n += 1 + l + sovServiceApi(uint64(l))
 	}
+	if m.Verification != nil {
+		l = m.Verification.Size()
+		n += 1 + l + sovServiceApi(uint64(l))
+	}
+	return n
+}
+
+func (m *Verification) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.FunctionTemplate)
+	if l > 0 {
+		n += 1 + l + sovServiceApi(uint64(l))
+	}
+	l = m.ResultParsing.Size()
+	n += 1 + l + sovServiceApi(uint64(l))
+	l = len(m.ExpectedValue)
+	if l > 0 {
+		n += 1 + l + sovServiceApi(uint64(l))
+	}
 	return n
 }
 
@@ -1697,6 +1885,189 @@ func (m *ApiInterface) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Verification", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowServiceApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Verification == nil {
+				m.Verification = &Verification{}
+			}
+			if err := m.Verification.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipServiceApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Verification) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowServiceApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Verification: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Verification: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FunctionTemplate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowServiceApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FunctionTemplate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResultParsing", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowServiceApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ResultParsing.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpectedValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowServiceApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthServiceApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExpectedValue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipServiceApi(dAtA[iNdEx:])
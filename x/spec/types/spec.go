@@ -54,6 +54,15 @@ func (spec Spec) ValidateSpec(maxCU uint64) (map[string]string, error) {
 			if _, ok := availableAPIInterface[apiInterface.Interface]; !ok {
 				return details, fmt.Errorf("unsupported api interface %v", apiInterface.Interface)
 			}
+			if apiInterface.Verification != nil {
+				details["api"] = api.Name
+				if apiInterface.Interface == APIInterfaceGrpc {
+					return details, fmt.Errorf("verification is not supported on %v", apiInterface.Interface)
+				}
+				if apiInterface.Verification.FunctionTemplate == "" || apiInterface.Verification.ExpectedValue == "" {
+					return details, fmt.Errorf("verification must have a function template and an expected value")
+				}
+			}
 		}
 
 		if api.Parsing.FunctionTag != "" {
@@ -65,6 +65,8 @@ type Spec struct {
 	MinStakeProvider              types.Coin          `protobuf:"bytes,12,opt,name=min_stake_provider,json=minStakeProvider,proto3" json:"min_stake_provider"`
 	MinStakeClient                types.Coin          `protobuf:"bytes,13,opt,name=min_stake_client,json=minStakeClient,proto3" json:"min_stake_client"`
 	ProvidersTypes                Spec_ProvidersTypes `protobuf:"varint,14,opt,name=providers_types,json=providersTypes,proto3,enum=lavanet.lava.spec.Spec_ProvidersTypes" json:"providers_types,omitempty"`
+	// bumped on every change of the spec, block_last_updated is the block the version became active at
+	Version uint64 `protobuf:"varint,16,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (m *Spec) Reset()         { *m = Spec{} }
@@ -205,6 +207,13 @@ func (m *Spec) GetProvidersTypes() Spec_ProvidersTypes {
 	return Spec_dynamic
 }
 
+func (m *Spec) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("lavanet.lava.spec.Spec_ProvidersTypes", Spec_ProvidersTypes_name, Spec_ProvidersTypes_value)
 	proto.RegisterType((*Spec)(nil), "lavanet.lava.spec.Spec")
@@ -213,47 +222,47 @@ func init() {
 func init() { proto.RegisterFile("spec/spec.proto", fileDescriptor_c4cc771ffab81d0a) }
 
 var fileDescriptor_c4cc771ffab81d0a = []byte{
-	// 629 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0xcf, 0x4e, 0xdc, 0x38,
-	0x18, 0x1f, 0xef, 0x0c, 0x03, 0x78, 0x96, 0x61, 0xd6, 0x62, 0x91, 0x41, 0x4b, 0x36, 0x45, 0x55,
-	0x95, 0x4a, 0x55, 0x22, 0xe0, 0xd0, 0xde, 0x2a, 0x06, 0x3a, 0x2a, 0x52, 0xab, 0xd2, 0x0c, 0xbd,
-	0xf4, 0x62, 0x39, 0x8e, 0x19, 0x2c, 0x12, 0x3b, 0x8d, 0xcd, 0x94, 0xe9, 0x53, 0xf4, 0x31, 0xfa,
-	0x28, 0x1c, 0x7a, 0xe0, 0xd8, 0x53, 0x55, 0x0d, 0x2f, 0x52, 0xd9, 0x49, 0x04, 0xa8, 0x3d, 0xf4,
-	0x62, 0xfb, 0xf3, 0xef, 0xcf, 0xf7, 0x8b, 0xf2, 0x19, 0xae, 0xea, 0x82, 0xb3, 0xc8, 0x2e, 0x61,
-	0x51, 0x2a, 0xa3, 0xd0, 0x3f, 0x19, 0x9d, 0x52, 0xc9, 0x4d, 0x68, 0xf7, 0xd0, 0x02, 0x9b, 0x6b,
-	0x13, 0x35, 0x51, 0x0e, 0x8d, 0xec, 0xa9, 0x22, 0x6e, 0xae, 0x57, 0x4a, 0x5e, 0x4e, 0x05, 0xe3,
-	0x84, 0x16, 0xa2, 0xbe, 0xf7, 0x98, 0xd2, 0xb9, 0xd2, 0x51, 0x42, 0x35, 0x8f, 0xa6, 0x3b, 0x09,
-	0x37, 0x74, 0x27, 0x62, 0x4a, 0xc8, 0x0a, 0xdf, 0xfe, 0xda, 0x85, 0x9d, 0x71, 0xc1, 0x19, 0x5a,
-	0x83, 0x0b, 0x42, 0xa6, 0xfc, 0x12, 0x03, 0x1f, 0x04, 0xcb, 0x71, 0x55, 0x20, 0x04, 0x3b, 0x92,
-	0xe6, 0x1c, 0xff, 0xe5, 0x2e, 0xdd, 0x19, 0x61, 0xb8, 0x28, 0xf2, 0x42, 0x95, 0x46, 0xe3, 0x55,
-	0xbf, 0x1d, 0x2c, 0xc7, 0x4d, 0x89, 0x9e, 0xc2, 0x0e, 0x2d, 0x84, 0xc6, 0x6d, 0xbf, 0x1d, 0xf4,
-	0x76, 0xb7, 0xc2, 0x5f, 0xc2, 0x87, 0xe3, 0x2a, 0xe0, 0x7e, 0x21, 0x86, 0x9d, 0xab, 0xef, 0xff,
-	0xb7, 0x62, 0x27, 0xb0, 0x96, 0x5c, 0xd2, 0x24, 0xe3, 0x29, 0xee, 0xf8, 0x20, 0x58, 0x8a, 0x9b,
-	0x12, 0xed, 0xc1, 0x7f, 0x4b, 0x9e, 0x09, 0x9a, 0x88, 0x4c, 0x98, 0x19, 0x31, 0x67, 0x25, 0xd7,
-	0x67, 0x2a, 0x4b, 0xf1, 0x82, 0x0f, 0x82, 0x95, 0x78, 0xed, 0x0e, 0x78, 0xd2, 0x60, 0xe8, 0x19,
-	0xc4, 0x29, 0x35, 0x94, 0xdc, 0x55, 0x36, 0xfe, 0x5d, 0xe7, 0xbf, 0x6e, 0xf1, 0xf8, 0x16, 0x7e,
-	0x51, 0xb7, 0x7b, 0x09, 0x1f, 0x24, 0x99, 0x62, 0xe7, 0x24, 0x15, 0xda, 0x50, 0xc9, 0x38, 0x39,
-	0x55, 0x25, 0x39, 0x15, 0x92, 0x66, 0xe2, 0x13, 0x4f, 0x89, 0x95, 0xe1, 0x45, 0xd7, 0x7a, 0xcb,
-	0x11, 0x0f, 0x6b, 0xde, 0x48, 0x95, 0xa3, 0x86, 0x75, 0x48, 0x0d, 0x45, 0xcf, 0xe1, 0x7f, 0x8e,
-	0xa0, 0x89, 0x90, 0x8d, 0x01, 0x35, 0x42, 0x49, 0x52, 0x94, 0x4a, 0x9d, 0xe2, 0x25, 0x67, 0xb2,
-	0x51, 0x71, 0x8e, 0xe4, 0xe8, 0x0e, 0xe3, 0xd8, 0x12, 0xd0, 0x13, 0x88, 0xe8, 0x94, 0x97, 0x74,
-	0xc2, 0x49, 0x15, 0xc9, 0x88, 0x9c, 0xe3, 0x65, 0x1f, 0x04, 0xed, 0x78, 0x50, 0x23, 0x43, 0x0b,
-	0x9c, 0x88, 0x9c, 0xa3, 0x7d, 0xe8, 0xd1, 0x2c, 0x53, 0x1f, 0x79, 0x5a, 0xb3, 0x33, 0x3a, 0x71,
-	0xd9, 0x3f, 0x28, 0x4d, 0xf4, 0x4c, 0x32, 0x0c, 0x9d, 0x72, 0xa3, 0x66, 0x39, 0xe5, 0x2b, 0x3a,
-	0x19, 0xa9, 0xf2, 0xad, 0xd2, 0xe3, 0x99, 0x64, 0xb6, 0x61, 0x23, 0xd5, 0x86, 0x5c, 0x14, 0x29,
-	0x35, 0x3c, 0xc5, 0x3d, 0x1f, 0x04, 0x9d, 0x78, 0x90, 0x54, 0x7c, 0x6d, 0xde, 0x55, 0xf7, 0xe8,
-	0x35, 0x44, 0xb9, 0x90, 0x44, 0x1b, 0x7a, 0xce, 0xed, 0x27, 0x4d, 0x45, 0xca, 0x4b, 0xfc, 0xb7,
-	0x0f, 0x82, 0xde, 0xee, 0x46, 0x58, 0x4d, 0x5d, 0x68, 0xa7, 0x2e, 0xac, 0xa7, 0x2e, 0x3c, 0x50,
-	0x42, 0xd6, 0x7f, 0x7d, 0x90, 0x0b, 0x39, 0xb6, 0xca, 0xe3, 0x5a, 0x88, 0x8e, 0xe0, 0xe0, 0xd6,
-	0x8e, 0x65, 0x82, 0x4b, 0x83, 0x57, 0xfe, 0xcc, 0xac, 0xdf, 0x98, 0x1d, 0x38, 0x19, 0x7a, 0x03,
-	0x57, 0x9b, 0x3c, 0x9a, 0x98, 0x59, 0xc1, 0x35, 0xee, 0xfb, 0x20, 0xe8, 0xef, 0x3e, 0xfa, 0xdd,
-	0x40, 0xda, 0xa5, 0x49, 0xa1, 0x4f, 0x2c, 0x3b, 0xee, 0x17, 0xf7, 0xea, 0xed, 0xc7, 0xb0, 0x7f,
-	0x9f, 0x81, 0x7a, 0x70, 0x31, 0x9d, 0x49, 0x9a, 0x0b, 0x36, 0x68, 0x21, 0x08, 0xbb, 0xda, 0x50,
-	0x23, 0xd8, 0x00, 0x0c, 0x87, 0x5f, 0xe6, 0x1e, 0xb8, 0x9a, 0x7b, 0xe0, 0x7a, 0xee, 0x81, 0x1f,
-	0x73, 0x0f, 0x7c, 0xbe, 0xf1, 0x5a, 0xd7, 0x37, 0x5e, 0xeb, 0xdb, 0x8d, 0xd7, 0x7a, 0xff, 0x70,
-	0x22, 0xcc, 0xd9, 0x45, 0x12, 0x32, 0x95, 0x47, 0x75, 0x14, 0xb7, 0x47, 0x97, 0xee, 0xcd, 0x47,
-	0x2e, 0x6c, 0xd2, 0x75, 0x2f, 0x73, 0xef, 0x67, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1c, 0x0c, 0xe0,
-	0x42, 0x0d, 0x04, 0x00, 0x00,
+	// 637 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcf, 0x6e, 0xd3, 0x3e,
+	0x1c, 0x6f, 0x7e, 0xed, 0xba, 0xcd, 0xfd, 0xad, 0x0b, 0xd6, 0x98, 0xbc, 0x89, 0x85, 0x30, 0x21,
+	0x14, 0x24, 0x94, 0x68, 0xdb, 0x01, 0x6e, 0x68, 0xdd, 0xa8, 0x98, 0x04, 0x62, 0xa4, 0xe3, 0xc2,
+	0xc5, 0x72, 0x1c, 0xaf, 0xb3, 0x96, 0xd8, 0x21, 0xf6, 0xca, 0xca, 0x53, 0xf0, 0x18, 0x3c, 0xca,
+	0x8e, 0x3b, 0x72, 0x42, 0xa8, 0x93, 0x78, 0x0e, 0x64, 0x27, 0xd1, 0x3a, 0xc1, 0x81, 0x8b, 0xed,
+	0xaf, 0x3f, 0x7f, 0xfc, 0x71, 0xf2, 0x4d, 0xc0, 0xaa, 0x2a, 0x18, 0x8d, 0xcc, 0x10, 0x16, 0xa5,
+	0xd4, 0x12, 0xde, 0xcb, 0xc8, 0x84, 0x08, 0xa6, 0x43, 0x33, 0x87, 0x06, 0xd8, 0x5c, 0x1b, 0xcb,
+	0xb1, 0xb4, 0x68, 0x64, 0x56, 0x15, 0x71, 0x73, 0xbd, 0x52, 0xb2, 0x72, 0xc2, 0x29, 0xc3, 0xa4,
+	0xe0, 0xf5, 0xbe, 0x47, 0xa5, 0xca, 0xa5, 0x8a, 0x12, 0xa2, 0x58, 0x34, 0xd9, 0x49, 0x98, 0x26,
+	0x3b, 0x11, 0x95, 0x5c, 0x54, 0xf8, 0xf6, 0xaf, 0x2e, 0xe8, 0x8c, 0x0a, 0x46, 0xe1, 0x1a, 0x58,
+	0xe0, 0x22, 0x65, 0x97, 0xc8, 0xf1, 0x9d, 0x60, 0x39, 0xae, 0x0a, 0x08, 0x41, 0x47, 0x90, 0x9c,
+	0xa1, 0xff, 0xec, 0xa6, 0x5d, 0x43, 0x04, 0x16, 0x79, 0x5e, 0xc8, 0x52, 0x2b, 0xb4, 0xea, 0xb7,
+	0x83, 0xe5, 0xb8, 0x29, 0xe1, 0x73, 0xd0, 0x21, 0x05, 0x57, 0xa8, 0xed, 0xb7, 0x83, 0xde, 0xee,
+	0x56, 0xf8, 0x47, 0xf8, 0x70, 0x54, 0x05, 0xdc, 0x2f, 0xf8, 0xa0, 0x73, 0xf5, 0xe3, 0x61, 0x2b,
+	0xb6, 0x02, 0x63, 0xc9, 0x04, 0x49, 0x32, 0x96, 0xa2, 0x8e, 0xef, 0x04, 0x4b, 0x71, 0x53, 0xc2,
+	0x3d, 0x70, 0xbf, 0x64, 0x19, 0x27, 0x09, 0xcf, 0xb8, 0x9e, 0x62, 0x7d, 0x56, 0x32, 0x75, 0x26,
+	0xb3, 0x14, 0x2d, 0xf8, 0x4e, 0xb0, 0x12, 0xaf, 0xcd, 0x81, 0x27, 0x0d, 0x06, 0x5f, 0x00, 0x94,
+	0x12, 0x4d, 0xf0, 0xbc, 0xb2, 0xf1, 0xef, 0x5a, 0xff, 0x75, 0x83, 0xc7, 0xb7, 0xf0, 0xab, 0xfa,
+	0xb8, 0xd7, 0xe0, 0x51, 0x92, 0x49, 0x7a, 0x8e, 0x53, 0xae, 0x34, 0x11, 0x94, 0xe1, 0x53, 0x59,
+	0xe2, 0x53, 0x2e, 0x48, 0xc6, 0xbf, 0xb0, 0x14, 0x1b, 0x19, 0x5a, 0xb4, 0x47, 0x6f, 0x59, 0xe2,
+	0x61, 0xcd, 0x1b, 0xca, 0x72, 0xd8, 0xb0, 0x0e, 0x89, 0x26, 0xf0, 0x25, 0x78, 0x60, 0x09, 0x0a,
+	0x73, 0xd1, 0x18, 0x10, 0xcd, 0xa5, 0xc0, 0x45, 0x29, 0xe5, 0x29, 0x5a, 0xb2, 0x26, 0x1b, 0x15,
+	0xe7, 0x48, 0x0c, 0xe7, 0x18, 0xc7, 0x86, 0x00, 0x9f, 0x01, 0x48, 0x26, 0xac, 0x24, 0x63, 0x86,
+	0xab, 0x48, 0x9a, 0xe7, 0x0c, 0x2d, 0xfb, 0x4e, 0xd0, 0x8e, 0xdd, 0x1a, 0x19, 0x18, 0xe0, 0x84,
+	0xe7, 0x0c, 0xee, 0x03, 0x8f, 0x64, 0x99, 0xfc, 0xcc, 0xd2, 0x9a, 0x9d, 0x91, 0xb1, 0xcd, 0xfe,
+	0x49, 0x2a, 0xac, 0xa6, 0x82, 0x22, 0x60, 0x95, 0x1b, 0x35, 0xcb, 0x2a, 0xdf, 0x90, 0xf1, 0x50,
+	0x96, 0xef, 0xa5, 0x1a, 0x4d, 0x05, 0x35, 0x07, 0x36, 0x52, 0xa5, 0xf1, 0x45, 0x91, 0x12, 0xcd,
+	0x52, 0xd4, 0xf3, 0x9d, 0xa0, 0x13, 0xbb, 0x49, 0xc5, 0x57, 0xfa, 0x43, 0xb5, 0x0f, 0xdf, 0x02,
+	0x98, 0x73, 0x81, 0x95, 0x26, 0xe7, 0xcc, 0x5c, 0x69, 0xc2, 0x53, 0x56, 0xa2, 0xff, 0x7d, 0x27,
+	0xe8, 0xed, 0x6e, 0x84, 0x55, 0xd7, 0x85, 0xa6, 0xeb, 0xc2, 0xba, 0xeb, 0xc2, 0x03, 0xc9, 0x45,
+	0xfd, 0xd6, 0xdd, 0x9c, 0x8b, 0x91, 0x51, 0x1e, 0xd7, 0x42, 0x78, 0x04, 0xdc, 0x5b, 0x3b, 0x9a,
+	0x71, 0x26, 0x34, 0x5a, 0xf9, 0x37, 0xb3, 0x7e, 0x63, 0x76, 0x60, 0x65, 0xf0, 0x1d, 0x58, 0x6d,
+	0xf2, 0x28, 0xac, 0xa7, 0x05, 0x53, 0xa8, 0xef, 0x3b, 0x41, 0x7f, 0xf7, 0xc9, 0xdf, 0x1a, 0xd2,
+	0x0c, 0x4d, 0x0a, 0x75, 0x62, 0xd8, 0x71, 0xbf, 0xb8, 0x53, 0x9b, 0xee, 0x9c, 0xb0, 0x52, 0x71,
+	0x29, 0x90, 0x6b, 0x9f, 0x46, 0x53, 0x6e, 0x3f, 0x05, 0xfd, 0xbb, 0x5a, 0xd8, 0x03, 0x8b, 0xe9,
+	0x54, 0x90, 0x9c, 0x53, 0xb7, 0x05, 0x01, 0xe8, 0x2a, 0x4d, 0x34, 0xa7, 0xae, 0x33, 0x18, 0x7c,
+	0x9b, 0x79, 0xce, 0xd5, 0xcc, 0x73, 0xae, 0x67, 0x9e, 0xf3, 0x73, 0xe6, 0x39, 0x5f, 0x6f, 0xbc,
+	0xd6, 0xf5, 0x8d, 0xd7, 0xfa, 0x7e, 0xe3, 0xb5, 0x3e, 0x3e, 0x1e, 0x73, 0x7d, 0x76, 0x91, 0x84,
+	0x54, 0xe6, 0x51, 0x1d, 0xd2, 0xce, 0xd1, 0xa5, 0xfd, 0x1b, 0x44, 0xf6, 0x1a, 0x49, 0xd7, 0x7e,
+	0xb3, 0x7b, 0xbf, 0x07, 0x00, 0xb6, 0x60, 0xf7, 0x50, 0x27, 0x04, 0x00, 0x00,
 }
 
 func (this *Spec) Equal(that interface{}) bool {
@@ -330,6 +339,9 @@ func (this *Spec) Equal(that interface{}) bool {
 	if this.ProvidersTypes != that1.ProvidersTypes {
 		return false
 	}
+	if this.Version != that1.Version {
+		return false
+	}
 	return true
 }
 func (m *Spec) Marshal() (dAtA []byte, err error) {
@@ -352,6 +364,13 @@ func (m *Spec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Version != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
 	if len(m.Imports) > 0 {
 		for iNdEx := len(m.Imports) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Imports[iNdEx])
@@ -535,6 +554,9 @@ func (m *Spec) Size() (n int) {
 			n += 1 + l + sovSpec(uint64(l))
 		}
 	}
+	if m.Version != 0 {
+		n += 2 + sovSpec(uint64(m.Version))
+	}
 	return n
 }
 
@@ -942,6 +964,25 @@ func (m *Spec) Unmarshal(dAtA []byte) error {
 			}
 			m.Imports = append(m.Imports, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
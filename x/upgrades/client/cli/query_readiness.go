@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"github.com/spf13/cobra"
+)
+
+func CmdReadiness() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "readiness [plan-name]",
+		Short: "shows the percentage of bonded stake and provider stake that signaled readiness for an upgrade plan",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			params := &types.QueryReadinessRequest{
+				PlanName: args[0],
+			}
+
+			res, err := queryClient.Readiness(context.Background(), params)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
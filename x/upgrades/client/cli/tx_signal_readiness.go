@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/version"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"github.com/spf13/cobra"
+)
+
+const FlagProviderChain = "provider-chain"
+
+func CmdSignalReadiness() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signal-readiness [plan-name] [version]",
+		Short: "Signal the binary of the scheduled upgrade plan is installed",
+		Long: `Signal the binary of the scheduled upgrade plan is installed, only validators (from the operator account) and staked providers can signal.
+Providers pass a chain they are staked on with --provider-chain. The version defaults to the version of this binary, run it with the new binary or pass the version explicitly.`,
+		Example: fmt.Sprintf("%s tx %s signal-readiness v0.7.0 --from <operator key>\n%[1]s tx %[2]s signal-readiness v0.7.0 --from <provider key> --%[3]s ETH1", version.AppName, types.ModuleName, FlagProviderChain),
+		Args:    cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			argPlanName := args[0]
+			argVersion := version.Version
+			if len(args) > 1 {
+				argVersion = args[1]
+			}
+
+			argChainID, err := cmd.Flags().GetString(FlagProviderChain)
+			if err != nil {
+				return err
+			}
+
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSignalReadiness(
+				clientCtx.GetFromAddress().String(),
+				argPlanName,
+				argVersion,
+				argChainID,
+			)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String(FlagProviderChain, "", "the chain the provider is staked on, not needed for validators")
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
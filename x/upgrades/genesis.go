@@ -0,0 +1,31 @@
+package upgrades
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/upgrades/keeper"
+	"github.com/lavanet/lava/x/upgrades/types"
+)
+
+// InitGenesis initializes the upgrades module's state from a provided genesis
+// state.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState) {
+	// Set all the readinessSignal
+	for _, elem := range genState.ReadinessSignals {
+		k.SetReadinessSignal(ctx, elem)
+	}
+	// Set all the upgradeRecord
+	for _, elem := range genState.UpgradeRecords {
+		k.SetUpgradeRecord(ctx, elem)
+	}
+	// this line is used by starport scaffolding # genesis/module/init
+}
+
+// ExportGenesis returns the upgrades module's exported genesis.
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	genesis := types.DefaultGenesis()
+	genesis.ReadinessSignals = k.GetAllReadinessSignal(ctx)
+	genesis.UpgradeRecords = k.GetAllUpgradeRecord(ctx)
+	// this line is used by starport scaffolding # genesis/module/export
+
+	return genesis
+}
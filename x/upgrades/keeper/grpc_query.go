@@ -0,0 +1,7 @@
+package keeper
+
+import (
+	"github.com/lavanet/lava/x/upgrades/types"
+)
+
+var _ types.QueryServer = Keeper{}
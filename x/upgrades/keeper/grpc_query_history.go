@@ -0,0 +1,39 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (k Keeper) History(goCtx context.Context, req *types.QueryHistoryRequest) (*types.QueryHistoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	var records []types.UpgradeRecord
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	store := ctx.KVStore(k.storeKey)
+	upgradeRecordStore := prefix.NewStore(store, types.KeyPrefix(types.UpgradeRecordKeyPrefix))
+
+	pageRes, err := query.Paginate(upgradeRecordStore, req.Pagination, func(key []byte, value []byte) error {
+		var upgradeRecord types.UpgradeRecord
+		if err := k.cdc.Unmarshal(value, &upgradeRecord); err != nil {
+			return err
+		}
+
+		records = append(records, upgradeRecord)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryHistoryResponse{Records: records, Pagination: pageRes}, nil
+}
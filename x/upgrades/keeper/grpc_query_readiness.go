@@ -0,0 +1,20 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (k Keeper) Readiness(goCtx context.Context, req *types.QueryReadinessRequest) (*types.QueryReadinessResponse, error) {
+	if req == nil || req.PlanName == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	readiness := k.PlanReadiness(ctx, req.PlanName)
+	return &readiness, nil
+}
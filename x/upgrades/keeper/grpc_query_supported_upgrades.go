@@ -0,0 +1,17 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/lavanet/lava/x/upgrades/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (k Keeper) SupportedUpgrades(goCtx context.Context, req *types.QuerySupportedUpgradesRequest) (*types.QuerySupportedUpgradesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	return &types.QuerySupportedUpgradesResponse{Upgrades: k.GetSupportedUpgrades()}, nil
+}
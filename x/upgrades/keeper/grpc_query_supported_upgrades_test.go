@@ -0,0 +1,26 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	testkeeper "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportedUpgradesQuery(t *testing.T) {
+	supportedUpgrades := []types.SupportedUpgrade{
+		{Name: "v0.4.0"},
+		{Name: "v0.7.0", AddedStores: []string{"lavaupgrades"}, Declarative: true},
+	}
+	keeper, ctx := testkeeper.UpgradesKeeper(t, supportedUpgrades)
+	wctx := sdk.WrapSDKContext(ctx)
+
+	response, err := keeper.SupportedUpgrades(wctx, &types.QuerySupportedUpgradesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, &types.QuerySupportedUpgradesResponse{Upgrades: supportedUpgrades}, response)
+
+	_, err = keeper.SupportedUpgrades(wctx, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/upgrades/types"
+)
+
+type (
+	Keeper struct {
+		cdc      codec.BinaryCodec
+		storeKey sdk.StoreKey
+
+		// the upgrades the running binary has handlers for, set by the app from its upgrade registry
+		supportedUpgrades []types.SupportedUpgrade
+
+		stakingKeeper      types.StakingKeeper
+		upgradeKeeper      types.UpgradeKeeper
+		epochstorageKeeper types.EpochstorageKeeper
+		specKeeper         types.SpecKeeper
+	}
+)
+
+func NewKeeper(
+	cdc codec.BinaryCodec,
+	storeKey sdk.StoreKey,
+	supportedUpgrades []types.SupportedUpgrade,
+
+	stakingKeeper types.StakingKeeper, upgradeKeeper types.UpgradeKeeper, epochstorageKeeper types.EpochstorageKeeper, specKeeper types.SpecKeeper,
+) *Keeper {
+	return &Keeper{
+		cdc:               cdc,
+		storeKey:          storeKey,
+		supportedUpgrades: supportedUpgrades,
+		stakingKeeper:     stakingKeeper, upgradeKeeper: upgradeKeeper, epochstorageKeeper: epochstorageKeeper, specKeeper: specKeeper,
+	}
+}
+
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
+}
+
+func (k Keeper) GetSupportedUpgrades() []types.SupportedUpgrade {
+	return k.supportedUpgrades
+}
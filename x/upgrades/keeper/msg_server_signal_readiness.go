@@ -0,0 +1,37 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/utils"
+	"github.com/lavanet/lava/x/upgrades/types"
+)
+
+func (k msgServer) SignalReadiness(goCtx context.Context, msg *types.MsgSignalReadiness) (*types.MsgSignalReadinessResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	logger := k.Logger(ctx)
+
+	creator, err := sdk.AccAddressFromBech32(msg.Creator)
+	if err != nil {
+		return nil, utils.LavaError(ctx, logger, "signal_readiness", map[string]string{"creator": msg.Creator, "error": err.Error()}, "invalid creator address")
+	}
+	plan, found := k.upgradeKeeper.GetUpgradePlan(ctx)
+	if !found || plan.Name != msg.PlanName {
+		return nil, utils.LavaError(ctx, logger, "signal_readiness", map[string]string{"creator": msg.Creator, "plan": msg.PlanName, "error": types.ErrNoUpgradePlan.Error()}, "can only signal readiness for the scheduled upgrade plan")
+	}
+	if !k.IsValidatorOrProvider(ctx, creator, msg.ChainID) {
+		return nil, utils.LavaError(ctx, logger, "signal_readiness", map[string]string{"creator": msg.Creator, "plan": msg.PlanName, "chainID": msg.ChainID, "error": types.ErrNotValidatorOrProvider.Error()}, "only validators and staked providers can signal readiness")
+	}
+
+	k.RemoveStaleReadinessSignals(ctx, plan.Name)
+	k.SetReadinessSignal(ctx, types.ReadinessSignal{
+		PlanName: plan.Name,
+		Address:  msg.Creator,
+		Version:  msg.Version,
+		Block:    ctx.BlockHeight(),
+	})
+	utils.LogLavaEvent(ctx, logger, types.SignalReadinessEventName, map[string]string{"creator": msg.Creator, "plan": plan.Name, "version": msg.Version}, "upgrade readiness signaled")
+
+	return &types.MsgSignalReadinessResponse{}, nil
+}
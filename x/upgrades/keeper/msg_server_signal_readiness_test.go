@@ -0,0 +1,107 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	testkeeper "github.com/lavanet/lava/testutil/keeper"
+	"github.com/lavanet/lava/testutil/sample"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	spectypes "github.com/lavanet/lava/x/spec/types"
+	"github.com/lavanet/lava/x/upgrades/keeper"
+	"github.com/lavanet/lava/x/upgrades/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignalReadiness(t *testing.T) {
+	ks, ctx := testkeeper.UpgradesKeeperWithDeps(t, nil)
+	msgServer := keeper.NewMsgServerImpl(*ks.Upgrades)
+	wctx := sdk.WrapSDKContext(ctx)
+
+	// two bonded validators with 30 and 70 tokens, and an unbonded validator that doesn't count
+	validator0, validator1, unbonded := sample.AccAddress(), sample.AccAddress(), sample.AccAddress()
+	addValidator := func(address string, tokens int64, status stakingtypes.BondStatus) {
+		accAddress, err := sdk.AccAddressFromBech32(address)
+		require.NoError(t, err)
+		ks.Staking.SetValidator(stakingtypes.Validator{OperatorAddress: sdk.ValAddress(accAddress).String(), Tokens: sdk.NewInt(tokens), Status: status})
+	}
+	addValidator(validator0, 30, stakingtypes.Bonded)
+	addValidator(validator1, 70, stakingtypes.Bonded)
+	addValidator(unbonded, 1000, stakingtypes.Unbonded)
+
+	// provider0 is staked on two chains, provider1 on one
+	provider0, provider1 := sample.AccAddress(), sample.AccAddress()
+	addProvider := func(address string, chainID string, stake int64) {
+		ks.Epochstorage.AppendStakeEntryCurrent(ctx, epochstoragetypes.ProviderKey, chainID, epochstoragetypes.StakeEntry{
+			Address: address,
+			Chain:   chainID,
+			Stake:   sdk.NewCoin(epochstoragetypes.TokenDenom, sdk.NewInt(stake)),
+		})
+	}
+	ks.Spec.SetSpec(ctx, spectypes.Spec{Index: "ETH1", Enabled: true})
+	ks.Spec.SetSpec(ctx, spectypes.Spec{Index: "LAV1", Enabled: true})
+	addProvider(provider0, "ETH1", 100)
+	addProvider(provider0, "LAV1", 100)
+	addProvider(provider1, "ETH1", 200)
+
+	// no plan is scheduled
+	_, err := msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(validator0, "v1", "v1.0.0", ""))
+	require.Error(t, err)
+
+	ks.Upgrade.SetPlan(&upgradetypes.Plan{Name: "v1", Height: 100})
+	// readiness for a plan that isn't scheduled
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(validator0, "v2", "v2.0.0", ""))
+	require.Error(t, err)
+	// not a validator or a provider
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(sample.AccAddress(), "v1", "v1.0.0", ""))
+	require.Error(t, err)
+	// a provider signals on a chain it is staked on
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(provider1, "v1", "v1.0.0", ""))
+	require.Error(t, err)
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(provider1, "v1", "v1.0.0", "LAV1"))
+	require.Error(t, err)
+
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(validator0, "v1", "v1.0.0", ""))
+	require.NoError(t, err)
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(unbonded, "v1", "v1.0.0", ""))
+	require.NoError(t, err)
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(provider0, "v1", "v1.0.0", "LAV1"))
+	require.NoError(t, err)
+
+	readiness, err := ks.Upgrades.Readiness(wctx, &types.QueryReadinessRequest{PlanName: "v1"})
+	require.NoError(t, err)
+	require.Len(t, readiness.Signals, 3)
+	require.Equal(t, sdk.NewInt(30), readiness.BondedReadyTokens)
+	require.Equal(t, sdk.NewInt(100), readiness.BondedTotalTokens)
+	require.Equal(t, sdk.NewDec(30), readiness.BondedReadyPercentage)
+	require.Equal(t, sdk.NewInt(200), readiness.ProviderReadyStake)
+	require.Equal(t, sdk.NewInt(400), readiness.ProviderTotalStake)
+	require.Equal(t, sdk.NewDec(50), readiness.ProviderReadyPercentage)
+
+	// signaling again updates the version
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(validator0, "v1", "v1.0.1", ""))
+	require.NoError(t, err)
+	readinessSignal, found := ks.Upgrades.GetReadinessSignal(ctx, "v1", validator0)
+	require.True(t, found)
+	require.Equal(t, "v1.0.1", readinessSignal.Version)
+
+	// a new plan removes the signals of the older plans, plan names sorting before and after it
+	ks.Upgrades.SetReadinessSignal(ctx, types.ReadinessSignal{PlanName: "v3", Address: validator1, Version: "v3.0.0"})
+	ks.Upgrades.SetReadinessSignal(ctx, types.ReadinessSignal{PlanName: "v2-rc", Address: validator1, Version: "v2.0.0-rc"})
+	ks.Upgrade.SetPlan(&upgradetypes.Plan{Name: "v2", Height: 200})
+	_, err = msgServer.SignalReadiness(wctx, types.NewMsgSignalReadiness(validator1, "v2", "v2.0.0", ""))
+	require.NoError(t, err)
+	require.Len(t, ks.Upgrades.GetAllReadinessSignal(ctx), 1)
+	_, found = ks.Upgrades.GetReadinessSignal(ctx, "v2", validator1)
+	require.True(t, found)
+
+	readiness, err = ks.Upgrades.Readiness(wctx, &types.QueryReadinessRequest{PlanName: "v2"})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDec(70), readiness.BondedReadyPercentage)
+	require.True(t, readiness.ProviderReadyPercentage.IsZero())
+
+	_, err = ks.Upgrades.Readiness(wctx, &types.QueryReadinessRequest{})
+	require.Error(t, err)
+}
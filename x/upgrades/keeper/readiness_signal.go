@@ -0,0 +1,169 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	epochstoragetypes "github.com/lavanet/lava/x/epochstorage/types"
+	"github.com/lavanet/lava/x/upgrades/types"
+)
+
+// SetReadinessSignal set a specific readinessSignal in the store from its index
+func (k Keeper) SetReadinessSignal(ctx sdk.Context, readinessSignal types.ReadinessSignal) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.ReadinessSignalKeyPrefix))
+	b := k.cdc.MustMarshal(&readinessSignal)
+	store.Set(types.ReadinessSignalKey(
+		readinessSignal.PlanName,
+		readinessSignal.Address,
+	), b)
+}
+
+// GetReadinessSignal returns a readinessSignal from its index
+func (k Keeper) GetReadinessSignal(
+	ctx sdk.Context,
+	planName string,
+	address string,
+) (val types.ReadinessSignal, found bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.ReadinessSignalKeyPrefix))
+
+	b := store.Get(types.ReadinessSignalKey(
+		planName,
+		address,
+	))
+	if b == nil {
+		return val, false
+	}
+
+	k.cdc.MustUnmarshal(b, &val)
+	return val, true
+}
+
+// RemoveReadinessSignal removes a readinessSignal from the store
+func (k Keeper) RemoveReadinessSignal(
+	ctx sdk.Context,
+	planName string,
+	address string,
+) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.ReadinessSignalKeyPrefix))
+	store.Delete(types.ReadinessSignalKey(
+		planName,
+		address,
+	))
+}
+
+// GetAllReadinessSignal returns all readinessSignal
+func (k Keeper) GetAllReadinessSignal(ctx sdk.Context) (list []types.ReadinessSignal) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.ReadinessSignalKeyPrefix))
+	iterator := sdk.KVStorePrefixIterator(store, []byte{})
+
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var val types.ReadinessSignal
+		k.cdc.MustUnmarshal(iterator.Value(), &val)
+		list = append(list, val)
+	}
+
+	return
+}
+
+// GetPlanReadinessSignals returns the readiness signals of an upgrade plan
+func (k Keeper) GetPlanReadinessSignals(ctx sdk.Context, planName string) (list []types.ReadinessSignal) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.ReadinessSignalKeyPrefix))
+	iterator := sdk.KVStorePrefixIterator(store, types.ReadinessSignalPlanKey(planName))
+
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var val types.ReadinessSignal
+		k.cdc.MustUnmarshal(iterator.Value(), &val)
+		list = append(list, val)
+	}
+
+	return
+}
+
+// RemoveStaleReadinessSignals removes the signals of every plan other than planName,
+// only one plan can be scheduled so signals for older plans are no longer useful.
+// the signals are kept by plan, the ones of other plans are the ranges before and after the signals of planName
+func (k Keeper) RemoveStaleReadinessSignals(ctx sdk.Context, planName string) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.ReadinessSignalKeyPrefix))
+	planKey := types.ReadinessSignalPlanKey(planName)
+	removeRange := func(start []byte, end []byte) {
+		iterator := store.Iterator(start, end)
+		keys := [][]byte{}
+		for ; iterator.Valid(); iterator.Next() {
+			keys = append(keys, iterator.Key())
+		}
+		iterator.Close()
+		for _, key := range keys {
+			store.Delete(key)
+		}
+	}
+	removeRange(nil, planKey)
+	removeRange(sdk.PrefixEndBytes(planKey), nil)
+}
+
+// IsValidatorOrProvider checks the address is the operator account of a validator or has a provider stake entry on chainID
+func (k Keeper) IsValidatorOrProvider(ctx sdk.Context, address sdk.AccAddress, chainID string) bool {
+	if _, found := k.stakingKeeper.GetValidator(ctx, sdk.ValAddress(address)); found {
+		return true
+	}
+	if chainID == "" {
+		return false
+	}
+	_, found, _ := k.epochstorageKeeper.GetStakeEntryByAddressCurrent(ctx, epochstoragetypes.ProviderKey, chainID, address)
+	return found
+}
+
+// PlanReadiness sums the bonded tokens of validators and the stake of providers that signaled readiness for the plan,
+// the stake is taken from the current validator set and provider stake entries so signers that unbonded or unstaked don't count
+func (k Keeper) PlanReadiness(ctx sdk.Context, planName string) types.QueryReadinessResponse {
+	signals := k.GetPlanReadinessSignals(ctx, planName)
+	ready := map[string]struct{}{}
+	for _, readinessSignal := range signals {
+		ready[readinessSignal.Address] = struct{}{}
+	}
+
+	bondedReady := sdk.ZeroInt()
+	k.stakingKeeper.IterateBondedValidatorsByPower(ctx, func(_ int64, validator stakingtypes.ValidatorI) bool {
+		if _, ok := ready[sdk.AccAddress(validator.GetOperator()).String()]; ok {
+			bondedReady = bondedReady.Add(validator.GetBondedTokens())
+		}
+		return false
+	})
+	bondedTotal := k.stakingKeeper.TotalBondedTokens(ctx)
+
+	providerReady := sdk.ZeroInt()
+	providerTotal := sdk.ZeroInt()
+	for _, chainID := range k.specKeeper.GetAllChainIDs(ctx) {
+		stakeStorage, found := k.epochstorageKeeper.GetStakeStorageCurrent(ctx, epochstoragetypes.ProviderKey, chainID)
+		if !found {
+			continue
+		}
+		for _, stakeEntry := range stakeStorage.StakeEntries {
+			providerTotal = providerTotal.Add(stakeEntry.Stake.Amount)
+			if _, ok := ready[stakeEntry.Address]; ok {
+				providerReady = providerReady.Add(stakeEntry.Stake.Amount)
+			}
+		}
+	}
+
+	return types.QueryReadinessResponse{
+		PlanName:                planName,
+		BondedReadyPercentage:   percentage(bondedReady, bondedTotal),
+		BondedReadyTokens:       bondedReady,
+		BondedTotalTokens:       bondedTotal,
+		ProviderReadyPercentage: percentage(providerReady, providerTotal),
+		ProviderReadyStake:      providerReady,
+		ProviderTotalStake:      providerTotal,
+		Signals:                 signals,
+	}
+}
+
+func percentage(part sdk.Int, total sdk.Int) sdk.Dec {
+	if !total.IsPositive() {
+		return sdk.ZeroDec()
+	}
+	return sdk.NewDecFromInt(part).MulInt64(100).QuoInt(total)
+}
@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/lavanet/lava/x/upgrades/types"
+)
+
+// SetUpgradeRecord set a specific upgradeRecord in the store from its height
+func (k Keeper) SetUpgradeRecord(ctx sdk.Context, upgradeRecord types.UpgradeRecord) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.UpgradeRecordKeyPrefix))
+	b := k.cdc.MustMarshal(&upgradeRecord)
+	store.Set(types.UpgradeRecordKey(upgradeRecord.Height), b)
+}
+
+// GetUpgradeRecord returns the upgradeRecord of the upgrade applied at height
+func (k Keeper) GetUpgradeRecord(ctx sdk.Context, height int64) (val types.UpgradeRecord, found bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.UpgradeRecordKeyPrefix))
+
+	b := store.Get(types.UpgradeRecordKey(height))
+	if b == nil {
+		return val, false
+	}
+
+	k.cdc.MustUnmarshal(b, &val)
+	return val, true
+}
+
+// GetAllUpgradeRecord returns all upgradeRecord, oldest first
+func (k Keeper) GetAllUpgradeRecord(ctx sdk.Context) (list []types.UpgradeRecord) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefix(types.UpgradeRecordKeyPrefix))
+	iterator := sdk.KVStorePrefixIterator(store, []byte{})
+
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var val types.UpgradeRecord
+		k.cdc.MustUnmarshal(iterator.Value(), &val)
+		list = append(list, val)
+	}
+
+	return
+}
@@ -0,0 +1,14 @@
+package types
+
+// DONTCOVER
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// x/upgrades module sentinel errors
+var (
+	ErrSample                 = sdkerrors.Register(ModuleName, 1100, "sample error")
+	ErrNoUpgradePlan          = sdkerrors.Register(ModuleName, 1101, "upgrade plan is not scheduled")
+	ErrNotValidatorOrProvider = sdkerrors.Register(ModuleName, 1102, "signer is not a validator or a staked provider")
+)
@@ -0,0 +1,39 @@
+package types
+
+import "fmt"
+
+// DefaultGenesis returns the default upgrades genesis state
+func DefaultGenesis() *GenesisState {
+	return &GenesisState{
+		ReadinessSignals: []ReadinessSignal{},
+		UpgradeRecords:   []UpgradeRecord{},
+		// this line is used by starport scaffolding # genesis/types/default
+	}
+}
+
+// Validate performs basic genesis state validation returning an error upon any
+// failure.
+func (gs GenesisState) Validate() error {
+	// Check for duplicated index in readinessSignal
+	readinessSignalIndexMap := make(map[string]struct{})
+
+	for _, elem := range gs.ReadinessSignals {
+		index := string(ReadinessSignalKey(elem.PlanName, elem.Address))
+		if _, ok := readinessSignalIndexMap[index]; ok {
+			return fmt.Errorf("duplicated index for readinessSignal")
+		}
+		readinessSignalIndexMap[index] = struct{}{}
+	}
+	// Check for duplicated height in upgradeRecord
+	upgradeRecordIndexMap := make(map[int64]struct{})
+
+	for _, elem := range gs.UpgradeRecords {
+		if _, ok := upgradeRecordIndexMap[elem.Height]; ok {
+			return fmt.Errorf("duplicated height for upgradeRecord")
+		}
+		upgradeRecordIndexMap[elem.Height] = struct{}{}
+	}
+	// this line is used by starport scaffolding # genesis/types/validate
+
+	return nil
+}
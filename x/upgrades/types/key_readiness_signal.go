@@ -0,0 +1,33 @@
+package types
+
+const (
+	// ReadinessSignalKeyPrefix is the prefix to retrieve all ReadinessSignal
+	ReadinessSignalKeyPrefix = "ReadinessSignal/value/"
+)
+
+// ReadinessSignalPlanKey returns the store key prefix of the signals of a plan
+func ReadinessSignalPlanKey(
+	planName string,
+) []byte {
+	var key []byte
+
+	planNameBytes := []byte(planName)
+	key = append(key, planNameBytes...)
+	key = append(key, []byte("/")...)
+
+	return key
+}
+
+// ReadinessSignalKey returns the store key to retrieve a ReadinessSignal from the index fields
+func ReadinessSignalKey(
+	planName string,
+	address string,
+) []byte {
+	key := ReadinessSignalPlanKey(planName)
+
+	addressBytes := []byte(address)
+	key = append(key, addressBytes...)
+	key = append(key, []byte("/")...)
+
+	return key
+}
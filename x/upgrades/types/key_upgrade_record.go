@@ -0,0 +1,17 @@
+package types
+
+import "encoding/binary"
+
+const (
+	// UpgradeRecordKeyPrefix is the prefix to retrieve all UpgradeRecord
+	UpgradeRecordKeyPrefix = "UpgradeRecord/value/"
+)
+
+// UpgradeRecordKey returns the store key to retrieve an UpgradeRecord, records are keyed by height so they iterate in the order they were applied
+func UpgradeRecordKey(
+	height int64,
+) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(height))
+	return key
+}
@@ -0,0 +1,20 @@
+package types
+
+const (
+	// ModuleName defines the module name
+	ModuleName = "upgrades"
+
+	// StoreKey defines the primary module store key, the module name can't be used since
+	// the sdk upgrade module store key "upgrade" is a prefix of it
+	StoreKey = "lava" + ModuleName
+
+	// RouterKey is the message route for slashing
+	RouterKey = ModuleName
+
+	// QuerierRoute defines the module's query routing key
+	QuerierRoute = ModuleName
+)
+
+func KeyPrefix(p string) []byte {
+	return []byte(p)
+}
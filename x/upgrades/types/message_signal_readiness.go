@@ -0,0 +1,54 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const TypeMsgSignalReadiness = "signal_readiness"
+
+var _ sdk.Msg = &MsgSignalReadiness{}
+
+func NewMsgSignalReadiness(creator string, planName string, version string, chainID string) *MsgSignalReadiness {
+	return &MsgSignalReadiness{
+		Creator:  creator,
+		PlanName: planName,
+		Version:  version,
+		ChainID:  chainID,
+	}
+}
+
+func (msg *MsgSignalReadiness) Route() string {
+	return RouterKey
+}
+
+func (msg *MsgSignalReadiness) Type() string {
+	return TypeMsgSignalReadiness
+}
+
+func (msg *MsgSignalReadiness) GetSigners() []sdk.AccAddress {
+	creator, err := sdk.AccAddressFromBech32(msg.Creator)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{creator}
+}
+
+func (msg *MsgSignalReadiness) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+func (msg *MsgSignalReadiness) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Creator)
+	if err != nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid creator address (%s)", err)
+	}
+	if msg.PlanName == "" {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty plan name")
+	}
+	if msg.Version == "" || len(msg.Version) > MaxVersionLength {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "version must be between 1 and %d characters", MaxVersionLength)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/lavanet/lava/testutil/sample"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgSignalReadiness_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  MsgSignalReadiness
+		err  error
+	}{
+		{
+			name: "invalid address",
+			msg: MsgSignalReadiness{
+				Creator:  "invalid_address",
+				PlanName: "v0.7.0",
+				Version:  "v0.7.0",
+			},
+			err: sdkerrors.ErrInvalidAddress,
+		}, {
+			name: "missing plan name",
+			msg: MsgSignalReadiness{
+				Creator: sample.AccAddress(),
+				Version: "v0.7.0",
+			},
+			err: sdkerrors.ErrInvalidRequest,
+		}, {
+			name: "version too long",
+			msg: MsgSignalReadiness{
+				Creator:  sample.AccAddress(),
+				PlanName: "v0.7.0",
+				Version:  strings.Repeat("v", MaxVersionLength+1),
+			},
+			err: sdkerrors.ErrInvalidRequest,
+		}, {
+			name: "valid",
+			msg: MsgSignalReadiness{
+				Creator:  sample.AccAddress(),
+				PlanName: "v0.7.0",
+				Version:  "v0.7.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,760 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: upgrades/supported_upgrade.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// SupportedUpgrade is an upgrade the running binary has a handler for
+type SupportedUpgrade struct {
+	Name          string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AddedStores   []string      `protobuf:"bytes,2,rep,name=added_stores,json=addedStores,proto3" json:"added_stores,omitempty"`
+	RenamedStores []StoreRename `protobuf:"bytes,3,rep,name=renamed_stores,json=renamedStores,proto3" json:"renamed_stores"`
+	DeletedStores []string      `protobuf:"bytes,4,rep,name=deleted_stores,json=deletedStores,proto3" json:"deleted_stores,omitempty"`
+	Declarative   bool          `protobuf:"varint,5,opt,name=declarative,proto3" json:"declarative,omitempty"`
+}
+
+func (m *SupportedUpgrade) Reset()         { *m = SupportedUpgrade{} }
+func (m *SupportedUpgrade) String() string { return proto.CompactTextString(m) }
+func (*SupportedUpgrade) ProtoMessage()    {}
+func (*SupportedUpgrade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_19868690c446d406, []int{0}
+}
+func (m *SupportedUpgrade) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SupportedUpgrade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SupportedUpgrade.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SupportedUpgrade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupportedUpgrade.Merge(m, src)
+}
+func (m *SupportedUpgrade) XXX_Size() int {
+	return m.Size()
+}
+func (m *SupportedUpgrade) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupportedUpgrade.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupportedUpgrade proto.InternalMessageInfo
+
+func (m *SupportedUpgrade) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SupportedUpgrade) GetAddedStores() []string {
+	if m != nil {
+		return m.AddedStores
+	}
+	return nil
+}
+
+func (m *SupportedUpgrade) GetRenamedStores() []StoreRename {
+	if m != nil {
+		return m.RenamedStores
+	}
+	return nil
+}
+
+func (m *SupportedUpgrade) GetDeletedStores() []string {
+	if m != nil {
+		return m.DeletedStores
+	}
+	return nil
+}
+
+func (m *SupportedUpgrade) GetDeclarative() bool {
+	if m != nil {
+		return m.Declarative
+	}
+	return false
+}
+
+type StoreRename struct {
+	OldKey string `protobuf:"bytes,1,opt,name=old_key,json=oldKey,proto3" json:"old_key,omitempty"`
+	NewKey string `protobuf:"bytes,2,opt,name=new_key,json=newKey,proto3" json:"new_key,omitempty"`
+}
+
+func (m *StoreRename) Reset()         { *m = StoreRename{} }
+func (m *StoreRename) String() string { return proto.CompactTextString(m) }
+func (*StoreRename) ProtoMessage()    {}
+func (*StoreRename) Descriptor() ([]byte, []int) {
+	return fileDescriptor_19868690c446d406, []int{1}
+}
+func (m *StoreRename) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StoreRename) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StoreRename.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StoreRename) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreRename.Merge(m, src)
+}
+func (m *StoreRename) XXX_Size() int {
+	return m.Size()
+}
+func (m *StoreRename) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreRename.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreRename proto.InternalMessageInfo
+
+func (m *StoreRename) GetOldKey() string {
+	if m != nil {
+		return m.OldKey
+	}
+	return ""
+}
+
+func (m *StoreRename) GetNewKey() string {
+	if m != nil {
+		return m.NewKey
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*SupportedUpgrade)(nil), "lavanet.lava.upgrades.SupportedUpgrade")
+	proto.RegisterType((*StoreRename)(nil), "lavanet.lava.upgrades.StoreRename")
+}
+
+func init() { proto.RegisterFile("upgrades/supported_upgrade.proto", fileDescriptor_19868690c446d406) }
+
+var fileDescriptor_19868690c446d406 = []byte{
+	// 310 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x4f, 0xc2, 0x30,
+	0x14, 0xc7, 0x57, 0x40, 0x94, 0x4e, 0x88, 0x69, 0x34, 0x12, 0x0f, 0xb5, 0x92, 0x98, 0xec, 0xd4,
+	0x25, 0xfa, 0x01, 0x4c, 0xb8, 0x72, 0x30, 0x19, 0xf1, 0xe2, 0x85, 0x14, 0xfa, 0x32, 0x89, 0x63,
+	0x5d, 0xba, 0x02, 0xee, 0x5b, 0xf8, 0xb1, 0x38, 0x72, 0xf4, 0x64, 0x0c, 0xfb, 0x22, 0x66, 0x5d,
+	0x41, 0x0e, 0x9e, 0xde, 0xcb, 0xff, 0xfd, 0xfe, 0xff, 0xb7, 0xb7, 0x62, 0xb6, 0xcc, 0x62, 0x2d,
+	0x24, 0xe4, 0x61, 0xbe, 0xcc, 0x32, 0xa5, 0x0d, 0xc8, 0x89, 0x93, 0x78, 0xa6, 0x95, 0x51, 0xe4,
+	0x2a, 0x11, 0x2b, 0x91, 0x82, 0xe1, 0x55, 0xe5, 0x7b, 0xfc, 0xe6, 0x32, 0x56, 0xb1, 0xb2, 0x44,
+	0x58, 0x75, 0x35, 0x3c, 0x28, 0x11, 0xbe, 0x18, 0xef, 0x83, 0x5e, 0x6a, 0x96, 0x10, 0xdc, 0x4a,
+	0xc5, 0x02, 0xfa, 0x88, 0xa1, 0xa0, 0x13, 0xd9, 0x9e, 0xdc, 0xe1, 0x73, 0x21, 0x25, 0xc8, 0x49,
+	0x6e, 0x94, 0x86, 0xbc, 0xdf, 0x60, 0xcd, 0xa0, 0x13, 0xf9, 0x56, 0x1b, 0x5b, 0x89, 0x3c, 0xe3,
+	0x9e, 0x86, 0x0a, 0x3e, 0x40, 0x4d, 0xd6, 0x0c, 0xfc, 0x87, 0x01, 0xff, 0xf7, 0x8b, 0xb8, 0xb5,
+	0x45, 0xd6, 0x31, 0x6c, 0x6d, 0xbe, 0x6f, 0xbd, 0xa8, 0xeb, 0xfc, 0x2e, 0xf0, 0x1e, 0xf7, 0x24,
+	0x24, 0x60, 0xfe, 0x02, 0x5b, 0x76, 0x6b, 0xd7, 0xa9, 0x0e, 0x63, 0xd8, 0x97, 0x30, 0x4b, 0x84,
+	0x16, 0x66, 0xbe, 0x82, 0xfe, 0x09, 0x43, 0xc1, 0x59, 0x74, 0x2c, 0x0d, 0x9e, 0xb0, 0x7f, 0xb4,
+	0x8c, 0x5c, 0xe3, 0x53, 0x95, 0xc8, 0xc9, 0x3b, 0x14, 0xee, 0xc4, 0xb6, 0x4a, 0xe4, 0x08, 0x8a,
+	0x6a, 0x90, 0xc2, 0xda, 0x0e, 0x1a, 0xf5, 0x20, 0x85, 0xf5, 0x08, 0x8a, 0xe1, 0x70, 0xb3, 0xa3,
+	0x68, 0xbb, 0xa3, 0xe8, 0x67, 0x47, 0xd1, 0x67, 0x49, 0xbd, 0x6d, 0x49, 0xbd, 0xaf, 0x92, 0x7a,
+	0xaf, 0x41, 0x3c, 0x37, 0x6f, 0xcb, 0x29, 0x9f, 0xa9, 0x45, 0xe8, 0xce, 0xb4, 0x35, 0xfc, 0x08,
+	0x0f, 0x2f, 0x65, 0x8a, 0x0c, 0xf2, 0x69, 0xdb, 0xfe, 0xf1, 0xc7, 0xdf, 0x01, 0x00, 0xe1, 0x49,
+	0x6b, 0x69, 0xc2, 0x01, 0x00, 0x00,
+}
+
+func (m *SupportedUpgrade) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SupportedUpgrade) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SupportedUpgrade) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Declarative {
+		i--
+		if m.Declarative {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.DeletedStores) > 0 {
+		for iNdEx := len(m.DeletedStores) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DeletedStores[iNdEx])
+			copy(dAtA[i:], m.DeletedStores[iNdEx])
+			i = encodeVarintSupportedUpgrade(dAtA, i, uint64(len(m.DeletedStores[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.RenamedStores) > 0 {
+		for iNdEx := len(m.RenamedStores) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RenamedStores[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSupportedUpgrade(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.AddedStores) > 0 {
+		for iNdEx := len(m.AddedStores) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AddedStores[iNdEx])
+			copy(dAtA[i:], m.AddedStores[iNdEx])
+			i = encodeVarintSupportedUpgrade(dAtA, i, uint64(len(m.AddedStores[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSupportedUpgrade(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StoreRename) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreRename) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StoreRename) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NewKey) > 0 {
+		i -= len(m.NewKey)
+		copy(dAtA[i:], m.NewKey)
+		i = encodeVarintSupportedUpgrade(dAtA, i, uint64(len(m.NewKey)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.OldKey) > 0 {
+		i -= len(m.OldKey)
+		copy(dAtA[i:], m.OldKey)
+		i = encodeVarintSupportedUpgrade(dAtA, i, uint64(len(m.OldKey)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintSupportedUpgrade(dAtA []byte, offset int, v uint64) int {
+	offset -= sovSupportedUpgrade(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *SupportedUpgrade) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSupportedUpgrade(uint64(l))
+	}
+	if len(m.AddedStores) > 0 {
+		for _, s := range m.AddedStores {
+			l = len(s)
+			n += 1 + l + sovSupportedUpgrade(uint64(l))
+		}
+	}
+	if len(m.RenamedStores) > 0 {
+		for _, e := range m.RenamedStores {
+			l = e.Size()
+			n += 1 + l + sovSupportedUpgrade(uint64(l))
+		}
+	}
+	if len(m.DeletedStores) > 0 {
+		for _, s := range m.DeletedStores {
+			l = len(s)
+			n += 1 + l + sovSupportedUpgrade(uint64(l))
+		}
+	}
+	if m.Declarative {
+		n += 2
+	}
+	return n
+}
+
+func (m *StoreRename) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.OldKey)
+	if l > 0 {
+		n += 1 + l + sovSupportedUpgrade(uint64(l))
+	}
+	l = len(m.NewKey)
+	if l > 0 {
+		n += 1 + l + sovSupportedUpgrade(uint64(l))
+	}
+	return n
+}
+
+func sovSupportedUpgrade(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozSupportedUpgrade(x uint64) (n int) {
+	return sovSupportedUpgrade(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *SupportedUpgrade) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSupportedUpgrade
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SupportedUpgrade: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SupportedUpgrade: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AddedStores", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AddedStores = append(m.AddedStores, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RenamedStores", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RenamedStores = append(m.RenamedStores, StoreRename{})
+			if err := m.RenamedStores[len(m.RenamedStores)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeletedStores", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DeletedStores = append(m.DeletedStores, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Declarative", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Declarative = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSupportedUpgrade(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StoreRename) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSupportedUpgrade
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreRename: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreRename: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OldKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSupportedUpgrade(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSupportedUpgrade
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipSupportedUpgrade(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowSupportedUpgrade
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSupportedUpgrade
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthSupportedUpgrade
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupSupportedUpgrade
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthSupportedUpgrade
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthSupportedUpgrade        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowSupportedUpgrade          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupSupportedUpgrade = fmt.Errorf("proto: unexpected end of group")
+)
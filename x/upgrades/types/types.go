@@ -0,0 +1,8 @@
+package types
+
+const (
+	SignalReadinessEventName = "upgrade_readiness_signaled"
+)
+
+// MaxVersionLength limits the version string of a readiness signal, it is kept in state until the next plan
+const MaxVersionLength = 128
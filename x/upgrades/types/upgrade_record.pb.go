@@ -0,0 +1,1255 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: upgrades/upgrade_record.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	time "time"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// UpgradeRecord is written by every lava upgrade handler, it describes what the upgrade changed on chain
+type UpgradeRecord struct {
+	PlanName      string              `protobuf:"bytes,1,opt,name=plan_name,json=planName,proto3" json:"plan_name,omitempty"`
+	Height        int64               `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Time          time.Time           `protobuf:"bytes,3,opt,name=time,proto3,stdtime" json:"time"`
+	FromVersions  []ModuleVersion     `protobuf:"bytes,4,rep,name=from_versions,json=fromVersions,proto3" json:"from_versions"`
+	ToVersions    []ModuleVersion     `protobuf:"bytes,5,rep,name=to_versions,json=toVersions,proto3" json:"to_versions"`
+	AddedSpecs    []string            `protobuf:"bytes,6,rep,name=added_specs,json=addedSpecs,proto3" json:"added_specs,omitempty"`
+	ModifiedSpecs []string            `protobuf:"bytes,7,rep,name=modified_specs,json=modifiedSpecs,proto3" json:"modified_specs,omitempty"`
+	RemovedSpecs  []string            `protobuf:"bytes,8,rep,name=removed_specs,json=removedSpecs,proto3" json:"removed_specs,omitempty"`
+	ParamChanges  []ParamChangeRecord `protobuf:"bytes,9,rep,name=param_changes,json=paramChanges,proto3" json:"param_changes"`
+}
+
+func (m *UpgradeRecord) Reset()         { *m = UpgradeRecord{} }
+func (m *UpgradeRecord) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRecord) ProtoMessage()    {}
+func (*UpgradeRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0501369836708595, []int{0}
+}
+func (m *UpgradeRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpgradeRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpgradeRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UpgradeRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRecord.Merge(m, src)
+}
+func (m *UpgradeRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpgradeRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradeRecord proto.InternalMessageInfo
+
+func (m *UpgradeRecord) GetPlanName() string {
+	if m != nil {
+		return m.PlanName
+	}
+	return ""
+}
+
+func (m *UpgradeRecord) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *UpgradeRecord) GetTime() time.Time {
+	if m != nil {
+		return m.Time
+	}
+	return time.Time{}
+}
+
+func (m *UpgradeRecord) GetFromVersions() []ModuleVersion {
+	if m != nil {
+		return m.FromVersions
+	}
+	return nil
+}
+
+func (m *UpgradeRecord) GetToVersions() []ModuleVersion {
+	if m != nil {
+		return m.ToVersions
+	}
+	return nil
+}
+
+func (m *UpgradeRecord) GetAddedSpecs() []string {
+	if m != nil {
+		return m.AddedSpecs
+	}
+	return nil
+}
+
+func (m *UpgradeRecord) GetModifiedSpecs() []string {
+	if m != nil {
+		return m.ModifiedSpecs
+	}
+	return nil
+}
+
+func (m *UpgradeRecord) GetRemovedSpecs() []string {
+	if m != nil {
+		return m.RemovedSpecs
+	}
+	return nil
+}
+
+func (m *UpgradeRecord) GetParamChanges() []ParamChangeRecord {
+	if m != nil {
+		return m.ParamChanges
+	}
+	return nil
+}
+
+type ModuleVersion struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *ModuleVersion) Reset()         { *m = ModuleVersion{} }
+func (m *ModuleVersion) String() string { return proto.CompactTextString(m) }
+func (*ModuleVersion) ProtoMessage()    {}
+func (*ModuleVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0501369836708595, []int{1}
+}
+func (m *ModuleVersion) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ModuleVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ModuleVersion.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ModuleVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ModuleVersion.Merge(m, src)
+}
+func (m *ModuleVersion) XXX_Size() int {
+	return m.Size()
+}
+func (m *ModuleVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ModuleVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ModuleVersion proto.InternalMessageInfo
+
+func (m *ModuleVersion) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ModuleVersion) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// ParamChangeRecord is a param the upgrade set, value is the json value it was set to (empty when it was removed)
+type ParamChangeRecord struct {
+	Subspace string `protobuf:"bytes,1,opt,name=subspace,proto3" json:"subspace,omitempty"`
+	Key      string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value    string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *ParamChangeRecord) Reset()         { *m = ParamChangeRecord{} }
+func (m *ParamChangeRecord) String() string { return proto.CompactTextString(m) }
+func (*ParamChangeRecord) ProtoMessage()    {}
+func (*ParamChangeRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0501369836708595, []int{2}
+}
+func (m *ParamChangeRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ParamChangeRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ParamChangeRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ParamChangeRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParamChangeRecord.Merge(m, src)
+}
+func (m *ParamChangeRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *ParamChangeRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParamChangeRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParamChangeRecord proto.InternalMessageInfo
+
+func (m *ParamChangeRecord) GetSubspace() string {
+	if m != nil {
+		return m.Subspace
+	}
+	return ""
+}
+
+func (m *ParamChangeRecord) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ParamChangeRecord) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*UpgradeRecord)(nil), "lavanet.lava.upgrades.UpgradeRecord")
+	proto.RegisterType((*ModuleVersion)(nil), "lavanet.lava.upgrades.ModuleVersion")
+	proto.RegisterType((*ParamChangeRecord)(nil), "lavanet.lava.upgrades.ParamChangeRecord")
+}
+
+func init() { proto.RegisterFile("upgrades/upgrade_record.proto", fileDescriptor_0501369836708595) }
+
+var fileDescriptor_0501369836708595 = []byte{
+	// 469 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xcd, 0x8e, 0xd3, 0x30,
+	0x10, 0xc7, 0x1b, 0x92, 0xed, 0x36, 0xd3, 0x0d, 0x02, 0x6b, 0x41, 0x51, 0x11, 0x69, 0x54, 0x40,
+	0xca, 0x29, 0x91, 0x96, 0x0b, 0x17, 0x2e, 0xe5, 0x88, 0xf8, 0x50, 0x96, 0x0f, 0x89, 0x4b, 0xe4,
+	0x26, 0x6e, 0x1a, 0x91, 0xc4, 0x56, 0xec, 0x54, 0xec, 0x5b, 0xec, 0x63, 0xed, 0x71, 0x8f, 0x9c,
+	0x16, 0xd4, 0xbe, 0x08, 0xb2, 0x9d, 0xa4, 0x20, 0xe0, 0xb0, 0x27, 0xcf, 0xfc, 0xfb, 0xeb, 0x7f,
+	0x3c, 0x9e, 0x09, 0x3c, 0x6e, 0x59, 0xde, 0xe0, 0x8c, 0xf0, 0xa8, 0x0b, 0x92, 0x86, 0xa4, 0xb4,
+	0xc9, 0x42, 0xd6, 0x50, 0x41, 0xd1, 0x83, 0x12, 0x6f, 0x71, 0x4d, 0x44, 0x28, 0xcf, 0xb0, 0x67,
+	0x67, 0xa7, 0x39, 0xcd, 0xa9, 0x22, 0x22, 0x19, 0x69, 0x78, 0x36, 0xcf, 0x29, 0xcd, 0x4b, 0x12,
+	0xa9, 0x6c, 0xd5, 0xae, 0x23, 0x51, 0x54, 0x84, 0x0b, 0x5c, 0x31, 0x0d, 0x2c, 0x6e, 0x4c, 0x70,
+	0x3e, 0x6a, 0x8f, 0x58, 0x55, 0x41, 0x8f, 0xc0, 0x66, 0x25, 0xae, 0x93, 0x1a, 0x57, 0xc4, 0x35,
+	0x7c, 0x23, 0xb0, 0xe3, 0x89, 0x14, 0xde, 0xe2, 0x8a, 0xa0, 0x87, 0x30, 0xde, 0x90, 0x22, 0xdf,
+	0x08, 0xf7, 0x8e, 0x6f, 0x04, 0x66, 0xdc, 0x65, 0xe8, 0x05, 0x58, 0xd2, 0xd9, 0x35, 0x7d, 0x23,
+	0x98, 0x9e, 0xcd, 0x42, 0x5d, 0x36, 0xec, 0xcb, 0x86, 0x1f, 0xfa, 0xb2, 0xcb, 0xc9, 0xd5, 0xcd,
+	0x7c, 0x74, 0xf9, 0x63, 0x6e, 0xc4, 0xea, 0x1f, 0xe8, 0x1d, 0x38, 0xeb, 0x86, 0x56, 0xc9, 0x96,
+	0x34, 0xbc, 0xa0, 0x35, 0x77, 0x2d, 0xdf, 0x0c, 0xa6, 0x67, 0x4f, 0xc3, 0x7f, 0xb6, 0x19, 0xbe,
+	0xa1, 0x59, 0x5b, 0x92, 0x4f, 0x1a, 0x5e, 0x5a, 0xd2, 0x2c, 0x3e, 0x91, 0x06, 0x9d, 0xc4, 0xd1,
+	0x6b, 0x98, 0x0a, 0x7a, 0xb0, 0x3b, 0xba, 0xb5, 0x1d, 0x08, 0x3a, 0x98, 0xcd, 0x61, 0x8a, 0xb3,
+	0x8c, 0x64, 0x09, 0x67, 0x24, 0xe5, 0xee, 0xd8, 0x37, 0x03, 0x3b, 0x06, 0x25, 0x9d, 0x4b, 0x05,
+	0x3d, 0x83, 0xbb, 0x15, 0xcd, 0x8a, 0x75, 0x31, 0x30, 0xc7, 0x8a, 0x71, 0x7a, 0x55, 0x63, 0x4f,
+	0xc0, 0x69, 0x48, 0x45, 0xb7, 0x03, 0x35, 0x51, 0xd4, 0x49, 0x27, 0x6a, 0xe8, 0x1c, 0x1c, 0x86,
+	0x1b, 0x5c, 0x25, 0xe9, 0x06, 0xd7, 0x39, 0xe1, 0xae, 0xad, 0xee, 0x1e, 0xfc, 0xe7, 0xee, 0xef,
+	0x25, 0xfb, 0x4a, 0xa1, 0x7a, 0x74, 0xfd, 0x73, 0xb0, 0xc3, 0x0f, 0x7c, 0xf1, 0x12, 0x9c, 0x3f,
+	0x9a, 0x44, 0x08, 0xac, 0xdf, 0x46, 0xab, 0x62, 0xe4, 0xc2, 0x71, 0xf7, 0x60, 0x6a, 0xae, 0x56,
+	0xdc, 0xa7, 0x8b, 0xcf, 0x70, 0xff, 0xaf, 0x3a, 0x68, 0x06, 0x13, 0xde, 0xae, 0x38, 0xc3, 0xe9,
+	0xb0, 0x21, 0x7d, 0x8e, 0xee, 0x81, 0xf9, 0x95, 0x5c, 0x28, 0x1b, 0x3b, 0x96, 0x21, 0x3a, 0x85,
+	0xa3, 0x2d, 0x2e, 0x5b, 0xbd, 0x1c, 0x76, 0xac, 0x93, 0xe5, 0xf2, 0x6a, 0xe7, 0x19, 0xd7, 0x3b,
+	0xcf, 0xf8, 0xb9, 0xf3, 0x8c, 0xcb, 0xbd, 0x37, 0xba, 0xde, 0x7b, 0xa3, 0xef, 0x7b, 0x6f, 0xf4,
+	0x25, 0xc8, 0x0b, 0xb1, 0x69, 0x57, 0x61, 0x4a, 0xab, 0xa8, 0xeb, 0x5c, 0x9d, 0xd1, 0xb7, 0x68,
+	0xf8, 0x32, 0xc4, 0x05, 0x23, 0x7c, 0x35, 0x56, 0xfb, 0xf5, 0xfc, 0xd7, 0x00, 0x74, 0xb3, 0xed,
+	0x2e, 0x32, 0x03, 0x00, 0x00,
+}
+
+func (m *UpgradeRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpgradeRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpgradeRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ParamChanges) > 0 {
+		for iNdEx := len(m.ParamChanges) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ParamChanges[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintUpgradeRecord(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.RemovedSpecs) > 0 {
+		for iNdEx := len(m.RemovedSpecs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RemovedSpecs[iNdEx])
+			copy(dAtA[i:], m.RemovedSpecs[iNdEx])
+			i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.RemovedSpecs[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.ModifiedSpecs) > 0 {
+		for iNdEx := len(m.ModifiedSpecs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ModifiedSpecs[iNdEx])
+			copy(dAtA[i:], m.ModifiedSpecs[iNdEx])
+			i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.ModifiedSpecs[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.AddedSpecs) > 0 {
+		for iNdEx := len(m.AddedSpecs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AddedSpecs[iNdEx])
+			copy(dAtA[i:], m.AddedSpecs[iNdEx])
+			i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.AddedSpecs[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.ToVersions) > 0 {
+		for iNdEx := len(m.ToVersions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ToVersions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintUpgradeRecord(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.FromVersions) > 0 {
+		for iNdEx := len(m.FromVersions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.FromVersions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintUpgradeRecord(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err1 != nil {
+		return 0, err1
+	}
+	i -= n1
+	i = encodeVarintUpgradeRecord(dAtA, i, uint64(n1))
+	i--
+	dAtA[i] = 0x1a
+	if m.Height != 0 {
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.PlanName) > 0 {
+		i -= len(m.PlanName)
+		copy(dAtA[i:], m.PlanName)
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.PlanName)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ModuleVersion) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ModuleVersion) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ModuleVersion) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ParamChangeRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ParamChangeRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ParamChangeRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Subspace) > 0 {
+		i -= len(m.Subspace)
+		copy(dAtA[i:], m.Subspace)
+		i = encodeVarintUpgradeRecord(dAtA, i, uint64(len(m.Subspace)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintUpgradeRecord(dAtA []byte, offset int, v uint64) int {
+	offset -= sovUpgradeRecord(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *UpgradeRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.PlanName)
+	if l > 0 {
+		n += 1 + l + sovUpgradeRecord(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovUpgradeRecord(uint64(m.Height))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Time)
+	n += 1 + l + sovUpgradeRecord(uint64(l))
+	if len(m.FromVersions) > 0 {
+		for _, e := range m.FromVersions {
+			l = e.Size()
+			n += 1 + l + sovUpgradeRecord(uint64(l))
+		}
+	}
+	if len(m.ToVersions) > 0 {
+		for _, e := range m.ToVersions {
+			l = e.Size()
+			n += 1 + l + sovUpgradeRecord(uint64(l))
+		}
+	}
+	if len(m.AddedSpecs) > 0 {
+		for _, s := range m.AddedSpecs {
+			l = len(s)
+			n += 1 + l + sovUpgradeRecord(uint64(l))
+		}
+	}
+	if len(m.ModifiedSpecs) > 0 {
+		for _, s := range m.ModifiedSpecs {
+			l = len(s)
+			n += 1 + l + sovUpgradeRecord(uint64(l))
+		}
+	}
+	if len(m.RemovedSpecs) > 0 {
+		for _, s := range m.RemovedSpecs {
+			l = len(s)
+			n += 1 + l + sovUpgradeRecord(uint64(l))
+		}
+	}
+	if len(m.ParamChanges) > 0 {
+		for _, e := range m.ParamChanges {
+			l = e.Size()
+			n += 1 + l + sovUpgradeRecord(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ModuleVersion) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovUpgradeRecord(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovUpgradeRecord(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *ParamChangeRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Subspace)
+	if l > 0 {
+		n += 1 + l + sovUpgradeRecord(uint64(l))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovUpgradeRecord(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovUpgradeRecord(uint64(l))
+	}
+	return n
+}
+
+func sovUpgradeRecord(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozUpgradeRecord(x uint64) (n int) {
+	return sovUpgradeRecord(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *UpgradeRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUpgradeRecord
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpgradeRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpgradeRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PlanName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PlanName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Time, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromVersions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FromVersions = append(m.FromVersions, ModuleVersion{})
+			if err := m.FromVersions[len(m.FromVersions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToVersions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ToVersions = append(m.ToVersions, ModuleVersion{})
+			if err := m.ToVersions[len(m.ToVersions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AddedSpecs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AddedSpecs = append(m.AddedSpecs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ModifiedSpecs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ModifiedSpecs = append(m.ModifiedSpecs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RemovedSpecs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RemovedSpecs = append(m.RemovedSpecs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParamChanges", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ParamChanges = append(m.ParamChanges, ParamChangeRecord{})
+			if err := m.ParamChanges[len(m.ParamChanges)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUpgradeRecord(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ModuleVersion) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUpgradeRecord
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ModuleVersion: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ModuleVersion: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUpgradeRecord(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParamChangeRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUpgradeRecord
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParamChangeRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParamChangeRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subspace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subspace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUpgradeRecord(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthUpgradeRecord
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipUpgradeRecord(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowUpgradeRecord
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowUpgradeRecord
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthUpgradeRecord
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupUpgradeRecord
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthUpgradeRecord
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthUpgradeRecord        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowUpgradeRecord          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupUpgradeRecord = fmt.Errorf("proto: unexpected end of group")
+)